@@ -0,0 +1,244 @@
+/*
+blocking.go
+Description:
+	Diagnostics and repairs for transition systems that are blocking, i.e. systems where
+	some state and input pair has an empty Post. The paper
+	'Formal Methods for Adaptive Control of Dynamical Systems' by Sadra Sadraddini and Calin Belta
+	assumes that every transition system is non-blocking.
+*/
+
+package adaptive
+
+import (
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+StateInputPair
+Description:
+	A single (state, input) pair of a transition system.
+*/
+type StateInputPair struct {
+	State TransitionSystemState
+	Input string
+}
+
+/*
+NonBlockingMethod
+Description:
+	Identifies how MakeNonBlocking() should repair the blocking pairs of a system.
+	- AddSinkState redirects every blocking pair to a new (labelled) sink state.
+	- RemoveDisabledInputs removes every input which is not enabled at some state.
+*/
+type NonBlockingMethod int
+
+const (
+	AddSinkState NonBlockingMethod = iota
+	RemoveDisabledInputs
+)
+
+/*
+NonBlockingStrategy
+Description:
+	Describes the repair that MakeNonBlocking() should perform.
+	SinkName and SinkLabels are only used by the AddSinkState method.
+	If SinkName is empty, then the sink is named "sink".
+*/
+type NonBlockingStrategy struct {
+	Method     NonBlockingMethod
+	SinkName   string
+	SinkLabels []string
+}
+
+/*
+NonBlockingReport
+Description:
+	Summarizes the changes that MakeNonBlocking() made to a transition system.
+*/
+type NonBlockingReport struct {
+	BlockingPairs []StateInputPair
+	AddedStates   []TransitionSystemState
+	RemovedInputs []string
+}
+
+/*
+Functions
+*/
+
+/*
+String
+Description:
+	Prints the pair as (state,input).
+*/
+func (pair StateInputPair) String() string {
+	return fmt.Sprintf("(%v,%v)", pair.State, pair.Input)
+}
+
+/*
+BlockingPairs
+Description:
+	Lists every (state, input) pair of the transition system whose Post is empty.
+	The pairs are listed in the order of ts.X and then ts.U.
+Usage:
+	pairs := ts.BlockingPairs()
+*/
+func (ts TransitionSystem) BlockingPairs() []StateInputPair {
+	var pairs []StateInputPair
+	for _, state := range ts.X {
+		for _, input := range ts.U {
+			tempPostValue, _ := Post(state, input)
+			if len(tempPostValue) == 0 {
+				pairs = append(pairs, StateInputPair{State: state, Input: input})
+			}
+		}
+	}
+
+	return pairs
+}
+
+/*
+MakeNonBlocking
+Description:
+	Creates a non-blocking version of the transition system using the given strategy.
+	The original system is not modified. The report lists the blocking pairs of the
+	original system and the states or inputs that were added or removed.
+Usage:
+	tsOut, report, err := ts.MakeNonBlocking(NonBlockingStrategy{Method: AddSinkState, SinkLabels: []string{"fail"}})
+*/
+func (ts TransitionSystem) MakeNonBlocking(strategy NonBlockingStrategy) (TransitionSystem, NonBlockingReport, error) {
+	// Check the input system
+	err := ts.Check()
+	if err != nil {
+		return TransitionSystem{}, NonBlockingReport{}, fmt.Errorf("There was an issue checking the transition system: %v", err)
+	}
+
+	report := NonBlockingReport{BlockingPairs: ts.BlockingPairs()}
+
+	// Collect the components of the system as names
+	stateNames, inputNames, transitionMap, apNames, labelMap := ts.toNames()
+
+	switch strategy.Method {
+	case AddSinkState:
+		// Nothing is added to a system which is already non-blocking, so its names cannot collide with the sink
+		if len(report.BlockingPairs) == 0 {
+			break
+		}
+
+		sinkName := strategy.SinkName
+		if sinkName == "" {
+			sinkName = "sink"
+		}
+		if _, found := mc.FindInSlice(sinkName, stateNames); found {
+			return TransitionSystem{}, report, fmt.Errorf("The sink state \"%v\" is already in the state space.", sinkName)
+		}
+
+		// Redirect every blocking pair to the sink
+		for _, pair := range report.BlockingPairs {
+			if _, ok := transitionMap[pair.State.Name]; !ok {
+				transitionMap[pair.State.Name] = make(map[string][]string)
+			}
+			transitionMap[pair.State.Name][pair.Input] = []string{sinkName}
+		}
+
+		// The sink loops onto itself for every input
+		sinkMap := make(map[string][]string)
+		for _, input := range inputNames {
+			sinkMap[input] = []string{sinkName}
+		}
+		transitionMap[sinkName] = sinkMap
+
+		stateNames = append(stateNames, sinkName)
+		apNames = mc.AppendIfUnique(apNames, strategy.SinkLabels...)
+		labelMap[sinkName] = strategy.SinkLabels
+
+	case RemoveDisabledInputs:
+		var remainingInputs []string
+		for _, input := range inputNames {
+			inputIsBlocked := false
+			for _, pair := range report.BlockingPairs {
+				if pair.Input == input {
+					inputIsBlocked = true
+				}
+			}
+
+			if inputIsBlocked {
+				report.RemovedInputs = append(report.RemovedInputs, input)
+				for _, actionMap := range transitionMap {
+					delete(actionMap, input)
+				}
+			} else {
+				remainingInputs = append(remainingInputs, input)
+			}
+		}
+
+		if len(remainingInputs) == 0 && len(stateNames) > 0 {
+			return TransitionSystem{}, report, fmt.Errorf("Every input is blocked at some state; removing them would leave an empty input set.")
+		}
+		inputNames = remainingInputs
+
+	default:
+		return TransitionSystem{}, report, fmt.Errorf("Unexpected non-blocking method given to MakeNonBlocking(): %v", strategy.Method)
+	}
+
+	tsOut, err := GetTransitionSystem(stateNames, inputNames, transitionMap, apNames, labelMap)
+	if err != nil {
+		return tsOut, report, fmt.Errorf("There was an issue creating the non-blocking transition system: %v", err)
+	}
+
+	// Collect the added states from the new system
+	if strategy.Method == AddSinkState && len(report.BlockingPairs) > 0 {
+		report.AddedStates = []TransitionSystemState{tsOut.X[len(tsOut.X)-1]}
+	}
+
+	return tsOut, report, nil
+}
+
+/*
+toNames
+Description:
+	Converts the transition system into the slices and maps of strings that GetTransitionSystem()
+	accepts. The returned maps are copies and can be modified freely.
+*/
+func (ts TransitionSystem) toNames() ([]string, []string, map[string]map[string][]string, []string, map[string][]string) {
+	var stateNames []string
+	for _, state := range ts.X {
+		stateNames = append(stateNames, state.Name)
+	}
+
+	inputNames := append([]string{}, ts.U...)
+
+	transitionMap := make(map[string]map[string][]string)
+	for state, actionMap := range ts.Transition {
+		tempActionMap := make(map[string][]string)
+		for input, targetStates := range actionMap {
+			var targetNames []string
+			for _, targetState := range targetStates {
+				targetNames = append(targetNames, targetState.Name)
+			}
+			tempActionMap[input] = targetNames
+		}
+		transitionMap[state.Name] = tempActionMap
+	}
+
+	var apNames []string
+	for _, ap := range ts.Pi {
+		apNames = append(apNames, ap.Name)
+	}
+
+	labelMap := make(map[string][]string)
+	for state, observations := range ts.O {
+		var observationNames []string
+		for _, ap := range observations {
+			observationNames = append(observationNames, ap.Name)
+		}
+		labelMap[state.Name] = observationNames
+	}
+
+	return stateNames, inputNames, transitionMap, apNames, labelMap
+}
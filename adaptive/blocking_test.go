@@ -0,0 +1,228 @@
+/*
+blocking_test.go
+Description:
+	Tests the functions and objects created in blocking.go
+*/
+
+package adaptive
+
+import (
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+GetBlockingTS
+Description:
+	Creates a small transition system where state "2" has no successors under input "b"
+	and state "3" has no successors at all.
+*/
+func GetBlockingTS() TransitionSystem {
+	ts0, _ := GetTransitionSystem(
+		[]string{"1", "2", "3"}, []string{"a", "b"},
+		map[string]map[string][]string{
+			"1": {
+				"a": {"2"},
+				"b": {"1", "3"},
+			},
+			"2": {
+				"a": {"3"},
+				"b": {},
+			},
+		},
+		[]string{"A", "B"},
+		map[string][]string{
+			"1": {"A"},
+			"2": {"B"},
+			"3": {"A", "B"},
+		},
+	)
+
+	return ts0
+}
+
+/*
+TestBlocking_BlockingPairs1
+Description:
+	Verifies that BlockingPairs() finds all three blocking pairs of GetBlockingTS().
+*/
+func TestBlocking_BlockingPairs1(t *testing.T) {
+	// Constants
+	ts0 := GetBlockingTS()
+
+	// Algorithm
+	pairs := ts0.BlockingPairs()
+	if len(pairs) != 3 {
+		t.Errorf("Expected 3 blocking pairs, but found %v: %v", len(pairs), pairs)
+	}
+
+	expectedPairs := []string{"(2,b)", "(3,a)", "(3,b)"}
+	for pairIndex, pair := range pairs {
+		if pair.String() != expectedPairs[pairIndex] {
+			t.Errorf("Expected pair %v to be %v, but it was %v.", pairIndex, expectedPairs[pairIndex], pair)
+		}
+	}
+}
+
+/*
+TestBlocking_BlockingPairs2
+Description:
+	Verifies that BlockingPairs() is empty for a non-blocking system.
+*/
+func TestBlocking_BlockingPairs2(t *testing.T) {
+	// Constants
+	ts0, err := GetTransitionSystem(
+		[]string{"1", "2"}, []string{"a"},
+		map[string]map[string][]string{
+			"1": {"a": {"2"}},
+			"2": {"a": {"1"}},
+		},
+		[]string{"A"},
+		map[string][]string{},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Algorithm
+	if len(ts0.BlockingPairs()) != 0 {
+		t.Errorf("Expected no blocking pairs, but found %v.", ts0.BlockingPairs())
+	}
+
+	if !ts0.IsNonBlocking() {
+		t.Errorf("Expected the system to be non-blocking.")
+	}
+}
+
+/*
+TestBlocking_MakeNonBlocking1
+Description:
+	Verifies that the AddSinkState method creates a labelled sink and a non-blocking system.
+*/
+func TestBlocking_MakeNonBlocking1(t *testing.T) {
+	// Constants
+	ts0 := GetBlockingTS()
+
+	// Algorithm
+	ts1, report, err := ts0.MakeNonBlocking(
+		NonBlockingStrategy{Method: AddSinkState, SinkName: "trap", SinkLabels: []string{"fail"}},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !ts1.IsNonBlocking() {
+		t.Errorf("Expected the new system to be non-blocking, but found blocking pairs %v.", ts1.BlockingPairs())
+	}
+
+	if len(ts1.X) != 4 {
+		t.Errorf("Expected 4 states in the new system, but found %v.", len(ts1.X))
+	}
+
+	if len(report.BlockingPairs) != 3 {
+		t.Errorf("Expected the report to contain 3 blocking pairs, but found %v.", len(report.BlockingPairs))
+	}
+
+	if len(report.AddedStates) != 1 || report.AddedStates[0].Name != "trap" {
+		t.Errorf("Expected the report to list the sink \"trap\", but it listed %v.", report.AddedStates)
+	}
+
+	sink := report.AddedStates[0]
+	if tf, _ := sink.Satisfies(mc.AtomicProposition{Name: "fail"}); !tf {
+		t.Errorf("Expected the sink to be labelled with \"fail\".")
+	}
+
+	// The original system should not be modified.
+	if ts0.IsNonBlocking() || len(ts0.X) != 3 {
+		t.Errorf("The original system was modified by MakeNonBlocking().")
+	}
+}
+
+/*
+TestBlocking_MakeNonBlocking2
+Description:
+	Verifies that the RemoveDisabledInputs method fails when every input is blocked somewhere.
+*/
+func TestBlocking_MakeNonBlocking2(t *testing.T) {
+	// Constants
+	ts0 := GetBlockingTS()
+
+	// Algorithm
+	_, _, err := ts0.MakeNonBlocking(NonBlockingStrategy{Method: RemoveDisabledInputs})
+	if err == nil {
+		t.Errorf("Expected an error when every input is removed, but there was none.")
+	}
+}
+
+/*
+TestBlocking_MakeNonBlocking3
+Description:
+	Verifies that the RemoveDisabledInputs method removes only the inputs that are blocked.
+*/
+func TestBlocking_MakeNonBlocking3(t *testing.T) {
+	// Constants
+	ts0, err := GetTransitionSystem(
+		[]string{"1", "2"}, []string{"a", "b"},
+		map[string]map[string][]string{
+			"1": {"a": {"2"}, "b": {"1"}},
+			"2": {"a": {"1"}},
+		},
+		[]string{"A"},
+		map[string][]string{"1": {"A"}},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Algorithm
+	ts1, report, err := ts0.MakeNonBlocking(NonBlockingStrategy{Method: RemoveDisabledInputs})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(report.RemovedInputs) != 1 || report.RemovedInputs[0] != "b" {
+		t.Errorf("Expected the report to list the removed input \"b\", but it listed %v.", report.RemovedInputs)
+	}
+
+	if len(ts1.U) != 1 || !ts1.IsNonBlocking() {
+		t.Errorf("Expected a non-blocking system with one input, but U = %v.", ts1.U)
+	}
+}
+
+/*
+TestBlocking_MakeNonBlocking4
+Description:
+
+	Verifies that the AddSinkState method leaves a non-blocking system unchanged, even if one of its states
+	has the name of the sink.
+*/
+func TestBlocking_MakeNonBlocking4(t *testing.T) {
+	// Constants
+	ts0, err := GetTransitionSystem(
+		[]string{"1", "sink"}, []string{"a"},
+		map[string]map[string][]string{
+			"1":    {"a": {"sink"}},
+			"sink": {"a": {"sink"}},
+		},
+		[]string{"A"},
+		map[string][]string{"1": {"A"}},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Algorithm
+	ts1, report, err := ts0.MakeNonBlocking(NonBlockingStrategy{Method: AddSinkState})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(report.BlockingPairs) != 0 || len(report.AddedStates) != 0 {
+		t.Errorf("Expected an empty report, but found the pairs %v and the states %v.", report.BlockingPairs, report.AddedStates)
+	}
+
+	if len(ts1.X) != 2 || len(ts1.U) != 1 || !ts1.IsNonBlocking() {
+		t.Errorf("Expected the system to be unchanged, but it has the states %v and the inputs %v.", ts1.X, ts1.U)
+	}
+}
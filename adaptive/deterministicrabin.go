@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/omega"
 )
 
 /*
@@ -21,6 +22,8 @@ Types
 DeterministicRabinAutomaton
 Description:
 	An object to represent the Deterministic Rabin Automaton.
+	The language queries (AcceptsLasso, IsEmpty, ToParity, ...) convert the current fields of the DRA with
+	ToAutomaton() on every call, so changes to S, Alphabet or Omega are always taken into account.
 */
 type DeterministicRabinAutomaton struct {
 	S        []DRAState
@@ -281,3 +284,284 @@ func (draIn DeterministicRabinAutomaton) CheckOmega() error {
 	// If all checks out return no errors
 	return nil
 }
+
+/*
+Functions for the omega-automaton view
+*/
+
+/*
+InitialState
+Description:
+	Returns the initial state s0 of the automaton.
+*/
+func (draIn DeterministicRabinAutomaton) InitialState() DRAState {
+	return draIn.s0
+}
+
+/*
+ToAutomaton
+Description:
+	Creates the generic omega.Automaton with a Rabin acceptance condition that this DRA represents.
+	Each pair (E,F) of Omega is accepting if E is visited finitely often and F is visited infinitely often.
+	The automaton is built from the current fields of the DRA and shares no maps with it.
+*/
+func (draIn DeterministicRabinAutomaton) ToAutomaton() omega.Automaton {
+	a := &omega.Automaton{Alphabet: append([]mc.AtomicProposition{}, draIn.Alphabet...)}
+
+	for _, s := range draIn.S {
+		a.Q = append(a.Q, omega.State{Name: s.Name, Automaton: a})
+	}
+	a.Q0 = a.StatesNamed(draIn.s0.Name)
+
+	a.Delta = make(map[omega.State]map[mc.AtomicProposition][]omega.State)
+	for s, apMap := range draIn.alpha {
+		tempAPMap := make(map[mc.AtomicProposition][]omega.State)
+		for ap, successor := range apMap {
+			tempAPMap[ap] = []omega.State{{Name: successor.Name, Automaton: a}}
+		}
+		a.Delta[omega.State{Name: s.Name, Automaton: a}] = tempAPMap
+	}
+
+	var pairs []omega.AcceptancePair
+	for _, pair := range draIn.Omega {
+		pairs = append(pairs, omega.AcceptancePair{
+			E: a.StatesNamed(draStateNames(pair[0])...),
+			F: a.StatesNamed(draStateNames(pair[1])...),
+		})
+	}
+	a.Acceptance = omega.Rabin{Pairs: pairs}
+
+	return *a
+}
+
+/*
+GetDRAFromAutomaton
+Description:
+	Creates a DeterministicRabinAutomaton from a deterministic omega.Automaton with a Rabin acceptance condition.
+	Automata with other acceptance conditions can be converted with their ToRabin() method first.
+*/
+func GetDRAFromAutomaton(a omega.Automaton) (DeterministicRabinAutomaton, error) {
+	// Input Processing
+	if !a.IsDeterministic() || len(a.Q0) != 1 {
+		return DeterministicRabinAutomaton{}, fmt.Errorf("The automaton must be deterministic with exactly one initial state.")
+	}
+
+	rabinCondition, ok := a.Acceptance.(omega.Rabin)
+	if !ok {
+		return DeterministicRabinAutomaton{}, fmt.Errorf("The automaton has the acceptance condition %v, not a Rabin condition.", a.Acceptance)
+	}
+
+	// Collect names
+	var SNames []string
+	for _, q := range a.Q {
+		SNames = append(SNames, q.Name)
+	}
+
+	var alphabetNames []string
+	for _, ap := range a.Alphabet {
+		alphabetNames = append(alphabetNames, ap.Name)
+	}
+
+	transitionMap := make(map[string]map[string]string)
+	for _, q := range a.Q {
+		apMap := make(map[string]string)
+		for _, ap := range a.Alphabet {
+			if successors := a.Post(q, ap); len(successors) == 1 {
+				apMap[ap.Name] = successors[0].Name
+			}
+		}
+		transitionMap[q.Name] = apMap
+	}
+
+	var omegaSlice [][2][]string
+	for _, pair := range rabinCondition.Pairs {
+		omegaSlice = append(omegaSlice, [2][]string{omegaStateNames(pair.E), omegaStateNames(pair.F)})
+	}
+
+	return GetDRA(SNames, a.Q0[0].Name, alphabetNames, transitionMap, omegaSlice)
+}
+
+/*
+AcceptsLasso
+Description:
+	Determines if the DRA accepts the infinite word prefix (cycle)^omega.
+*/
+func (draIn DeterministicRabinAutomaton) AcceptsLasso(prefix []mc.AtomicProposition, cycle []mc.AtomicProposition) (bool, error) {
+	return draIn.ToAutomaton().AcceptsLasso(prefix, cycle)
+}
+
+/*
+ToParity
+Description:
+	Converts the DRA into an equivalent deterministic parity automaton using index appearance records.
+*/
+func (draIn DeterministicRabinAutomaton) ToParity() (omega.Automaton, error) {
+	return draIn.ToAutomaton().ToParity()
+}
+
+/*
+draStateNames
+Description:
+	Collects the names of a slice of DRAState objects.
+*/
+func draStateNames(states []DRAState) []string {
+	var names []string
+	for _, s := range states {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+/*
+omegaStateNames
+Description:
+	Collects the names of a slice of omega.State objects.
+*/
+func omegaStateNames(states []omega.State) []string {
+	var names []string
+	for _, q := range states {
+		names = append(names, q.Name)
+	}
+	return names
+}
+
+/*
+Functions for the language of the DRA
+*/
+
+/*
+IsEmpty
+Description:
+	Determines if the DRA accepts no words. If it accepts some word, then the returned lasso
+	is an accepted word prefix (cycle)^omega together with the accepting run.
+Usage:
+	isEmpty, witness, err := dra0.IsEmpty()
+*/
+func (draIn DeterministicRabinAutomaton) IsEmpty() (bool, omega.Lasso, error) {
+	return draIn.ToAutomaton().IsEmpty()
+}
+
+/*
+Complement
+Description:
+	Creates a deterministic Streett automaton which accepts exactly the words that the DRA rejects.
+	The DRA is completed with a rejecting sink state first if some transitions are missing.
+*/
+func (draIn DeterministicRabinAutomaton) Complement() (omega.Automaton, error) {
+	return draIn.ToAutomaton().Complement()
+}
+
+/*
+Equivalent
+Description:
+	Determines if two DRAs accept the same language. If they do not, then the returned lasso
+	is a word that exactly one of the DRAs accepts.
+*/
+func Equivalent(dra1 DeterministicRabinAutomaton, dra2 DeterministicRabinAutomaton) (bool, omega.Lasso, error) {
+	return omega.Equivalent(dra1.ToAutomaton(), dra2.ToAutomaton())
+}
+
+/*
+Reduce
+Description:
+	Creates a smaller DRA which accepts the same language. This is a heuristic:
+	- states which are unreachable from s0 are removed, and
+	- states are merged when they belong to the same sets of Omega and their successors are merged for every symbol.
+	Merging only states with the same Omega membership keeps the Rabin condition unchanged, so the result need not be minimal.
+*/
+func (draIn DeterministicRabinAutomaton) Reduce() (DeterministicRabinAutomaton, error) {
+	// Remove the unreachable states
+	a := draIn.ToAutomaton()
+	if err := a.Check(); err != nil {
+		return DeterministicRabinAutomaton{}, fmt.Errorf("There was an issue checking the DRA: %v", err)
+	}
+	reachable := a.Reachable()
+
+	// Create the initial partition from the membership of each state in the sets of Omega.
+	blockOf := make(map[string]int)
+	signatureToBlock := make(map[string]int)
+	for _, q := range reachable {
+		signature := ""
+		for _, pair := range draIn.Omega {
+			signature += fmt.Sprintf("%v%v", (DRAState{Name: q.Name}).In(pair[0]), (DRAState{Name: q.Name}).In(pair[1]))
+		}
+		if _, found := signatureToBlock[signature]; !found {
+			signatureToBlock[signature] = len(signatureToBlock)
+		}
+		blockOf[q.Name] = signatureToBlock[signature]
+	}
+
+	// Refine the partition until the successors of each block agree
+	numBlocks := len(signatureToBlock)
+	for {
+		nextBlockOf := make(map[string]int)
+		signatureToBlock = make(map[string]int)
+		for _, q := range reachable {
+			signature := fmt.Sprintf("%v", blockOf[q.Name])
+			for _, ap := range a.Alphabet {
+				successors := a.Post(q, ap)
+				if len(successors) == 0 {
+					signature += "|-"
+				} else {
+					signature += fmt.Sprintf("|%v", blockOf[successors[0].Name])
+				}
+			}
+			if _, found := signatureToBlock[signature]; !found {
+				signatureToBlock[signature] = len(signatureToBlock)
+			}
+			nextBlockOf[q.Name] = signatureToBlock[signature]
+		}
+
+		blockOf = nextBlockOf
+		if len(signatureToBlock) == numBlocks {
+			break
+		}
+		numBlocks = len(signatureToBlock)
+	}
+
+	// Name each block after its first state
+	blockNames := make([]string, numBlocks)
+	var SNames []string
+	for _, q := range reachable {
+		if blockNames[blockOf[q.Name]] == "" {
+			blockNames[blockOf[q.Name]] = q.Name
+			SNames = append(SNames, q.Name)
+		}
+	}
+
+	var alphabetNames []string
+	for _, ap := range a.Alphabet {
+		alphabetNames = append(alphabetNames, ap.Name)
+	}
+
+	transitionMap := make(map[string]map[string]string)
+	for _, q := range reachable {
+		blockName := blockNames[blockOf[q.Name]]
+		if _, found := transitionMap[blockName]; found {
+			continue
+		}
+		apMap := make(map[string]string)
+		for _, ap := range a.Alphabet {
+			if successors := a.Post(q, ap); len(successors) > 0 {
+				apMap[ap.Name] = blockNames[blockOf[successors[0].Name]]
+			}
+		}
+		transitionMap[blockName] = apMap
+	}
+
+	var omegaSlice [][2][]string
+	for _, pair := range draIn.Omega {
+		var reducedPair [2][]string
+		for setIndex, setIn := range pair {
+			reducedPair[setIndex] = []string{}
+			for _, blockName := range SNames {
+				if (DRAState{Name: blockName}).In(setIn) {
+					reducedPair[setIndex] = append(reducedPair[setIndex], blockName)
+				}
+			}
+		}
+		omegaSlice = append(omegaSlice, reducedPair)
+	}
+
+	return GetDRA(SNames, blockNames[blockOf[draIn.s0.Name]], alphabetNames, transitionMap, omegaSlice)
+}
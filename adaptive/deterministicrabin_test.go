@@ -573,3 +573,294 @@ func TestDeterministicRabin_CheckOmega3(t *testing.T) {
 	}
 
 }
+
+/*
+GetEventuallyAlwaysRedDRA
+Description:
+	Creates a DRA over {red,blue} which accepts the words with finitely many blue's.
+*/
+func GetEventuallyAlwaysRedDRA() DeterministicRabinAutomaton {
+	dra0, _ := GetDRA(
+		[]string{"sawRed", "sawBlue"}, "sawRed", []string{"red", "blue"},
+		map[string]map[string]string{
+			"sawRed":  {"red": "sawRed", "blue": "sawBlue"},
+			"sawBlue": {"red": "sawRed", "blue": "sawBlue"},
+		},
+		[][2][]string{{{"sawBlue"}, {"sawRed"}}},
+	)
+
+	return dra0
+}
+
+/*
+TestDeterministicRabin_ToAutomaton1
+Description:
+	Verifies that the omega.Automaton view has the same states and a Rabin condition,
+	and that converting it back gives the same DRA.
+*/
+func TestDeterministicRabin_ToAutomaton1(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+
+	// Algorithm
+	a := dra0.ToAutomaton()
+	if len(a.Q) != 2 || len(a.Q0) != 1 || a.Q0[0].Name != "sawRed" {
+		t.Errorf("Unexpected states in the automaton view: %v, %v", a.Q, a.Q0)
+	}
+
+	if err := a.Check(); err != nil {
+		t.Errorf("Unexpected error checking the automaton view: %v", err)
+	}
+
+	dra1, err := GetDRAFromAutomaton(a)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(dra1.Omega) != 1 || !dra1.InitialState().Equals(dra0.InitialState()) {
+		t.Errorf("The round trip changed the DRA: %v", dra1)
+	}
+}
+
+/*
+TestDeterministicRabin_ToAutomaton2
+Description:
+	Verifies that the language queries follow changes to Omega and that the automata returned by ToAutomaton()
+	do not share their transitions with the DRA.
+*/
+func TestDeterministicRabin_ToAutomaton2(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+	red := mc.AtomicProposition{Name: "red"}
+
+	// Algorithm
+	a1 := dra0.ToAutomaton()
+	for q := range a1.Delta {
+		delete(a1.Delta, q)
+	}
+	if accepted, err := dra0.AcceptsLasso(nil, []mc.AtomicProposition{red}); err != nil || !accepted {
+		t.Errorf("Expected the DRA to accept red^omega after changing a copy of its automaton: %v", err)
+	}
+
+	dra0.Omega = nil
+	if accepted, _ := dra0.AcceptsLasso(nil, []mc.AtomicProposition{red}); accepted {
+		t.Errorf("Expected the DRA without pairs to reject red^omega.")
+	}
+	if empty, _, err := dra0.IsEmpty(); err != nil || !empty {
+		t.Errorf("Expected the DRA without pairs to be empty: %v", err)
+	}
+}
+
+/*
+TestDeterministicRabin_AcceptsLasso1
+Description:
+	Verifies that the DRA accepts blue (red)^omega but not (red blue)^omega.
+*/
+func TestDeterministicRabin_AcceptsLasso1(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+	red := mc.AtomicProposition{Name: "red"}
+	blue := mc.AtomicProposition{Name: "blue"}
+
+	// Algorithm
+	tf, err := dra0.AcceptsLasso([]mc.AtomicProposition{blue}, []mc.AtomicProposition{red})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !tf {
+		t.Errorf("Expected blue (red)^omega to be accepted.")
+	}
+
+	tf, _ = dra0.AcceptsLasso([]mc.AtomicProposition{}, []mc.AtomicProposition{red, blue})
+	if tf {
+		t.Errorf("Expected (red blue)^omega to be rejected.")
+	}
+}
+
+/*
+TestDeterministicRabin_ToParity1
+Description:
+	Verifies that the parity automaton of the DRA agrees with the DRA on a few lassos.
+*/
+func TestDeterministicRabin_ToParity1(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+	red := mc.AtomicProposition{Name: "red"}
+	blue := mc.AtomicProposition{Name: "blue"}
+	lassos := [][2][]mc.AtomicProposition{
+		{{blue}, {red}},
+		{{}, {red, blue}},
+		{{red}, {blue}},
+		{{blue, blue}, {red, red}},
+	}
+
+	// Algorithm
+	parityAutomaton, err := dra0.ToParity()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, lasso := range lassos {
+		tf1, _ := dra0.AcceptsLasso(lasso[0], lasso[1])
+		tf2, _ := parityAutomaton.AcceptsLasso(lasso[0], lasso[1])
+		if tf1 != tf2 {
+			t.Errorf("The DRA and the parity automaton disagree on %v (%v)^omega.", lasso[0], lasso[1])
+		}
+	}
+}
+
+/*
+TestDeterministicRabin_IsEmpty1
+Description:
+	Verifies that the DRA for finitely many blue's is not empty and that the witness is accepted.
+*/
+func TestDeterministicRabin_IsEmpty1(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+
+	// Algorithm
+	isEmpty, witness, err := dra0.IsEmpty()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if isEmpty {
+		t.Errorf("Expected the language of the DRA to be nonempty.")
+	}
+
+	tf, _ := dra0.AcceptsLasso(witness.Prefix, witness.Cycle)
+	if !tf {
+		t.Errorf("The witness %v is not accepted by the DRA.", witness)
+	}
+}
+
+/*
+TestDeterministicRabin_IsEmpty2
+Description:
+	Verifies that a DRA whose only F state is never reached is empty.
+*/
+func TestDeterministicRabin_IsEmpty2(t *testing.T) {
+	// Constants
+	dra0, _ := GetDRA(
+		[]string{"q0", "q1"}, "q0", []string{"red"},
+		map[string]map[string]string{
+			"q0": {"red": "q0"},
+			"q1": {"red": "q1"},
+		},
+		[][2][]string{{{}, {"q1"}}},
+	)
+
+	// Algorithm
+	isEmpty, _, err := dra0.IsEmpty()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !isEmpty {
+		t.Errorf("Expected the language of the DRA to be empty.")
+	}
+}
+
+/*
+TestDeterministicRabin_Complement1
+Description:
+	Verifies that the complement of the DRA disagrees with it on a few lassos.
+*/
+func TestDeterministicRabin_Complement1(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+	red := mc.AtomicProposition{Name: "red"}
+	blue := mc.AtomicProposition{Name: "blue"}
+	lassos := [][2][]mc.AtomicProposition{
+		{{blue}, {red}},
+		{{}, {red, blue}},
+		{{red}, {blue}},
+	}
+
+	// Algorithm
+	complement, err := dra0.Complement()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, lasso := range lassos {
+		tf1, _ := dra0.AcceptsLasso(lasso[0], lasso[1])
+		tf2, _ := complement.AcceptsLasso(lasso[0], lasso[1])
+		if tf1 == tf2 {
+			t.Errorf("The DRA and its complement agree on %v (%v)^omega.", lasso[0], lasso[1])
+		}
+	}
+}
+
+/*
+TestDeterministicRabin_Reduce1
+Description:
+	Creates a DRA with a duplicated state and an unreachable state, and verifies that Reduce()
+	removes both without changing the language.
+*/
+func TestDeterministicRabin_Reduce1(t *testing.T) {
+	// Constants
+	dra0, _ := GetDRA(
+		[]string{"sawRed", "sawRed2", "sawBlue", "unreachable"}, "sawRed", []string{"red", "blue"},
+		map[string]map[string]string{
+			"sawRed":      {"red": "sawRed2", "blue": "sawBlue"},
+			"sawRed2":     {"red": "sawRed", "blue": "sawBlue"},
+			"sawBlue":     {"red": "sawRed", "blue": "sawBlue"},
+			"unreachable": {"red": "sawRed", "blue": "sawBlue"},
+		},
+		[][2][]string{{{"sawBlue"}, {"sawRed", "sawRed2"}}},
+	)
+
+	// Algorithm
+	reduced, err := dra0.Reduce()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(reduced.S) != 2 {
+		t.Errorf("Expected the reduced DRA to have 2 states, but found %v.", reduced.S)
+	}
+
+	tf, witness, err := Equivalent(dra0, reduced)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !tf {
+		t.Errorf("Expected the reduced DRA to be equivalent to the original, but they disagree on %v.", witness)
+	}
+}
+
+/*
+TestDeterministicRabin_Equivalent1
+Description:
+	Verifies that Equivalent() distinguishes the DRA for finitely many blue's from the DRA for finitely many red's.
+*/
+func TestDeterministicRabin_Equivalent1(t *testing.T) {
+	// Constants
+	dra0 := GetEventuallyAlwaysRedDRA()
+	dra1, _ := GetDRA(
+		[]string{"sawRed", "sawBlue"}, "sawRed", []string{"red", "blue"},
+		map[string]map[string]string{
+			"sawRed":  {"red": "sawRed", "blue": "sawBlue"},
+			"sawBlue": {"red": "sawRed", "blue": "sawBlue"},
+		},
+		[][2][]string{{{"sawRed"}, {"sawBlue"}}},
+	)
+
+	// Algorithm
+	tf, witness, err := Equivalent(dra0, dra1)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if tf {
+		t.Errorf("Expected the DRAs to be different.")
+	}
+
+	tf0, _ := dra0.AcceptsLasso(witness.Prefix, witness.Cycle)
+	tf1, _ := dra1.AcceptsLasso(witness.Prefix, witness.Cycle)
+	if tf0 == tf1 {
+		t.Errorf("The witness %v does not distinguish the DRAs.", witness)
+	}
+}
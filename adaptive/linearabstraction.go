@@ -0,0 +1,502 @@
+/*
+linearabstraction.go
+Description:
+	Abstracts a discrete-time affine system
+		x+ = A x + B u + C + w
+	into a finite TransitionSystem by gridding a bounded box of the state space into hyper-rectangles.
+	This is the kind of front end that is assumed by the workflow in
+	'Formal Methods for Adaptive Control of Dynamical Systems' by Sadra Sadraddini and Calin Belta.
+*/
+
+package adaptive
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Box
+Description:
+	An axis-aligned hyper-rectangle { x : Lower[i] <= x[i] <= Upper[i] }.
+*/
+type Box struct {
+	Lower []float64
+	Upper []float64
+}
+
+/*
+AffineSystem
+Description:
+	The discrete-time system x+ = A x + B u + C + w where the disturbance w lies in the box W.
+	C and W may be left empty, in which case they are treated as zero.
+*/
+type AffineSystem struct {
+	A [][]float64
+	B [][]float64
+	C []float64
+	W Box
+}
+
+/*
+RegionOfInterest
+Description:
+	A named box of the state space. Every cell of the abstraction which overlaps Region
+	is labelled with the atomic proposition Name.
+*/
+type RegionOfInterest struct {
+	Name   string
+	Region Box
+}
+
+/*
+Constants
+*/
+
+const (
+	OutOfDomainStateName = "out"
+)
+
+/*
+Functions for Box
+*/
+
+/*
+Dim
+Description:
+	Returns the dimension of the box.
+*/
+func (b Box) Dim() int {
+	return len(b.Lower)
+}
+
+/*
+Check
+Description:
+	Verifies that the bounds of the box have the same dimension and are ordered.
+*/
+func (b Box) Check() error {
+	if len(b.Lower) != len(b.Upper) {
+		return fmt.Errorf("The box has %v lower bounds but %v upper bounds.", len(b.Lower), len(b.Upper))
+	}
+
+	for dimIndex := range b.Lower {
+		if b.Lower[dimIndex] > b.Upper[dimIndex] {
+			return fmt.Errorf("The lower bound %v is greater than the upper bound %v in dimension %v.", b.Lower[dimIndex], b.Upper[dimIndex], dimIndex)
+		}
+	}
+
+	return nil
+}
+
+/*
+Intersects
+Description:
+	Determines if two boxes overlap with a nonempty interior.
+	If one of the boxes is degenerate in some dimension, touching is enough.
+*/
+func (b Box) Intersects(b2 Box) bool {
+	for dimIndex := range b.Lower {
+		lo := math.Max(b.Lower[dimIndex], b2.Lower[dimIndex])
+		hi := math.Min(b.Upper[dimIndex], b2.Upper[dimIndex])
+		if lo > hi {
+			return false
+		}
+
+		bothWide := (b.Lower[dimIndex] < b.Upper[dimIndex]) && (b2.Lower[dimIndex] < b2.Upper[dimIndex])
+		if bothWide && lo == hi {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Contains
+Description:
+	Determines if the box b2 is a subset of b.
+*/
+func (b Box) Contains(b2 Box) bool {
+	for dimIndex := range b.Lower {
+		if b2.Lower[dimIndex] < b.Lower[dimIndex] || b2.Upper[dimIndex] > b.Upper[dimIndex] {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Functions for AffineSystem
+*/
+
+/*
+Check
+Description:
+	Verifies that the matrices and vectors of the system have consistent dimensions.
+*/
+func (sys AffineSystem) Check() error {
+	n := len(sys.A)
+	if n == 0 {
+		return fmt.Errorf("The matrix A is empty.")
+	}
+
+	for rowIndex, row := range sys.A {
+		if len(row) != n {
+			return fmt.Errorf("The matrix A is not square; row %v has %v entries but A has %v rows.", rowIndex, len(row), n)
+		}
+	}
+
+	if len(sys.B) != 0 && len(sys.B) != n {
+		return fmt.Errorf("The matrix B has %v rows but A has %v rows.", len(sys.B), n)
+	}
+
+	for rowIndex, row := range sys.B {
+		if len(row) != len(sys.B[0]) {
+			return fmt.Errorf("Row %v of B has %v entries but row 0 has %v entries.", rowIndex, len(row), len(sys.B[0]))
+		}
+	}
+
+	if len(sys.C) != 0 && len(sys.C) != n {
+		return fmt.Errorf("The vector C has %v entries but A has %v rows.", len(sys.C), n)
+	}
+
+	if sys.W.Dim() != 0 || len(sys.W.Upper) != 0 {
+		if err := sys.W.Check(); err != nil {
+			return fmt.Errorf("There was an issue checking the disturbance set W: %v", err)
+		}
+		if sys.W.Dim() != n {
+			return fmt.Errorf("The disturbance set W has dimension %v but A has %v rows.", sys.W.Dim(), n)
+		}
+	}
+
+	return nil
+}
+
+/*
+InputDim
+Description:
+	Returns the number of inputs of the system (the number of columns of B).
+*/
+func (sys AffineSystem) InputDim() int {
+	if len(sys.B) == 0 {
+		return 0
+	}
+	return len(sys.B[0])
+}
+
+/*
+ReachableBox
+Description:
+	Computes an over-approximation of the set { A x + B u + C + w : x in X, w in W } using interval arithmetic.
+*/
+func (sys AffineSystem) ReachableBox(X Box, u []float64) Box {
+	n := len(sys.A)
+	reach := Box{Lower: make([]float64, n), Upper: make([]float64, n)}
+
+	for rowIndex := 0; rowIndex < n; rowIndex++ {
+		lo, hi := 0.0, 0.0
+
+		// A x
+		for colIndex, a := range sys.A[rowIndex] {
+			if a >= 0 {
+				lo += a * X.Lower[colIndex]
+				hi += a * X.Upper[colIndex]
+			} else {
+				lo += a * X.Upper[colIndex]
+				hi += a * X.Lower[colIndex]
+			}
+		}
+
+		// B u
+		if len(sys.B) > 0 {
+			for colIndex, b := range sys.B[rowIndex] {
+				lo += b * u[colIndex]
+				hi += b * u[colIndex]
+			}
+		}
+
+		// C
+		if len(sys.C) > 0 {
+			lo += sys.C[rowIndex]
+			hi += sys.C[rowIndex]
+		}
+
+		// w
+		if sys.W.Dim() > 0 {
+			lo += sys.W.Lower[rowIndex]
+			hi += sys.W.Upper[rowIndex]
+		}
+
+		reach.Lower[rowIndex] = lo
+		reach.Upper[rowIndex] = hi
+	}
+
+	return reach
+}
+
+/*
+AbstractAffineSystem
+Description:
+	Grids the box domain into divisions[i] equal intervals along dimension i and creates a
+	TransitionSystem whose states are the resulting cells. The inputs of the abstraction are the
+	names of the finite input set inputs. There is a transition from a cell under input u to every cell
+	that overlaps the over-approximated reachable set of that cell.
+	If the reachable set leaves the domain, the cell also transitions to the absorbing state
+	"out", which is labelled with the atomic proposition "out".
+	Cells are named "(i_1,...,i_n)" using their grid indices and are labelled with the names of
+	the regions of interest that they overlap. The regions must have distinct, nonempty names other than "out".
+Usage:
+	ts, err := AbstractAffineSystem(sys, domain, []int{10, 10}, map[string][]float64{"left": {-1}, "right": {1}}, regions)
+*/
+func AbstractAffineSystem(sys AffineSystem, domain Box, divisions []int, inputs map[string][]float64, regions []RegionOfInterest) (TransitionSystem, error) {
+	// Input Processing
+	err := sys.Check()
+	if err != nil {
+		return TransitionSystem{}, fmt.Errorf("There was an issue checking the affine system: %v", err)
+	}
+
+	err = domain.Check()
+	if err != nil {
+		return TransitionSystem{}, fmt.Errorf("There was an issue checking the domain: %v", err)
+	}
+
+	for dimIndex := range domain.Lower {
+		if domain.Lower[dimIndex] == domain.Upper[dimIndex] {
+			return TransitionSystem{}, fmt.Errorf("The domain has zero width in dimension %v, so it cannot be divided into cells.", dimIndex)
+		}
+	}
+
+	n := len(sys.A)
+	if domain.Dim() != n {
+		return TransitionSystem{}, fmt.Errorf("The domain has dimension %v but the system has dimension %v.", domain.Dim(), n)
+	}
+
+	if len(divisions) != n {
+		return TransitionSystem{}, fmt.Errorf("Expected %v divisions but received %v.", n, len(divisions))
+	}
+
+	for dimIndex, numDivisions := range divisions {
+		if numDivisions < 1 {
+			return TransitionSystem{}, fmt.Errorf("The number of divisions in dimension %v must be positive, but it was %v.", dimIndex, numDivisions)
+		}
+	}
+
+	if len(inputs) == 0 {
+		return TransitionSystem{}, fmt.Errorf("The input set is empty.")
+	}
+
+	var inputNames []string
+	for inputName, inputValue := range inputs {
+		if len(inputValue) != sys.InputDim() {
+			return TransitionSystem{}, fmt.Errorf("The input \"%v\" has dimension %v but B has %v columns.", inputName, len(inputValue), sys.InputDim())
+		}
+		inputNames = append(inputNames, inputName)
+	}
+	sort.Strings(inputNames)
+
+	regionNames := make(map[string]bool)
+	for _, region := range regions {
+		switch {
+		case region.Name == "":
+			return TransitionSystem{}, fmt.Errorf("Every region of interest needs a name, but one of them has an empty name.")
+		case region.Name == OutOfDomainStateName:
+			return TransitionSystem{}, fmt.Errorf("The region name \"%v\" is reserved for the states outside of the domain.", OutOfDomainStateName)
+		case regionNames[region.Name]:
+			return TransitionSystem{}, fmt.Errorf("There are two regions of interest named \"%v\".", region.Name)
+		}
+		regionNames[region.Name] = true
+
+		if err := region.Region.Check(); err != nil {
+			return TransitionSystem{}, fmt.Errorf("There was an issue checking the region \"%v\": %v", region.Name, err)
+		}
+		if region.Region.Dim() != n {
+			return TransitionSystem{}, fmt.Errorf("The region \"%v\" has dimension %v but the system has dimension %v.", region.Name, region.Region.Dim(), n)
+		}
+	}
+
+	// Create the cells
+	grid := abstractionGrid{Domain: domain, Divisions: divisions}
+	cellIndices := grid.AllIndices()
+
+	var stateNames []string
+	for _, indices := range cellIndices {
+		stateNames = append(stateNames, grid.CellName(indices))
+	}
+	stateNames = append(stateNames, OutOfDomainStateName)
+
+	// Create the transitions
+	transitionMap := make(map[string]map[string][]string)
+	for _, indices := range cellIndices {
+		cell := grid.Cell(indices)
+		tempActionMap := make(map[string][]string)
+		for _, inputName := range inputNames {
+			reach := sys.ReachableBox(cell, inputs[inputName])
+
+			var successors []string
+			for _, successorIndices := range grid.OverlappingIndices(reach) {
+				successors = append(successors, grid.CellName(successorIndices))
+			}
+			if !domain.Contains(reach) {
+				successors = append(successors, OutOfDomainStateName)
+			}
+			tempActionMap[inputName] = successors
+		}
+		transitionMap[grid.CellName(indices)] = tempActionMap
+	}
+
+	outActionMap := make(map[string][]string)
+	for _, inputName := range inputNames {
+		outActionMap[inputName] = []string{OutOfDomainStateName}
+	}
+	transitionMap[OutOfDomainStateName] = outActionMap
+
+	// Create the observations
+	var apNames []string
+	for _, region := range regions {
+		apNames = append(apNames, region.Name)
+	}
+	apNames = append(apNames, OutOfDomainStateName)
+
+	labelMap := make(map[string][]string)
+	for _, indices := range cellIndices {
+		cell := grid.Cell(indices)
+		labels := []string{}
+		for _, region := range regions {
+			if cell.Intersects(region.Region) {
+				labels = append(labels, region.Name)
+			}
+		}
+		labelMap[grid.CellName(indices)] = labels
+	}
+	labelMap[OutOfDomainStateName] = []string{OutOfDomainStateName}
+
+	return GetTransitionSystem(stateNames, inputNames, transitionMap, apNames, labelMap)
+}
+
+/*
+abstractionGrid
+Description:
+	A uniform grid of the box Domain with Divisions[i] cells along dimension i.
+*/
+type abstractionGrid struct {
+	Domain    Box
+	Divisions []int
+}
+
+/*
+Width
+Description:
+	Returns the width of each cell along dimension dimIndex.
+*/
+func (grid abstractionGrid) Width(dimIndex int) float64 {
+	return (grid.Domain.Upper[dimIndex] - grid.Domain.Lower[dimIndex]) / float64(grid.Divisions[dimIndex])
+}
+
+/*
+Cell
+Description:
+	Returns the box of the cell with the given grid indices.
+*/
+func (grid abstractionGrid) Cell(indices []int) Box {
+	cell := Box{Lower: make([]float64, len(indices)), Upper: make([]float64, len(indices))}
+	for dimIndex, index := range indices {
+		cell.Lower[dimIndex] = grid.Domain.Lower[dimIndex] + float64(index)*grid.Width(dimIndex)
+		cell.Upper[dimIndex] = grid.Domain.Lower[dimIndex] + float64(index+1)*grid.Width(dimIndex)
+	}
+	return cell
+}
+
+/*
+CellName
+Description:
+	Returns the name of the cell with the given grid indices, i.e. "(i_1,...,i_n)".
+*/
+func (grid abstractionGrid) CellName(indices []int) string {
+	var indexStrings []string
+	for _, index := range indices {
+		indexStrings = append(indexStrings, fmt.Sprintf("%v", index))
+	}
+	return fmt.Sprintf("(%v)", strings.Join(indexStrings, ","))
+}
+
+/*
+AllIndices
+Description:
+	Lists the grid indices of every cell, with the last dimension changing fastest.
+*/
+func (grid abstractionGrid) AllIndices() [][]int {
+	lower := make([]int, len(grid.Divisions))
+	upper := make([]int, len(grid.Divisions))
+	for dimIndex, numDivisions := range grid.Divisions {
+		upper[dimIndex] = numDivisions - 1
+	}
+	return indexRange(lower, upper)
+}
+
+/*
+OverlappingIndices
+Description:
+	Lists the grid indices of every cell which intersects the box b. The cells are closed, so a box which
+	touches the face shared by two cells overlaps both of them; otherwise a reachable point on that face
+	would only be mapped to one of the cells and the abstraction would miss a transition.
+*/
+func (grid abstractionGrid) OverlappingIndices(b Box) [][]int {
+	lower := make([]int, len(grid.Divisions))
+	upper := make([]int, len(grid.Divisions))
+	for dimIndex, numDivisions := range grid.Divisions {
+		width := grid.Width(dimIndex)
+		lo := (b.Lower[dimIndex] - grid.Domain.Lower[dimIndex]) / width
+		hi := (b.Upper[dimIndex] - grid.Domain.Lower[dimIndex]) / width
+
+		if hi < 0 || lo > float64(numDivisions) {
+			return [][]int{}
+		}
+
+		// The cell i is [i,i+1] in grid coordinates, so an integer lo also touches the cell lo-1
+		lower[dimIndex] = int(math.Ceil(lo)) - 1
+		upper[dimIndex] = int(math.Floor(hi))
+
+		if lower[dimIndex] < 0 {
+			lower[dimIndex] = 0
+		}
+		if upper[dimIndex] > numDivisions-1 {
+			upper[dimIndex] = numDivisions - 1
+		}
+	}
+
+	return indexRange(lower, upper)
+}
+
+/*
+indexRange
+Description:
+	Lists every integer vector v with lower <= v <= upper, with the last dimension changing fastest.
+*/
+func indexRange(lower, upper []int) [][]int {
+	var out [][]int
+	current := append([]int{}, lower...)
+	for {
+		out = append(out, append([]int{}, current...))
+
+		// Increment the counter
+		dimIndex := len(current) - 1
+		for ; dimIndex >= 0; dimIndex-- {
+			if current[dimIndex] < upper[dimIndex] {
+				current[dimIndex]++
+				break
+			}
+			current[dimIndex] = lower[dimIndex]
+		}
+		if dimIndex < 0 {
+			return out
+		}
+	}
+}
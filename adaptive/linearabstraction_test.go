@@ -0,0 +1,234 @@
+/*
+linearabstraction_test.go
+Description:
+	Tests the functions and objects created in linearabstraction.go
+*/
+
+package adaptive
+
+import (
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+GetScalarIntegrator
+Description:
+	Creates the scalar system x+ = x + u + w with w in [-0.1,0.1].
+*/
+func GetScalarIntegrator() AffineSystem {
+	return AffineSystem{
+		A: [][]float64{{1}},
+		B: [][]float64{{1}},
+		W: Box{Lower: []float64{-0.1}, Upper: []float64{0.1}},
+	}
+}
+
+/*
+TestLinearAbstraction_ReachableBox1
+Description:
+	Verifies the interval arithmetic of ReachableBox() with a negative entry in A.
+*/
+func TestLinearAbstraction_ReachableBox1(t *testing.T) {
+	// Constants
+	sys := AffineSystem{
+		A: [][]float64{{1, -1}, {0, 2}},
+		B: [][]float64{{1}, {0}},
+		C: []float64{0, 1},
+	}
+	X := Box{Lower: []float64{0, 0}, Upper: []float64{1, 2}}
+
+	// Algorithm
+	reach := sys.ReachableBox(X, []float64{0.5})
+
+	expectedLower := []float64{-1.5, 1}
+	expectedUpper := []float64{1.5, 5}
+	for dimIndex := range expectedLower {
+		if reach.Lower[dimIndex] != expectedLower[dimIndex] || reach.Upper[dimIndex] != expectedUpper[dimIndex] {
+			t.Errorf("Expected reachable box %v-%v but received %v-%v.", expectedLower, expectedUpper, reach.Lower, reach.Upper)
+		}
+	}
+}
+
+/*
+TestLinearAbstraction_Check1
+Description:
+	Verifies that Check() catches a non-square A matrix.
+*/
+func TestLinearAbstraction_Check1(t *testing.T) {
+	// Constants
+	sys := AffineSystem{
+		A: [][]float64{{1, 0}},
+	}
+
+	// Algorithm
+	if sys.Check() == nil {
+		t.Errorf("Expected Check() to identify that A is not square.")
+	}
+}
+
+/*
+TestLinearAbstraction_AbstractAffineSystem1
+Description:
+	Abstracts the scalar integrator on [0,4] with four cells and verifies the transitions and labels.
+*/
+func TestLinearAbstraction_AbstractAffineSystem1(t *testing.T) {
+	// Constants
+	domain := Box{Lower: []float64{0}, Upper: []float64{4}}
+	regions := []RegionOfInterest{
+		{Name: "goal", Region: Box{Lower: []float64{3}, Upper: []float64{4}}},
+	}
+
+	// Algorithm
+	ts, err := AbstractAffineSystem(
+		GetScalarIntegrator(), domain, []int{4},
+		map[string][]float64{"stay": {0}, "right": {1}},
+		regions,
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(ts.X) != 5 {
+		t.Errorf("Expected 4 cells and the out state, but found %v states.", len(ts.X))
+	}
+
+	// Cell (1) = [1,2] under "right" reaches [1.9,3.1] which overlaps (1), (2) and (3).
+	post, _ := Post(ts.X[1], "right")
+	if len(post) != 3 {
+		t.Errorf("Expected 3 successors of (1) under \"right\", but found %v.", post)
+	}
+
+	// Cell (3) = [3,4] under "right" leaves the domain.
+	post, _ = Post(ts.X[3], "right")
+	if !(TransitionSystemState{Name: OutOfDomainStateName}).In(post) {
+		t.Errorf("Expected (3) to transition to the out state, but Post = %v.", post)
+	}
+
+	// Only cell (3) is labelled with goal.
+	for stateIndex, state := range ts.X[:4] {
+		tf, _ := state.Satisfies(mc.AtomicProposition{Name: "goal"})
+		if tf != (stateIndex == 3) {
+			t.Errorf("Unexpected label %v for state %v.", ts.O[state], state)
+		}
+	}
+
+	if !ts.IsNonBlocking() {
+		t.Errorf("Expected the abstraction to be non-blocking, but found %v.", ts.BlockingPairs())
+	}
+}
+
+/*
+TestLinearAbstraction_AbstractAffineSystem2
+Description:
+	Verifies that AbstractAffineSystem() rejects inputs of the wrong dimension.
+*/
+func TestLinearAbstraction_AbstractAffineSystem2(t *testing.T) {
+	// Constants
+	domain := Box{Lower: []float64{0}, Upper: []float64{4}}
+
+	// Algorithm
+	_, err := AbstractAffineSystem(
+		GetScalarIntegrator(), domain, []int{4},
+		map[string][]float64{"bad": {0, 1}},
+		[]RegionOfInterest{},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for an input with the wrong dimension.")
+	}
+}
+
+/*
+TestLinearAbstraction_OverlappingIndices1
+Description:
+	Verifies that a box on the face shared by two closed cells overlaps both of them, whether it is a single
+	point or a wider box which ends on the face.
+*/
+func TestLinearAbstraction_OverlappingIndices1(t *testing.T) {
+	// Constants
+	grid := abstractionGrid{Domain: Box{Lower: []float64{0}, Upper: []float64{4}}, Divisions: []int{4}}
+	testCases := []struct {
+		Box      Box
+		Expected []int
+	}{
+		{Box{Lower: []float64{2}, Upper: []float64{2}}, []int{1, 2}},
+		{Box{Lower: []float64{2.5}, Upper: []float64{2.5}}, []int{2}},
+		{Box{Lower: []float64{0.5}, Upper: []float64{1}}, []int{0, 1}},
+		{Box{Lower: []float64{1}, Upper: []float64{1.5}}, []int{0, 1}},
+		{Box{Lower: []float64{4}, Upper: []float64{5}}, []int{3}},
+		{Box{Lower: []float64{-1}, Upper: []float64{0}}, []int{0}},
+		{Box{Lower: []float64{4.5}, Upper: []float64{5}}, []int{}},
+	}
+
+	// Algorithm
+	for _, testCase := range testCases {
+		var indices []int
+		for _, cell := range grid.OverlappingIndices(testCase.Box) {
+			indices = append(indices, cell[0])
+		}
+
+		if len(indices) != len(testCase.Expected) {
+			t.Errorf("Expected the box %v-%v to overlap the cells %v, but it overlaps %v.", testCase.Box.Lower, testCase.Box.Upper, testCase.Expected, indices)
+			continue
+		}
+		for i := range indices {
+			if indices[i] != testCase.Expected[i] {
+				t.Errorf("Expected the box %v-%v to overlap the cells %v, but it overlaps %v.", testCase.Box.Lower, testCase.Box.Upper, testCase.Expected, indices)
+			}
+		}
+	}
+}
+
+/*
+TestLinearAbstraction_AbstractAffineSystem3
+Description:
+	Verifies that a deterministic system whose reachable point lies on a cell boundary transitions to both
+	neighbouring cells, and that a domain of zero width is rejected.
+*/
+func TestLinearAbstraction_AbstractAffineSystem3(t *testing.T) {
+	// Constants
+	sys := AffineSystem{A: [][]float64{{0}}, B: [][]float64{{1}}}
+	domain := Box{Lower: []float64{0}, Upper: []float64{4}}
+
+	// Algorithm
+	ts, err := AbstractAffineSystem(sys, domain, []int{4}, map[string][]float64{"to2": {2}}, []RegionOfInterest{})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	post, _ := Post(ts.X[0], "to2")
+	if len(post) != 2 || !(TransitionSystemState{Name: "(1)"}).In(post) || !(TransitionSystemState{Name: "(2)"}).In(post) {
+		t.Errorf("Expected the successors (1) and (2) of the point 2, but found %v.", post)
+	}
+
+	_, err = AbstractAffineSystem(sys, Box{Lower: []float64{1}, Upper: []float64{1}}, []int{4}, map[string][]float64{"to2": {2}}, []RegionOfInterest{})
+	if err == nil {
+		t.Errorf("Expected an error for a domain of zero width.")
+	}
+}
+
+/*
+TestLinearAbstraction_AbstractAffineSystem4
+Description:
+	Verifies that regions of interest with an empty name, a repeated name or the name of the
+	out-of-domain state are rejected.
+*/
+func TestLinearAbstraction_AbstractAffineSystem4(t *testing.T) {
+	// Constants
+	domain := Box{Lower: []float64{0}, Upper: []float64{4}}
+	region := Box{Lower: []float64{0}, Upper: []float64{1}}
+	testCases := [][]RegionOfInterest{
+		{{Name: "", Region: region}},
+		{{Name: OutOfDomainStateName, Region: region}},
+		{{Name: "goal", Region: region}, {Name: "goal", Region: domain}},
+	}
+
+	// Algorithm
+	for _, regions := range testCases {
+		_, err := AbstractAffineSystem(GetScalarIntegrator(), domain, []int{4}, map[string][]float64{"stay": {0}}, regions)
+		if err == nil {
+			t.Errorf("Expected an error for the regions %v.", regions)
+		}
+	}
+}
@@ -555,3 +555,23 @@ func (ts TransitionSystem) HasObservationPreservingStateSpacePartition(Q [][]Tra
 
 	return true
 }
+
+/*
+ToModelCheckingTransitionSystem
+Description:
+	Converts the transition system into a modelchecking.TransitionSystem with the given initial states,
+	so that the tools written for that type (e.g. simulation and the sequences package) can be used.
+	The inputs U become the actions and the observations O become the labels.
+Usage:
+	ts2, err := ts.ToModelCheckingTransitionSystem([]string{"x0"})
+*/
+func (ts TransitionSystem) ToModelCheckingTransitionSystem(initialStateNames []string) (mc.TransitionSystem, error) {
+	stateNames, inputNames, transitionMap, apNames, labelMap := ts.toNames()
+
+	tsOut, err := mc.GetTransitionSystem(stateNames, inputNames, transitionMap, initialStateNames, apNames, labelMap)
+	if err != nil {
+		return mc.TransitionSystem{}, err
+	}
+
+	return tsOut, nil
+}
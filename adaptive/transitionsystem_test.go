@@ -9,6 +9,8 @@ package adaptive
 import (
 	"fmt"
 	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
 )
 
 func TestTransitionSystem_GetState1(t *testing.T) {
@@ -647,3 +649,50 @@ func TestTransitionSystem_HasObservationPreservingStateSpacePartition1(t *testin
 		t.Errorf("The function HasObservationPreservingStateSpacePartition() does not properly identify that Q does not preserve observations!")
 	}
 }
+
+/*
+TestTransitionSystem_ToModelCheckingTransitionSystem1
+Description:
+	Converts the beverage vending machine and checks its transitions and labels.
+*/
+func TestTransitionSystem_ToModelCheckingTransitionSystem1(t *testing.T) {
+	// Constants
+	ts0 := GetBeverageVendingMachineTS()
+
+	// Algorithm
+	ts1, err := ts0.ToModelCheckingTransitionSystem([]string{"pay"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(ts1.S) != len(ts0.X) || len(ts1.Act) != len(ts0.U) {
+		t.Errorf("Expected %v states and %v actions, but found %v and %v.", len(ts0.X), len(ts0.U), len(ts1.S), len(ts1.Act))
+	}
+
+	if len(ts1.I) != 1 || ts1.I[0].Name != "pay" {
+		t.Errorf("Expected the initial state to be pay, but found %v.", ts1.I)
+	}
+
+	successors, _ := mc.Post(ts1.S[1], "")
+	if len(successors) != 2 {
+		t.Errorf("Expected select to have 2 successors, but found %v.", len(successors))
+	}
+
+	if !(mc.AtomicProposition{Name: "drink"}).In(ts1.L[ts1.S[2]]) {
+		t.Errorf("Expected beer to be labelled with drink, but found %v.", ts1.L[ts1.S[2]])
+	}
+}
+
+/*
+TestTransitionSystem_ToModelCheckingTransitionSystem2
+Description:
+	An initial state which is not in the state set produces an error.
+*/
+func TestTransitionSystem_ToModelCheckingTransitionSystem2(t *testing.T) {
+	ts0 := GetBeverageVendingMachineTS()
+
+	_, err := ts0.ToModelCheckingTransitionSystem([]string{"wine"})
+	if err == nil {
+		t.Errorf("Expected an error for the unknown initial state.")
+	}
+}
@@ -0,0 +1,778 @@
+/*
+atl.go
+Description:
+ 	Formulas of Alternating-Time Temporal Logic (ATL) and a parser for them.
+	A coalition A of agents is written <<A1,A2>> (or with the unicode brackets ⟨⟨A1,A2⟩⟩), so that
+		<<A>>X p, <<A>>G p, <<A>>F p and <<A>>(p U q)
+	are the temporal formulas. The boolean connectives are !, &, | and -> (or ¬, ∧, ∨ and →).
+	The epistemic operators are written K[a] p (agent a knows p), E[a,b] p (everyone in the group knows p)
+	and C[a,b] p (p is common knowledge in the group).
+	Names which contain spaces or operator characters can be written in double quotes.
+*/
+package modelchecking
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+/*
+Type Definitions
+*/
+
+type ATLOperator int
+
+const (
+	ATLOpTrue ATLOperator = iota
+	ATLOpFalse
+	ATLOpAtom
+	ATLOpNot
+	ATLOpAnd
+	ATLOpOr
+	ATLOpImplies
+	ATLOpNext
+	ATLOpAlways
+	ATLOpEventually
+	ATLOpUntil
+	ATLOpKnows
+	ATLOpEveryoneKnows
+	ATLOpCommonKnowledge
+)
+
+/*
+ATLFormula
+Description:
+	A node of the syntax tree of an ATL formula.
+	Atom is only used by ATLOpAtom and Coalition (the names of the agents) is only used by the temporal
+	and epistemic operators.
+*/
+type ATLFormula struct {
+	Operator  ATLOperator
+	Atom      AtomicProposition
+	Coalition []string
+	Operands  []ATLFormula
+}
+
+/*
+Constructors
+*/
+
+/*
+ATLTrue
+Description:
+	The formula which holds in every state.
+*/
+func ATLTrue() ATLFormula {
+	return ATLFormula{Operator: ATLOpTrue}
+}
+
+/*
+ATLFalse
+Description:
+	The formula which holds in no state.
+*/
+func ATLFalse() ATLFormula {
+	return ATLFormula{Operator: ATLOpFalse}
+}
+
+/*
+ATLAtom
+Description:
+	The formula which holds in the states labelled with the atomic proposition apName.
+*/
+func ATLAtom(apName string) ATLFormula {
+	return ATLFormula{Operator: ATLOpAtom, Atom: AtomicProposition{Name: apName}}
+}
+
+/*
+ATLNot
+Description:
+	The negation of the formula.
+*/
+func ATLNot(formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpNot, Operands: []ATLFormula{formula}}
+}
+
+/*
+ATLAnd
+Description:
+	The conjunction of the formulas. The conjunction of no formulas is true.
+*/
+func ATLAnd(formulas ...ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpAnd, Operands: formulas}
+}
+
+/*
+ATLOr
+Description:
+	The disjunction of the formulas. The disjunction of no formulas is false.
+*/
+func ATLOr(formulas ...ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpOr, Operands: formulas}
+}
+
+/*
+ATLImplies
+Description:
+	The formula premise -> conclusion.
+*/
+func ATLImplies(premise ATLFormula, conclusion ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpImplies, Operands: []ATLFormula{premise, conclusion}}
+}
+
+/*
+ATLNext
+Description:
+	The formula <<coalition>>X formula.
+*/
+func ATLNext(coalition []string, formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpNext, Coalition: coalition, Operands: []ATLFormula{formula}}
+}
+
+/*
+ATLAlways
+Description:
+	The formula <<coalition>>G formula.
+*/
+func ATLAlways(coalition []string, formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpAlways, Coalition: coalition, Operands: []ATLFormula{formula}}
+}
+
+/*
+ATLEventually
+Description:
+	The formula <<coalition>>F formula, which is the same as <<coalition>>(true U formula).
+*/
+func ATLEventually(coalition []string, formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpEventually, Coalition: coalition, Operands: []ATLFormula{formula}}
+}
+
+/*
+ATLUntil
+Description:
+	The formula <<coalition>>(formula1 U formula2).
+*/
+func ATLUntil(coalition []string, formula1 ATLFormula, formula2 ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpUntil, Coalition: coalition, Operands: []ATLFormula{formula1, formula2}}
+}
+
+/*
+ATLKnows
+Description:
+	The formula K[agent] formula, which holds when formula holds in every state that agent cannot distinguish from the current one.
+*/
+func ATLKnows(agent string, formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpKnows, Coalition: []string{agent}, Operands: []ATLFormula{formula}}
+}
+
+/*
+ATLEveryoneKnows
+Description:
+	The formula E[group] formula, which holds when every agent of the group knows formula.
+*/
+func ATLEveryoneKnows(group []string, formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpEveryoneKnows, Coalition: group, Operands: []ATLFormula{formula}}
+}
+
+/*
+ATLCommonKnowledge
+Description:
+	The formula C[group] formula, which holds when formula is common knowledge in the group.
+*/
+func ATLCommonKnowledge(group []string, formula ATLFormula) ATLFormula {
+	return ATLFormula{Operator: ATLOpCommonKnowledge, Coalition: group, Operands: []ATLFormula{formula}}
+}
+
+/*
+Functions for ATLFormula
+*/
+
+/*
+IsTemporal
+Description:
+	Returns true if the top-level operator of the formula is X, G, F or U.
+*/
+func (formula ATLFormula) IsTemporal() bool {
+	switch formula.Operator {
+	case ATLOpNext, ATLOpAlways, ATLOpEventually, ATLOpUntil:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+IsEpistemic
+Description:
+	Returns true if the top-level operator of the formula is K, E or C.
+*/
+func (formula ATLFormula) IsEpistemic() bool {
+	switch formula.Operator {
+	case ATLOpKnows, ATLOpEveryoneKnows, ATLOpCommonKnowledge:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+String
+Description:
+	Prints the formula in the syntax accepted by ParseATLFormula().
+*/
+func (formula ATLFormula) String() string {
+	switch formula.Operator {
+	case ATLOpTrue:
+		return "true"
+	case ATLOpFalse:
+		return "false"
+	case ATLOpAtom:
+		return atlNameString(formula.Atom.Name)
+	case ATLOpNot:
+		return fmt.Sprintf("!%v", formula.Operands[0])
+	case ATLOpAnd, ATLOpOr:
+		if len(formula.Operands) == 0 {
+			if formula.Operator == ATLOpAnd {
+				return "true"
+			}
+			return "false"
+		}
+		connective := " & "
+		if formula.Operator == ATLOpOr {
+			connective = " | "
+		}
+		var operandStrings []string
+		for _, operand := range formula.Operands {
+			operandStrings = append(operandStrings, operand.String())
+		}
+		return "(" + strings.Join(operandStrings, connective) + ")"
+	case ATLOpImplies:
+		return fmt.Sprintf("(%v -> %v)", formula.Operands[0], formula.Operands[1])
+	case ATLOpNext:
+		return fmt.Sprintf("%vX %v", atlCoalitionString(formula.Coalition), formula.Operands[0])
+	case ATLOpAlways:
+		return fmt.Sprintf("%vG %v", atlCoalitionString(formula.Coalition), formula.Operands[0])
+	case ATLOpEventually:
+		return fmt.Sprintf("%vF %v", atlCoalitionString(formula.Coalition), formula.Operands[0])
+	case ATLOpUntil:
+		return fmt.Sprintf("%v(%v U %v)", atlCoalitionString(formula.Coalition), formula.Operands[0], formula.Operands[1])
+	case ATLOpKnows:
+		return fmt.Sprintf("K%v %v", atlGroupString(formula.Coalition), formula.Operands[0])
+	case ATLOpEveryoneKnows:
+		return fmt.Sprintf("E%v %v", atlGroupString(formula.Coalition), formula.Operands[0])
+	case ATLOpCommonKnowledge:
+		return fmt.Sprintf("C%v %v", atlGroupString(formula.Coalition), formula.Operands[0])
+	default:
+		return "?"
+	}
+}
+
+/*
+atlCoalitionString
+Description:
+	Prints the coalition as <<A1,A2>>.
+*/
+func atlCoalitionString(coalition []string) string {
+	var names []string
+	for _, name := range coalition {
+		names = append(names, atlNameString(name))
+	}
+	return "<<" + strings.Join(names, ",") + ">>"
+}
+
+/*
+atlGroupString
+Description:
+	Prints the agents of an epistemic operator as [a1,a2].
+*/
+func atlGroupString(group []string) string {
+	var names []string
+	for _, name := range group {
+		names = append(names, atlNameString(name))
+	}
+	return "[" + strings.Join(names, ",") + "]"
+}
+
+/*
+atlNameString
+Description:
+	Prints the name of an agent or atomic proposition, quoting it if it could not be parsed as an identifier.
+*/
+func atlNameString(name string) string {
+	if name == "" || atlIsKeyword(name) {
+		return fmt.Sprintf("%q", name)
+	}
+	for _, r := range name {
+		if !atlIsIdentifierRune(r) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+/*
+Parsing
+*/
+
+type atlTokenKind int
+
+const (
+	atlTokenEnd atlTokenKind = iota
+	atlTokenName
+	atlTokenLeftParen
+	atlTokenRightParen
+	atlTokenLeftBracket
+	atlTokenRightBracket
+	atlTokenCoalitionOpen
+	atlTokenCoalitionClose
+	atlTokenComma
+	atlTokenNot
+	atlTokenAnd
+	atlTokenOr
+	atlTokenImplies
+)
+
+type atlToken struct {
+	Kind     atlTokenKind
+	Text     string
+	Quoted   bool
+	Position int
+}
+
+var atlTwoCharacterSymbols = map[string]atlTokenKind{
+	"<<": atlTokenCoalitionOpen,
+	">>": atlTokenCoalitionClose,
+	"⟨⟨": atlTokenCoalitionOpen,
+	"⟩⟩": atlTokenCoalitionClose,
+	"->": atlTokenImplies,
+	"&&": atlTokenAnd,
+	"||": atlTokenOr,
+}
+
+var atlOneCharacterSymbols = map[rune]atlTokenKind{
+	'(': atlTokenLeftParen,
+	')': atlTokenRightParen,
+	'[': atlTokenLeftBracket,
+	']': atlTokenRightBracket,
+	',': atlTokenComma,
+	'!': atlTokenNot,
+	'¬': atlTokenNot,
+	'&': atlTokenAnd,
+	'∧': atlTokenAnd,
+	'|': atlTokenOr,
+	'∨': atlTokenOr,
+	'→': atlTokenImplies,
+}
+
+type atlParser struct {
+	Tokens []atlToken
+	Index  int
+}
+
+/*
+ParseATLFormula
+Description:
+	Parses an ATL formula such as "<<Agent1>>(!crash U <<Agent1,Agent2>>X goal)".
+	Implication is right-associative and binds weaker than |, which binds weaker than &.
+Usage:
+	formula, err := ParseATLFormula("<<Agent1>>G Sober")
+*/
+func ParseATLFormula(formulaString string) (ATLFormula, error) {
+	tokens, err := atlTokenize(formulaString)
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	parser := atlParser{Tokens: tokens}
+	formula, err := parser.parseImplication()
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	if next := parser.peek(); next.Kind != atlTokenEnd {
+		return ATLFormula{}, fmt.Errorf("Unexpected \"%v\" at position %v.", next.Text, next.Position)
+	}
+
+	return formula, nil
+}
+
+/*
+atlTokenize
+Description:
+	Splits the formula string into tokens. Positions are counted in characters, starting from 1.
+*/
+func atlTokenize(formulaString string) ([]atlToken, error) {
+	runes := []rune(formulaString)
+	var tokens []atlToken
+
+	for index := 0; index < len(runes); {
+		r := runes[index]
+		position := index + 1
+
+		// Two-character symbols
+		if index+1 < len(runes) {
+			pair := string(runes[index : index+2])
+			kind, isSymbol := atlTwoCharacterSymbols[pair]
+			if isSymbol {
+				tokens = append(tokens, atlToken{Kind: kind, Text: pair, Position: position})
+				index += 2
+				continue
+			}
+		}
+
+		// One-character symbols
+		kind, isSymbol := atlOneCharacterSymbols[r]
+		if isSymbol {
+			tokens = append(tokens, atlToken{Kind: kind, Text: string(r), Position: position})
+			index++
+			continue
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			index++
+		case r == '"':
+			// Quoted name
+			end := index + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("The quoted name starting at position %v is never closed.", position)
+			}
+			tokens = append(tokens, atlToken{Kind: atlTokenName, Text: string(runes[index+1 : end]), Quoted: true, Position: position})
+			index = end + 1
+		case atlIsIdentifierRune(r):
+			end := index
+			for end < len(runes) && atlIsIdentifierRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, atlToken{Kind: atlTokenName, Text: string(runes[index:end]), Position: position})
+			index = end
+		default:
+			return nil, fmt.Errorf("Unexpected character '%v' at position %v.", string(r), position)
+		}
+	}
+
+	return append(tokens, atlToken{Kind: atlTokenEnd, Text: "end of formula", Position: len(runes) + 1}), nil
+}
+
+/*
+atlIsIdentifierRune
+Description:
+	Returns true if the rune can be part of an unquoted name.
+*/
+func atlIsIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+/*
+atlIsKeyword
+Description:
+	Returns true if the unquoted name has a special meaning in the parser.
+*/
+func atlIsKeyword(name string) bool {
+	switch name {
+	case "true", "false", "X", "G", "F", "U", "K", "E", "C":
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+peek
+Description:
+	Returns the next token without consuming it.
+*/
+func (parser *atlParser) peek() atlToken {
+	return parser.Tokens[parser.Index]
+}
+
+/*
+next
+Description:
+	Consumes and returns the next token.
+*/
+func (parser *atlParser) next() atlToken {
+	token := parser.Tokens[parser.Index]
+	if token.Kind != atlTokenEnd {
+		parser.Index++
+	}
+	return token
+}
+
+/*
+expect
+Description:
+	Consumes the next token and returns an error if it is not of the given kind.
+*/
+func (parser *atlParser) expect(kind atlTokenKind, description string) (atlToken, error) {
+	token := parser.next()
+	if token.Kind != kind {
+		return token, fmt.Errorf("Expected %v at position %v, but found \"%v\".", description, token.Position, token.Text)
+	}
+	return token, nil
+}
+
+/*
+parseImplication
+Description:
+	implication := disjunction [ "->" implication ]
+*/
+func (parser *atlParser) parseImplication() (ATLFormula, error) {
+	premise, err := parser.parseDisjunction()
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	if parser.peek().Kind != atlTokenImplies {
+		return premise, nil
+	}
+	parser.next()
+
+	conclusion, err := parser.parseImplication()
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	return ATLImplies(premise, conclusion), nil
+}
+
+/*
+parseDisjunction
+Description:
+	disjunction := conjunction { "|" conjunction }
+*/
+func (parser *atlParser) parseDisjunction() (ATLFormula, error) {
+	operand, err := parser.parseConjunction()
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	operands := []ATLFormula{operand}
+	for parser.peek().Kind == atlTokenOr {
+		parser.next()
+		operand, err = parser.parseConjunction()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return ATLOr(operands...), nil
+}
+
+/*
+parseConjunction
+Description:
+	conjunction := unary { "&" unary }
+*/
+func (parser *atlParser) parseConjunction() (ATLFormula, error) {
+	operand, err := parser.parseUnary()
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	operands := []ATLFormula{operand}
+	for parser.peek().Kind == atlTokenAnd {
+		parser.next()
+		operand, err = parser.parseUnary()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return ATLAnd(operands...), nil
+}
+
+/*
+parseUnary
+Description:
+	unary := "!" unary | coalition temporal | ("K" | "E" | "C") "[" names "]" unary
+		| "true" | "false" | name | "(" implication ")"
+*/
+func (parser *atlParser) parseUnary() (ATLFormula, error) {
+	token := parser.next()
+	switch token.Kind {
+	case atlTokenNot:
+		operand, err := parser.parseUnary()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+		return ATLNot(operand), nil
+
+	case atlTokenCoalitionOpen:
+		coalition, err := parser.parseCoalition()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+		return parser.parseTemporal(coalition)
+
+	case atlTokenLeftParen:
+		formula, err := parser.parseImplication()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+		if _, err = parser.expect(atlTokenRightParen, "\")\""); err != nil {
+			return ATLFormula{}, err
+		}
+		return formula, nil
+
+	case atlTokenName:
+		if !token.Quoted {
+			switch token.Text {
+			case "true":
+				return ATLTrue(), nil
+			case "false":
+				return ATLFalse(), nil
+			case "K", "E", "C":
+				if parser.peek().Kind == atlTokenLeftBracket {
+					return parser.parseEpistemic(token)
+				}
+			case "X", "G", "F", "U":
+				return ATLFormula{}, fmt.Errorf("The temporal operator \"%v\" at position %v must follow a coalition such as <<A>>.", token.Text, token.Position)
+			}
+		}
+		return ATLAtom(token.Text), nil
+
+	default:
+		return ATLFormula{}, fmt.Errorf("Expected a formula at position %v, but found \"%v\".", token.Position, token.Text)
+	}
+}
+
+/*
+parseCoalition
+Description:
+	Parses the agent names of a coalition after its opening brackets, up to and including the closing brackets.
+*/
+func (parser *atlParser) parseCoalition() ([]string, error) {
+	coalition := []string{}
+	if parser.peek().Kind == atlTokenCoalitionClose {
+		parser.next()
+		return coalition, nil
+	}
+
+	for {
+		token, err := parser.expect(atlTokenName, "an agent name")
+		if err != nil {
+			return nil, err
+		}
+		coalition = append(coalition, token.Text)
+
+		token = parser.next()
+		switch token.Kind {
+		case atlTokenComma:
+			continue
+		case atlTokenCoalitionClose:
+			return coalition, nil
+		default:
+			return nil, fmt.Errorf("Expected \",\" or \">>\" at position %v, but found \"%v\".", token.Position, token.Text)
+		}
+	}
+}
+
+/*
+parseEpistemic
+Description:
+	Parses the agents and the operand of an epistemic operator, after the operator itself.
+*/
+func (parser *atlParser) parseEpistemic(operatorToken atlToken) (ATLFormula, error) {
+	parser.next()
+
+	var group []string
+	for {
+		token, err := parser.expect(atlTokenName, "an agent name")
+		if err != nil {
+			return ATLFormula{}, err
+		}
+		group = append(group, token.Text)
+
+		token = parser.next()
+		if token.Kind == atlTokenRightBracket {
+			break
+		}
+		if token.Kind != atlTokenComma {
+			return ATLFormula{}, fmt.Errorf("Expected \",\" or \"]\" at position %v, but found \"%v\".", token.Position, token.Text)
+		}
+	}
+
+	operand, err := parser.parseUnary()
+	if err != nil {
+		return ATLFormula{}, err
+	}
+
+	switch operatorToken.Text {
+	case "K":
+		if len(group) != 1 {
+			return ATLFormula{}, fmt.Errorf("The operator K at position %v takes exactly one agent, but received %v.", operatorToken.Position, group)
+		}
+		return ATLKnows(group[0], operand), nil
+	case "E":
+		return ATLEveryoneKnows(group, operand), nil
+	default:
+		return ATLCommonKnowledge(group, operand), nil
+	}
+}
+
+/*
+parseTemporal
+Description:
+	temporal := "X" unary | "G" unary | "F" unary | "(" implication "U" implication ")"
+*/
+func (parser *atlParser) parseTemporal(coalition []string) (ATLFormula, error) {
+	token := parser.next()
+
+	if token.Kind == atlTokenName && !token.Quoted {
+		var constructor func([]string, ATLFormula) ATLFormula
+		switch token.Text {
+		case "X":
+			constructor = ATLNext
+		case "G":
+			constructor = ATLAlways
+		case "F":
+			constructor = ATLEventually
+		}
+
+		if constructor != nil {
+			operand, err := parser.parseUnary()
+			if err != nil {
+				return ATLFormula{}, err
+			}
+			return constructor(coalition, operand), nil
+		}
+	}
+
+	if token.Kind == atlTokenLeftParen {
+		formula1, err := parser.parseImplication()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+
+		untilToken := parser.next()
+		if untilToken.Kind != atlTokenName || untilToken.Quoted || untilToken.Text != "U" {
+			return ATLFormula{}, fmt.Errorf("Expected \"U\" at position %v, but found \"%v\".", untilToken.Position, untilToken.Text)
+		}
+
+		formula2, err := parser.parseImplication()
+		if err != nil {
+			return ATLFormula{}, err
+		}
+
+		if _, err = parser.expect(atlTokenRightParen, "\")\""); err != nil {
+			return ATLFormula{}, err
+		}
+
+		return ATLUntil(coalition, formula1, formula2), nil
+	}
+
+	return ATLFormula{}, fmt.Errorf("Expected X, G, F or (... U ...) after the coalition at position %v, but found \"%v\".", token.Position, token.Text)
+}
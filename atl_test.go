@@ -0,0 +1,152 @@
+/*
+atl_test.go
+Description:
+	Tests the functions and objects created in atl.go
+*/
+package modelchecking
+
+import (
+	"testing"
+)
+
+/*
+TestATL_ParseATLFormula1
+Description:
+	Parses a formula with every temporal operator and verifies the syntax tree.
+*/
+func TestATL_ParseATLFormula1(t *testing.T) {
+	// Algorithm
+	formula, err := ParseATLFormula("<<Agent1>>(!Drunk U <<Agent1,Agent2>>X Sober) & <<>>G true -> <<Agent2>>F Drunk")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if formula.Operator != ATLOpImplies {
+		t.Errorf("Expected the top-level operator to be ->, but found %v.", formula)
+	}
+
+	conjunction := formula.Operands[0]
+	if conjunction.Operator != ATLOpAnd || len(conjunction.Operands) != 2 {
+		t.Errorf("Expected a conjunction of two formulas, but found %v.", conjunction)
+	}
+
+	until := conjunction.Operands[0]
+	if until.Operator != ATLOpUntil || len(until.Coalition) != 1 || until.Coalition[0] != "Agent1" {
+		t.Errorf("Expected <<Agent1>>(... U ...), but found %v.", until)
+	}
+
+	if until.Operands[1].Operator != ATLOpNext || len(until.Operands[1].Coalition) != 2 {
+		t.Errorf("Expected <<Agent1,Agent2>>X Sober, but found %v.", until.Operands[1])
+	}
+
+	if conjunction.Operands[1].Operator != ATLOpAlways || len(conjunction.Operands[1].Coalition) != 0 {
+		t.Errorf("Expected <<>>G true, but found %v.", conjunction.Operands[1])
+	}
+}
+
+/*
+TestATL_ParseATLFormula2
+Description:
+	Verifies that the unicode syntax and quoted names are parsed the same way as the ASCII syntax.
+*/
+func TestATL_ParseATLFormula2(t *testing.T) {
+	// Algorithm
+	formula1, err := ParseATLFormula("⟨⟨\"Agent 1\"⟩⟩G (¬p ∨ q)")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	formula2, err := ParseATLFormula("<<\"Agent 1\">>G (!p | q)")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if formula1.String() != formula2.String() {
+		t.Errorf("Expected the formulas to be the same, but found %v and %v.", formula1, formula2)
+	}
+
+	if formula1.Coalition[0] != "Agent 1" {
+		t.Errorf("Expected the coalition to contain \"Agent 1\", but found %v.", formula1.Coalition)
+	}
+}
+
+/*
+TestATL_String1
+Description:
+	Verifies that the string of a formula can be parsed back into the same formula.
+*/
+func TestATL_String1(t *testing.T) {
+	// Constants
+	formula := ATLImplies(
+		ATLAtom("Drunk"),
+		ATLUntil([]string{"Agent1"}, ATLNot(ATLAtom("X")), ATLEventually([]string{"Agent1", "Agent 2"}, ATLAtom("Sober"))),
+	)
+
+	// Algorithm
+	parsedFormula, err := ParseATLFormula(formula.String())
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", formula, err)
+	}
+
+	if parsedFormula.String() != formula.String() {
+		t.Errorf("Expected %v after parsing, but found %v.", formula, parsedFormula)
+	}
+}
+
+/*
+TestATL_ParseATLFormula3
+Description:
+	Verifies that malformed formulas are rejected.
+*/
+func TestATL_ParseATLFormula3(t *testing.T) {
+	// Constants
+	badFormulas := []string{
+		"",
+		"<<Agent1>> p",
+		"G p",
+		"<<Agent1>>(p q)",
+		"<<Agent1 p",
+		"(p & q",
+		"p & q)",
+		"p # q",
+		"\"unterminated",
+	}
+
+	// Algorithm
+	for _, badFormula := range badFormulas {
+		if _, err := ParseATLFormula(badFormula); err == nil {
+			t.Errorf("Expected an error when parsing \"%v\".", badFormula)
+		}
+	}
+}
+
+/*
+TestATL_ParseATLFormula4
+Description:
+	Parses the epistemic operators and verifies that K takes exactly one agent.
+*/
+func TestATL_ParseATLFormula4(t *testing.T) {
+	// Algorithm
+	formula, err := ParseATLFormula("K[Agent1] E[Agent1,Agent2] C[Agent2] p")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if formula.Operator != ATLOpKnows || formula.Operands[0].Operator != ATLOpEveryoneKnows || formula.Operands[0].Operands[0].Operator != ATLOpCommonKnowledge {
+		t.Errorf("Unexpected syntax tree: %v", formula)
+	}
+
+	if formula.String() != "K[Agent1] E[Agent1,Agent2] C[Agent2] p" {
+		t.Errorf("Unexpected string: %v", formula)
+	}
+
+	if _, err = ParseATLFormula("K[Agent1,Agent2] p"); err == nil {
+		t.Errorf("Expected an error for K with two agents.")
+	}
+
+	// Without brackets, K is an atomic proposition
+	formula, err = ParseATLFormula("K & E")
+	if err != nil || formula.Operands[0].Operator != ATLOpAtom {
+		t.Errorf("Expected K to be parsed as an atomic proposition: %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+/*
+atlchecking.go
+Description:
+ 	Model checking of ATL formulas on a Concurrent Game Model.
+	The temporal operators are computed with fixed points of the coalition controllable predecessor:
+		Sat(<<A>>X p)       = Pre_A(Sat(p))
+		Sat(<<A>>G p)       = nu Z. Sat(p) ∩ Pre_A(Z)
+		Sat(<<A>>(p U q))   = mu Z. Sat(q) ∪ (Sat(p) ∩ Pre_A(Z))
+*/
+package modelchecking
+
+import (
+	"fmt"
+)
+
+/*
+ControllablePredecessor
+Description:
+	Returns the states in which the coalition has a joint move such that, whatever the other agents do,
+	the next state is in target. States in which some agent has no permitted action are never included.
+*/
+func (cgm ConcurrentGameModel) ControllablePredecessor(coalition []CGMAgent, target []CGMState) []CGMState {
+	var predecessors []CGMState
+	for _, state := range cgm.St {
+		if _, found := cgm.controllableMove(state, coalition, target); found {
+			predecessors = append(predecessors, state)
+		}
+	}
+
+	return predecessors
+}
+
+/*
+SatisfyingStates
+Description:
+	Returns the states of the game which satisfy the ATL formula.
+	The strategic operators are evaluated under perfect information (see SatisfyingStatesIR()).
+Usage:
+	formula, _ := ParseATLFormula("<<Agent1>>F Drunk")
+	states, err := cgm.SatisfyingStates(formula)
+*/
+func (cgm ConcurrentGameModel) SatisfyingStates(formula ATLFormula) ([]CGMState, error) {
+	return cgm.satisfyingStates(formula, false)
+}
+
+/*
+satisfyingStates
+Description:
+	Computes the satisfying states of the formula. When uniform is true, the strategic operators
+	are evaluated with uniform memoryless strategies under imperfect information.
+*/
+func (cgm ConcurrentGameModel) satisfyingStates(formula ATLFormula, uniform bool) ([]CGMState, error) {
+	switch formula.Operator {
+	case ATLOpTrue:
+		return append([]CGMState{}, cgm.St...), nil
+
+	case ATLOpFalse:
+		return []CGMState{}, nil
+
+	case ATLOpAtom:
+		if !formula.Atom.In(cgm.Pi) {
+			return nil, fmt.Errorf("The atomic proposition \"%v\" is not in the game's set of atomic propositions.", formula.Atom)
+		}
+		var labelledStates []CGMState
+		for ap, states := range cgm.v {
+			if ap.Equals(formula.Atom) {
+				labelledStates = states
+			}
+		}
+		return cgm.intersectStates(cgm.St, labelledStates), nil
+
+	case ATLOpNot:
+		sat, err := cgm.satisfyingStates(formula.Operands[0], uniform)
+		if err != nil {
+			return nil, err
+		}
+		return cgm.complementStates(sat), nil
+
+	case ATLOpAnd:
+		satOut := append([]CGMState{}, cgm.St...)
+		for _, operand := range formula.Operands {
+			sat, err := cgm.satisfyingStates(operand, uniform)
+			if err != nil {
+				return nil, err
+			}
+			satOut = cgm.intersectStates(satOut, sat)
+		}
+		return satOut, nil
+
+	case ATLOpOr:
+		satOut := []CGMState{}
+		for _, operand := range formula.Operands {
+			sat, err := cgm.satisfyingStates(operand, uniform)
+			if err != nil {
+				return nil, err
+			}
+			satOut = cgm.unionStates(satOut, sat)
+		}
+		return satOut, nil
+
+	case ATLOpImplies:
+		return cgm.satisfyingStates(ATLOr(ATLNot(formula.Operands[0]), formula.Operands[1]), uniform)
+
+	case ATLOpNext, ATLOpAlways, ATLOpEventually, ATLOpUntil:
+		coalition, err := cgm.AgentsNamed(formula.Coalition...)
+		if err != nil {
+			return nil, fmt.Errorf("There was an issue with the coalition of %v: %v", formula, err)
+		}
+
+		var operandSats [][]CGMState
+		for _, operand := range formula.Operands {
+			sat, err := cgm.satisfyingStates(operand, uniform)
+			if err != nil {
+				return nil, err
+			}
+			operandSats = append(operandSats, sat)
+		}
+
+		if uniform {
+			return cgm.uniformTemporalSatisfyingStates(formula.Operator, coalition, operandSats), nil
+		}
+		return cgm.temporalFixedPoint(formula.Operator, operandSats, func(target []CGMState) []CGMState {
+			return cgm.ControllablePredecessor(coalition, target)
+		}), nil
+
+	case ATLOpKnows, ATLOpEveryoneKnows, ATLOpCommonKnowledge:
+		group, err := cgm.AgentsNamed(formula.Coalition...)
+		if err != nil {
+			return nil, fmt.Errorf("There was an issue with the agents of %v: %v", formula, err)
+		}
+
+		sat, err := cgm.satisfyingStates(formula.Operands[0], uniform)
+		if err != nil {
+			return nil, err
+		}
+
+		satOut := []CGMState{}
+		for _, state := range cgm.St {
+			considered := cgm.IndistinguishableStates(group, state)
+			if formula.Operator == ATLOpCommonKnowledge {
+				considered = cgm.CommonKnowledgeStates(group, state)
+			}
+			if len(cgm.intersectStates(considered, sat)) == len(considered) {
+				satOut = append(satOut, state)
+			}
+		}
+		return satOut, nil
+
+	default:
+		return nil, fmt.Errorf("Unrecognized ATL operator %v.", formula.Operator)
+	}
+}
+
+/*
+Satisfies
+Description:
+	Determines if the state of the game satisfies the ATL formula.
+*/
+func (cgm ConcurrentGameModel) Satisfies(state CGMState, formula ATLFormula) (bool, error) {
+	if !state.In(cgm.St) {
+		return false, fmt.Errorf("The state \"%v\" is not in the game.", state)
+	}
+
+	sat, err := cgm.SatisfyingStates(formula)
+	if err != nil {
+		return false, err
+	}
+
+	return state.In(sat), nil
+}
+
+/*
+temporalFixedPoint
+Description:
+	Computes the satisfying states of X, G, F or U from the satisfying states of the operands
+	and a predecessor function pre (the states from which the coalition can force the next state into a target).
+*/
+func (cgm ConcurrentGameModel) temporalFixedPoint(operator ATLOperator, operandSats [][]CGMState, pre func([]CGMState) []CGMState) []CGMState {
+	switch operator {
+	case ATLOpNext:
+		return cgm.intersectStates(cgm.St, pre(operandSats[0]))
+
+	case ATLOpAlways:
+		// Greatest fixed point
+		Z := operandSats[0]
+		for {
+			nextZ := cgm.intersectStates(operandSats[0], pre(Z))
+			if len(nextZ) == len(Z) {
+				return nextZ
+			}
+			Z = nextZ
+		}
+
+	default:
+		// Least fixed point of Until (Eventually is Until with true on the left)
+		stayIn := cgm.St
+		goal := operandSats[0]
+		if operator == ATLOpUntil {
+			stayIn = operandSats[0]
+			goal = operandSats[1]
+		}
+
+		Z := cgm.intersectStates(cgm.St, goal)
+		for {
+			nextZ := cgm.unionStates(Z, cgm.intersectStates(stayIn, pre(Z)))
+			if len(nextZ) == len(Z) {
+				return nextZ
+			}
+			Z = nextZ
+		}
+	}
+}
+
+/*
+controllableMove
+Description:
+	Returns the first move of the coalition (one action per agent of coalition) in state
+	which leads to target for every move of the other agents.
+	The second output is false if there is no such move.
+*/
+func (cgm ConcurrentGameModel) controllableMove(state CGMState, coalition []CGMAgent, target []CGMState) ([]string, bool) {
+	var opponents []CGMAgent
+	for _, agent := range cgm.Agents {
+		if !agent.In(coalition) {
+			opponents = append(opponents, agent)
+		}
+	}
+
+	for _, coalitionMove := range cgm.actionProfiles(coalition, state) {
+		if cgm.moveEnforces(state, coalition, coalitionMove, opponents, target) {
+			return coalitionMove, true
+		}
+	}
+
+	return nil, false
+}
+
+/*
+moveEnforces
+Description:
+	Determines if the coalition's move in state leads to target for every move of the opponents.
+*/
+func (cgm ConcurrentGameModel) moveEnforces(state CGMState, coalition []CGMAgent, coalitionMove []string, opponents []CGMAgent, target []CGMState) bool {
+	opponentMoves := cgm.actionProfiles(opponents, state)
+	if len(opponentMoves) == 0 {
+		return false
+	}
+
+	for _, opponentMove := range opponentMoves {
+		// Assemble the joint action in the order of cgm.Agents
+		jointAction := make(JointAction, len(cgm.Agents))
+		for agentIndex, agent := range coalition {
+			position, _ := cgm.agentIndex(agent)
+			jointAction[position] = coalitionMove[agentIndex]
+		}
+		for agentIndex, agent := range opponents {
+			position, _ := cgm.agentIndex(agent)
+			jointAction[position] = opponentMove[agentIndex]
+		}
+
+		successor, found := cgm.Successor(state, jointAction)
+		if !found || !successor.In(target) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+intersectStates
+Description:
+	Returns the states of set1 which are also in set2.
+*/
+func (cgm ConcurrentGameModel) intersectStates(set1 []CGMState, set2 []CGMState) []CGMState {
+	intersection := []CGMState{}
+	for _, state := range set1 {
+		if state.In(set2) {
+			intersection = state.AppendIfUniqueTo(intersection)
+		}
+	}
+	return intersection
+}
+
+/*
+unionStates
+Description:
+	Returns the states which are in set1 or in set2.
+*/
+func (cgm ConcurrentGameModel) unionStates(set1 []CGMState, set2 []CGMState) []CGMState {
+	union := []CGMState{}
+	for _, state := range append(append([]CGMState{}, set1...), set2...) {
+		union = state.AppendIfUniqueTo(union)
+	}
+	return union
+}
+
+/*
+complementStates
+Description:
+	Returns the states of the game which are not in setIn.
+*/
+func (cgm ConcurrentGameModel) complementStates(setIn []CGMState) []CGMState {
+	complement := []CGMState{}
+	for _, state := range cgm.St {
+		if !state.In(setIn) {
+			complement = append(complement, state)
+		}
+	}
+	return complement
+}
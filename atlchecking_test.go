@@ -0,0 +1,124 @@
+/*
+atlchecking_test.go
+Description:
+	Tests the functions and objects created in atlchecking.go
+*/
+package modelchecking
+
+import (
+	"testing"
+)
+
+/*
+checkATLSatisfyingStates
+Description:
+	Parses the formula, computes its satisfying states on the simple CGM and compares them to expectedNames.
+*/
+func checkATLSatisfyingStates(t *testing.T, formulaString string, expectedNames []string) {
+	cgm, _ := CreateSimpleCGM()
+
+	formula, err := ParseATLFormula(formulaString)
+	if err != nil {
+		t.Errorf("Unexpected error parsing %v: %v", formulaString, err)
+		return
+	}
+
+	sat, err := cgm.SatisfyingStates(formula)
+	if err != nil {
+		t.Errorf("Unexpected error checking %v: %v", formulaString, err)
+		return
+	}
+
+	if len(sat) != len(expectedNames) {
+		t.Errorf("Expected %v to hold in %v, but it holds in %v.", formulaString, expectedNames, sat)
+		return
+	}
+	for _, name := range expectedNames {
+		if !(CGMState{Name: name}).In(sat) {
+			t.Errorf("Expected %v to hold in %v, but it holds in %v.", formulaString, expectedNames, sat)
+		}
+	}
+}
+
+/*
+TestATLChecking_ControllablePredecessor1
+Description:
+	Verifies that Agent1 can force the game into Rome from Rome and Florence, but not from Venice.
+*/
+func TestATLChecking_ControllablePredecessor1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	coalition, err := cgm.AgentsNamed("Agent1")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Algorithm
+	pre := cgm.ControllablePredecessor(coalition, []CGMState{{Name: "Rome"}})
+	if len(pre) != 2 || !(CGMState{Name: "Rome"}).In(pre) || !(CGMState{Name: "Florence"}).In(pre) {
+		t.Errorf("Expected Pre = {Rome, Florence}, but found %v.", pre)
+	}
+}
+
+/*
+TestATLChecking_SatisfyingStates1
+Description:
+	Verifies the Next operator for single agents and for the empty coalition.
+*/
+func TestATLChecking_SatisfyingStates1(t *testing.T) {
+	checkATLSatisfyingStates(t, "<<Agent1>>X Drunk", []string{"Rome", "Florence"})
+	checkATLSatisfyingStates(t, "<<Agent2>>X Drunk", []string{"Venice"})
+	checkATLSatisfyingStates(t, "<<>>X Sober", []string{})
+}
+
+/*
+TestATLChecking_SatisfyingStates2
+Description:
+	Verifies the Always, Eventually and Until operators.
+*/
+func TestATLChecking_SatisfyingStates2(t *testing.T) {
+	checkATLSatisfyingStates(t, "<<Agent1>>G Sober", []string{"Florence"})
+	checkATLSatisfyingStates(t, "<<Agent2>>F Drunk", []string{"Rome", "Venice"})
+	checkATLSatisfyingStates(t, "<<Agent1,Agent2>>F Drunk", []string{"Rome", "Florence", "Venice"})
+	checkATLSatisfyingStates(t, "<<Agent2>>(Sober U Drunk)", []string{"Rome", "Venice"})
+	checkATLSatisfyingStates(t, "!<<Agent1>>G Sober & Sober", []string{"Venice"})
+}
+
+/*
+TestATLChecking_SatisfyingStates3
+Description:
+	Verifies that unknown agents and atomic propositions are reported.
+*/
+func TestATLChecking_SatisfyingStates3(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+
+	// Algorithm
+	if _, err := cgm.SatisfyingStates(ATLNext([]string{"Agent3"}, ATLTrue())); err == nil {
+		t.Errorf("Expected an error for the unknown agent Agent3.")
+	}
+
+	if _, err := cgm.SatisfyingStates(ATLAtom("Hungry")); err == nil {
+		t.Errorf("Expected an error for the unknown atomic proposition Hungry.")
+	}
+}
+
+/*
+TestATLChecking_Satisfies1
+Description:
+	Verifies that Rome satisfies <<Agent1>>G Drunk, since Agent1 can follow the legion forever.
+*/
+func TestATLChecking_Satisfies1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+
+	// Algorithm
+	tf, err := cgm.Satisfies(cgm.St[0], ATLAlways([]string{"Agent1"}, ATLAtom("Drunk")))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !tf {
+		t.Errorf("Expected Rome to satisfy <<Agent1>>G Drunk.")
+	}
+}
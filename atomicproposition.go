@@ -54,6 +54,25 @@ func (ap AtomicProposition) In(apSliceIn []AtomicProposition) bool {
 	return false
 }
 
+/*
+APSetsAreEqual
+Description:
+	Determines if the two slices contain the same atomic propositions, ignoring order and repetitions.
+*/
+func APSetsAreEqual(set1 []AtomicProposition, set2 []AtomicProposition) bool {
+	for _, ap := range set1 {
+		if !ap.In(set2) {
+			return false
+		}
+	}
+	for _, ap := range set2 {
+		if !ap.In(set1) {
+			return false
+		}
+	}
+	return true
+}
+
 /*
 ToSliceOfAtomicPropositions
 Description:
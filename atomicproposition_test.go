@@ -96,3 +96,23 @@ func TestAtomicProposition_Powerset1(t *testing.T) {
 	}
 
 }
+
+/*
+TestAtomicProposition_APSetsAreEqual1
+Description:
+	Verifies that the order and repetitions of the atomic propositions are ignored.
+*/
+func TestAtomicProposition_APSetsAreEqual1(t *testing.T) {
+	// Constants
+	set1 := StringSliceToAPs([]string{"A", "B"})
+	set2 := StringSliceToAPs([]string{"B", "A", "B"})
+	set3 := StringSliceToAPs([]string{"A"})
+
+	if !APSetsAreEqual(set1, set2) {
+		t.Errorf("Expected %v and %v to be equal.", set1, set2)
+	}
+
+	if APSetsAreEqual(set1, set3) || APSetsAreEqual(set3, set1) {
+		t.Errorf("Expected %v and %v to be different.", set1, set3)
+	}
+}
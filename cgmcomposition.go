@@ -0,0 +1,413 @@
+/*
+cgmcomposition.go
+Description:
+ 	Composition of a Concurrent Game Model from one Transition System per agent, and projection of a
+	Concurrent Game Model back to a Transition System once the agents' strategies are fixed.
+*/
+package modelchecking
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+InterferenceRule
+Description:
+	Decides the next local state of every agent when the agents in localStates take jointAction.
+	localStates[i] is a state of systems[i] and jointAction[i] is the action of agent i.
+	The rule must return one state of systems[i] for every agent i.
+*/
+type InterferenceRule func(systems []TransitionSystem, localStates []TransitionSystemState, jointAction JointAction) ([]TransitionSystemState, error)
+
+/*
+Constants
+*/
+
+const (
+	// The action permitted to an agent whose local state has no outgoing transitions. It leaves the local state unchanged.
+	IdleAction = "idle"
+)
+
+/*
+Interference Rules
+*/
+
+/*
+IndependentInterference
+Description:
+	Every agent moves according to its own transition system, independently of the others.
+	Each local transition must be deterministic.
+*/
+func IndependentInterference(systems []TransitionSystem, localStates []TransitionSystemState, jointAction JointAction) ([]TransitionSystemState, error) {
+	var nextStates []TransitionSystemState
+	for agentIndex, localState := range localStates {
+		nextState, err := localSuccessor(systems[agentIndex], localState, jointAction[agentIndex])
+		if err != nil {
+			return nil, err
+		}
+		nextStates = append(nextStates, nextState)
+	}
+	return nextStates, nil
+}
+
+/*
+HandshakeInterference
+Description:
+	Creates a rule where the actions in syncActions are handshakes: an agent which takes a handshake action only
+	moves if every agent with that action in its action set takes it at the same time; otherwise it stays where it is.
+	The other actions are taken independently.
+*/
+func HandshakeInterference(syncActions ...string) InterferenceRule {
+	return func(systems []TransitionSystem, localStates []TransitionSystemState, jointAction JointAction) ([]TransitionSystemState, error) {
+		var nextStates []TransitionSystemState
+		for agentIndex, localState := range localStates {
+			action := jointAction[agentIndex]
+
+			if _, isSync := FindInSlice(action, syncActions); isSync {
+				// Check that every participant of the handshake takes part
+				handshake := true
+				for otherIndex, otherSystem := range systems {
+					if _, participates := FindInSlice(action, otherSystem.Act); participates && jointAction[otherIndex] != action {
+						handshake = false
+					}
+				}
+				if !handshake {
+					nextStates = append(nextStates, localState)
+					continue
+				}
+			}
+
+			nextState, err := localSuccessor(systems[agentIndex], localState, action)
+			if err != nil {
+				return nil, err
+			}
+			nextStates = append(nextStates, nextState)
+		}
+		return nextStates, nil
+	}
+}
+
+/*
+localSuccessor
+Description:
+	Returns the unique successor of the local state under the action.
+	The IdleAction leaves a state without enabled actions unchanged.
+*/
+func localSuccessor(ts TransitionSystem, localState TransitionSystemState, action string) (TransitionSystemState, error) {
+	if action == IdleAction && len(enabledActions(ts, localState)) == 0 {
+		return localState, nil
+	}
+
+	successors, err := Post(localState, action)
+	if err != nil {
+		return localState, err
+	}
+
+	if len(successors) != 1 {
+		return localState, fmt.Errorf("The state \"%v\" has %v successors under the action \"%v\", but the composition requires exactly one.", localState, len(successors), action)
+	}
+
+	return successors[0], nil
+}
+
+/*
+enabledActions
+Description:
+	Returns the actions of the transition system which have a successor from the local state.
+*/
+func enabledActions(ts TransitionSystem, localState TransitionSystemState) []string {
+	var actions []string
+	for _, action := range ts.Act {
+		if successors, _ := Post(localState, action); len(successors) > 0 {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+/*
+localStatesName
+Description:
+	Names a tuple of local states as (s1,s2,...).
+*/
+func localStatesName(localStates []TransitionSystemState) string {
+	var names []string
+	for _, localState := range localStates {
+		names = append(names, localState.Name)
+	}
+	return "(" + strings.Join(names, ",") + ")"
+}
+
+/*
+Functions
+*/
+
+/*
+ComposeConcurrentGameModel
+Description:
+	Creates the Concurrent Game Model in which agent agentNames[i] behaves like systems[i] and the
+	interference rule combines the agents' actions. If rule is nil, then IndependentInterference is used.
+	- St contains the tuples of local states reachable from the tuples of initial states,
+	- d permits each agent the actions that are enabled in its local state (or IdleAction if there are none),
+	- o is given by the rule, and
+	- v labels each tuple with the labels of its local states.
+	The systems may not use IdleAction as one of their own actions.
+	The second output contains the states built from the initial states of the systems.
+*/
+func ComposeConcurrentGameModel(agentNames []string, systems []TransitionSystem, rule InterferenceRule) (ConcurrentGameModel, []CGMState, error) {
+	// Input Processing
+	if len(agentNames) != len(systems) {
+		return ConcurrentGameModel{}, nil, fmt.Errorf("Received %v agent names but %v transition systems.", len(agentNames), len(systems))
+	}
+
+	if len(systems) == 0 {
+		return ConcurrentGameModel{}, nil, errors.New("At least one transition system is required.")
+	}
+
+	for systemIndex, ts := range systems {
+		if err := ts.Check(); err != nil {
+			return ConcurrentGameModel{}, nil, fmt.Errorf("There was an issue checking the transition system of agent \"%v\": %v", agentNames[systemIndex], err)
+		}
+
+		if _, found := FindInSlice(IdleAction, ts.Act); found {
+			return ConcurrentGameModel{}, nil, fmt.Errorf("The transition system of agent \"%v\" has the action \"%v\", which is reserved for agents without enabled actions.", agentNames[systemIndex], IdleAction)
+		}
+	}
+
+	if rule == nil {
+		rule = IndependentInterference
+	}
+
+	// Collect the actions and atomic propositions
+	var actionNames, apNames []string
+	for _, ts := range systems {
+		actionNames = AppendIfUnique(actionNames, ts.Act...)
+		for _, ap := range ts.AP {
+			apNames = AppendIfUnique(apNames, ap.Name)
+		}
+	}
+
+	// Create the initial tuples
+	initialTuples := [][]TransitionSystemState{{}}
+	for _, ts := range systems {
+		var nextTuples [][]TransitionSystemState
+		for _, tuple := range initialTuples {
+			for _, initialState := range ts.I {
+				nextTuples = append(nextTuples, append(append([]TransitionSystemState{}, tuple...), initialState))
+			}
+		}
+		initialTuples = nextTuples
+	}
+
+	// Explore the reachable tuples
+	var stateNames, initialStateNames []string
+	actionFunction := make(map[string]map[string][]string)
+	transitionFunction := make(map[string]map[string]string)
+	valuationFunction := make(map[string][]string)
+	for _, agentName := range agentNames {
+		actionFunction[agentName] = make(map[string][]string)
+	}
+
+	queue := initialTuples
+	for _, tuple := range initialTuples {
+		initialStateNames = AppendIfUnique(initialStateNames, localStatesName(tuple))
+	}
+	stateNames = append(stateNames, initialStateNames...)
+
+	for len(queue) > 0 {
+		tuple := queue[0]
+		queue = queue[1:]
+		tupleName := localStatesName(tuple)
+
+		// Permitted actions and labels
+		permitted := make([][]string, len(systems))
+		for agentIndex, ts := range systems {
+			permitted[agentIndex] = enabledActions(ts, tuple[agentIndex])
+			if len(permitted[agentIndex]) == 0 {
+				permitted[agentIndex] = []string{IdleAction}
+				actionNames = AppendIfUnique(actionNames, IdleAction)
+			}
+			actionFunction[agentNames[agentIndex]][tupleName] = permitted[agentIndex]
+
+			for _, ap := range ts.L[tuple[agentIndex]] {
+				valuationFunction[ap.Name] = AppendIfUnique(valuationFunction[ap.Name], tupleName)
+			}
+		}
+
+		// Transitions for every joint action
+		jointActions := [][]string{{}}
+		for _, actions := range permitted {
+			var nextJointActions [][]string
+			for _, jointAction := range jointActions {
+				for _, action := range actions {
+					nextJointActions = append(nextJointActions, append(append([]string{}, jointAction...), action))
+				}
+			}
+			jointActions = nextJointActions
+		}
+
+		transitionFunction[tupleName] = make(map[string]string)
+		for _, jointAction := range jointActions {
+			nextTuple, err := rule(systems, tuple, JointAction(jointAction))
+			if err != nil {
+				return ConcurrentGameModel{}, nil, fmt.Errorf("The interference rule failed in state \"%v\" for the joint action \"%v\": %v", tupleName, JointAction(jointAction), err)
+			}
+
+			if len(nextTuple) != len(systems) {
+				return ConcurrentGameModel{}, nil, fmt.Errorf("The interference rule returned %v local states for %v agents.", len(nextTuple), len(systems))
+			}
+			for agentIndex, localState := range nextTuple {
+				if !localState.In(systems[agentIndex].S) {
+					return ConcurrentGameModel{}, nil, fmt.Errorf("The interference rule returned the state \"%v\", which is not a state of agent \"%v\".", localState, agentNames[agentIndex])
+				}
+			}
+
+			nextTupleName := localStatesName(nextTuple)
+			transitionFunction[tupleName][JointAction(jointAction).String()] = nextTupleName
+			if _, found := FindInSlice(nextTupleName, stateNames); !found {
+				stateNames = append(stateNames, nextTupleName)
+				queue = append(queue, nextTuple)
+			}
+		}
+	}
+
+	// Create the game
+	cgm, err := CreateConcurrentGameModel(agentNames, stateNames, apNames, actionNames, actionFunction, transitionFunction, valuationFunction)
+	if err != nil {
+		return cgm, nil, fmt.Errorf("There was an issue creating the composed game: %v", err)
+	}
+
+	var initialStates []CGMState
+	for _, state := range cgm.St {
+		if _, found := FindInSlice(state.Name, initialStateNames); found {
+			initialStates = append(initialStates, state)
+		}
+	}
+
+	return cgm, initialStates, nil
+}
+
+/*
+ToTransitionSystem
+Description:
+	Projects the game to a transition system by fixing the strategy of a coalition.
+	The actions of the transition system are the joint actions of the game; the agents outside of the coalition
+	become the nondeterminism of the transition system (if the coalition contains every agent, the result is
+	deterministic). For strategies with memory, the states are named (q,mode).
+	Agents of the coalition without a prescribed action play their first permitted action.
+Usage:
+	strategy, _, _ := cgm.SynthesizeStrategy(formula)
+	ts, err := cgm.ToTransitionSystem(strategy, initialStates)
+*/
+func (cgm ConcurrentGameModel) ToTransitionSystem(strategy CoalitionStrategy, initialStates []CGMState) (TransitionSystem, error) {
+	// Input Processing
+	for _, initialState := range initialStates {
+		if !initialState.In(cgm.St) {
+			return TransitionSystem{}, fmt.Errorf("The initial state \"%v\" is not a state of the game.", initialState)
+		}
+	}
+
+	for _, agent := range strategy.Coalition {
+		if !agent.In(cgm.Agents) {
+			return TransitionSystem{}, fmt.Errorf("The agent \"%v\" of the strategy is not an agent of the game.", agent)
+		}
+	}
+
+	type memoryState struct {
+		State CGMState
+		Mode  int
+	}
+	nameOf := func(ms memoryState) string {
+		if strategy.IsMemoryless() {
+			return ms.State.Name
+		}
+		return fmt.Sprintf("(%v,%v)", ms.State.Name, ms.Mode)
+	}
+
+	var opponents []CGMAgent
+	for _, agent := range cgm.Agents {
+		if !agent.In(strategy.Coalition) {
+			opponents = append(opponents, agent)
+		}
+	}
+
+	// Explore the states reachable under the strategy
+	var stateNames, initialNames, actionNames []string
+	var queue []memoryState
+	for _, initialState := range initialStates {
+		ms := memoryState{State: initialState, Mode: strategy.NextMode(strategy.InitialMode, initialState)}
+		if _, found := FindInSlice(nameOf(ms), stateNames); !found {
+			stateNames = append(stateNames, nameOf(ms))
+			queue = append(queue, ms)
+		}
+		initialNames = AppendIfUnique(initialNames, nameOf(ms))
+	}
+
+	transitionMap := make(map[string]map[string][]string)
+	labelMap := make(map[string][]string)
+	for len(queue) > 0 {
+		ms := queue[0]
+		queue = queue[1:]
+		msName := nameOf(ms)
+
+		// Labels
+		for ap, labelledStates := range cgm.v {
+			if ms.State.In(labelledStates) {
+				labelMap[msName] = append(labelMap[msName], ap.Name)
+			}
+		}
+
+		// Actions of the coalition
+		coalitionMove := make([]string, len(strategy.Coalition))
+		for agentIndex, agent := range strategy.Coalition {
+			action, found := strategyAction(strategy, ms.Mode, ms.State, agent)
+			if !found {
+				if permittedActions := cgm.PermittedActions(agent, ms.State); len(permittedActions) > 0 {
+					action = permittedActions[0]
+				}
+			}
+			coalitionMove[agentIndex] = action
+		}
+
+		// Every answer of the opponents
+		transitionMap[msName] = make(map[string][]string)
+		for _, opponentMove := range cgm.actionProfiles(opponents, ms.State) {
+			jointAction := make(JointAction, len(cgm.Agents))
+			for agentIndex, agent := range strategy.Coalition {
+				position, _ := cgm.agentIndex(agent)
+				jointAction[position] = coalitionMove[agentIndex]
+			}
+			for agentIndex, agent := range opponents {
+				position, _ := cgm.agentIndex(agent)
+				jointAction[position] = opponentMove[agentIndex]
+			}
+
+			successor, found := cgm.Successor(ms.State, jointAction)
+			if !found {
+				return TransitionSystem{}, fmt.Errorf("The joint action \"%v\" has no successor from state \"%v\".", jointAction, ms.State)
+			}
+
+			nextMS := memoryState{State: successor, Mode: strategy.NextMode(ms.Mode, successor)}
+			actionNames = AppendIfUnique(actionNames, jointAction.String())
+			transitionMap[msName][jointAction.String()] = append(transitionMap[msName][jointAction.String()], nameOf(nextMS))
+
+			if _, found := FindInSlice(nameOf(nextMS), stateNames); !found {
+				stateNames = append(stateNames, nameOf(nextMS))
+				queue = append(queue, nextMS)
+			}
+		}
+	}
+
+	var apNames []string
+	for _, ap := range cgm.Pi {
+		apNames = append(apNames, ap.Name)
+	}
+
+	return GetTransitionSystem(stateNames, actionNames, transitionMap, initialNames, apNames, labelMap)
+}
@@ -0,0 +1,268 @@
+package modelchecking
+
+import (
+	"testing"
+)
+
+/*
+GetLampTS
+Description:
+	A lamp which can be toggled or left alone. The state prefix+"1" is labelled with label.
+*/
+func GetLampTS(prefix string, label string) TransitionSystem {
+	ts, _ := GetTransitionSystem(
+		[]string{prefix + "0", prefix + "1"},
+		[]string{"toggle", "wait"},
+		map[string]map[string][]string{
+			prefix + "0": {
+				"toggle": {prefix + "1"},
+				"wait":   {prefix + "0"},
+			},
+			prefix + "1": {
+				"toggle": {prefix + "0"},
+				"wait":   {prefix + "1"},
+			},
+		},
+		[]string{prefix + "0"},
+		[]string{label},
+		map[string][]string{
+			prefix + "1": {label},
+		},
+	)
+	return ts
+}
+
+/*
+TestComposeConcurrentGameModel1
+Description:
+	Composes two independent lamps and checks the generated game.
+*/
+func TestComposeConcurrentGameModel1(t *testing.T) {
+	cgm, initialStates, err := ComposeConcurrentGameModel(
+		[]string{"A", "B"},
+		[]TransitionSystem{GetLampTS("a", "aOn"), GetLampTS("b", "bOn")},
+		nil,
+	)
+	if err != nil {
+		t.Errorf("There was an issue composing the game: %v", err)
+	}
+
+	if len(cgm.St) != 4 {
+		t.Errorf("Expected 4 states, but found %v.", len(cgm.St))
+	}
+
+	if len(initialStates) != 1 || initialStates[0].Name != "(a0,b0)" {
+		t.Errorf("Expected the initial state (a0,b0), but received %v.", initialStates)
+	}
+
+	if len(cgm.JointActions(initialStates[0])) != 4 {
+		t.Errorf("Expected 4 joint actions in the initial state, but found %v.", len(cgm.JointActions(initialStates[0])))
+	}
+
+	successor, found := cgm.Successor(initialStates[0], JointAction{"toggle", "wait"})
+	if !found || successor.Name != "(a1,b0)" {
+		t.Errorf("Expected (toggle, wait) to lead to (a1,b0), but received %v.", successor)
+	}
+
+	formulas := map[string]bool{
+		"<<A>>G !aOn":          true,
+		"<<A>>F aOn":           true,
+		"<<A>>F bOn":           false,
+		"<<A,B>>F (aOn & bOn)": true,
+	}
+	for formulaString, expected := range formulas {
+		formula, _ := ParseATLFormula(formulaString)
+		satisfied, err := cgm.Satisfies(initialStates[0], formula)
+		if err != nil {
+			t.Errorf("There was an issue checking %v: %v", formulaString, err)
+		}
+		if satisfied != expected {
+			t.Errorf("Expected %v to be %v in the initial state, but it was %v.", formulaString, expected, satisfied)
+		}
+	}
+}
+
+/*
+TestComposeConcurrentGameModel2
+Description:
+	Composes two lamps which can only be toggled together.
+*/
+func TestComposeConcurrentGameModel2(t *testing.T) {
+	cgm, initialStates, err := ComposeConcurrentGameModel(
+		[]string{"A", "B"},
+		[]TransitionSystem{GetLampTS("a", "aOn"), GetLampTS("b", "bOn")},
+		HandshakeInterference("toggle"),
+	)
+	if err != nil {
+		t.Errorf("There was an issue composing the game: %v", err)
+	}
+
+	if len(cgm.St) != 2 {
+		t.Errorf("Expected 2 reachable states, but found %v.", len(cgm.St))
+	}
+
+	successor, _ := cgm.Successor(initialStates[0], JointAction{"toggle", "wait"})
+	if successor.Name != "(a0,b0)" {
+		t.Errorf("Expected a toggle without a handshake to stay in (a0,b0), but reached %v.", successor)
+	}
+
+	formula, _ := ParseATLFormula("<<A>>F aOn")
+	if satisfied, _ := cgm.Satisfies(initialStates[0], formula); satisfied {
+		t.Errorf("Expected A to be unable to turn on its lamp alone.")
+	}
+
+	formula, _ = ParseATLFormula("<<A,B>>X (aOn & bOn)")
+	if satisfied, _ := cgm.Satisfies(initialStates[0], formula); !satisfied {
+		t.Errorf("Expected A and B to be able to turn on both lamps.")
+	}
+}
+
+/*
+TestComposeConcurrentGameModel3
+Description:
+	Verifies that agents without enabled actions idle and that bad inputs are rejected.
+*/
+func TestComposeConcurrentGameModel3(t *testing.T) {
+	oneShot, _ := GetTransitionSystem(
+		[]string{"c0", "c1"},
+		[]string{"go"},
+		map[string]map[string][]string{
+			"c0": {"go": {"c1"}},
+		},
+		[]string{"c0"},
+		[]string{"done"},
+		map[string][]string{"c1": {"done"}},
+	)
+
+	cgm, _, err := ComposeConcurrentGameModel(
+		[]string{"A", "C"},
+		[]TransitionSystem{GetLampTS("a", "aOn"), oneShot},
+		nil,
+	)
+	if err != nil {
+		t.Errorf("There was an issue composing the game: %v", err)
+	}
+
+	agentC, _ := cgm.AgentsNamed("C")
+	for _, state := range cgm.St {
+		if state.Name == "(a0,c1)" {
+			permitted := cgm.PermittedActions(agentC[0], state)
+			if len(permitted) != 1 || permitted[0] != IdleAction {
+				t.Errorf("Expected C to only idle in (a0,c1), but it may play %v.", permitted)
+			}
+		}
+	}
+
+	_, _, err = ComposeConcurrentGameModel([]string{"A"}, []TransitionSystem{}, nil)
+	if err == nil {
+		t.Errorf("Expected an error when the numbers of agents and systems differ.")
+	}
+}
+
+/*
+TestComposeConcurrentGameModel4
+Description:
+	Verifies that a system which uses the IdleAction as one of its own actions is rejected.
+*/
+func TestComposeConcurrentGameModel4(t *testing.T) {
+	idler, _ := GetTransitionSystem(
+		[]string{"d0", "d1"},
+		[]string{IdleAction, "go"},
+		map[string]map[string][]string{
+			"d0": {IdleAction: {"d0"}, "go": {"d1"}},
+			"d1": {IdleAction: {"d1"}},
+		},
+		[]string{"d0"},
+		[]string{"moved"},
+		map[string][]string{"d1": {"moved"}},
+	)
+
+	_, _, err := ComposeConcurrentGameModel(
+		[]string{"A", "D"},
+		[]TransitionSystem{GetLampTS("a", "aOn"), idler},
+		nil,
+	)
+	if err == nil {
+		t.Errorf("Expected an error when a system uses the action \"%v\".", IdleAction)
+	}
+}
+
+/*
+TestConcurrentGameModel_ToTransitionSystem1
+Description:
+	Projects the composed lamps with a full strategy profile: A always toggles and B always waits.
+*/
+func TestConcurrentGameModel_ToTransitionSystem1(t *testing.T) {
+	cgm, initialStates, _ := ComposeConcurrentGameModel(
+		[]string{"A", "B"},
+		[]TransitionSystem{GetLampTS("a", "aOn"), GetLampTS("b", "bOn")},
+		nil,
+	)
+
+	actions := make(map[CGMState]map[CGMAgent]string)
+	for _, state := range cgm.St {
+		actions[state] = map[CGMAgent]string{cgm.Agents[0]: "toggle", cgm.Agents[1]: "wait"}
+	}
+	strategy := NewMemorylessStrategy(cgm.Agents, actions)
+
+	ts, err := cgm.ToTransitionSystem(strategy, initialStates)
+	if err != nil {
+		t.Errorf("There was an issue projecting the game: %v", err)
+	}
+
+	if len(ts.S) != 2 {
+		t.Errorf("Expected 2 states, but found %v.", len(ts.S))
+	}
+
+	if len(ts.Act) != 1 || ts.Act[0] != "toggle, wait" {
+		t.Errorf("Expected the only action to be \"toggle, wait\", but found %v.", ts.Act)
+	}
+
+	if !ts.IsActionDeterministic() {
+		t.Errorf("Expected a full strategy profile to give an action-deterministic transition system.")
+	}
+
+	for _, state := range ts.S {
+		if state.Name == "(a1,b0)" && (len(ts.L[state]) != 1 || ts.L[state][0].Name != "aOn") {
+			t.Errorf("Expected (a1,b0) to be labelled with aOn, but found %v.", ts.L[state])
+		}
+	}
+}
+
+/*
+TestConcurrentGameModel_ToTransitionSystem2
+Description:
+	Projects the composed lamps with a strategy for A alone; the moves of B become nondeterminism.
+*/
+func TestConcurrentGameModel_ToTransitionSystem2(t *testing.T) {
+	cgm, initialStates, _ := ComposeConcurrentGameModel(
+		[]string{"A", "B"},
+		[]TransitionSystem{GetLampTS("a", "aOn"), GetLampTS("b", "bOn")},
+		nil,
+	)
+
+	agentA, _ := cgm.AgentsNamed("A")
+	actions := make(map[CGMState]map[CGMAgent]string)
+	for _, state := range cgm.St {
+		actions[state] = map[CGMAgent]string{agentA[0]: "wait"}
+	}
+	strategy := NewMemorylessStrategy(agentA, actions)
+
+	ts, err := cgm.ToTransitionSystem(strategy, initialStates)
+	if err != nil {
+		t.Errorf("There was an issue projecting the game: %v", err)
+	}
+
+	if len(ts.S) != 2 {
+		t.Errorf("Expected 2 states (B's lamp only), but found %v.", len(ts.S))
+	}
+
+	if len(ts.Act) != 2 {
+		t.Errorf("Expected 2 actions (one per move of B), but found %v.", ts.Act)
+	}
+
+	badState := CGMState{Name: "nowhere"}
+	if _, err := cgm.ToTransitionSystem(strategy, []CGMState{badState}); err == nil {
+		t.Errorf("Expected an error for an initial state outside of the game.")
+	}
+}
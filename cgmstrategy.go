@@ -0,0 +1,450 @@
+/*
+cgmstrategy.go
+Description:
+ 	Strategies of coalitions in a Concurrent Game Model: synthesis from ATL formulas and simulation of plays.
+*/
+package modelchecking
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+CoalitionStrategy
+Description:
+	A finite-memory strategy for the agents of Coalition.
+	The memory of the strategy is a mode. The mode starts at InitialMode and is updated with Update each time
+	a state of the play is visited (including the first state). Update[m][q] is the mode after visiting q in mode m;
+	if it is missing, then the mode does not change.
+	Actions[m][q] gives the action of each agent of the coalition in state q and mode m.
+	A strategy with one mode and no updates is memoryless.
+*/
+type CoalitionStrategy struct {
+	Coalition   []CGMAgent
+	Actions     []map[CGMState]map[CGMAgent]string
+	Update      []map[CGMState]int
+	InitialMode int
+}
+
+/*
+OpponentStrategy
+Description:
+	An interface for the agents outside of a coalition. Choose receives the play so far (whose last state
+	is the current state) and returns the action of each of the given agents.
+*/
+type OpponentStrategy interface {
+	Choose(play Play, agents []CGMAgent) (map[CGMAgent]string, error)
+}
+
+/*
+OpponentStrategyFunc
+Description:
+	Allows a function to be used as an OpponentStrategy.
+*/
+type OpponentStrategyFunc func(play Play, agents []CGMAgent) (map[CGMAgent]string, error)
+
+/*
+Play
+Description:
+	The history of a game: States[i+1] is reached from States[i] with JointActions[i].
+	Modes[i] is the mode of the coalition's strategy when it chose its actions in States[i].
+*/
+type Play struct {
+	States       []CGMState
+	JointActions []JointAction
+	Modes        []int
+}
+
+/*
+Functions for OpponentStrategyFunc
+*/
+
+/*
+Choose
+Description:
+	Calls the function.
+*/
+func (f OpponentStrategyFunc) Choose(play Play, agents []CGMAgent) (map[CGMAgent]string, error) {
+	return f(play, agents)
+}
+
+/*
+Functions for CoalitionStrategy
+*/
+
+/*
+NewMemorylessStrategy
+Description:
+	Creates a strategy with one mode which plays actions[q] in each state q.
+*/
+func NewMemorylessStrategy(coalition []CGMAgent, actions map[CGMState]map[CGMAgent]string) CoalitionStrategy {
+	return CoalitionStrategy{
+		Coalition:   coalition,
+		Actions:     []map[CGMState]map[CGMAgent]string{actions},
+		Update:      []map[CGMState]int{{}},
+		InitialMode: 0,
+	}
+}
+
+/*
+NumberOfModes
+Description:
+	Returns the number of memory modes of the strategy.
+*/
+func (strategy CoalitionStrategy) NumberOfModes() int {
+	return len(strategy.Actions)
+}
+
+/*
+IsMemoryless
+Description:
+	Returns true if the strategy only has one mode.
+*/
+func (strategy CoalitionStrategy) IsMemoryless() bool {
+	return strategy.NumberOfModes() == 1
+}
+
+/*
+ActionsAt
+Description:
+	Returns the actions that the strategy prescribes in the state and mode.
+	The second output is false if the strategy does not prescribe anything there.
+*/
+func (strategy CoalitionStrategy) ActionsAt(mode int, state CGMState) (map[CGMAgent]string, bool) {
+	if mode < 0 || mode >= len(strategy.Actions) {
+		return nil, false
+	}
+
+	for tempState, actions := range strategy.Actions[mode] {
+		if tempState.Equals(state) {
+			return actions, true
+		}
+	}
+	return nil, false
+}
+
+/*
+NextMode
+Description:
+	Returns the mode of the strategy after visiting the state in the given mode.
+*/
+func (strategy CoalitionStrategy) NextMode(mode int, state CGMState) int {
+	if mode < 0 || mode >= len(strategy.Update) {
+		return mode
+	}
+
+	for tempState, nextMode := range strategy.Update[mode] {
+		if tempState.Equals(state) {
+			return nextMode
+		}
+	}
+	return mode
+}
+
+/*
+addModes
+Description:
+	Appends the modes of the strategy inner to strategy and returns the index of the first appended mode.
+*/
+func (strategy *CoalitionStrategy) addModes(inner CoalitionStrategy) int {
+	offset := len(strategy.Actions)
+	strategy.Actions = append(strategy.Actions, inner.Actions...)
+	for _, updateMap := range inner.Update {
+		shiftedUpdateMap := make(map[CGMState]int)
+		for state, nextMode := range updateMap {
+			shiftedUpdateMap[state] = nextMode + offset
+		}
+		strategy.Update = append(strategy.Update, shiftedUpdateMap)
+	}
+	return offset
+}
+
+/*
+Strategy Synthesis
+*/
+
+/*
+SynthesizeStrategy
+Description:
+	Computes the states where the coalition of the temporal ATL formula can enforce it,
+	and a strategy of the coalition which enforces it from each of those states.
+	- <<A>>G p and <<A>>(p U q) (without nested strategic formulas) give memoryless strategies.
+	- When the formula reached by X or U is itself a temporal formula of a coalition contained in A,
+	  the strategy switches to the strategy of that formula once it holds, which requires memory.
+	Agents of the coalition without a prescribed action may play any permitted action.
+Usage:
+	formula, _ := ParseATLFormula("<<Agent1>>G Sober")
+	strategy, winningStates, err := cgm.SynthesizeStrategy(formula)
+*/
+func (cgm ConcurrentGameModel) SynthesizeStrategy(formula ATLFormula) (CoalitionStrategy, []CGMState, error) {
+	// Input Processing
+	if !formula.IsTemporal() {
+		return CoalitionStrategy{}, nil, fmt.Errorf("The formula %v does not start with a coalition operator.", formula)
+	}
+
+	coalition, err := cgm.AgentsNamed(formula.Coalition...)
+	if err != nil {
+		return CoalitionStrategy{}, nil, fmt.Errorf("There was an issue with the coalition of %v: %v", formula, err)
+	}
+
+	winningStates, err := cgm.SatisfyingStates(formula)
+	if err != nil {
+		return CoalitionStrategy{}, nil, err
+	}
+
+	var operandSats [][]CGMState
+	for _, operand := range formula.Operands {
+		sat, err := cgm.SatisfyingStates(operand)
+		if err != nil {
+			return CoalitionStrategy{}, nil, err
+		}
+		operandSats = append(operandSats, sat)
+	}
+
+	// Algorithm
+	switch formula.Operator {
+	case ATLOpNext:
+		actions := make(map[CGMState]map[CGMAgent]string)
+		for _, state := range winningStates {
+			move, _ := cgm.controllableMove(state, coalition, operandSats[0])
+			actions[state] = cgm.moveToActionMap(coalition, move)
+		}
+
+		strategy := NewMemorylessStrategy(coalition, actions)
+		if !cgm.isStrategicFor(formula.Operands[0], coalition) {
+			return strategy, winningStates, nil
+		}
+
+		// Mode 0 waits for the first state, mode 1 makes the first move and the inner strategy takes over afterwards.
+		strategy = CoalitionStrategy{
+			Coalition:   coalition,
+			Actions:     []map[CGMState]map[CGMAgent]string{{}, actions},
+			Update:      []map[CGMState]int{{}, {}},
+			InitialMode: 0,
+		}
+		for _, state := range cgm.St {
+			strategy.Update[0][state] = 1
+		}
+		err = cgm.switchToInnerStrategy(&strategy, 1, formula.Operands[0], operandSats[0])
+		if err != nil {
+			return CoalitionStrategy{}, nil, err
+		}
+		return strategy, winningStates, nil
+
+	case ATLOpAlways:
+		actions := make(map[CGMState]map[CGMAgent]string)
+		for _, state := range winningStates {
+			move, _ := cgm.controllableMove(state, coalition, winningStates)
+			actions[state] = cgm.moveToActionMap(coalition, move)
+		}
+		return NewMemorylessStrategy(coalition, actions), winningStates, nil
+
+	default:
+		// Attractor strategy: each state added to the attractor moves into the previous layer.
+		stayIn := cgm.St
+		goalFormula := formula.Operands[0]
+		goal := operandSats[0]
+		if formula.Operator == ATLOpUntil {
+			stayIn = operandSats[0]
+			goalFormula = formula.Operands[1]
+			goal = operandSats[1]
+		}
+
+		actions := make(map[CGMState]map[CGMAgent]string)
+		layer := cgm.intersectStates(cgm.St, goal)
+		for {
+			var newStates []CGMState
+			for _, state := range cgm.St {
+				if state.In(layer) || !state.In(stayIn) {
+					continue
+				}
+				if move, found := cgm.controllableMove(state, coalition, layer); found {
+					actions[state] = cgm.moveToActionMap(coalition, move)
+					newStates = append(newStates, state)
+				}
+			}
+
+			if len(newStates) == 0 {
+				break
+			}
+			layer = cgm.unionStates(layer, newStates)
+		}
+
+		strategy := NewMemorylessStrategy(coalition, actions)
+		if cgm.isStrategicFor(goalFormula, coalition) {
+			err = cgm.switchToInnerStrategy(&strategy, 0, goalFormula, goal)
+			if err != nil {
+				return CoalitionStrategy{}, nil, err
+			}
+		}
+		return strategy, winningStates, nil
+	}
+}
+
+/*
+isStrategicFor
+Description:
+	Returns true if the formula is a temporal formula whose coalition is contained in coalition,
+	so that coalition can play the strategy of formula once it holds.
+*/
+func (cgm ConcurrentGameModel) isStrategicFor(formula ATLFormula, coalition []CGMAgent) bool {
+	if !formula.IsTemporal() {
+		return false
+	}
+
+	innerCoalition, err := cgm.AgentsNamed(formula.Coalition...)
+	if err != nil {
+		return false
+	}
+
+	for _, agent := range innerCoalition {
+		if !agent.In(coalition) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+switchToInnerStrategy
+Description:
+	Synthesizes the strategy of innerFormula, adds its modes to strategy and switches
+	from fromMode to the inner strategy when a state in innerSat is visited.
+*/
+func (cgm ConcurrentGameModel) switchToInnerStrategy(strategy *CoalitionStrategy, fromMode int, innerFormula ATLFormula, innerSat []CGMState) error {
+	innerStrategy, _, err := cgm.SynthesizeStrategy(innerFormula)
+	if err != nil {
+		return fmt.Errorf("There was an issue synthesizing the strategy for %v: %v", innerFormula, err)
+	}
+
+	offset := strategy.addModes(innerStrategy)
+	for _, state := range innerSat {
+		strategy.Update[fromMode][state] = offset + innerStrategy.NextMode(innerStrategy.InitialMode, state)
+	}
+	return nil
+}
+
+/*
+moveToActionMap
+Description:
+	Converts a move of the coalition (one action per agent, in the order of coalition) into a map.
+*/
+func (cgm ConcurrentGameModel) moveToActionMap(coalition []CGMAgent, move []string) map[CGMAgent]string {
+	actionMap := make(map[CGMAgent]string)
+	for agentIndex, agent := range coalition {
+		actionMap[agent] = move[agentIndex]
+	}
+	return actionMap
+}
+
+/*
+Play Simulation
+*/
+
+/*
+SimulatePlay
+Description:
+	Plays the coalition's strategy against the opponents for numSteps steps, starting from initialState.
+	Agents of the coalition without a prescribed action play their first permitted action.
+	An error is returned if an agent is given an action that it is not permitted to take or if
+	the joint action has no successor.
+*/
+func (cgm ConcurrentGameModel) SimulatePlay(initialState CGMState, strategy CoalitionStrategy, opponents OpponentStrategy, numSteps int) (Play, error) {
+	// Input Processing
+	if !initialState.In(cgm.St) {
+		return Play{}, fmt.Errorf("The initial state \"%v\" is not a state of the game.", initialState)
+	}
+
+	if numSteps < 0 {
+		return Play{}, errors.New("The number of steps must be nonnegative.")
+	}
+
+	var opponentAgents []CGMAgent
+	for _, agent := range cgm.Agents {
+		if !agent.In(strategy.Coalition) {
+			opponentAgents = append(opponentAgents, agent)
+		}
+	}
+
+	if len(opponentAgents) > 0 && opponents == nil {
+		return Play{}, errors.New("An opponent strategy is required when the coalition does not contain every agent.")
+	}
+
+	// Algorithm
+	play := Play{States: []CGMState{initialState}}
+	mode := strategy.InitialMode
+	for step := 0; step < numSteps; step++ {
+		state := play.LastState()
+		mode = strategy.NextMode(mode, state)
+		play.Modes = append(play.Modes, mode)
+
+		// Collect the actions of every agent
+		chosenActions := make(map[string]string)
+		prescribedActions, _ := strategy.ActionsAt(mode, state)
+		for _, agent := range strategy.Coalition {
+			for tempAgent, action := range prescribedActions {
+				if tempAgent.Equals(agent) {
+					chosenActions[agent.Name] = action
+				}
+			}
+			if _, found := chosenActions[agent.Name]; !found {
+				if permittedActions := cgm.PermittedActions(agent, state); len(permittedActions) > 0 {
+					chosenActions[agent.Name] = permittedActions[0]
+				}
+			}
+		}
+
+		if len(opponentAgents) > 0 {
+			opponentActions, err := opponents.Choose(play, opponentAgents)
+			if err != nil {
+				return play, fmt.Errorf("The opponents could not choose their actions at step %v: %v", step, err)
+			}
+			for tempAgent, action := range opponentActions {
+				if tempAgent.In(opponentAgents) {
+					chosenActions[tempAgent.Name] = action
+				}
+			}
+		}
+
+		// Assemble and apply the joint action
+		jointAction := make(JointAction, len(cgm.Agents))
+		for agentIndex, agent := range cgm.Agents {
+			action, found := chosenActions[agent.Name]
+			if !found {
+				return play, fmt.Errorf("No action was chosen for agent \"%v\" in state \"%v\" at step %v.", agent, state, step)
+			}
+			if _, permitted := FindInSlice(action, cgm.PermittedActions(agent, state)); !permitted {
+				return play, fmt.Errorf("The action \"%v\" of agent \"%v\" is not permitted in state \"%v\" at step %v.", action, agent, state, step)
+			}
+			jointAction[agentIndex] = action
+		}
+
+		successor, found := cgm.Successor(state, jointAction)
+		if !found {
+			return play, fmt.Errorf("The joint action \"%v\" has no successor from state \"%v\" at step %v.", jointAction, state, step)
+		}
+
+		play.JointActions = append(play.JointActions, jointAction)
+		play.States = append(play.States, successor)
+	}
+
+	return play, nil
+}
+
+/*
+Functions for Play
+*/
+
+/*
+LastState
+Description:
+	Returns the current state of the play.
+*/
+func (play Play) LastState() CGMState {
+	return play.States[len(play.States)-1]
+}
@@ -0,0 +1,161 @@
+/*
+cgmstrategy_test.go
+Description:
+	Tests the functions and objects created in cgmstrategy.go
+*/
+package modelchecking
+
+import (
+	"testing"
+)
+
+/*
+lastActionOpponent
+Description:
+	An opponent which always plays the last permitted action of each agent.
+*/
+func lastActionOpponent(cgm ConcurrentGameModel) OpponentStrategy {
+	return OpponentStrategyFunc(func(play Play, agents []CGMAgent) (map[CGMAgent]string, error) {
+		actions := make(map[CGMAgent]string)
+		for _, agent := range agents {
+			permittedActions := cgm.PermittedActions(agent, play.LastState())
+			actions[agent] = permittedActions[len(permittedActions)-1]
+		}
+		return actions, nil
+	})
+}
+
+/*
+TestCGMStrategy_SynthesizeStrategy1
+Description:
+	Verifies that the strategy for <<Agent1>>G Sober is memoryless and keeps the game in Florence.
+*/
+func TestCGMStrategy_SynthesizeStrategy1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent1>>G Sober")
+
+	// Algorithm
+	strategy, winningStates, err := cgm.SynthesizeStrategy(formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !strategy.IsMemoryless() {
+		t.Errorf("Expected a memoryless strategy, but found %v modes.", strategy.NumberOfModes())
+	}
+
+	if len(winningStates) != 1 || winningStates[0].Name != "Florence" {
+		t.Errorf("Expected the winning states to be {Florence}, but found %v.", winningStates)
+	}
+
+	actions, found := strategy.ActionsAt(0, CGMState{Name: "Florence"})
+	if !found || actions[cgm.Agents[0]] != "Take Boat" {
+		t.Errorf("Expected Agent1 to take the boat in Florence, but found %v.", actions)
+	}
+
+	play, err := cgm.SimulatePlay(winningStates[0], strategy, lastActionOpponent(cgm), 5)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, state := range play.States {
+		if state.Name != "Florence" {
+			t.Errorf("Expected the play to stay in Florence, but found %v.", play.States)
+		}
+	}
+}
+
+/*
+TestCGMStrategy_SynthesizeStrategy2
+Description:
+	Verifies that the attractor strategy of Agent2 for <<Agent2>>F Drunk reaches Rome from Venice.
+*/
+func TestCGMStrategy_SynthesizeStrategy2(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent2>>F Drunk")
+
+	// Algorithm
+	strategy, _, err := cgm.SynthesizeStrategy(formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	play, err := cgm.SimulatePlay(CGMState{Name: "Venice"}, strategy, lastActionOpponent(cgm), 1)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(play.JointActions) != 1 || play.JointActions[0].String() != "Take Boat, Take Boat" {
+		t.Errorf("Expected the joint action \"Take Boat, Take Boat\", but found %v.", play.JointActions)
+	}
+
+	if play.LastState().Name != "Rome" {
+		t.Errorf("Expected the play to reach Rome, but found %v.", play.States)
+	}
+}
+
+/*
+TestCGMStrategy_SynthesizeStrategy3
+Description:
+	Verifies that the strategy for <<Agent1>>X <<Agent1>>G Drunk has memory and switches
+	to the inner strategy after the first move.
+*/
+func TestCGMStrategy_SynthesizeStrategy3(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent1>>X <<Agent1>>G Drunk")
+
+	// Algorithm
+	strategy, winningStates, err := cgm.SynthesizeStrategy(formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if strategy.IsMemoryless() {
+		t.Errorf("Expected a strategy with memory.")
+	}
+
+	if !(CGMState{Name: "Florence"}).In(winningStates) {
+		t.Errorf("Expected Florence to be a winning state, but found %v.", winningStates)
+	}
+
+	play, err := cgm.SimulatePlay(CGMState{Name: "Florence"}, strategy, lastActionOpponent(cgm), 3)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expectedStates := []string{"Florence", "Rome", "Rome", "Rome"}
+	for stateIndex, state := range play.States {
+		if state.Name != expectedStates[stateIndex] {
+			t.Errorf("Expected the play %v, but found %v.", expectedStates, play.States)
+		}
+	}
+
+	if play.Modes[0] == play.Modes[1] {
+		t.Errorf("Expected the strategy to switch modes after the first move, but found modes %v.", play.Modes)
+	}
+}
+
+/*
+TestCGMStrategy_SimulatePlay1
+Description:
+	Verifies that SimulatePlay() reports an opponent which plays an action that is not permitted.
+*/
+func TestCGMStrategy_SimulatePlay1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent1>>G Sober")
+	strategy, _, _ := cgm.SynthesizeStrategy(formula)
+
+	cheatingOpponent := OpponentStrategyFunc(func(play Play, agents []CGMAgent) (map[CGMAgent]string, error) {
+		return map[CGMAgent]string{agents[0]: "Take Boat"}, nil
+	})
+
+	// Algorithm
+	_, err := cgm.SimulatePlay(CGMState{Name: "Florence"}, strategy, cheatingOpponent, 1)
+	if err == nil {
+		t.Errorf("Expected an error because Agent2 may not take the boat in Florence.")
+	}
+}
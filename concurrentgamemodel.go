@@ -5,14 +5,20 @@ Description:
 */
 package modelchecking
 
+import (
+	"fmt"
+	"strings"
+)
+
 type ConcurrentGameModel struct {
 	Agents []CGMAgent
 	St     []CGMState
 	Pi     []AtomicProposition
 	Act    []string                           // Actions
 	d      map[CGMAgent]map[CGMState][]string //Permitted Actions (Action Function)
-	o      map[CGMState]map[string]CGMState   //Transition Function
+	o      map[CGMState]map[string]CGMState   //Transition Function (keyed by JointAction.String())
 	v      map[AtomicProposition][]CGMState   // Valuation Function
+	obs    map[CGMAgent]map[CGMState]string   // Observation Function (see WithObservationFunction())
 }
 
 type CGMAgent struct {
@@ -25,6 +31,18 @@ type CGMState struct {
 	ParentGame *ConcurrentGameModel
 }
 
+/*
+JointAction
+Description:
+	One action per agent, in the order of the game's Agents slice.
+	The transition function o is keyed by the String() of a JointAction.
+*/
+type JointAction []string
+
+// JointActionSeparator separates the actions of the agents in the String() of a JointAction. Check() rejects
+// actions which contain it, so that every key of the transition function o belongs to exactly one JointAction.
+const JointActionSeparator = ", "
+
 // Atomic Proposition Already Defined
 
 /*
@@ -98,8 +116,398 @@ func CreateConcurrentGameModel(agentNames []string, stateNames []string, atomicP
 	}
 	cgm.v = tempValuationFunction
 
-	//transitionFunction
+	return cgm, cgm.Check()
+
+}
+
+/*
+Functions for CGMAgent
+*/
+
+/*
+String
+Description:
+	Provides the name of the agent.
+*/
+func (agentIn CGMAgent) String() string {
+	return agentIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the two agents have the same name.
+*/
+func (agentIn CGMAgent) Equals(agent2 CGMAgent) bool {
+	return agentIn.Name == agent2.Name
+}
+
+/*
+In
+Description:
+	Determines if the agent is in a given slice of CGMAgent objects.
+*/
+func (agentIn CGMAgent) In(agentSliceIn []CGMAgent) bool {
+	for _, tempAgent := range agentSliceIn {
+		if agentIn.Equals(tempAgent) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Functions for CGMState
+*/
+
+/*
+String
+Description:
+	Provides the name of the state.
+*/
+func (stateIn CGMState) String() string {
+	return stateIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the two states have the same name.
+*/
+func (stateIn CGMState) Equals(state2 CGMState) bool {
+	return stateIn.Name == state2.Name
+}
+
+/*
+In
+Description:
+	Determines if the state is in a given slice of CGMState objects.
+*/
+func (stateIn CGMState) In(stateSliceIn []CGMState) bool {
+	for _, tempState := range stateSliceIn {
+		if stateIn.Equals(tempState) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+AppendIfUniqueTo
+Description:
+	Appends the state to sliceIn if and only if it is not already in sliceIn.
+*/
+func (stateIn CGMState) AppendIfUniqueTo(sliceIn []CGMState) []CGMState {
+	if stateIn.In(sliceIn) {
+		return sliceIn
+	}
+	return append(sliceIn, stateIn)
+}
+
+/*
+Functions for JointAction
+*/
+
+/*
+String
+Description:
+	Creates the key of the joint action in the transition function o,
+	which is the actions of the agents in order separated by JointActionSeparator.
+*/
+func (jaIn JointAction) String() string {
+	return strings.Join(jaIn, JointActionSeparator)
+}
+
+/*
+ToJointAction
+Description:
+	Splits a key of the transition function o (e.g. "Ride Horse, Take Boat") into a JointAction.
+*/
+func ToJointAction(jointActionString string) JointAction {
+	return JointAction(strings.Split(jointActionString, JointActionSeparator))
+}
+
+/*
+Functions for ConcurrentGameModel
+*/
+
+/*
+AgentsNamed
+Description:
+	Returns the agents of the game with the given names, in the order of the names.
+	An error is returned if one of the names does not belong to an agent of the game.
+*/
+func (cgm ConcurrentGameModel) AgentsNamed(names ...string) ([]CGMAgent, error) {
+	var agentsOut []CGMAgent
+	for _, name := range names {
+		agentIndex, foundAgent := cgm.agentIndex(CGMAgent{Name: name})
+		if !foundAgent {
+			return nil, fmt.Errorf("The agent \"%v\" is not an agent of the game.", name)
+		}
+		agentsOut = append(agentsOut, cgm.Agents[agentIndex])
+	}
+	return agentsOut, nil
+}
 
-	return cgm, nil
+/*
+PermittedActions
+Description:
+	Returns the actions that the agent may take in the state, according to the action function d.
+*/
+func (cgm ConcurrentGameModel) PermittedActions(agent CGMAgent, state CGMState) []string {
+	for tempAgent, stateMap := range cgm.d {
+		if !tempAgent.Equals(agent) {
+			continue
+		}
+		for tempState, actions := range stateMap {
+			if tempState.Equals(state) {
+				return actions
+			}
+		}
+	}
+	return []string{}
+}
 
+/*
+Successor
+Description:
+	Returns the state reached from state when each agent takes the action at its index in jointAction.
+	The actions must be given in the order of cgm.Agents. The second output is false if o has no such transition.
+*/
+func (cgm ConcurrentGameModel) Successor(state CGMState, jointAction JointAction) (CGMState, bool) {
+	key := jointAction.String()
+	for tempState, jointActionMap := range cgm.o {
+		if tempState.Equals(state) {
+			successor, found := jointActionMap[key]
+			return successor, found
+		}
+	}
+	return CGMState{}, false
+}
+
+/*
+JointActions
+Description:
+	Returns every joint action that the agents are permitted to take in the state according to d.
+	If some agent has no permitted action, then there are no joint actions.
+*/
+func (cgm ConcurrentGameModel) JointActions(state CGMState) []JointAction {
+	var jointActions []JointAction
+	for _, profile := range cgm.actionProfiles(cgm.Agents, state) {
+		jointActions = append(jointActions, JointAction(profile))
+	}
+	return jointActions
+}
+
+/*
+Labels
+Description:
+	Returns the atomic propositions of Pi which hold in the state, according to the valuation function v.
+*/
+func (cgm ConcurrentGameModel) Labels(state CGMState) []AtomicProposition {
+	var labels []AtomicProposition
+	for _, ap := range cgm.Pi {
+		for tempAP, states := range cgm.v {
+			if tempAP.Equals(ap) && state.In(states) {
+				labels = append(labels, ap)
+			}
+		}
+	}
+	return labels
+}
+
+/*
+CheckAct
+Description:
+	Checks that no action contains JointActionSeparator. Such an action would make the key of a joint action
+	in the transition function o ambiguous, e.g. the joint actions ("a, b", "c") and ("a", "b, c") would both
+	have the key "a, b, c".
+*/
+func (cgm ConcurrentGameModel) CheckAct() error {
+	for _, action := range cgm.Act {
+		if strings.Contains(action, JointActionSeparator) {
+			return fmt.Errorf("The action \"%v\" contains the separator \"%v\" of joint actions.", action, JointActionSeparator)
+		}
+	}
+
+	return nil
+}
+
+/*
+CheckD
+Description:
+	Checks that every entry of the action function d refers to an agent and a state of the game,
+	and that every permitted action is in Act.
+*/
+func (cgm ConcurrentGameModel) CheckD() error {
+	for tempAgent, stateMap := range cgm.d {
+		if !tempAgent.In(cgm.Agents) {
+			return fmt.Errorf("The agent \"%v\" in the action function d is not an agent of the game.", tempAgent)
+		}
+
+		for tempState, actions := range stateMap {
+			if !tempState.In(cgm.St) {
+				return fmt.Errorf("The state \"%v\" in the action function d of agent \"%v\" is not a state of the game.", tempState, tempAgent)
+			}
+
+			for _, action := range actions {
+				if _, foundInAct := FindInSlice(action, cgm.Act); !foundInAct {
+					return fmt.Errorf("The action \"%v\" permitted for agent \"%v\" in state \"%v\" is not in the action set Act.", action, tempAgent, tempState)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+CheckO
+Description:
+	Checks the transition function o:
+	- every source and successor state is a state of the game,
+	- every joint action in o has one action from Act per agent, and
+	- every joint action of permitted moves has a successor in o.
+*/
+func (cgm ConcurrentGameModel) CheckO() error {
+	for tempState, jointActionMap := range cgm.o {
+		if !tempState.In(cgm.St) {
+			return fmt.Errorf("The source state \"%v\" in the transition function o is not a state of the game.", tempState)
+		}
+
+		for jointActionString, successor := range jointActionMap {
+			jointAction := ToJointAction(jointActionString)
+			if len(jointAction) != len(cgm.Agents) {
+				return fmt.Errorf(
+					"The joint action \"%v\" in state \"%v\" has %v actions, but the game has %v agents.",
+					jointActionString, tempState, len(jointAction), len(cgm.Agents),
+				)
+			}
+
+			for agentIndex, action := range jointAction {
+				if _, foundInAct := FindInSlice(action, cgm.Act); !foundInAct {
+					return fmt.Errorf(
+						"The action \"%v\" of agent \"%v\" in the joint action \"%v\" from state \"%v\" is not in the action set Act.",
+						action, cgm.Agents[agentIndex], jointActionString, tempState,
+					)
+				}
+			}
+
+			if !successor.In(cgm.St) {
+				return fmt.Errorf(
+					"The successor \"%v\" of state \"%v\" under the joint action \"%v\" is not a state of the game.",
+					successor, tempState, jointActionString,
+				)
+			}
+		}
+	}
+
+	// Every permitted joint action must have a successor
+	for _, state := range cgm.St {
+		for _, jointAction := range cgm.JointActions(state) {
+			if _, found := cgm.Successor(state, jointAction); !found {
+				return fmt.Errorf(
+					"The joint action \"%v\" is permitted in state \"%v\", but has no successor in the transition function o.",
+					jointAction, state,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+CheckV
+Description:
+	Checks that the valuation function v only uses atomic propositions from Pi and states of the game.
+*/
+func (cgm ConcurrentGameModel) CheckV() error {
+	for ap, states := range cgm.v {
+		if !ap.In(cgm.Pi) {
+			return fmt.Errorf("The atomic proposition \"%v\" in the valuation function v is not in Pi.", ap)
+		}
+
+		for _, state := range states {
+			if !state.In(cgm.St) {
+				return fmt.Errorf("The state \"%v\" labelled with \"%v\" in the valuation function v is not a state of the game.", state, ap)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+Check
+Description:
+	Checks the following components of the concurrent game model:
+	- the action names (see CheckAct()),
+	- the action function d (see CheckD()),
+	- the transition function o (see CheckO()),
+	- the valuation function v (see CheckV()), and
+	- the observation function, if there is one (see CheckObservations()).
+*/
+func (cgm ConcurrentGameModel) Check() error {
+	err := cgm.CheckAct()
+	if err != nil {
+		return err
+	}
+
+	err = cgm.CheckD()
+	if err != nil {
+		return err
+	}
+
+	err = cgm.CheckO()
+	if err != nil {
+		return err
+	}
+
+	err = cgm.CheckV()
+	if err != nil {
+		return err
+	}
+
+	err = cgm.CheckObservations()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+actionProfiles
+Description:
+	Returns every combination of permitted actions of the agents in the state.
+	Each profile lists one action per agent, in the order of agents.
+*/
+func (cgm ConcurrentGameModel) actionProfiles(agents []CGMAgent, state CGMState) [][]string {
+	profiles := [][]string{{}}
+	for _, agent := range agents {
+		var nextProfiles [][]string
+		for _, profile := range profiles {
+			for _, action := range cgm.PermittedActions(agent, state) {
+				nextProfile := append(append([]string{}, profile...), action)
+				nextProfiles = append(nextProfiles, nextProfile)
+			}
+		}
+		profiles = nextProfiles
+	}
+	return profiles
+}
+
+/*
+agentIndex
+Description:
+	Returns the index of the agent in cgm.Agents.
+*/
+func (cgm ConcurrentGameModel) agentIndex(agent CGMAgent) (int, bool) {
+	for agentIndex, tempAgent := range cgm.Agents {
+		if tempAgent.Equals(agent) {
+			return agentIndex, true
+		}
+	}
+	return -1, false
 }
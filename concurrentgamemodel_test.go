@@ -1,6 +1,7 @@
 package modelchecking
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -90,3 +91,179 @@ func TestConcurrentGameModel_CreateConcurrentGameModel1(t *testing.T) {
 	}
 
 }
+
+/*
+createTwoStateCGM
+Description:
+	Creates a one-agent game with the given action, transition and valuation functions,
+	so that each test can break one of them.
+*/
+func createTwoStateCGM(actionFunction map[string]map[string][]string, transitionFunction map[string]map[string]string, valuationFunction map[string][]string) (ConcurrentGameModel, error) {
+	return CreateConcurrentGameModel(
+		[]string{"Agent1"},
+		[]string{"s0", "s1"},
+		[]string{"p"},
+		[]string{"stay", "go"},
+		actionFunction,
+		transitionFunction,
+		valuationFunction,
+	)
+}
+
+func TestConcurrentGameModel_JointAction1(t *testing.T) {
+	// Constants
+	jointAction := JointAction{"Ride Horse", "Take Boat"}
+
+	// Test
+	if jointAction.String() != "Ride Horse, Take Boat" {
+		t.Errorf("Expected the key \"Ride Horse, Take Boat\", but received \"%v\".", jointAction)
+	}
+
+	if ToJointAction(jointAction.String())[1] != "Take Boat" {
+		t.Errorf("Expected ToJointAction() to invert String(), but received %v.", ToJointAction(jointAction.String()))
+	}
+}
+
+func TestConcurrentGameModel_JointActions1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+
+	// Test: Venice permits {Take Boat} x {Take Boat, Follow Legion}
+	jointActions := cgm.JointActions(CGMState{Name: "Venice"})
+	if len(jointActions) != 2 {
+		t.Errorf("Expected 2 joint actions in Venice, but found %v.", jointActions)
+	}
+
+	successor, found := cgm.Successor(CGMState{Name: "Venice"}, JointAction{"Take Boat", "Follow Legion"})
+	if !found || successor.Name != "Florence" {
+		t.Errorf("Expected the successor Florence, but found %v (%v).", successor, found)
+	}
+}
+
+func TestConcurrentGameModel_Labels1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+
+	// Test: Rome is only labelled with Drunk
+	labels := cgm.Labels(CGMState{Name: "Rome"})
+	if len(labels) != 1 || labels[0].Name != "Drunk" {
+		t.Errorf("Expected Rome to be labelled with Drunk only, but found %v.", labels)
+	}
+
+	// Test: Unknown states have no labels
+	if labels := cgm.Labels(CGMState{Name: "Milan"}); len(labels) != 0 {
+		t.Errorf("Expected no labels for Milan, but found %v.", labels)
+	}
+}
+
+func TestConcurrentGameModel_CheckD1(t *testing.T) {
+	// Create a game where an unknown action is permitted
+	_, err := createTwoStateCGM(
+		map[string]map[string][]string{"Agent1": {"s0": {"stay", "fly"}, "s1": {"stay"}}},
+		map[string]map[string]string{"s0": {"stay": "s0"}, "s1": {"stay": "s1"}},
+		map[string][]string{"p": {"s0"}},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the unknown action \"fly\".")
+	} else if !strings.Contains(err.Error(), "fly") || !strings.Contains(err.Error(), "s0") || !strings.Contains(err.Error(), "Agent1") {
+		t.Errorf("Expected the error to name the action, state and agent, but received: %v", err)
+	}
+
+	// Create a game where an unknown agent has permitted actions
+	_, err = createTwoStateCGM(
+		map[string]map[string][]string{"Agent2": {"s0": {"stay"}}},
+		map[string]map[string]string{},
+		map[string][]string{},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the unknown agent \"Agent2\".")
+	}
+}
+
+func TestConcurrentGameModel_CheckO1(t *testing.T) {
+	// Create a game where the permitted joint action "go" in s0 has no successor
+	_, err := createTwoStateCGM(
+		map[string]map[string][]string{"Agent1": {"s0": {"stay", "go"}, "s1": {"stay"}}},
+		map[string]map[string]string{"s0": {"stay": "s0"}, "s1": {"stay": "s1"}},
+		map[string][]string{"p": {"s0"}},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the missing transition.")
+	} else if !strings.Contains(err.Error(), "\"go\"") || !strings.Contains(err.Error(), "s0") {
+		t.Errorf("Expected the error to name the joint action and state, but received: %v", err)
+	}
+
+	// Create a game whose successor is not a state
+	_, err = createTwoStateCGM(
+		map[string]map[string][]string{"Agent1": {"s0": {"stay"}, "s1": {"stay"}}},
+		map[string]map[string]string{"s0": {"stay": "s2"}, "s1": {"stay": "s1"}},
+		map[string][]string{"p": {"s0"}},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the unknown successor \"s2\".")
+	}
+
+	// Create a game with a joint action for two agents
+	_, err = createTwoStateCGM(
+		map[string]map[string][]string{"Agent1": {"s0": {"stay"}, "s1": {"stay"}}},
+		map[string]map[string]string{"s0": {"stay": "s0", "stay, go": "s1"}, "s1": {"stay": "s1"}},
+		map[string][]string{"p": {"s0"}},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the joint action with two actions.")
+	}
+}
+
+func TestConcurrentGameModel_CheckV1(t *testing.T) {
+	// Create a game whose valuation uses an unknown atomic proposition
+	_, err := createTwoStateCGM(
+		map[string]map[string][]string{"Agent1": {"s0": {"stay"}, "s1": {"stay"}}},
+		map[string]map[string]string{"s0": {"stay": "s0"}, "s1": {"stay": "s1"}},
+		map[string][]string{"q": {"s0"}},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the unknown atomic proposition \"q\".")
+	}
+
+	// Create a game whose valuation uses an unknown state
+	_, err = createTwoStateCGM(
+		map[string]map[string][]string{"Agent1": {"s0": {"stay"}, "s1": {"stay"}}},
+		map[string]map[string]string{"s0": {"stay": "s0"}, "s1": {"stay": "s1"}},
+		map[string][]string{"p": {"s3"}},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the unknown state \"s3\".")
+	}
+}
+
+/*
+TestConcurrentGameModel_CheckAct1
+Description:
+	Verifies that an action which contains the separator of joint actions is rejected, since the keys of the
+	joint actions ("a, b", "c") and ("a", "b, c") would collide.
+*/
+func TestConcurrentGameModel_CheckAct1(t *testing.T) {
+	// Constants
+	_, err := CreateConcurrentGameModel(
+		[]string{"Agent1", "Agent2"},
+		[]string{"s0", "s1"},
+		[]string{},
+		[]string{"a", "c", "a, b", "b, c"},
+		map[string]map[string][]string{
+			"Agent1": {"s0": {"a, b", "a"}, "s1": {"a"}},
+			"Agent2": {"s0": {"c", "b, c"}, "s1": {"c"}},
+		},
+		map[string]map[string]string{
+			"s0": {"a, b, c": "s1", "a, c": "s0"},
+			"s1": {"a, c": "s1"},
+		},
+		map[string][]string{},
+	)
+
+	// Test
+	if err == nil {
+		t.Errorf("Expected an error for the actions which contain \", \".")
+	} else if err.Error() != "The action \"a, b\" contains the separator \", \" of joint actions." {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
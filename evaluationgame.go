@@ -0,0 +1,800 @@
+/*
+evaluationgame.go
+Description:
+	Evaluation games for ATL formulas on a Concurrent Game Model, following the game-theoretic semantics of ATL.
+	Two players, the Verifier and the Falsifier, argue about whether a formula holds in a state:
+	- the claimant of a disjunction chooses a disjunct and the other player chooses a conjunct of a conjunction,
+	- a negation swaps the roles of the players,
+	- for <<A>>X p the claimant chooses the actions of A, then the other player chooses the actions of the
+	  remaining agents and the game continues with p in the next state,
+	- for <<A>>G p the other player may challenge p in the current state or let the game take a step,
+	- for <<A>>(p U q) the claimant may claim q in the current state or continue, after which the other player
+	  may challenge p in the current state or let the game take a step, and
+	- for K[a] p, E[A] p and C[A] p the other player picks a state that the agents cannot rule out.
+	A play that stays in <<A>>G p forever is won by its claimant and a play that stays in <<A>>(p U q)
+	or <<A>>F q forever is lost by its claimant.
+*/
+package modelchecking
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+Type Definitions
+*/
+
+type EvaluationPlayer int
+
+const (
+	Verifier EvaluationPlayer = iota
+	Falsifier
+)
+
+type EvaluationPhase int
+
+const (
+	EvaluatePhase      EvaluationPhase = iota // The formula is about to be evaluated
+	ChallengePhase                            // <<A>>(p U q) only: the claimant continued and p may be challenged
+	CoalitionMovePhase                        // The claimant chooses the actions of the coalition
+	OpponentMovePhase                         // The other player chooses the actions of the remaining agents
+)
+
+/*
+EvaluationPosition
+Description:
+	A position of the evaluation game. The claimant of the position (see Claimant()) claims that
+	Formula holds in State; when Negated is true, the Falsifier is the claimant.
+	CoalitionMove holds the actions chosen for the coalition during the OpponentMovePhase.
+*/
+type EvaluationPosition struct {
+	State         CGMState
+	Formula       ATLFormula
+	Negated       bool
+	Phase         EvaluationPhase
+	CoalitionMove map[string]string
+}
+
+/*
+EvaluationMove
+Description:
+	A move of the evaluation game, with a description of the move in terms of the subformulas.
+*/
+type EvaluationMove struct {
+	Player      EvaluationPlayer
+	Description string
+	Next        EvaluationPosition
+}
+
+/*
+EvaluationGame
+Description:
+	An evaluation game which can be played move by move. History contains the positions of the play
+	and Explanations[i] explains the move from History[i] to History[i+1].
+*/
+type EvaluationGame struct {
+	Model        ConcurrentGameModel
+	History      []EvaluationPosition
+	Explanations []string
+	loopWinner   *EvaluationPlayer
+	solution     map[string]evaluationSolution
+	nextRank     int
+}
+
+/*
+evaluationSolution
+Description:
+	The player who has a winning strategy from a position and the rank of the position (see solve()).
+*/
+type evaluationSolution struct {
+	Winner EvaluationPlayer
+	Rank   int
+}
+
+/*
+Functions for EvaluationPlayer
+*/
+
+/*
+String
+Description:
+	Returns "Verifier" or "Falsifier".
+*/
+func (player EvaluationPlayer) String() string {
+	if player == Verifier {
+		return "Verifier"
+	}
+	return "Falsifier"
+}
+
+/*
+Opponent
+Description:
+	Returns the other player.
+*/
+func (player EvaluationPlayer) Opponent() EvaluationPlayer {
+	if player == Verifier {
+		return Falsifier
+	}
+	return Verifier
+}
+
+/*
+Functions for EvaluationPosition
+*/
+
+/*
+Claimant
+Description:
+	Returns the player who claims that the formula of the position holds in its state.
+*/
+func (position EvaluationPosition) Claimant() EvaluationPlayer {
+	if position.Negated {
+		return Falsifier
+	}
+	return Verifier
+}
+
+/*
+String
+Description:
+	Describes the position, e.g. "Verifier claims <<Agent1>>G Sober in Florence".
+*/
+func (position EvaluationPosition) String() string {
+	description := fmt.Sprintf("%v claims %v in %v", position.Claimant(), position.Formula, position.State)
+	switch position.Phase {
+	case ChallengePhase:
+		description += fmt.Sprintf(" (%v may challenge %v)", position.Claimant().Opponent(), position.Formula.Operands[0])
+	case CoalitionMovePhase:
+		description += fmt.Sprintf(" (%v chooses the actions of %v)", position.Claimant(), strings.Join(position.Formula.Coalition, ","))
+	case OpponentMovePhase:
+		description += fmt.Sprintf(" (%v answers %v)", position.Claimant().Opponent(), actionMapString(position.CoalitionMove))
+	}
+	return description
+}
+
+/*
+Key
+Description:
+	Returns a string which identifies the position.
+*/
+func (position EvaluationPosition) Key() string {
+	return fmt.Sprintf("%v|%v|%v|%v|%v", position.State, position.Formula, position.Negated, position.Phase, actionMapString(position.CoalitionMove))
+}
+
+/*
+normalized
+Description:
+	Removes negations and implications at the top of the formula of the position by swapping roles.
+	The second output is true if the roles were swapped.
+*/
+func (position EvaluationPosition) normalized() (EvaluationPosition, bool) {
+	swapped := false
+	for {
+		switch position.Formula.Operator {
+		case ATLOpNot:
+			position.Formula = position.Formula.Operands[0]
+			position.Negated = !position.Negated
+			swapped = !swapped
+		case ATLOpImplies:
+			position.Formula = ATLOr(ATLNot(position.Formula.Operands[0]), position.Formula.Operands[1])
+		default:
+			return position, swapped
+		}
+	}
+}
+
+/*
+actionMapString
+Description:
+	Prints a map from agent names to actions in a fixed order.
+*/
+func actionMapString(actionMap map[string]string) string {
+	var entries []string
+	for agentName, action := range actionMap {
+		entries = append(entries, fmt.Sprintf("%v: %v", agentName, action))
+	}
+	sort.Strings(entries)
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+/*
+Functions for EvaluationGame
+*/
+
+/*
+NewEvaluationGame
+Description:
+	Creates the evaluation game in which the Verifier claims that the formula holds in the state.
+Usage:
+	formula, _ := ParseATLFormula("<<Agent1>>G Sober")
+	game, err := cgm.NewEvaluationGame(CGMState{Name: "Florence"}, formula)
+*/
+func (cgm ConcurrentGameModel) NewEvaluationGame(state CGMState, formula ATLFormula) (*EvaluationGame, error) {
+	// Input Processing
+	if !state.In(cgm.St) {
+		return nil, fmt.Errorf("The state \"%v\" is not in the game.", state)
+	}
+
+	game := &EvaluationGame{Model: cgm, solution: make(map[string]evaluationSolution)}
+	if err := game.checkFormula(formula); err != nil {
+		return nil, err
+	}
+
+	initialPosition, _ := EvaluationPosition{State: state, Formula: formula}.normalized()
+	game.History = []EvaluationPosition{initialPosition}
+	return game, nil
+}
+
+/*
+Current
+Description:
+	Returns the current position of the play.
+*/
+func (game *EvaluationGame) Current() EvaluationPosition {
+	return game.History[len(game.History)-1]
+}
+
+/*
+Moves
+Description:
+	Returns the moves which are available in the position, together with the player who makes them.
+	Terminal positions (atomic propositions, true and false) have no moves.
+*/
+func (game *EvaluationGame) Moves(position EvaluationPosition) []EvaluationMove {
+	claimant := position.Claimant()
+	formula := position.Formula
+
+	switch formula.Operator {
+	case ATLOpAnd, ATLOpOr:
+		chooser := claimant
+		kind := "disjunct"
+		if formula.Operator == ATLOpAnd {
+			chooser = claimant.Opponent()
+			kind = "conjunct"
+		}
+
+		var moves []EvaluationMove
+		for _, operand := range formula.Operands {
+			next := position
+			next.Formula = operand
+			moves = append(moves, game.newMove(chooser, fmt.Sprintf("%v chooses the %v %v of %v in %v", chooser, kind, operand, formula, position.State), next))
+		}
+		return moves
+
+	case ATLOpNext, ATLOpAlways, ATLOpEventually, ATLOpUntil:
+		return game.temporalMoves(position)
+
+	case ATLOpKnows, ATLOpEveryoneKnows, ATLOpCommonKnowledge:
+		// The other player picks a state that the group considers possible
+		challenger := claimant.Opponent()
+		group, _ := game.Model.AgentsNamed(formula.Coalition...)
+		considered := game.Model.IndistinguishableStates(group, position.State)
+		if formula.Operator == ATLOpCommonKnowledge {
+			considered = game.Model.CommonKnowledgeStates(group, position.State)
+		}
+
+		var moves []EvaluationMove
+		for _, state := range considered {
+			next := position
+			next.State = state
+			next.Formula = formula.Operands[0]
+			moves = append(moves, game.newMove(
+				challenger,
+				fmt.Sprintf("%v picks %v, which %v cannot rule out in %v, so %v must hold there", challenger, state, strings.Join(formula.Coalition, ","), position.State, formula.Operands[0]),
+				next,
+			))
+		}
+		return moves
+
+	default:
+		return []EvaluationMove{}
+	}
+}
+
+/*
+temporalMoves
+Description:
+	Returns the moves of a position whose formula is X, G, F or U.
+*/
+func (game *EvaluationGame) temporalMoves(position EvaluationPosition) []EvaluationMove {
+	cgm := game.Model
+	claimant := position.Claimant()
+	formula := position.Formula
+
+	withPhase := func(phase EvaluationPhase) EvaluationPosition {
+		next := position
+		next.Phase = phase
+		next.CoalitionMove = nil
+		return next
+	}
+	withFormula := func(operand ATLFormula) EvaluationPosition {
+		next := withPhase(EvaluatePhase)
+		next.Formula = operand
+		return next
+	}
+
+	switch position.Phase {
+	case EvaluatePhase:
+		switch formula.Operator {
+		case ATLOpNext:
+			return []EvaluationMove{game.newMove(claimant, fmt.Sprintf("%v must pick the actions of %v to make %v hold in the next state", claimant, strings.Join(formula.Coalition, ","), formula.Operands[0]), withPhase(CoalitionMovePhase))}
+		case ATLOpAlways:
+			challenger := claimant.Opponent()
+			return []EvaluationMove{
+				game.newMove(challenger, fmt.Sprintf("%v challenges %v in %v", challenger, formula.Operands[0], position.State), withFormula(formula.Operands[0])),
+				game.newMove(challenger, fmt.Sprintf("%v accepts %v in %v for now and lets the game take a step", challenger, formula.Operands[0], position.State), withPhase(CoalitionMovePhase)),
+			}
+		case ATLOpEventually:
+			return []EvaluationMove{
+				game.newMove(claimant, fmt.Sprintf("%v claims that %v already holds in %v", claimant, formula.Operands[0], position.State), withFormula(formula.Operands[0])),
+				game.newMove(claimant, fmt.Sprintf("%v postpones %v and lets the game take a step", claimant, formula.Operands[0]), withPhase(CoalitionMovePhase)),
+			}
+		default:
+			return []EvaluationMove{
+				game.newMove(claimant, fmt.Sprintf("%v claims that %v already holds in %v", claimant, formula.Operands[1], position.State), withFormula(formula.Operands[1])),
+				game.newMove(claimant, fmt.Sprintf("%v postpones %v, so %v must hold in %v", claimant, formula.Operands[1], formula.Operands[0], position.State), withPhase(ChallengePhase)),
+			}
+		}
+
+	case ChallengePhase:
+		challenger := claimant.Opponent()
+		return []EvaluationMove{
+			game.newMove(challenger, fmt.Sprintf("%v challenges %v in %v", challenger, formula.Operands[0], position.State), withFormula(formula.Operands[0])),
+			game.newMove(challenger, fmt.Sprintf("%v accepts %v in %v and lets the game take a step", challenger, formula.Operands[0], position.State), withPhase(CoalitionMovePhase)),
+		}
+
+	case CoalitionMovePhase:
+		coalition, _ := cgm.AgentsNamed(formula.Coalition...)
+		var moves []EvaluationMove
+		for _, coalitionMove := range cgm.actionProfiles(coalition, position.State) {
+			next := withPhase(OpponentMovePhase)
+			next.CoalitionMove = make(map[string]string)
+			for agentIndex, agent := range coalition {
+				next.CoalitionMove[agent.Name] = coalitionMove[agentIndex]
+			}
+			moves = append(moves, game.newMove(claimant, fmt.Sprintf("%v chooses the actions %v for the coalition", claimant, actionMapString(next.CoalitionMove)), next))
+		}
+		return moves
+
+	default:
+		// OpponentMovePhase
+		answerer := claimant.Opponent()
+		var opponents []CGMAgent
+		for _, agent := range cgm.Agents {
+			if _, inCoalition := position.CoalitionMove[agent.Name]; !inCoalition {
+				opponents = append(opponents, agent)
+			}
+		}
+
+		var moves []EvaluationMove
+		for _, opponentMove := range cgm.actionProfiles(opponents, position.State) {
+			jointAction := make(JointAction, len(cgm.Agents))
+			for agentIndex, agent := range cgm.Agents {
+				jointAction[agentIndex] = position.CoalitionMove[agent.Name]
+			}
+			for opponentIndex, agent := range opponents {
+				agentIndex, _ := cgm.agentIndex(agent)
+				jointAction[agentIndex] = opponentMove[opponentIndex]
+			}
+
+			successor, found := cgm.Successor(position.State, jointAction)
+			if !found {
+				continue
+			}
+
+			next := withPhase(EvaluatePhase)
+			next.State = successor
+			nextFormulaText := fmt.Sprintf("%v", formula)
+			if formula.Operator == ATLOpNext {
+				next.Formula = formula.Operands[0]
+				nextFormulaText = fmt.Sprintf("%v", formula.Operands[0])
+			}
+			moves = append(moves, game.newMove(
+				answerer,
+				fmt.Sprintf("%v answers with the joint action \"%v\", which leads to %v where %v must hold", answerer, jointAction, successor, nextFormulaText),
+				next,
+			))
+		}
+		return moves
+	}
+}
+
+/*
+newMove
+Description:
+	Creates a move whose next position is normalized and mentions any swap of roles in its description.
+*/
+func (game *EvaluationGame) newMove(player EvaluationPlayer, description string, next EvaluationPosition) EvaluationMove {
+	next, swapped := next.normalized()
+	if swapped {
+		description += fmt.Sprintf("; because of the negation, %v now claims %v", next.Claimant(), next.Formula)
+	}
+	return EvaluationMove{Player: player, Description: description, Next: next}
+}
+
+/*
+Mover
+Description:
+	Returns the player who moves in the current position. The second output is false if the play is over.
+*/
+func (game *EvaluationGame) Mover() (EvaluationPlayer, bool) {
+	if over, _ := game.IsOver(); over {
+		return Verifier, false
+	}
+	return game.Moves(game.Current())[0].Player, true
+}
+
+/*
+IsOver
+Description:
+	Determines if the play is over and, if so, who won it. A play is over when it reaches a terminal
+	position or when it repeats a position, which means that it would stay in a G, F or U formula forever.
+*/
+func (game *EvaluationGame) IsOver() (bool, EvaluationPlayer) {
+	if game.loopWinner != nil {
+		return true, *game.loopWinner
+	}
+
+	current := game.Current()
+	if len(game.Moves(current)) > 0 {
+		return false, Verifier
+	}
+
+	// Terminal position
+	return true, game.terminalWinner(current)
+}
+
+/*
+Move
+Description:
+	Plays the move with the given index in Moves(Current()) and returns its explanation.
+*/
+func (game *EvaluationGame) Move(moveIndex int) (string, error) {
+	if over, winner := game.IsOver(); over {
+		return "", fmt.Errorf("The play is over; %v won.", winner)
+	}
+
+	moves := game.Moves(game.Current())
+	if moveIndex < 0 || moveIndex >= len(moves) {
+		return "", fmt.Errorf("The move index %v is not between 0 and %v.", moveIndex, len(moves)-1)
+	}
+
+	move := moves[moveIndex]
+	explanation := move.Description
+
+	// Detect a play that stays in a temporal formula forever
+	if move.Next.Phase == EvaluatePhase {
+		for _, position := range game.History {
+			if position.Key() != move.Next.Key() {
+				continue
+			}
+			winner := infiniteWinner(move.Next)
+			game.loopWinner = &winner
+			explanation += fmt.Sprintf(". The position repeats, so the play stays in %v forever and %v wins", move.Next.Formula, winner)
+			break
+		}
+	}
+
+	game.History = append(game.History, move.Next)
+	game.Explanations = append(game.Explanations, explanation)
+	return explanation, nil
+}
+
+/*
+Undo
+Description:
+	Takes back the last move of the play.
+*/
+func (game *EvaluationGame) Undo() error {
+	if len(game.History) == 1 {
+		return errors.New("There is no move to undo.")
+	}
+
+	game.History = game.History[:len(game.History)-1]
+	game.Explanations = game.Explanations[:len(game.Explanations)-1]
+	game.loopWinner = nil
+	return nil
+}
+
+/*
+Winner
+Description:
+	Solves the game: returns the player who has a winning strategy from the position.
+	The game is solved on its own positions (see solve()).
+Usage:
+	winner, err := game.Winner(game.Current())
+*/
+func (game *EvaluationGame) Winner(position EvaluationPosition) (EvaluationPlayer, error) {
+	position, _ = position.normalized()
+	if err := game.solve(position); err != nil {
+		return Verifier, err
+	}
+	return game.solution[position.Key()].Winner, nil
+}
+
+/*
+solve
+Description:
+	Solves the game on the positions that are reachable from the given position and stores the winner
+	of each position together with a rank. Terminal positions are decided by IsOver()'s rules and the
+	other positions by attractors: a position is won by its mover if some move leads to a position won
+	by the mover, and by the other player if every move does.
+	The positions which remain undecided can be kept in their temporal formula forever by both players.
+	Since a play leaves a temporal formula only towards its subformulas, the undecided positions of a
+	formula whose subformulas are decided are won by the player who wins the infinite plays
+	(see Move()), after which the attractors are extended again.
+	Every position decided by an attractor has a move to a position of lower rank, which WinningMove()
+	uses to make progress.
+*/
+func (game *EvaluationGame) solve(root EvaluationPosition) error {
+	// Input Processing
+	if _, solved := game.solution[root.Key()]; solved {
+		return nil
+	}
+
+	err := game.checkPosition(root)
+	if err != nil {
+		return err
+	}
+
+	// Constants
+	positions := []EvaluationPosition{root}
+	indexOf := map[string]int{root.Key(): 0}
+	var successors [][]int
+	var movers []EvaluationPlayer
+	for index := 0; index < len(positions); index++ {
+		moves := game.Moves(positions[index])
+		var next []int
+		for _, move := range moves {
+			nextIndex, seen := indexOf[move.Next.Key()]
+			if !seen {
+				nextIndex = len(positions)
+				indexOf[move.Next.Key()] = nextIndex
+				positions = append(positions, move.Next)
+			}
+			next = append(next, nextIndex)
+		}
+		successors = append(successors, next)
+
+		mover := Verifier
+		if len(moves) > 0 {
+			mover = moves[0].Player
+		}
+		movers = append(movers, mover)
+	}
+
+	predecessors := make([][]int, len(positions))
+	remaining := make([]int, len(positions))
+	for index, next := range successors {
+		for _, nextIndex := range next {
+			predecessors[nextIndex] = append(predecessors[nextIndex], index)
+		}
+		remaining[index] = len(next)
+	}
+
+	// Algorithm
+	resolved := make([]bool, len(positions))
+	var queue []int
+	decide := func(index int, winner EvaluationPlayer) {
+		resolved[index] = true
+		game.solution[positions[index].Key()] = evaluationSolution{Winner: winner, Rank: game.nextRank}
+		game.nextRank++
+		queue = append(queue, index)
+	}
+
+	for index, position := range positions {
+		if solution, solved := game.solution[position.Key()]; solved {
+			resolved[index] = true
+			game.solution[position.Key()] = solution
+			queue = append(queue, index)
+		} else if len(successors[index]) == 0 {
+			decide(index, game.terminalWinner(position))
+		}
+	}
+
+	for {
+		// Attractors
+		for len(queue) > 0 {
+			index := queue[0]
+			queue = queue[1:]
+			winner := game.solution[positions[index].Key()].Winner
+			for _, predecessor := range predecessors[index] {
+				if resolved[predecessor] {
+					continue
+				}
+				remaining[predecessor]--
+				if movers[predecessor] == winner || remaining[predecessor] == 0 {
+					decide(predecessor, winner)
+				}
+			}
+		}
+
+		// Infinite plays in a temporal formula whose subformulas are decided
+		stratum := func(position EvaluationPosition) string {
+			return fmt.Sprintf("%v|%v", position.Formula, position.Negated)
+		}
+		blocked := make(map[string]bool)
+		var undecided []int
+		for index, next := range successors {
+			if resolved[index] {
+				continue
+			}
+			undecided = append(undecided, index)
+			for _, nextIndex := range next {
+				if !resolved[nextIndex] && stratum(positions[nextIndex]) != stratum(positions[index]) {
+					blocked[stratum(positions[index])] = true
+				}
+			}
+		}
+		if len(undecided) == 0 {
+			return nil
+		}
+
+		for _, index := range undecided {
+			if blocked[stratum(positions[index])] {
+				continue
+			}
+			chosen := stratum(positions[index])
+			for _, otherIndex := range undecided {
+				if stratum(positions[otherIndex]) == chosen {
+					decide(otherIndex, infiniteWinner(positions[otherIndex]))
+				}
+			}
+			break
+		}
+	}
+}
+
+/*
+infiniteWinner
+Description:
+	Returns the winner of a play which stays in the temporal formula of the position forever:
+	the claimant of G wins and the claimant of F or U loses.
+*/
+func infiniteWinner(position EvaluationPosition) EvaluationPlayer {
+	if position.Formula.Operator == ATLOpAlways {
+		return position.Claimant()
+	}
+	return position.Claimant().Opponent()
+}
+
+/*
+terminalWinner
+Description:
+	Returns the winner of a position without moves.
+*/
+func (game *EvaluationGame) terminalWinner(position EvaluationPosition) EvaluationPlayer {
+	claimant := position.Claimant()
+	switch position.Formula.Operator {
+	case ATLOpTrue:
+		return claimant
+	case ATLOpAtom:
+		if position.Formula.Atom.In(game.Model.Labels(position.State)) {
+			return claimant
+		}
+		return claimant.Opponent()
+	case ATLOpAnd:
+		// The empty conjunction is true
+		return claimant
+	default:
+		// false, the empty disjunction and positions without successors
+		return claimant.Opponent()
+	}
+}
+
+/*
+checkPosition
+Description:
+	Checks that the state of the position is in the game and that its formula and coalition move
+	only mention atomic propositions and agents of the game.
+*/
+func (game *EvaluationGame) checkPosition(position EvaluationPosition) error {
+	if !position.State.In(game.Model.St) {
+		return fmt.Errorf("The state \"%v\" is not in the game.", position.State)
+	}
+
+	for agentName := range position.CoalitionMove {
+		if _, err := game.Model.AgentsNamed(agentName); err != nil {
+			return fmt.Errorf("There was an issue with the coalition move of %v: %v", position, err)
+		}
+	}
+
+	return game.checkFormula(position.Formula)
+}
+
+/*
+checkFormula
+Description:
+	Checks that the formula only mentions atomic propositions and agents of the game.
+*/
+func (game *EvaluationGame) checkFormula(formula ATLFormula) error {
+	switch formula.Operator {
+	case ATLOpTrue, ATLOpFalse:
+		return nil
+
+	case ATLOpAtom:
+		if !formula.Atom.In(game.Model.Pi) {
+			return fmt.Errorf("The atomic proposition \"%v\" is not in the game's set of atomic propositions.", formula.Atom)
+		}
+		return nil
+
+	case ATLOpNot, ATLOpAnd, ATLOpOr, ATLOpImplies:
+
+	case ATLOpNext, ATLOpAlways, ATLOpEventually, ATLOpUntil:
+		if _, err := game.Model.AgentsNamed(formula.Coalition...); err != nil {
+			return fmt.Errorf("There was an issue with the coalition of %v: %v", formula, err)
+		}
+
+	case ATLOpKnows, ATLOpEveryoneKnows, ATLOpCommonKnowledge:
+		if _, err := game.Model.AgentsNamed(formula.Coalition...); err != nil {
+			return fmt.Errorf("There was an issue with the agents of %v: %v", formula, err)
+		}
+
+	default:
+		return fmt.Errorf("Unrecognized ATL operator %v.", formula.Operator)
+	}
+
+	for _, operand := range formula.Operands {
+		if err := game.checkFormula(operand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+WinningMove
+Description:
+	Returns the index of a move in Moves(Current()) which keeps the mover on a winning path.
+	The second output is false if the mover cannot win or the play is over.
+	The mover picks a winning move of lowest rank, so that in F, U and the challenges of G the
+	winner makes progress towards leaving the temporal formula and the play does not stay there forever.
+*/
+func (game *EvaluationGame) WinningMove() (int, bool) {
+	mover, notOver := game.Mover()
+	if !notOver {
+		return -1, false
+	}
+
+	current := game.Current()
+	if winner, err := game.Winner(current); err != nil || winner != mover {
+		return -1, false
+	}
+
+	bestIndex, bestRank := -1, -1
+	for moveIndex, move := range game.Moves(current) {
+		solution := game.solution[move.Next.Key()]
+		if solution.Winner != mover {
+			continue
+		}
+
+		if bestIndex == -1 || solution.Rank < bestRank {
+			bestIndex, bestRank = moveIndex, solution.Rank
+		}
+	}
+
+	return bestIndex, bestIndex != -1
+}
+
+/*
+Positions
+Description:
+	Constructs the evaluation game: returns every position which is reachable from the initial position.
+*/
+func (game *EvaluationGame) Positions() []EvaluationPosition {
+	positions := []EvaluationPosition{game.History[0]}
+	seen := map[string]bool{game.History[0].Key(): true}
+	for index := 0; index < len(positions); index++ {
+		for _, move := range game.Moves(positions[index]) {
+			if !seen[move.Next.Key()] {
+				seen[move.Next.Key()] = true
+				positions = append(positions, move.Next)
+			}
+		}
+	}
+	return positions
+}
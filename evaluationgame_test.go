@@ -0,0 +1,236 @@
+/*
+evaluationgame_test.go
+Description:
+	Tests the functions and objects created in evaluationgame.go
+*/
+package modelchecking
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+playOptimally
+Description:
+	Plays the evaluation game where each player makes a winning move whenever it has one
+	and its first move otherwise. Returns the winner of the play.
+*/
+func playOptimally(t *testing.T, game *EvaluationGame, maxMoves int) EvaluationPlayer {
+	for moveCount := 0; moveCount < maxMoves; moveCount++ {
+		if over, winner := game.IsOver(); over {
+			return winner
+		}
+
+		moveIndex, found := game.WinningMove()
+		if !found {
+			moveIndex = 0
+		}
+		if _, err := game.Move(moveIndex); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	t.Errorf("The play did not end after %v moves: %v", maxMoves, game.Explanations)
+	return Verifier
+}
+
+/*
+TestEvaluationGame_Winner1
+Description:
+	Verifies that the Verifier wins the evaluation game exactly in the states that satisfy the formula,
+	and that optimal play realizes the solution.
+*/
+func TestEvaluationGame_Winner1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formulaStrings := []string{
+		"<<Agent1>>G Sober",
+		"<<Agent2>>F Drunk",
+		"<<Agent2>>(Sober U Drunk)",
+		"<<Agent1>>X Drunk & !<<Agent2>>X Drunk",
+		"<<Agent1>>X <<Agent1>>G Drunk",
+		"Sober -> <<Agent1,Agent2>>F Drunk",
+	}
+
+	// Algorithm
+	for _, formulaString := range formulaStrings {
+		formula, _ := ParseATLFormula(formulaString)
+		sat, _ := cgm.SatisfyingStates(formula)
+
+		for _, state := range cgm.St {
+			game, err := cgm.NewEvaluationGame(state, formula)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				continue
+			}
+
+			expectedWinner := Falsifier
+			if state.In(sat) {
+				expectedWinner = Verifier
+			}
+
+			winner, err := game.Winner(game.Current())
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if winner != expectedWinner {
+				t.Errorf("Expected %v to win the game for %v in %v.", expectedWinner, formula, state)
+			}
+
+			if winner := playOptimally(t, game, 100); winner != expectedWinner {
+				t.Errorf("Expected %v to win the play for %v in %v, but %v won: %v", expectedWinner, formula, state, winner, game.Explanations)
+			}
+		}
+	}
+}
+
+/*
+TestEvaluationGame_Move1
+Description:
+	Plays <<Agent1>>G Sober from Venice and verifies that the explanations mention the subformulas
+	and that the Falsifier wins by challenging Sober in Rome.
+*/
+func TestEvaluationGame_Move1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent1>>G Sober")
+
+	// Algorithm
+	game, _ := cgm.NewEvaluationGame(CGMState{Name: "Venice"}, formula)
+	if mover, _ := game.Mover(); mover != Falsifier {
+		t.Errorf("Expected the Falsifier to decide whether to challenge, but %v moves.", mover)
+	}
+
+	playOptimally(t, game, 20)
+
+	if over, winner := game.IsOver(); !over || winner != Falsifier {
+		t.Errorf("Expected the Falsifier to win, but the play ended with %v (%v).", winner, over)
+	}
+
+	lastExplanation := game.Explanations[len(game.Explanations)-1]
+	if !strings.Contains(lastExplanation, "challenges Sober in Rome") {
+		t.Errorf("Expected the last move to challenge Sober in Rome, but found \"%v\".", lastExplanation)
+	}
+}
+
+/*
+TestEvaluationGame_Move2
+Description:
+	Verifies that a negation swaps the roles of the players and that invalid moves are rejected.
+*/
+func TestEvaluationGame_Move2(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("Drunk | !<<Agent1>>G Sober")
+
+	// Algorithm
+	game, _ := cgm.NewEvaluationGame(CGMState{Name: "Florence"}, formula)
+	if _, err := game.Move(5); err == nil {
+		t.Errorf("Expected an error for an invalid move index.")
+	}
+
+	explanation, err := game.Move(1)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(explanation, "Falsifier now claims") || game.Current().Claimant() != Falsifier {
+		t.Errorf("Expected the roles to swap, but found \"%v\".", explanation)
+	}
+
+	if err = game.Undo(); err != nil || len(game.History) != 1 {
+		t.Errorf("Expected Undo() to return to the initial position: %v", err)
+	}
+}
+
+/*
+TestEvaluationGame_Positions1
+Description:
+	Verifies that the constructed game contains the phases of the Until formula.
+*/
+func TestEvaluationGame_Positions1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent2>>(Sober U Drunk)")
+
+	// Algorithm
+	game, _ := cgm.NewEvaluationGame(CGMState{Name: "Venice"}, formula)
+	phases := make(map[EvaluationPhase]bool)
+	for _, position := range game.Positions() {
+		phases[position.Phase] = true
+	}
+
+	for _, phase := range []EvaluationPhase{EvaluatePhase, ChallengePhase, CoalitionMovePhase, OpponentMovePhase} {
+		if !phases[phase] {
+			t.Errorf("Expected a position in phase %v.", phase)
+		}
+	}
+}
+
+/*
+TestEvaluationGame_Winner2
+Description:
+	Verifies that the Falsifier wins the game for C[Player,Env] middle in left by picking the state win.
+*/
+func TestEvaluationGame_Winner2(t *testing.T) {
+	// Constants
+	cgm, _ := CreateShellGameCGM()
+	formula, _ := ParseATLFormula("C[Player,Env] middle")
+
+	// Algorithm
+	game, err := cgm.NewEvaluationGame(CGMState{Name: "left"}, formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if winner := playOptimally(t, game, 10); winner != Falsifier {
+		t.Errorf("Expected the Falsifier to win, but %v won.", winner)
+	}
+
+	if game.Current().State.Name != "win" {
+		t.Errorf("Expected the Falsifier to pick win, but the play ended in %v.", game.Current())
+	}
+}
+
+/*
+TestEvaluationGame_Winner3
+Description:
+	Verifies that Winner() rejects positions whose state or coalition is not in the game
+	and solves positions with several temporal formulas.
+*/
+func TestEvaluationGame_Winner3(t *testing.T) {
+	// Constants
+	cgm, _ := CreateSimpleCGM()
+	formula, _ := ParseATLFormula("<<Agent1>>G <<Agent2>>F Drunk")
+	unknownAgent, _ := ParseATLFormula("<<Agent3>>F Drunk")
+
+	// Algorithm
+	game, err := cgm.NewEvaluationGame(CGMState{Name: "Venice"}, formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err = game.Winner(EvaluationPosition{State: CGMState{Name: "Paris"}, Formula: formula}); err == nil {
+		t.Errorf("Expected an error for a state which is not in the game.")
+	}
+
+	if _, err = game.Winner(EvaluationPosition{State: CGMState{Name: "Venice"}, Formula: unknownAgent}); err == nil {
+		t.Errorf("Expected an error for an agent which is not in the game.")
+	}
+
+	sat, _ := cgm.SatisfyingStates(formula)
+	for _, position := range game.Positions() {
+		if position.Phase != EvaluatePhase || position.Formula.String() != formula.String() {
+			continue
+		}
+
+		expectedWinner := Falsifier
+		if position.State.In(sat) {
+			expectedWinner = Verifier
+		}
+		if winner, err := game.Winner(position); err != nil || winner != expectedWinner {
+			t.Errorf("Expected %v to win from %v, but found %v (%v).", expectedWinner, position, winner, err)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+/*
+arena.go
+Description:
+	Defines a two-player turn-based parity game: an arena whose vertices belong to one of the players
+	Even and Odd and carry a priority. A play is won by Even if the largest priority that it visits
+	infinitely often is even (the same convention as omega.Parity), and by Odd otherwise.
+*/
+
+package games
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Player
+Description:
+	One of the two players of a parity game.
+*/
+type Player int
+
+const (
+	Even Player = iota
+	Odd
+)
+
+/*
+Arena
+Description:
+	A parity game. Owner gives the player that chooses the successor of each vertex, Priority gives
+	the priority of each vertex (0 if it has none) and E gives the successors of each vertex.
+*/
+type Arena struct {
+	V        []Vertex
+	Owner    map[Vertex]Player
+	Priority map[Vertex]int
+	E        map[Vertex][]Vertex
+}
+
+/*
+Vertex
+Description:
+	A vertex of an arena.
+*/
+type Vertex struct {
+	Name  string
+	Arena *Arena
+}
+
+/*
+Functions for Player
+*/
+
+/*
+String
+Description:
+	Provides the name of the player.
+*/
+func (player Player) String() string {
+	switch player {
+	case Even:
+		return "Even"
+	case Odd:
+		return "Odd"
+	default:
+		return fmt.Sprintf("Player(%v)", int(player))
+	}
+}
+
+/*
+Opponent
+Description:
+	Returns the other player.
+*/
+func (player Player) Opponent() Player {
+	if player == Even {
+		return Odd
+	}
+	return Even
+}
+
+/*
+Wins
+Description:
+	Determines if the player wins a play whose largest priority visited infinitely often is priority.
+*/
+func (player Player) Wins(priority int) bool {
+	return Player(priority%2) == player
+}
+
+/*
+Functions for Vertex
+*/
+
+/*
+String
+Description:
+	Provides the name of the vertex.
+*/
+func (vertexIn Vertex) String() string {
+	return vertexIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the names of the two vertices are equal.
+*/
+func (vertexIn Vertex) Equals(vertex2 Vertex) bool {
+	return vertexIn.Name == vertex2.Name
+}
+
+/*
+In
+Description:
+	Determines whether or not the vertex is in a slice of vertices.
+*/
+func (vertexIn Vertex) In(vertexList []Vertex) bool {
+	for _, tempVertex := range vertexList {
+		if vertexIn.Equals(tempVertex) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Functions for Arena
+*/
+
+/*
+GetArena
+Description:
+	Creates an Arena from simple strings and maps of strings.
+	Vertices which are missing from ownerMap belong to Even and vertices which are missing from priorityMap have priority 0.
+Usage:
+	arena, err := GetArena(
+		[]string{"v0", "v1"},
+		map[string]Player{"v0": Even, "v1": Odd},
+		map[string]int{"v0": 2, "v1": 1},
+		map[string][]string{"v0": {"v0", "v1"}, "v1": {"v0"}},
+	)
+*/
+func GetArena(vertexNames []string, ownerMap map[string]Player, priorityMap map[string]int, edgeMap map[string][]string) (Arena, error) {
+	arena := &Arena{
+		Owner:    make(map[Vertex]Player),
+		Priority: make(map[Vertex]int),
+		E:        make(map[Vertex][]Vertex),
+	}
+
+	for _, name := range vertexNames {
+		v := Vertex{Name: name, Arena: arena}
+		if v.In(arena.V) {
+			return Arena{}, fmt.Errorf("The vertex \"%v\" was given more than once.", name)
+		}
+		arena.V = append(arena.V, v)
+	}
+
+	for name, owner := range ownerMap {
+		arena.Owner[Vertex{Name: name, Arena: arena}] = owner
+	}
+
+	for name, priority := range priorityMap {
+		arena.Priority[Vertex{Name: name, Arena: arena}] = priority
+	}
+
+	for name, successorNames := range edgeMap {
+		var successors []Vertex
+		for _, successorName := range successorNames {
+			successors = append(successors, Vertex{Name: successorName, Arena: arena})
+		}
+		arena.E[Vertex{Name: name, Arena: arena}] = successors
+	}
+
+	return *arena, arena.Check()
+}
+
+/*
+VerticesNamed
+Description:
+	Returns the vertices of the arena with the given names (names which are not vertices are skipped).
+*/
+func (arena Arena) VerticesNamed(names ...string) []Vertex {
+	var verticesOut []Vertex
+	for _, name := range names {
+		for _, v := range arena.V {
+			if v.Name == name {
+				verticesOut = append(verticesOut, v)
+			}
+		}
+	}
+	return verticesOut
+}
+
+/*
+OwnerOf
+Description:
+	Returns the player which chooses the successor of v (Even if it has no owner).
+*/
+func (arena Arena) OwnerOf(v Vertex) Player {
+	if owner, found := arena.Owner[v]; found {
+		return owner
+	}
+	for tempVertex, owner := range arena.Owner {
+		if tempVertex.Equals(v) {
+			return owner
+		}
+	}
+	return Even
+}
+
+/*
+PriorityOf
+Description:
+	Returns the priority of v (0 if it has none).
+*/
+func (arena Arena) PriorityOf(v Vertex) int {
+	if priority, found := arena.Priority[v]; found {
+		return priority
+	}
+	for tempVertex, priority := range arena.Priority {
+		if tempVertex.Equals(v) {
+			return priority
+		}
+	}
+	return 0
+}
+
+/*
+Successors
+Description:
+	Returns the successors of v.
+*/
+func (arena Arena) Successors(v Vertex) []Vertex {
+	if successors, found := arena.E[v]; found {
+		return successors
+	}
+	for tempVertex, successors := range arena.E {
+		if tempVertex.Equals(v) {
+			return successors
+		}
+	}
+	return []Vertex{}
+}
+
+/*
+Check
+Description:
+	Checks that the owners, priorities and edges only refer to vertices of the arena, that the owners are
+	Even or Odd, that the priorities are nonnegative and that every vertex has at least one successor.
+*/
+func (arena Arena) Check() error {
+	if len(arena.V) == 0 {
+		return errors.New("The arena has no vertices.")
+	}
+
+	for v, owner := range arena.Owner {
+		if !v.In(arena.V) {
+			return fmt.Errorf("The vertex \"%v\" has an owner, but is not a vertex of the arena.", v)
+		}
+		if owner != Even && owner != Odd {
+			return fmt.Errorf("The vertex \"%v\" has the unknown owner %v.", v, owner)
+		}
+	}
+
+	for v, priority := range arena.Priority {
+		if !v.In(arena.V) {
+			return fmt.Errorf("The vertex \"%v\" has a priority, but is not a vertex of the arena.", v)
+		}
+		if priority < 0 {
+			return fmt.Errorf("The vertex \"%v\" has the negative priority %v.", v, priority)
+		}
+	}
+
+	for v, successors := range arena.E {
+		if !v.In(arena.V) {
+			return fmt.Errorf("The vertex \"%v\" has successors, but is not a vertex of the arena.", v)
+		}
+		for _, successor := range successors {
+			if !successor.In(arena.V) {
+				return fmt.Errorf("The successor \"%v\" of vertex \"%v\" is not a vertex of the arena.", successor, v)
+			}
+		}
+	}
+
+	for _, v := range arena.V {
+		if len(arena.Successors(v)) == 0 {
+			return fmt.Errorf("The vertex \"%v\" has no successors.", v)
+		}
+	}
+
+	return nil
+}
+
+/*
+MaxPriority
+Description:
+	Returns the largest priority of the arena.
+*/
+func (arena Arena) MaxPriority() int {
+	maxPriority := 0
+	for _, v := range arena.V {
+		if priority := arena.PriorityOf(v); priority > maxPriority {
+			maxPriority = priority
+		}
+	}
+	return maxPriority
+}
+
+/*
+Dual
+Description:
+	Returns the arena in which the players swap roles: every owner is swapped and every priority is
+	increased by one, so that Even wins a play of the dual if and only if Odd wins it in the original.
+*/
+func (arena Arena) Dual() (Arena, error) {
+	var vertexNames []string
+	ownerMap := make(map[string]Player)
+	priorityMap := make(map[string]int)
+	edgeMap := make(map[string][]string)
+	for _, v := range arena.V {
+		vertexNames = append(vertexNames, v.Name)
+		ownerMap[v.Name] = arena.OwnerOf(v).Opponent()
+		priorityMap[v.Name] = arena.PriorityOf(v) + 1
+		for _, successor := range arena.Successors(v) {
+			edgeMap[v.Name] = append(edgeMap[v.Name], successor.Name)
+		}
+	}
+
+	return GetArena(vertexNames, ownerMap, priorityMap, edgeMap)
+}
+
+/*
+Attractor
+Description:
+	Returns the vertices from which the player can force a visit to target, together with an attractor
+	strategy: for each vertex of the player in the attractor (but not in target), a successor which is
+	closer to target.
+*/
+func (arena Arena) Attractor(player Player, target []Vertex) ([]Vertex, map[Vertex]Vertex) {
+	g := arena.indexed()
+	inSet := make([]bool, len(g.vertices))
+	for v := range inSet {
+		inSet[v] = true
+	}
+
+	targetMask := make([]bool, len(g.vertices))
+	for _, v := range target {
+		if index, found := g.indexOf[v.Name]; found {
+			targetMask[index] = true
+		}
+	}
+
+	attractorMask, strategy := g.attractor(player, targetMask, inSet)
+	return g.verticesOf(attractorMask), g.strategyOf(strategy)
+}
+
+/*
+indexedGame
+Description:
+	An arena in which the vertices are replaced by their indices in V. The solvers work on this form.
+*/
+type indexedGame struct {
+	vertices     []Vertex
+	indexOf      map[string]int
+	owner        []Player
+	priority     []int
+	successors   [][]int
+	predecessors [][]int
+}
+
+/*
+indexed
+Description:
+	Creates the indexedGame of the arena.
+*/
+func (arena Arena) indexed() indexedGame {
+	g := indexedGame{
+		vertices: arena.V,
+		indexOf:  make(map[string]int),
+	}
+	for index, v := range arena.V {
+		g.indexOf[v.Name] = index
+	}
+
+	g.successors = make([][]int, len(arena.V))
+	g.predecessors = make([][]int, len(arena.V))
+	for index, v := range arena.V {
+		g.owner = append(g.owner, arena.OwnerOf(v))
+		g.priority = append(g.priority, arena.PriorityOf(v))
+		for _, successor := range arena.Successors(v) {
+			successorIndex := g.indexOf[successor.Name]
+			g.successors[index] = append(g.successors[index], successorIndex)
+			g.predecessors[successorIndex] = append(g.predecessors[successorIndex], index)
+		}
+	}
+
+	return g
+}
+
+/*
+attractor
+Description:
+	Computes the attractor of the player to target within the subgame inSet (a set in which every vertex
+	has a successor). The strategy maps each vertex of the player which is attracted (but not in target)
+	to a successor; the other entries are -1.
+*/
+func (g indexedGame) attractor(player Player, target []bool, inSet []bool) ([]bool, []int) {
+	attractorMask := make([]bool, len(g.vertices))
+	strategy := make([]int, len(g.vertices))
+	remaining := make([]int, len(g.vertices))
+	var queue []int
+	for v := range g.vertices {
+		strategy[v] = -1
+		if !inSet[v] {
+			continue
+		}
+		for _, successor := range g.successors[v] {
+			if inSet[successor] {
+				remaining[v]++
+			}
+		}
+		if target[v] {
+			attractorMask[v] = true
+			queue = append(queue, v)
+		}
+	}
+
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+
+		for _, predecessor := range g.predecessors[v] {
+			if !inSet[predecessor] || attractorMask[predecessor] {
+				continue
+			}
+
+			if g.owner[predecessor] == player {
+				attractorMask[predecessor] = true
+				strategy[predecessor] = v
+				queue = append(queue, predecessor)
+				continue
+			}
+
+			remaining[predecessor]--
+			if remaining[predecessor] == 0 {
+				attractorMask[predecessor] = true
+				queue = append(queue, predecessor)
+			}
+		}
+	}
+
+	return attractorMask, strategy
+}
+
+/*
+verticesOf
+Description:
+	Returns the vertices whose entries in mask are true.
+*/
+func (g indexedGame) verticesOf(mask []bool) []Vertex {
+	verticesOut := []Vertex{}
+	for v, included := range mask {
+		if included {
+			verticesOut = append(verticesOut, g.vertices[v])
+		}
+	}
+	return verticesOut
+}
+
+/*
+strategyOf
+Description:
+	Converts a strategy on indices (with -1 for "no choice") to a map of vertices.
+*/
+func (g indexedGame) strategyOf(strategy []int) map[Vertex]Vertex {
+	strategyOut := make(map[Vertex]Vertex)
+	for v, successor := range strategy {
+		if successor >= 0 {
+			strategyOut[g.vertices[v]] = g.vertices[successor]
+		}
+	}
+	return strategyOut
+}
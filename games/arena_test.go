@@ -0,0 +1,126 @@
+/*
+arena_test.go
+Description:
+	Tests the functions and objects created in arena.go
+*/
+
+package games
+
+import (
+	"testing"
+)
+
+/*
+GetSimpleArena
+Description:
+	Creates an arena in which Even wins from a, b, d and f and Odd wins from c and e.
+	Even must move from a to b (c is a trap with priority 3) and from f to a (f has priority 5);
+	Odd moves from e to c.
+*/
+func GetSimpleArena() Arena {
+	arena, _ := GetArena(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		map[string]Player{"a": Even, "b": Odd, "c": Odd, "d": Even, "e": Odd, "f": Even},
+		map[string]int{"a": 2, "b": 1, "c": 3, "d": 0, "e": 4, "f": 5},
+		map[string][]string{
+			"a": {"c", "b"},
+			"b": {"a", "d"},
+			"c": {"c"},
+			"d": {"d"},
+			"e": {"a", "c"},
+			"f": {"f", "a"},
+		},
+	)
+	return arena
+}
+
+func TestPlayer_Wins1(t *testing.T) {
+	if !Even.Wins(4) || Even.Wins(3) || !Odd.Wins(1) || Odd.Wins(0) {
+		t.Errorf("Expected Even to win even priorities and Odd to win odd priorities.")
+	}
+
+	if Even.Opponent() != Odd || Odd.Opponent() != Even {
+		t.Errorf("Expected Even and Odd to be opponents.")
+	}
+
+	if Even.String() != "Even" || Odd.String() != "Odd" {
+		t.Errorf("Unexpected player names %v and %v.", Even, Odd)
+	}
+}
+
+func TestGetArena1(t *testing.T) {
+	arena := GetSimpleArena()
+
+	if len(arena.V) != 6 {
+		t.Errorf("Expected 6 vertices, but found %v.", len(arena.V))
+	}
+
+	e := arena.VerticesNamed("e")[0]
+	if arena.OwnerOf(e) != Odd || arena.PriorityOf(e) != 4 || len(arena.Successors(e)) != 2 {
+		t.Errorf("Expected e to be an Odd vertex with priority 4 and two successors.")
+	}
+
+	if arena.MaxPriority() != 5 {
+		t.Errorf("Expected the largest priority to be 5, but found %v.", arena.MaxPriority())
+	}
+}
+
+func TestArena_Check1(t *testing.T) {
+	// Dead end
+	_, err := GetArena([]string{"a", "b"}, nil, nil, map[string][]string{"a": {"b"}})
+	if err == nil {
+		t.Errorf("Expected an error for the vertex b without successors.")
+	}
+
+	// Unknown successor
+	_, err = GetArena([]string{"a"}, nil, nil, map[string][]string{"a": {"z"}})
+	if err == nil {
+		t.Errorf("Expected an error for the unknown successor z.")
+	}
+
+	// Duplicate vertex
+	_, err = GetArena([]string{"a", "a"}, nil, nil, map[string][]string{"a": {"a"}})
+	if err == nil {
+		t.Errorf("Expected an error for the duplicate vertex a.")
+	}
+
+	// Negative priority
+	_, err = GetArena([]string{"a"}, nil, map[string]int{"a": -1}, map[string][]string{"a": {"a"}})
+	if err == nil {
+		t.Errorf("Expected an error for the negative priority.")
+	}
+}
+
+func TestArena_Dual1(t *testing.T) {
+	arena := GetSimpleArena()
+
+	dual, err := arena.Dual()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	a := dual.VerticesNamed("a")[0]
+	if dual.OwnerOf(a) != Odd || dual.PriorityOf(a) != 3 {
+		t.Errorf("Expected a to belong to Odd with priority 3 in the dual.")
+	}
+}
+
+func TestArena_Attractor1(t *testing.T) {
+	arena := GetSimpleArena()
+
+	// Odd can force a visit to c from c and e; b may escape to d and a may avoid c
+	attractor, strategy := arena.Attractor(Odd, arena.VerticesNamed("c"))
+	if len(attractor) != 2 || !arena.VerticesNamed("e")[0].In(attractor) {
+		t.Errorf("Expected the Odd-attractor of c to be {c, e}, but found %v.", attractor)
+	}
+
+	if successor, found := strategy[arena.VerticesNamed("e")[0]]; !found || successor.Name != "c" {
+		t.Errorf("Expected the attractor strategy to move from e to c, but found %v.", strategy)
+	}
+
+	// b belongs to Odd, which can move back to a, so only d is attracted
+	attractor, _ = arena.Attractor(Even, arena.VerticesNamed("d"))
+	if len(attractor) != 1 {
+		t.Errorf("Expected the Even-attractor of d to be {d}, but found %v.", attractor)
+	}
+}
@@ -0,0 +1,254 @@
+/*
+conversion.go
+Description:
+	Creates parity games from turn-based Concurrent Game Models and from the product of a turn-based
+	Concurrent Game Model with a Deterministic Rabin Automaton.
+	The agents of a coalition play Even and the other agents play Odd.
+*/
+
+package games
+
+import (
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/adaptive"
+	"github.com/kwesiRutledge/ModelChecking/omega"
+)
+
+/*
+Constants
+*/
+
+const (
+	// The vertex of a product which is reached when the automaton has no transition. It is won by Odd.
+	RejectingSinkName = "(sink)"
+)
+
+/*
+FromConcurrentGameModel
+Description:
+	Creates the arena of a turn-based game: in every state at most one agent may choose between actions.
+	Each state becomes a vertex with the priority given in priorities (0 if it has none), owned by Even
+	if its choosing agent is in the coalition and by Odd otherwise. States without a choice belong to Even.
+Usage:
+	arena, err := FromConcurrentGameModel(cgm, []string{"Agent1"}, map[string]int{"Rome": 2})
+*/
+func FromConcurrentGameModel(cgm mc.ConcurrentGameModel, coalition []string, priorities map[string]int) (Arena, error) {
+	coalitionAgents, err := cgm.AgentsNamed(coalition...)
+	if err != nil {
+		return Arena{}, fmt.Errorf("There was an issue with the coalition: %v", err)
+	}
+
+	var vertexNames []string
+	ownerMap := make(map[string]Player)
+	edgeMap := make(map[string][]string)
+	for _, state := range cgm.St {
+		owner, err := turnOwner(cgm, coalitionAgents, state)
+		if err != nil {
+			return Arena{}, err
+		}
+
+		successors, err := cgmSuccessors(cgm, state)
+		if err != nil {
+			return Arena{}, err
+		}
+
+		vertexNames = append(vertexNames, state.Name)
+		ownerMap[state.Name] = owner
+		for _, successor := range successors {
+			edgeMap[state.Name] = mc.AppendIfUnique(edgeMap[state.Name], successor.Name)
+		}
+	}
+
+	for name := range priorities {
+		if _, found := mc.FindInSlice(name, vertexNames); !found {
+			return Arena{}, fmt.Errorf("The state \"%v\" has a priority, but is not a state of the game.", name)
+		}
+	}
+
+	return GetArena(vertexNames, ownerMap, priorities, edgeMap)
+}
+
+/*
+FromDRAProduct
+Description:
+	Creates the arena of the product of a turn-based game with a Deterministic Rabin Automaton, starting from initial.
+	Even wins a play of the product if and only if the word of labels of the corresponding play of the game
+	is accepted by the automaton. The DRA is converted to a deterministic parity automaton first (see ToParity()).
+	Each state of the game must be labelled with exactly one atomic proposition of the automaton's alphabet.
+	The vertex (q,p) is reached when the game enters q and the parity automaton moves to p after reading the label of q.
+	The second output is the vertex of the initial state.
+*/
+func FromDRAProduct(cgm mc.ConcurrentGameModel, coalition []string, initial mc.CGMState, dra adaptive.DeterministicRabinAutomaton) (Arena, Vertex, error) {
+	// Input Processing
+	coalitionAgents, err := cgm.AgentsNamed(coalition...)
+	if err != nil {
+		return Arena{}, Vertex{}, fmt.Errorf("There was an issue with the coalition: %v", err)
+	}
+
+	if !initial.In(cgm.St) {
+		return Arena{}, Vertex{}, fmt.Errorf("The initial state \"%v\" is not a state of the game.", initial)
+	}
+
+	parityAutomaton, err := dra.ToParity()
+	if err != nil {
+		return Arena{}, Vertex{}, fmt.Errorf("There was an issue converting the DRA to a parity automaton: %v", err)
+	}
+	parityCondition := parityAutomaton.Acceptance.(omega.Parity)
+
+	type productVertex struct {
+		State mc.CGMState
+		Q     omega.State
+	}
+	nameOf := func(pv productVertex) string {
+		return fmt.Sprintf("(%v,%v)", pv.State.Name, pv.Q.Name)
+	}
+
+	// step returns the vertex reached by entering state from the automaton state q
+	step := func(q omega.State, state mc.CGMState) (productVertex, bool, error) {
+		letter, err := draLetter(cgm, state, parityAutomaton.Alphabet)
+		if err != nil {
+			return productVertex{}, false, err
+		}
+		successors := parityAutomaton.Post(q, letter)
+		if len(successors) == 0 {
+			return productVertex{}, false, nil
+		}
+		return productVertex{State: state, Q: successors[0]}, true, nil
+	}
+
+	var vertexNames []string
+	ownerMap := make(map[string]Player)
+	priorityMap := make(map[string]int)
+	edgeMap := make(map[string][]string)
+
+	addSink := func() string {
+		if _, found := mc.FindInSlice(RejectingSinkName, vertexNames); !found {
+			vertexNames = append(vertexNames, RejectingSinkName)
+			priorityMap[RejectingSinkName] = 1
+			edgeMap[RejectingSinkName] = []string{RejectingSinkName}
+		}
+		return RejectingSinkName
+	}
+
+	// Explore the product from the initial state
+	var queue []productVertex
+	initialName := ""
+	initialVertex, found, err := step(parityAutomaton.Q0[0], initial)
+	if err != nil {
+		return Arena{}, Vertex{}, err
+	}
+	if found {
+		initialName = nameOf(initialVertex)
+		vertexNames = append(vertexNames, initialName)
+		queue = append(queue, initialVertex)
+	} else {
+		initialName = addSink()
+	}
+
+	for len(queue) > 0 {
+		pv := queue[0]
+		queue = queue[1:]
+		pvName := nameOf(pv)
+
+		owner, err := turnOwner(cgm, coalitionAgents, pv.State)
+		if err != nil {
+			return Arena{}, Vertex{}, err
+		}
+		ownerMap[pvName] = owner
+		priorityMap[pvName] = parityCondition.PriorityOf(pv.Q)
+
+		successors, err := cgmSuccessors(cgm, pv.State)
+		if err != nil {
+			return Arena{}, Vertex{}, err
+		}
+
+		for _, successor := range successors {
+			next, found, err := step(pv.Q, successor)
+			if err != nil {
+				return Arena{}, Vertex{}, err
+			}
+			if !found {
+				edgeMap[pvName] = mc.AppendIfUnique(edgeMap[pvName], addSink())
+				continue
+			}
+
+			nextName := nameOf(next)
+			edgeMap[pvName] = mc.AppendIfUnique(edgeMap[pvName], nextName)
+			if _, seen := mc.FindInSlice(nextName, vertexNames); !seen {
+				vertexNames = append(vertexNames, nextName)
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	arena, err := GetArena(vertexNames, ownerMap, priorityMap, edgeMap)
+	if err != nil {
+		return Arena{}, Vertex{}, err
+	}
+
+	return arena, arena.VerticesNamed(initialName)[0], nil
+}
+
+/*
+turnOwner
+Description:
+	Returns the player which chooses the successor of the state: the player of the only agent with more than
+	one permitted action, or Even if no agent has a choice. An error is returned if several agents have a choice.
+*/
+func turnOwner(cgm mc.ConcurrentGameModel, coalition []mc.CGMAgent, state mc.CGMState) (Player, error) {
+	var choosingAgents []mc.CGMAgent
+	for _, agent := range cgm.Agents {
+		if len(cgm.PermittedActions(agent, state)) > 1 {
+			choosingAgents = append(choosingAgents, agent)
+		}
+	}
+
+	switch {
+	case len(choosingAgents) > 1:
+		return Even, fmt.Errorf("The game is not turn-based: the agents %v may all choose an action in state \"%v\".", choosingAgents, state)
+	case len(choosingAgents) == 1 && !choosingAgents[0].In(coalition):
+		return Odd, nil
+	default:
+		return Even, nil
+	}
+}
+
+/*
+cgmSuccessors
+Description:
+	Returns the successors of the state under every permitted joint action.
+*/
+func cgmSuccessors(cgm mc.ConcurrentGameModel, state mc.CGMState) ([]mc.CGMState, error) {
+	var successors []mc.CGMState
+	for _, jointAction := range cgm.JointActions(state) {
+		if successor, found := cgm.Successor(state, jointAction); found {
+			successors = successor.AppendIfUniqueTo(successors)
+		}
+	}
+
+	if len(successors) == 0 {
+		return nil, fmt.Errorf("The state \"%v\" has no successors.", state)
+	}
+	return successors, nil
+}
+
+/*
+draLetter
+Description:
+	Returns the unique label of the state which is in the alphabet.
+*/
+func draLetter(cgm mc.ConcurrentGameModel, state mc.CGMState, alphabet []mc.AtomicProposition) (mc.AtomicProposition, error) {
+	var letters []mc.AtomicProposition
+	for _, ap := range cgm.Labels(state) {
+		if ap.In(alphabet) {
+			letters = append(letters, ap)
+		}
+	}
+
+	if len(letters) != 1 {
+		return mc.AtomicProposition{}, fmt.Errorf("The state \"%v\" must be labelled with exactly one symbol of the automaton's alphabet, but it has the labels %v.", state, letters)
+	}
+	return letters[0], nil
+}
@@ -0,0 +1,193 @@
+/*
+conversion_test.go
+Description:
+	Tests the functions created in conversion.go
+*/
+
+package games
+
+import (
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/adaptive"
+)
+
+/*
+GetTurnBasedCGM
+Description:
+	Creates a turn-based game: Eve chooses between left and right in s0, Adam chooses to stay in left or
+	to go back to s0, and right always leads back to s0.
+*/
+func GetTurnBasedCGM() (mc.ConcurrentGameModel, error) {
+	return mc.CreateConcurrentGameModel(
+		[]string{"Eve", "Adam"},
+		[]string{"s0", "left", "right"},
+		[]string{"neutral", "bad", "good"},
+		[]string{"goLeft", "goRight", "stay", "back", "wait"},
+		map[string]map[string][]string{
+			"Eve": {
+				"s0":    {"goLeft", "goRight"},
+				"left":  {"wait"},
+				"right": {"wait"},
+			},
+			"Adam": {
+				"s0":    {"wait"},
+				"left":  {"stay", "back"},
+				"right": {"wait"},
+			},
+		},
+		map[string]map[string]string{
+			"s0": {
+				"goLeft, wait":  "left",
+				"goRight, wait": "right",
+			},
+			"left": {
+				"wait, stay": "left",
+				"wait, back": "s0",
+			},
+			"right": {
+				"wait, wait": "s0",
+			},
+		},
+		map[string][]string{
+			"neutral": {"s0"},
+			"bad":     {"left"},
+			"good":    {"right"},
+		},
+	)
+}
+
+/*
+GetInfinitelyOftenGoodDRA
+Description:
+	Creates a DRA over {neutral, bad, good} which accepts the words with infinitely many good symbols.
+*/
+func GetInfinitelyOftenGoodDRA() adaptive.DeterministicRabinAutomaton {
+	dra, _ := adaptive.GetDRA(
+		[]string{"other", "sawGood"}, "other", []string{"neutral", "bad", "good"},
+		map[string]map[string]string{
+			"other":   {"neutral": "other", "bad": "other", "good": "sawGood"},
+			"sawGood": {"neutral": "other", "bad": "other", "good": "sawGood"},
+		},
+		[][2][]string{{{}, {"sawGood"}}},
+	)
+	return dra
+}
+
+func TestFromConcurrentGameModel1(t *testing.T) {
+	cgm, err := GetTurnBasedCGM()
+	if err != nil {
+		t.Errorf("There was an issue creating the game: %v", err)
+	}
+
+	arena, err := FromConcurrentGameModel(cgm, []string{"Eve"}, map[string]int{"left": 1, "right": 2})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	s0 := arena.VerticesNamed("s0")[0]
+	if arena.OwnerOf(s0) != Even || arena.OwnerOf(arena.VerticesNamed("left")[0]) != Odd {
+		t.Errorf("Expected s0 to belong to Even and left to belong to Odd.")
+	}
+
+	solution, err := arena.CrossCheck()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if winner, _ := solution.Winner(s0); winner != Even {
+		t.Errorf("Expected Even to win from s0, but %v does.", winner)
+	}
+
+	if successor, _ := solution.StrategyAt(s0); successor.Name != "right" {
+		t.Errorf("Expected Even to move to right, but it moves to %v.", successor)
+	}
+}
+
+func TestFromConcurrentGameModel2(t *testing.T) {
+	// A concurrent game is not turn-based
+	cgm, _ := mc.CreateConcurrentGameModel(
+		[]string{"A", "B"},
+		[]string{"s"},
+		[]string{},
+		[]string{"x", "y"},
+		map[string]map[string][]string{
+			"A": {"s": {"x", "y"}},
+			"B": {"s": {"x", "y"}},
+		},
+		map[string]map[string]string{
+			"s": {"x, x": "s", "x, y": "s", "y, x": "s", "y, y": "s"},
+		},
+		map[string][]string{},
+	)
+
+	if _, err := FromConcurrentGameModel(cgm, []string{"A"}, nil); err == nil {
+		t.Errorf("Expected an error for a game which is not turn-based.")
+	}
+
+	// Unknown coalition
+	cgm, _ = GetTurnBasedCGM()
+	if _, err := FromConcurrentGameModel(cgm, []string{"Zeus"}, nil); err == nil {
+		t.Errorf("Expected an error for the unknown agent Zeus.")
+	}
+}
+
+func TestFromDRAProduct1(t *testing.T) {
+	cgm, _ := GetTurnBasedCGM()
+	dra := GetInfinitelyOftenGoodDRA()
+	s0 := cgm.St[0]
+
+	// Eve can visit right infinitely often
+	arena, initialVertex, err := FromDRAProduct(cgm, []string{"Eve"}, s0, dra)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	solution, err := arena.CrossCheck()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if winner, _ := solution.Winner(initialVertex); winner != Even {
+		t.Errorf("Expected the coalition {Eve} to win from %v, but %v does.", initialVertex, winner)
+	}
+
+	// Adam cannot, because Eve keeps going left
+	arena, initialVertex, err = FromDRAProduct(cgm, []string{"Adam"}, s0, dra)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	solution, _ = arena.CrossCheck()
+	if winner, _ := solution.Winner(initialVertex); winner != Odd {
+		t.Errorf("Expected the coalition {Adam} to lose from %v, but %v wins.", initialVertex, winner)
+	}
+}
+
+func TestFromDRAProduct2(t *testing.T) {
+	cgm, _ := GetTurnBasedCGM()
+
+	// The DRA has no transition for bad, so entering left is rejected
+	dra, _ := adaptive.GetDRA(
+		[]string{"q"}, "q", []string{"neutral", "bad", "good"},
+		map[string]map[string]string{
+			"q": {"neutral": "q", "good": "q"},
+		},
+		[][2][]string{{{}, {"q"}}},
+	)
+
+	arena, initialVertex, err := FromDRAProduct(cgm, []string{"Adam"}, cgm.St[0], dra)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(arena.VerticesNamed(RejectingSinkName)) != 1 {
+		t.Errorf("Expected the product to contain the rejecting sink.")
+	}
+
+	solution, _ := arena.CrossCheck()
+	if winner, _ := solution.Winner(initialVertex); winner != Odd {
+		t.Errorf("Expected Eve to force a rejection by going left, but %v wins.", winner)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+progressmeasures.go
+Description:
+	Jurdzinski's small progress measures for parity games. A measure assigns to each vertex a vector that
+	counts, for every odd priority, how many times that priority can still be seen before a larger priority;
+	the measure Top means that Odd wins. Measures are lifted until they are stable and Even wins exactly
+	from the vertices whose measure is not Top. Odd's strategy is obtained by lifting the dual game.
+*/
+
+package games
+
+import (
+	"fmt"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+progressMeasure
+Description:
+	counts[k] is the counter of the odd priority 2k+1; the last counter is the most significant one.
+*/
+type progressMeasure struct {
+	counts []int
+	top    bool
+}
+
+/*
+SolveSmallProgressMeasures
+Description:
+	Solves the parity game by lifting small progress measures in the arena (for Even) and in its dual (for Odd).
+Usage:
+	solution, err := arena.SolveSmallProgressMeasures()
+*/
+func (arena Arena) SolveSmallProgressMeasures() (Solution, error) {
+	if err := arena.Check(); err != nil {
+		return Solution{}, fmt.Errorf("There was an issue checking the arena: %v", err)
+	}
+
+	dual, err := arena.Dual()
+	if err != nil {
+		return Solution{}, fmt.Errorf("There was an issue creating the dual arena: %v", err)
+	}
+
+	g := arena.indexed()
+	evenRegion, evenStrategy := g.liftProgressMeasures()
+	oddRegion, oddStrategy := dual.indexed().liftProgressMeasures()
+
+	for v := range g.vertices {
+		if evenRegion[v] == oddRegion[v] {
+			return Solution{}, fmt.Errorf("The progress measures are inconsistent in vertex \"%v\".", g.vertices[v])
+		}
+	}
+
+	strategy := make([]int, len(g.vertices))
+	for v := range strategy {
+		switch {
+		case evenRegion[v] && g.owner[v] == Even:
+			strategy[v] = evenStrategy[v]
+		case oddRegion[v] && g.owner[v] == Odd:
+			strategy[v] = oddStrategy[v]
+		default:
+			strategy[v] = -1
+		}
+	}
+
+	return Solution{
+		W:        [2][]Vertex{g.verticesOf(evenRegion), g.verticesOf(oddRegion)},
+		Strategy: g.strategyOf(strategy),
+	}, nil
+}
+
+/*
+liftProgressMeasures
+Description:
+	Computes the least stable progress measure of the game. Returns the region won by Even
+	(the vertices whose measure is not Top) and a strategy for Even's vertices in it.
+*/
+func (g indexedGame) liftProgressMeasures() ([]bool, []int) {
+	// The bound of the counter of each odd priority is the number of vertices with that priority
+	maxPriority := 0
+	for _, priority := range g.priority {
+		if priority > maxPriority {
+			maxPriority = priority
+		}
+	}
+	bounds := make([]int, (maxPriority+1)/2)
+	for _, priority := range g.priority {
+		if priority%2 == 1 {
+			bounds[priority/2]++
+		}
+	}
+
+	measures := make([]progressMeasure, len(g.vertices))
+	for v := range measures {
+		measures[v] = progressMeasure{counts: make([]int, len(bounds))}
+	}
+
+	// Lift until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for v := range g.vertices {
+			lifted := g.bestProgress(v, measures, bounds)
+			if compareMeasures(lifted, measures[v]) > 0 {
+				measures[v] = lifted
+				changed = true
+			}
+		}
+	}
+
+	region := make([]bool, len(g.vertices))
+	strategy := make([]int, len(g.vertices))
+	for v := range g.vertices {
+		region[v] = !measures[v].top
+		strategy[v] = -1
+		if !region[v] || g.owner[v] != Even {
+			continue
+		}
+
+		for _, successor := range g.successors[v] {
+			if compareMeasures(progress(measures[successor], g.priority[v], bounds), measures[v]) <= 0 {
+				strategy[v] = successor
+				break
+			}
+		}
+	}
+
+	return region, strategy
+}
+
+/*
+bestProgress
+Description:
+	Returns the smallest progress over the successors of an Even vertex, or the largest over those of an Odd vertex.
+*/
+func (g indexedGame) bestProgress(v int, measures []progressMeasure, bounds []int) progressMeasure {
+	var best progressMeasure
+	for successorIndex, successor := range g.successors[v] {
+		candidate := progress(measures[successor], g.priority[v], bounds)
+		if successorIndex == 0 {
+			best = candidate
+			continue
+		}
+
+		comparison := compareMeasures(candidate, best)
+		if (g.owner[v] == Even && comparison < 0) || (g.owner[v] == Odd && comparison > 0) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+/*
+progress
+Description:
+	Returns the least measure m which is at least the successor's measure on the counters of priorities
+	at least priority (and strictly larger if priority is odd). The counters of smaller priorities are reset.
+*/
+func progress(successorMeasure progressMeasure, priority int, bounds []int) progressMeasure {
+	if successorMeasure.top {
+		return progressMeasure{top: true}
+	}
+
+	m := progressMeasure{counts: make([]int, len(bounds))}
+	for k := range bounds {
+		if 2*k+1 >= priority {
+			m.counts[k] = successorMeasure.counts[k]
+		}
+	}
+
+	if priority%2 == 0 {
+		return m
+	}
+
+	// Increment the counter of priority, carrying into the more significant counters
+	for k := priority / 2; k < len(bounds); k++ {
+		if m.counts[k] < bounds[k] {
+			m.counts[k]++
+			return m
+		}
+		m.counts[k] = 0
+	}
+
+	return progressMeasure{top: true}
+}
+
+/*
+compareMeasures
+Description:
+	Compares the measures lexicographically, starting from the counter of the largest odd priority.
+	Returns -1, 0 or 1 if m1 is smaller than, equal to or larger than m2.
+*/
+func compareMeasures(m1 progressMeasure, m2 progressMeasure) int {
+	switch {
+	case m1.top && m2.top:
+		return 0
+	case m1.top:
+		return 1
+	case m2.top:
+		return -1
+	}
+
+	for k := len(m1.counts) - 1; k >= 0; k-- {
+		if m1.counts[k] != m2.counts[k] {
+			if m1.counts[k] < m2.counts[k] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+/*
+progressmeasures_test.go
+Description:
+	Tests the functions created in progressmeasures.go
+*/
+
+package games
+
+import (
+	"testing"
+)
+
+func TestArena_SolveSmallProgressMeasures1(t *testing.T) {
+	arena := GetSimpleArena()
+
+	solution, err := arena.SolveSmallProgressMeasures()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkSimpleArenaSolution(t, arena, solution)
+}
+
+func TestProgress1(t *testing.T) {
+	bounds := []int{1, 1}
+
+	// Odd priorities increment their counter and reset the smaller ones
+	m := progress(progressMeasure{counts: []int{1, 0}}, 3, bounds)
+	if m.top || m.counts[0] != 0 || m.counts[1] != 1 {
+		t.Errorf("Expected the measure (0,1), but found %v.", m)
+	}
+
+	// Even priorities only reset the smaller counters
+	m = progress(progressMeasure{counts: []int{1, 1}}, 2, bounds)
+	if m.top || m.counts[0] != 0 || m.counts[1] != 1 {
+		t.Errorf("Expected the measure (0,1), but found %v.", m)
+	}
+
+	// Exceeding every bound gives Top
+	m = progress(progressMeasure{counts: []int{1, 1}}, 1, bounds)
+	if !m.top {
+		t.Errorf("Expected the measure Top, but found %v.", m)
+	}
+}
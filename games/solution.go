@@ -0,0 +1,211 @@
+/*
+solution.go
+Description:
+	The solution of a parity game (winning regions and positional winning strategies) and the checks
+	that are used to validate solutions and to compare the solvers with each other.
+*/
+
+package games
+
+import (
+	"fmt"
+
+	"github.com/kwesiRutledge/ModelChecking/internal/graph"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Solution
+Description:
+	W[Even] and W[Odd] are the winning regions of the two players; together they partition the vertices.
+	Strategy gives, for every vertex in the winning region of its owner, the successor chosen by
+	the owner's positional winning strategy.
+*/
+type Solution struct {
+	W        [2][]Vertex
+	Strategy map[Vertex]Vertex
+}
+
+/*
+Functions for Solution
+*/
+
+/*
+Winner
+Description:
+	Returns the player which wins from v. The second output is false if v is in neither winning region.
+*/
+func (solution Solution) Winner(v Vertex) (Player, bool) {
+	for _, player := range []Player{Even, Odd} {
+		if v.In(solution.W[player]) {
+			return player, true
+		}
+	}
+	return Even, false
+}
+
+/*
+StrategyAt
+Description:
+	Returns the successor chosen in v by the winning strategy of v's owner, if v is in the owner's winning region.
+*/
+func (solution Solution) StrategyAt(v Vertex) (Vertex, bool) {
+	if successor, found := solution.Strategy[v]; found {
+		return successor, true
+	}
+	for tempVertex, successor := range solution.Strategy {
+		if tempVertex.Equals(v) {
+			return successor, true
+		}
+	}
+	return Vertex{}, false
+}
+
+/*
+CheckSolution
+Description:
+	Checks that the solution is correct for the arena:
+	- the winning regions partition the vertices,
+	- each region is closed under the strategy of its player and under every move of the opponent, and
+	- every cycle that the opponent can form in a region against the strategy is won by the region's player.
+	The last condition is checked for each priority p of the opponent's parity: no cycle of vertices with
+	priority at most p may pass through a vertex of priority p.
+*/
+func (arena Arena) CheckSolution(solution Solution) error {
+	g := arena.indexed()
+
+	// Partition
+	region := make([]int, len(g.vertices))
+	for v := range region {
+		region[v] = -1
+	}
+	for _, player := range []Player{Even, Odd} {
+		for _, v := range solution.W[player] {
+			index, found := g.indexOf[v.Name]
+			if !found {
+				return fmt.Errorf("The vertex \"%v\" in the winning region of %v is not a vertex of the arena.", v, player)
+			}
+			if region[index] >= 0 {
+				return fmt.Errorf("The vertex \"%v\" is in more than one winning region.", v)
+			}
+			region[index] = int(player)
+		}
+	}
+	for v, player := range region {
+		if player < 0 {
+			return fmt.Errorf("The vertex \"%v\" is in neither winning region.", g.vertices[v])
+		}
+	}
+
+	for _, player := range []Player{Even, Odd} {
+		// Restrict the edges to the strategy of player and check that the region is closed
+		edges := make([][]int, len(g.vertices))
+		for v := range g.vertices {
+			if region[v] != int(player) {
+				continue
+			}
+
+			if g.owner[v] == player {
+				successor, found := solution.StrategyAt(g.vertices[v])
+				if !found {
+					return fmt.Errorf("The strategy of %v has no move in its vertex \"%v\".", player, g.vertices[v])
+				}
+				successorIndex, isVertex := g.indexOf[successor.Name]
+				if !isVertex || !successor.In(arena.Successors(g.vertices[v])) {
+					return fmt.Errorf("The strategy of %v moves from \"%v\" to \"%v\", which is not a successor.", player, g.vertices[v], successor)
+				}
+				if region[successorIndex] != int(player) {
+					return fmt.Errorf("The strategy of %v leaves its winning region from \"%v\".", player, g.vertices[v])
+				}
+				edges[v] = []int{successorIndex}
+				continue
+			}
+
+			for _, successor := range g.successors[v] {
+				if region[successor] != int(player) {
+					return fmt.Errorf("%v can leave the winning region of %v from \"%v\".", player.Opponent(), player, g.vertices[v])
+				}
+			}
+			edges[v] = g.successors[v]
+		}
+
+		// Check the cycles which are bad for player
+		for _, badPriority := range g.priorities() {
+			if player.Wins(badPriority) {
+				continue
+			}
+
+			allowed := make([]bool, len(g.vertices))
+			for v := range g.vertices {
+				allowed[v] = region[v] == int(player) && g.priority[v] <= badPriority
+			}
+
+			for _, component := range graph.StronglyConnectedComponents(edges, allowed) {
+				if !graph.IsNontrivial(component, edges) {
+					continue
+				}
+				for _, v := range component {
+					if g.priority[v] == badPriority {
+						return fmt.Errorf("%v can win against the strategy of %v with a cycle through \"%v\" (priority %v).", player.Opponent(), player, g.vertices[v], badPriority)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+CrossCheck
+Description:
+	Solves the arena with both Zielonka's algorithm and small progress measures, checks both solutions
+	and checks that they have the same winning regions. The solution of Zielonka's algorithm is returned.
+*/
+func (arena Arena) CrossCheck() (Solution, error) {
+	zielonkaSolution, err := arena.SolveZielonka()
+	if err != nil {
+		return Solution{}, err
+	}
+	if err = arena.CheckSolution(zielonkaSolution); err != nil {
+		return Solution{}, fmt.Errorf("The solution of Zielonka's algorithm is incorrect: %v", err)
+	}
+
+	spmSolution, err := arena.SolveSmallProgressMeasures()
+	if err != nil {
+		return Solution{}, err
+	}
+	if err = arena.CheckSolution(spmSolution); err != nil {
+		return Solution{}, fmt.Errorf("The solution of small progress measures is incorrect: %v", err)
+	}
+
+	for _, v := range arena.V {
+		zielonkaWinner, _ := zielonkaSolution.Winner(v)
+		spmWinner, _ := spmSolution.Winner(v)
+		if zielonkaWinner != spmWinner {
+			return Solution{}, fmt.Errorf("Zielonka's algorithm says that %v wins from \"%v\", but small progress measures say that %v does.", zielonkaWinner, v, spmWinner)
+		}
+	}
+
+	return zielonkaSolution, nil
+}
+
+/*
+priorities
+Description:
+	Returns the distinct priorities of the game.
+*/
+func (g indexedGame) priorities() []int {
+	var prioritiesOut []int
+	seen := make(map[int]bool)
+	for _, priority := range g.priority {
+		if !seen[priority] {
+			seen[priority] = true
+			prioritiesOut = append(prioritiesOut, priority)
+		}
+	}
+	return prioritiesOut
+}
@@ -0,0 +1,79 @@
+/*
+solution_test.go
+Description:
+	Tests the functions and objects created in solution.go
+*/
+
+package games
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestArena_CheckSolution1(t *testing.T) {
+	arena := GetSimpleArena()
+	solution, _ := arena.SolveZielonka()
+
+	// Even moving from a to c loses
+	badSolution := Solution{W: solution.W, Strategy: make(map[Vertex]Vertex)}
+	for v, successor := range solution.Strategy {
+		badSolution.Strategy[v] = successor
+	}
+	badSolution.Strategy[arena.VerticesNamed("a")[0]] = arena.VerticesNamed("c")[0]
+	if err := arena.CheckSolution(badSolution); err == nil {
+		t.Errorf("Expected an error for the strategy that moves from a to c.")
+	}
+
+	// Even staying in f loses
+	badSolution.Strategy[arena.VerticesNamed("a")[0]] = arena.VerticesNamed("b")[0]
+	badSolution.Strategy[arena.VerticesNamed("f")[0]] = arena.VerticesNamed("f")[0]
+	if err := arena.CheckSolution(badSolution); err == nil {
+		t.Errorf("Expected an error for the strategy that stays in f.")
+	}
+
+	// Regions which do not partition the vertices
+	badSolution = Solution{W: [2][]Vertex{solution.W[Even], {}}, Strategy: solution.Strategy}
+	if err := arena.CheckSolution(badSolution); err == nil {
+		t.Errorf("Expected an error for regions that do not cover c and e.")
+	}
+}
+
+/*
+TestArena_CrossCheck1
+Description:
+	Compares the solvers on random arenas.
+*/
+func TestArena_CrossCheck1(t *testing.T) {
+	random := rand.New(rand.NewSource(36))
+
+	for gameIndex := 0; gameIndex < 100; gameIndex++ {
+		numVertices := 2 + random.Intn(8)
+
+		var vertexNames []string
+		ownerMap := make(map[string]Player)
+		priorityMap := make(map[string]int)
+		edgeMap := make(map[string][]string)
+		for v := 0; v < numVertices; v++ {
+			vertexNames = append(vertexNames, fmt.Sprintf("v%v", v))
+		}
+		for _, name := range vertexNames {
+			ownerMap[name] = Player(random.Intn(2))
+			priorityMap[name] = random.Intn(5)
+			for e := 0; e < 1+random.Intn(3); e++ {
+				edgeMap[name] = append(edgeMap[name], vertexNames[random.Intn(numVertices)])
+			}
+		}
+
+		arena, err := GetArena(vertexNames, ownerMap, priorityMap, edgeMap)
+		if err != nil {
+			t.Errorf("Unexpected error creating arena %v: %v", gameIndex, err)
+			continue
+		}
+
+		if _, err := arena.CrossCheck(); err != nil {
+			t.Errorf("The solvers disagree on arena %v: %v", gameIndex, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+zielonka.go
+Description:
+	Zielonka's recursive algorithm for parity games. Let p be the largest priority of a subgame and
+	let P be the player that wins p. The P-attractor A of the vertices with priority p is removed and
+	the rest is solved recursively. If the opponent wins nowhere in the rest, then P wins everywhere;
+	otherwise the opponent-attractor of the opponent's region is won by the opponent and the remaining
+	subgame is solved again.
+*/
+
+package games
+
+import (
+	"fmt"
+)
+
+/*
+SolveZielonka
+Description:
+	Solves the parity game with Zielonka's recursive algorithm.
+Usage:
+	solution, err := arena.SolveZielonka()
+	winner, _ := solution.Winner(arena.V[0])
+*/
+func (arena Arena) SolveZielonka() (Solution, error) {
+	if err := arena.Check(); err != nil {
+		return Solution{}, fmt.Errorf("There was an issue checking the arena: %v", err)
+	}
+
+	g := arena.indexed()
+	inSet := make([]bool, len(g.vertices))
+	strategy := make([]int, len(g.vertices))
+	for v := range inSet {
+		inSet[v] = true
+		strategy[v] = -1
+	}
+
+	regions := g.zielonka(inSet, strategy)
+
+	// Only keep the moves of the winners
+	for v := range strategy {
+		if !regions[g.owner[v]][v] {
+			strategy[v] = -1
+		}
+	}
+
+	return Solution{
+		W:        [2][]Vertex{g.verticesOf(regions[Even]), g.verticesOf(regions[Odd])},
+		Strategy: g.strategyOf(strategy),
+	}, nil
+}
+
+/*
+zielonka
+Description:
+	Solves the subgame inSet and returns the winning regions of Even and Odd.
+	The winning strategies in the subgame are written to strategy.
+*/
+func (g indexedGame) zielonka(inSet []bool, strategy []int) [2][]bool {
+	var regions [2][]bool
+	regions[Even] = make([]bool, len(g.vertices))
+	regions[Odd] = make([]bool, len(g.vertices))
+
+	// Find the largest priority
+	maxPriority := -1
+	for v, included := range inSet {
+		if included && g.priority[v] > maxPriority {
+			maxPriority = g.priority[v]
+		}
+	}
+	if maxPriority < 0 {
+		return regions
+	}
+
+	player := Player(maxPriority % 2)
+	opponent := player.Opponent()
+
+	// Attract to the vertices with the largest priority
+	top := make([]bool, len(g.vertices))
+	for v, included := range inSet {
+		top[v] = included && g.priority[v] == maxPriority
+	}
+	attractorMask, attractorStrategy := g.attractor(player, top, inSet)
+
+	subRegions := g.zielonka(g.difference(inSet, attractorMask), strategy)
+
+	if !g.isNonempty(subRegions[opponent]) {
+		// player wins everywhere: reach the top priority and stay in the subgame there
+		for v, included := range inSet {
+			if !included {
+				continue
+			}
+			regions[player][v] = true
+			if g.owner[v] != player {
+				continue
+			}
+			switch {
+			case attractorStrategy[v] >= 0:
+				strategy[v] = attractorStrategy[v]
+			case top[v]:
+				for _, successor := range g.successors[v] {
+					if inSet[successor] {
+						strategy[v] = successor
+						break
+					}
+				}
+			}
+		}
+		return regions
+	}
+
+	// The opponent wins its region and everything that it can attract to it
+	opponentAttractor, opponentStrategy := g.attractor(opponent, subRegions[opponent], inSet)
+	for v, included := range opponentAttractor {
+		if included && g.owner[v] == opponent && opponentStrategy[v] >= 0 {
+			strategy[v] = opponentStrategy[v]
+		}
+	}
+
+	regions = g.zielonka(g.difference(inSet, opponentAttractor), strategy)
+	for v, included := range opponentAttractor {
+		if included {
+			regions[opponent][v] = true
+		}
+	}
+
+	return regions
+}
+
+/*
+difference
+Description:
+	Returns the vertices of set1 which are not in set2.
+*/
+func (g indexedGame) difference(set1 []bool, set2 []bool) []bool {
+	differenceOut := make([]bool, len(set1))
+	for v := range set1 {
+		differenceOut[v] = set1[v] && !set2[v]
+	}
+	return differenceOut
+}
+
+/*
+isNonempty
+Description:
+	Determines if some entry of the mask is true.
+*/
+func (g indexedGame) isNonempty(mask []bool) bool {
+	for _, included := range mask {
+		if included {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+/*
+zielonka_test.go
+Description:
+	Tests the functions created in zielonka.go
+*/
+
+package games
+
+import (
+	"testing"
+)
+
+/*
+checkSimpleArenaSolution
+Description:
+	Checks a solution of GetSimpleArena().
+*/
+func checkSimpleArenaSolution(t *testing.T, arena Arena, solution Solution) {
+	expectedWinners := map[string]Player{"a": Even, "b": Even, "c": Odd, "d": Even, "e": Odd, "f": Even}
+	for name, expectedWinner := range expectedWinners {
+		winner, found := solution.Winner(arena.VerticesNamed(name)[0])
+		if !found || winner != expectedWinner {
+			t.Errorf("Expected %v to win from %v, but found %v.", expectedWinner, name, winner)
+		}
+	}
+
+	expectedMoves := map[string]string{"a": "b", "e": "c", "f": "a"}
+	for name, expectedMove := range expectedMoves {
+		successor, found := solution.StrategyAt(arena.VerticesNamed(name)[0])
+		if !found || successor.Name != expectedMove {
+			t.Errorf("Expected the winning strategy to move from %v to %v, but found %v.", name, expectedMove, successor)
+		}
+	}
+
+	if err := arena.CheckSolution(solution); err != nil {
+		t.Errorf("Expected the solution to be correct, but received: %v", err)
+	}
+}
+
+func TestArena_SolveZielonka1(t *testing.T) {
+	arena := GetSimpleArena()
+
+	solution, err := arena.SolveZielonka()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	checkSimpleArenaSolution(t, arena, solution)
+}
+
+func TestArena_SolveZielonka2(t *testing.T) {
+	// Even wins by visiting priority 4 infinitely often, which Odd can only delay
+	arena, _ := GetArena(
+		[]string{"start", "odd", "even"},
+		map[string]Player{"start": Odd},
+		map[string]int{"start": 0, "odd": 3, "even": 4},
+		map[string][]string{
+			"start": {"odd", "even"},
+			"odd":   {"even"},
+			"even":  {"start"},
+		},
+	)
+
+	solution, err := arena.SolveZielonka()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(solution.W[Even]) != 3 {
+		t.Errorf("Expected Even to win everywhere, but its winning region is %v.", solution.W[Even])
+	}
+}
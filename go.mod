@@ -1,5 +1,5 @@
 module github.com/kwesiRutledge/ModelChecking
 
-go 1.16
+go 1.23
 
 require github.com/mxschmitt/golang-combinations v1.1.0
@@ -0,0 +1,434 @@
+/*
+imperfectinformation.go
+Description:
+ 	Concurrent Game Models in which the agents cannot see the full state.
+	Each agent has an observation function which maps states to observations; an agent cannot distinguish
+	two states with the same observation. This file defines the indistinguishability relations used by the
+	epistemic operators K, E and C, and the evaluation of ATL with uniform memoryless strategies (ATL_ir).
+*/
+package modelchecking
+
+import (
+	"fmt"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+UniformityViolation
+Description:
+	Describes a strategy which prescribes different actions to Agent in two states that Agent cannot distinguish.
+*/
+type UniformityViolation struct {
+	Agent   CGMAgent
+	Mode    int
+	States  [2]CGMState
+	Actions [2]string
+}
+
+/*
+Functions for UniformityViolation
+*/
+
+/*
+String
+Description:
+	Explains the violation.
+*/
+func (violation UniformityViolation) String() string {
+	return fmt.Sprintf(
+		"Agent \"%v\" cannot distinguish \"%v\" from \"%v\", but the strategy (mode %v) plays \"%v\" in the first and \"%v\" in the second.",
+		violation.Agent, violation.States[0], violation.States[1], violation.Mode, violation.Actions[0], violation.Actions[1],
+	)
+}
+
+/*
+Functions for ConcurrentGameModel
+*/
+
+/*
+WithObservationFunction
+Description:
+	Returns a copy of the game in which each agent observes observationFunction[agent][state] in each state.
+	Two states with the same observation are indistinguishable to the agent. A state without an
+	observation can be distinguished from every other state, so an agent without observations has perfect information.
+Usage:
+	cgm2, err := cgm.WithObservationFunction(map[string]map[string]string{
+		"Agent1": {"Florence": "mainland", "Rome": "mainland"},
+	})
+*/
+func (cgm ConcurrentGameModel) WithObservationFunction(observationFunction map[string]map[string]string) (ConcurrentGameModel, error) {
+	observationMap := make(map[CGMAgent]map[CGMState]string)
+	for agentName, stateMap := range observationFunction {
+		agents, err := cgm.AgentsNamed(agentName)
+		if err != nil {
+			return cgm, fmt.Errorf("There was an issue with the observation function: %v", err)
+		}
+
+		tempStateMap := make(map[CGMState]string)
+		for stateName, observation := range stateMap {
+			tempStateMap[CGMState{Name: stateName, ParentGame: agents[0].ParentGame}] = observation
+		}
+		observationMap[agents[0]] = tempStateMap
+	}
+
+	cgm.obs = observationMap
+	return cgm, cgm.CheckObservations()
+}
+
+/*
+CheckObservations
+Description:
+	Checks that the observation function only refers to agents and states of the game.
+*/
+func (cgm ConcurrentGameModel) CheckObservations() error {
+	for tempAgent, stateMap := range cgm.obs {
+		if !tempAgent.In(cgm.Agents) {
+			return fmt.Errorf("The agent \"%v\" in the observation function is not an agent of the game.", tempAgent)
+		}
+
+		for tempState := range stateMap {
+			if !tempState.In(cgm.St) {
+				return fmt.Errorf("The state \"%v\" in the observation function of agent \"%v\" is not a state of the game.", tempState, tempAgent)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+Observation
+Description:
+	Returns what the agent observes in the state. The second output is false if the agent has no observation
+	for the state, in which case the agent can distinguish the state from every other state.
+*/
+func (cgm ConcurrentGameModel) Observation(agent CGMAgent, state CGMState) (string, bool) {
+	for tempAgent, stateMap := range cgm.obs {
+		if !tempAgent.Equals(agent) {
+			continue
+		}
+		for tempState, observation := range stateMap {
+			if tempState.Equals(state) {
+				return observation, true
+			}
+		}
+	}
+	return "", false
+}
+
+/*
+Indistinguishable
+Description:
+	Determines if the agent cannot distinguish state1 from state2.
+*/
+func (cgm ConcurrentGameModel) Indistinguishable(agent CGMAgent, state1 CGMState, state2 CGMState) bool {
+	if state1.Equals(state2) {
+		return true
+	}
+
+	observation1, found1 := cgm.Observation(agent, state1)
+	observation2, found2 := cgm.Observation(agent, state2)
+	return found1 && found2 && observation1 == observation2
+}
+
+/*
+IndistinguishableStates
+Description:
+	Returns the states that at least one agent of the group cannot distinguish from state (including state itself).
+	For one agent, these are the states the agent considers possible.
+*/
+func (cgm ConcurrentGameModel) IndistinguishableStates(group []CGMAgent, state CGMState) []CGMState {
+	statesOut := []CGMState{}
+	for _, tempState := range cgm.St {
+		if tempState.Equals(state) {
+			statesOut = append(statesOut, tempState)
+			continue
+		}
+		for _, agent := range group {
+			if cgm.Indistinguishable(agent, state, tempState) {
+				statesOut = append(statesOut, tempState)
+				break
+			}
+		}
+	}
+	return statesOut
+}
+
+/*
+CommonKnowledgeStates
+Description:
+	Returns the states reachable from state by a chain of indistinguishability steps of agents in the group.
+	A formula is common knowledge in the group exactly when it holds in all of these states.
+*/
+func (cgm ConcurrentGameModel) CommonKnowledgeStates(group []CGMAgent, state CGMState) []CGMState {
+	reached := cgm.IndistinguishableStates(group, state)
+	for index := 0; index < len(reached); index++ {
+		for _, tempState := range cgm.IndistinguishableStates(group, reached[index]) {
+			reached = tempState.AppendIfUniqueTo(reached)
+		}
+	}
+	return cgm.intersectStates(cgm.St, reached)
+}
+
+/*
+SatisfyingStatesIR
+Description:
+	Returns the states of the game which satisfy the ATL formula when the coalitions are restricted to
+	uniform memoryless strategies (ATL_ir). A state satisfies <<A>>p when there is one uniform strategy of A
+	which enforces p from every state that some agent of A cannot distinguish from it.
+	The strategies are enumerated, so this is only practical for small games.
+*/
+func (cgm ConcurrentGameModel) SatisfyingStatesIR(formula ATLFormula) ([]CGMState, error) {
+	return cgm.satisfyingStates(formula, true)
+}
+
+/*
+SatisfiesIR
+Description:
+	Determines if the state satisfies the ATL formula under uniform memoryless strategies.
+*/
+func (cgm ConcurrentGameModel) SatisfiesIR(state CGMState, formula ATLFormula) (bool, error) {
+	if !state.In(cgm.St) {
+		return false, fmt.Errorf("The state \"%v\" is not in the game.", state)
+	}
+
+	sat, err := cgm.SatisfyingStatesIR(formula)
+	if err != nil {
+		return false, err
+	}
+
+	return state.In(sat), nil
+}
+
+/*
+SynthesizeUniformStrategy
+Description:
+	Searches for a uniform memoryless strategy of the coalition of the temporal formula which enforces the formula
+	from state and from every state that an agent of the coalition cannot distinguish from state.
+	The second output is false if there is no such strategy. Nested strategic formulas are evaluated
+	under imperfect information, but their strategies are not included in the result.
+*/
+func (cgm ConcurrentGameModel) SynthesizeUniformStrategy(state CGMState, formula ATLFormula) (CoalitionStrategy, bool, error) {
+	// Input Processing
+	if !formula.IsTemporal() {
+		return CoalitionStrategy{}, false, fmt.Errorf("The formula %v does not start with a coalition operator.", formula)
+	}
+
+	if !state.In(cgm.St) {
+		return CoalitionStrategy{}, false, fmt.Errorf("The state \"%v\" is not in the game.", state)
+	}
+
+	coalition, err := cgm.AgentsNamed(formula.Coalition...)
+	if err != nil {
+		return CoalitionStrategy{}, false, fmt.Errorf("There was an issue with the coalition of %v: %v", formula, err)
+	}
+
+	var operandSats [][]CGMState
+	for _, operand := range formula.Operands {
+		sat, err := cgm.SatisfyingStatesIR(operand)
+		if err != nil {
+			return CoalitionStrategy{}, false, err
+		}
+		operandSats = append(operandSats, sat)
+	}
+
+	// Algorithm
+	considered := cgm.IndistinguishableStates(coalition, state)
+	for _, actions := range cgm.uniformStrategies(coalition) {
+		enforced := cgm.temporalFixedPoint(formula.Operator, operandSats, cgm.strategyPredecessor(coalition, actions))
+		if len(cgm.intersectStates(considered, enforced)) == len(considered) {
+			return NewMemorylessStrategy(coalition, actions), true, nil
+		}
+	}
+
+	return CoalitionStrategy{}, false, nil
+}
+
+/*
+UniformityViolations
+Description:
+	Returns every pair of states in which the strategy prescribes different actions to an agent
+	who cannot distinguish the two states. A strategy without violations can be played under imperfect information.
+*/
+func (cgm ConcurrentGameModel) UniformityViolations(strategy CoalitionStrategy) []UniformityViolation {
+	var violations []UniformityViolation
+	for mode := range strategy.Actions {
+		for _, agent := range strategy.Coalition {
+			for index1, state1 := range cgm.St {
+				for _, state2 := range cgm.St[index1+1:] {
+					if !cgm.Indistinguishable(agent, state1, state2) {
+						continue
+					}
+
+					action1, found1 := strategyAction(strategy, mode, state1, agent)
+					action2, found2 := strategyAction(strategy, mode, state2, agent)
+					if found1 && found2 && action1 != action2 {
+						violations = append(violations, UniformityViolation{
+							Agent:   agent,
+							Mode:    mode,
+							States:  [2]CGMState{state1, state2},
+							Actions: [2]string{action1, action2},
+						})
+					}
+				}
+			}
+		}
+	}
+	return violations
+}
+
+/*
+strategyAction
+Description:
+	Returns the action that the strategy prescribes to the agent in the state and mode.
+*/
+func strategyAction(strategy CoalitionStrategy, mode int, state CGMState, agent CGMAgent) (string, bool) {
+	actions, found := strategy.ActionsAt(mode, state)
+	if !found {
+		return "", false
+	}
+	for tempAgent, action := range actions {
+		if tempAgent.Equals(agent) {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+/*
+uniformTemporalSatisfyingStates
+Description:
+	Computes the satisfying states of <<A>>X, G, F or U under uniform memoryless strategies
+	from the satisfying states of the operands.
+*/
+func (cgm ConcurrentGameModel) uniformTemporalSatisfyingStates(operator ATLOperator, coalition []CGMAgent, operandSats [][]CGMState) []CGMState {
+	// Compute the states from which each uniform strategy enforces the formula
+	var enforcedSets [][]CGMState
+	for _, actions := range cgm.uniformStrategies(coalition) {
+		enforcedSets = append(enforcedSets, cgm.temporalFixedPoint(operator, operandSats, cgm.strategyPredecessor(coalition, actions)))
+	}
+
+	// A state satisfies the formula if one strategy works from every state the coalition considers possible
+	satOut := []CGMState{}
+	for _, state := range cgm.St {
+		considered := cgm.IndistinguishableStates(coalition, state)
+		for _, enforced := range enforcedSets {
+			if len(cgm.intersectStates(considered, enforced)) == len(considered) {
+				satOut = append(satOut, state)
+				break
+			}
+		}
+	}
+	return satOut
+}
+
+/*
+strategyPredecessor
+Description:
+	Returns the function which maps a target to the states from which the coalition's fixed strategy
+	leads to target for every move of the other agents. A state in which the strategy assigns no action
+	to some agent of the coalition is never a predecessor.
+*/
+func (cgm ConcurrentGameModel) strategyPredecessor(coalition []CGMAgent, actions map[CGMState]map[CGMAgent]string) func([]CGMState) []CGMState {
+	var opponents []CGMAgent
+	for _, agent := range cgm.Agents {
+		if !agent.In(coalition) {
+			opponents = append(opponents, agent)
+		}
+	}
+
+	return func(target []CGMState) []CGMState {
+		var predecessors []CGMState
+		for _, state := range cgm.St {
+			coalitionMove := make([]string, len(coalition))
+			assigned := true
+			for agentIndex, agent := range coalition {
+				coalitionMove[agentIndex], assigned = actions[state][agent]
+				if !assigned {
+					break
+				}
+			}
+			if assigned && cgm.moveEnforces(state, coalition, coalitionMove, opponents, target) {
+				predecessors = append(predecessors, state)
+			}
+		}
+		return predecessors
+	}
+}
+
+/*
+uniformStrategies
+Description:
+	Enumerates the uniform memoryless strategies of the coalition. In each set of states that an agent cannot
+	distinguish, the agent plays one action which is permitted in all of those states.
+	If some set of states has no such action, then the agent's action is left unassigned in those states
+	and the strategies lose from them (see strategyPredecessor()).
+*/
+func (cgm ConcurrentGameModel) uniformStrategies(coalition []CGMAgent) []map[CGMState]map[CGMAgent]string {
+	strategies := []map[CGMState]map[CGMAgent]string{{}}
+	for _, state := range cgm.St {
+		strategies[0][state] = make(map[CGMAgent]string)
+	}
+
+	for _, agent := range coalition {
+		// Partition the states into observation classes
+		var classes [][]CGMState
+		for _, state := range cgm.St {
+			placed := false
+			for classIndex, class := range classes {
+				if cgm.Indistinguishable(agent, class[0], state) {
+					classes[classIndex] = append(class, state)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				classes = append(classes, []CGMState{state})
+			}
+		}
+
+		for _, class := range classes {
+			// Find the actions permitted in the whole class
+			commonActions := cgm.PermittedActions(agent, class[0])
+			for _, state := range class[1:] {
+				var stillCommon []string
+				for _, action := range commonActions {
+					if _, found := FindInSlice(action, cgm.PermittedActions(agent, state)); found {
+						stillCommon = append(stillCommon, action)
+					}
+				}
+				commonActions = stillCommon
+			}
+
+			// Leave the class unassigned when no action is permitted in all of its states
+			if len(commonActions) == 0 {
+				continue
+			}
+
+			// Extend every strategy with each common action
+			var nextStrategies []map[CGMState]map[CGMAgent]string
+			for _, strategy := range strategies {
+				for _, action := range commonActions {
+					nextStrategy := make(map[CGMState]map[CGMAgent]string)
+					for state, actionMap := range strategy {
+						nextActionMap := make(map[CGMAgent]string)
+						for tempAgent, tempAction := range actionMap {
+							nextActionMap[tempAgent] = tempAction
+						}
+						if state.In(class) {
+							nextActionMap[agent] = action
+						}
+						nextStrategy[state] = nextActionMap
+					}
+					nextStrategies = append(nextStrategies, nextStrategy)
+				}
+			}
+			strategies = nextStrategies
+		}
+	}
+
+	return strategies
+}
@@ -0,0 +1,217 @@
+/*
+imperfectinformation_test.go
+Description:
+	Tests the functions and objects created in imperfectinformation.go
+*/
+package modelchecking
+
+import (
+	"testing"
+)
+
+/*
+CreateShellGameCGM
+Description:
+	Creates a game where Env sends the play left or right, and Player must then play "a" on the left
+	and "b" on the right to win. Player cannot distinguish left from right, and Env cannot distinguish
+	right from win.
+*/
+func CreateShellGameCGM() (ConcurrentGameModel, error) {
+	cgm, err := CreateConcurrentGameModel(
+		[]string{"Player", "Env"},
+		[]string{"start", "left", "right", "win", "lose"},
+		[]string{"goal", "middle"},
+		[]string{"wait", "goLeft", "goRight", "a", "b"},
+		map[string]map[string][]string{
+			"Player": {
+				"start": {"wait"}, "left": {"a", "b"}, "right": {"a", "b"}, "win": {"wait"}, "lose": {"wait"},
+			},
+			"Env": {
+				"start": {"goLeft", "goRight"}, "left": {"wait"}, "right": {"wait"}, "win": {"wait"}, "lose": {"wait"},
+			},
+		},
+		map[string]map[string]string{
+			"start": {"wait, goLeft": "left", "wait, goRight": "right"},
+			"left":  {"a, wait": "win", "b, wait": "lose"},
+			"right": {"a, wait": "lose", "b, wait": "win"},
+			"win":   {"wait, wait": "win"},
+			"lose":  {"wait, wait": "lose"},
+		},
+		map[string][]string{
+			"goal":   {"win"},
+			"middle": {"left", "right"},
+		},
+	)
+	if err != nil {
+		return cgm, err
+	}
+
+	return cgm.WithObservationFunction(map[string]map[string]string{
+		"Player": {"left": "middle", "right": "middle"},
+		"Env":    {"right": "rightOrWin", "win": "rightOrWin"},
+	})
+}
+
+func TestImperfectInformation_WithObservationFunction1(t *testing.T) {
+	// Constants
+	cgm, err := CreateShellGameCGM()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	player := cgm.Agents[0]
+
+	// Test
+	if !cgm.Indistinguishable(player, CGMState{Name: "left"}, CGMState{Name: "right"}) {
+		t.Errorf("Expected Player to confuse left and right.")
+	}
+
+	if cgm.Indistinguishable(player, CGMState{Name: "win"}, CGMState{Name: "lose"}) {
+		t.Errorf("Expected Player to distinguish win and lose.")
+	}
+
+	// An observation function with an unknown state is rejected
+	_, err = cgm.WithObservationFunction(map[string]map[string]string{"Player": {"nowhere": "x"}})
+	if err == nil {
+		t.Errorf("Expected an error for the unknown state \"nowhere\".")
+	}
+}
+
+func TestImperfectInformation_Epistemic1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateShellGameCGM()
+	left := CGMState{Name: "left"}
+	right := CGMState{Name: "right"}
+
+	// Test K, E and C
+	testCases := []struct {
+		FormulaString string
+		State         CGMState
+		Expected      bool
+	}{
+		{"K[Player] middle", left, true},
+		{"K[Env] middle", right, false},
+		{"E[Player,Env] middle", left, true},
+		{"C[Player,Env] middle", left, false},
+		{"K[Player] <<Player>>X goal", left, true},
+	}
+
+	for _, testCase := range testCases {
+		formula, err := ParseATLFormula(testCase.FormulaString)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %v: %v", testCase.FormulaString, err)
+			continue
+		}
+
+		tf, err := cgm.Satisfies(testCase.State, formula)
+		if err != nil {
+			t.Errorf("Unexpected error checking %v: %v", formula, err)
+		}
+
+		if tf != testCase.Expected {
+			t.Errorf("Expected %v in %v to be %v.", formula, testCase.State, testCase.Expected)
+		}
+	}
+}
+
+func TestImperfectInformation_SatisfyingStatesIR1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateShellGameCGM()
+	start := CGMState{Name: "start"}
+	formula, _ := ParseATLFormula("<<Player>>F goal")
+
+	// Test: Player wins with perfect information, but not with uniform strategies
+	tf, _ := cgm.Satisfies(start, formula)
+	if !tf {
+		t.Errorf("Expected %v to hold in start under perfect information.", formula)
+	}
+
+	tf, err := cgm.SatisfiesIR(start, formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if tf {
+		t.Errorf("Expected %v to fail in start under imperfect information.", formula)
+	}
+
+	// Together, Env can choose the side that Player's uniform strategy wins on.
+	formula, _ = ParseATLFormula("<<Player,Env>>F goal")
+	if tf, _ = cgm.SatisfiesIR(start, formula); !tf {
+		t.Errorf("Expected %v to hold in start under imperfect information.", formula)
+	}
+
+	strategy, found, err := cgm.SynthesizeUniformStrategy(start, formula)
+	if err != nil || !found {
+		t.Errorf("Expected a uniform strategy for %v: %v", formula, err)
+	}
+
+	if violations := cgm.UniformityViolations(strategy); len(violations) > 0 {
+		t.Errorf("Expected the synthesized strategy to be uniform, but found %v.", violations)
+	}
+}
+
+func TestImperfectInformation_UniformityViolations1(t *testing.T) {
+	// Constants
+	cgm, _ := CreateShellGameCGM()
+	formula, _ := ParseATLFormula("<<Player>>F goal")
+
+	// Test: the perfect-information strategy plays a on the left and b on the right
+	strategy, _, err := cgm.SynthesizeStrategy(formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	violations := cgm.UniformityViolations(strategy)
+	if len(violations) != 1 {
+		t.Errorf("Expected one uniformity violation, but found %v.", violations)
+		return
+	}
+
+	if violations[0].Agent.Name != "Player" || violations[0].States[0].Name != "left" || violations[0].States[1].Name != "right" {
+		t.Errorf("Unexpected violation: %v", violations[0])
+	}
+
+	if _, found, _ := cgm.SynthesizeUniformStrategy(CGMState{Name: "start"}, formula); found {
+		t.Errorf("Expected no uniform strategy for %v from start.", formula)
+	}
+}
+
+/*
+TestImperfectInformation_SatisfyingStatesIR2
+Description:
+	Verifies that a state in which an agent has no actions does not remove the agent's uniform strategies
+	in the other states.
+*/
+func TestImperfectInformation_SatisfyingStatesIR2(t *testing.T) {
+	// Constants
+	cgm, err := CreateConcurrentGameModel(
+		[]string{"a"},
+		[]string{"s0", "s1", "dead"},
+		[]string{"p"},
+		[]string{"go", "stay"},
+		map[string]map[string][]string{
+			"a": {"s0": {"go", "stay"}, "s1": {"stay"}},
+		},
+		map[string]map[string]string{
+			"s0": {"go": "s1", "stay": "s0"},
+			"s1": {"stay": "s1"},
+		},
+		map[string][]string{
+			"p": {"s1"},
+		},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	formula, _ := ParseATLFormula("<<a>>F p")
+
+	// Algorithm
+	expected, _ := cgm.SatisfyingStates(formula)
+	sat, err := cgm.SatisfyingStatesIR(formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(sat) != 2 || len(cgm.intersectStates(sat, expected)) != len(expected) {
+		t.Errorf("Expected %v to hold in %v, but it holds in %v.", formula, expected, sat)
+	}
+}
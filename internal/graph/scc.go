@@ -0,0 +1,131 @@
+/*
+scc.go
+Description:
+	Strongly connected components of directed graphs given by successor lists, shared by the packages which
+	decompose automata, games and Markov models. The vertices are the indices 0, ..., n-1.
+*/
+
+package graph
+
+/*
+Type Definitions
+*/
+
+/*
+frame
+Description:
+	A vertex whose successors are being explored, and the position of the next successor to explore.
+*/
+type frame struct {
+	Vertex int
+	Next   int
+}
+
+/*
+Functions
+*/
+
+/*
+StronglyConnectedComponents
+Description:
+	Computes the strongly connected components of the graph restricted to the allowed vertices with Tarjan's
+	algorithm. A nil allowed slice allows every vertex. The components are returned in reverse topological
+	order, i.e. every component is listed before the components which can reach it. The depth-first search
+	keeps its own stack, so long paths do not exhaust the goroutine stack.
+Usage:
+	components := graph.StronglyConnectedComponents([][]int{{1}, {0}, {0}}, nil) // [[1 0] [2]]
+*/
+func StronglyConnectedComponents(successors [][]int, allowed []bool) [][]int {
+	n := len(successors)
+	isAllowed := func(v int) bool {
+		return allowed == nil || allowed[v]
+	}
+
+	index := make([]int, n)
+	lowLink := make([]int, n)
+	onStack := make([]bool, n)
+	for v := range index {
+		index[v] = -1
+	}
+
+	var stack []int
+	var components [][]int
+	counter := 0
+
+	discover := func(v int) {
+		index[v], lowLink[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+	}
+
+	for root := 0; root < n; root++ {
+		if !isAllowed(root) || index[root] >= 0 {
+			continue
+		}
+
+		discover(root)
+		calls := []frame{{Vertex: root}}
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			v := top.Vertex
+
+			// Explore the next successor of v
+			if top.Next < len(successors[v]) {
+				w := successors[v][top.Next]
+				top.Next++
+				if !isAllowed(w) {
+					continue
+				}
+				if index[w] < 0 {
+					discover(w)
+					calls = append(calls, frame{Vertex: w})
+				} else if onStack[w] {
+					lowLink[v] = min(lowLink[v], index[w])
+				}
+				continue
+			}
+
+			// Every successor of v is explored: return to its caller
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				caller := calls[len(calls)-1].Vertex
+				lowLink[caller] = min(lowLink[caller], lowLink[v])
+			}
+
+			if lowLink[v] == index[v] {
+				var component []int
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component = append(component, w)
+					if w == v {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+		}
+	}
+
+	return components
+}
+
+/*
+IsNontrivial
+Description:
+	Determines if a strongly connected component contains a cycle, i.e. it has more than one vertex or its
+	only vertex has a self-loop.
+*/
+func IsNontrivial(component []int, successors [][]int) bool {
+	if len(component) > 1 {
+		return true
+	}
+	for _, w := range successors[component[0]] {
+		if w == component[0] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+/*
+scc_test.go
+Description:
+	Tests for the strongly connected components computed in scc.go
+*/
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+/*
+TestStronglyConnectedComponents1
+Description:
+
+	A graph with the cycle 0 -> 1 -> 2 -> 0, the vertex 3 which enters it and the self-loop of 4, which is
+	reached from 3. The components are listed before the components which reach them.
+*/
+func TestStronglyConnectedComponents1(t *testing.T) {
+	successors := [][]int{{1}, {2}, {0}, {0, 4}, {4}}
+
+	components := StronglyConnectedComponents(successors, nil)
+	if s := fmt.Sprint(components); s != "[[2 1 0] [4] [3]]" {
+		t.Errorf("Expected the components [[2 1 0] [4] [3]], but received %v.", s)
+	}
+
+	for _, component := range components {
+		expected := component[0] != 3
+		if IsNontrivial(component, successors) != expected {
+			t.Errorf("Expected IsNontrivial(%v) to be %v.", component, expected)
+		}
+	}
+}
+
+/*
+TestStronglyConnectedComponents2
+Description:
+
+	Vertices which are not allowed are neither listed nor followed, so removing 2 breaks the cycle.
+*/
+func TestStronglyConnectedComponents2(t *testing.T) {
+	successors := [][]int{{1}, {2}, {0}, {0, 4}, {4}}
+
+	components := StronglyConnectedComponents(successors, []bool{true, true, false, true, false})
+	if s := fmt.Sprint(components); s != "[[1] [0] [3]]" {
+		t.Errorf("Expected the components [[1] [0] [3]], but received %v.", s)
+	}
+}
+
+/*
+TestStronglyConnectedComponents3
+Description:
+
+	A path of a million vertices closed into a cycle is one component; the search does not recurse.
+*/
+func TestStronglyConnectedComponents3(t *testing.T) {
+	n := 1000000
+	successors := make([][]int, n)
+	for v := range successors {
+		successors[v] = []int{(v + 1) % n}
+	}
+
+	components := StronglyConnectedComponents(successors, nil)
+	if len(components) != 1 || len(components[0]) != n {
+		t.Errorf("Expected one component with %v vertices, but received %v components.", n, len(components))
+	}
+}
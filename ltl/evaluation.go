@@ -0,0 +1,220 @@
+/*
+evaluation.go
+Description:
+	Exact evaluation of LTL formulas on ultimately periodic words prefix (cycle)^omega and of LTLf formulas
+	on finite words. Each subformula is evaluated at every position of the word, so the running time is
+	linear in |word|·|formula|. On a lasso, the fixed points of U and R are found with two backward passes
+	over the cycle.
+*/
+
+package ltl
+
+import (
+	"errors"
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+EvaluateLasso
+Description:
+	Determines if the infinite word prefix (cycle)^omega satisfies the formula.
+	Each letter of the word is the set of atomic propositions which hold at that position.
+Usage:
+	formula, _ := Parse("G F p")
+	satisfied, err := EvaluateLasso(formula, [][]mc.AtomicProposition{{}}, [][]mc.AtomicProposition{{p}})
+*/
+func EvaluateLasso(formula Formula, prefix [][]mc.AtomicProposition, cycle [][]mc.AtomicProposition) (bool, error) {
+	if len(cycle) == 0 {
+		return false, errors.New("The cycle of an infinite word must contain at least one letter.")
+	}
+
+	word := append(append([][]mc.AtomicProposition{}, prefix...), cycle...)
+	values, err := evaluate(formula, word, len(prefix))
+	if err != nil {
+		return false, err
+	}
+
+	return values[0], nil
+}
+
+/*
+EvaluateFinite
+Description:
+	Determines if the finite, nonempty word satisfies the formula with the LTLf semantics:
+	X requires a next position, N holds at the last position, G only ranges over the remaining positions
+	and F, U must be fulfilled before the word ends.
+*/
+func EvaluateFinite(formula Formula, word [][]mc.AtomicProposition) (bool, error) {
+	if len(word) == 0 {
+		return false, errors.New("LTLf formulas are evaluated on nonempty words.")
+	}
+
+	values, err := evaluate(formula, word, -1)
+	if err != nil {
+		return false, err
+	}
+
+	return values[0], nil
+}
+
+/*
+evaluate
+Description:
+	Returns the truth value of the formula at every position of the word. The successor of the last position
+	is loopStart, or there is none if loopStart is negative.
+*/
+func evaluate(formula Formula, word [][]mc.AtomicProposition, loopStart int) ([]bool, error) {
+	n := len(word)
+	values := make([]bool, n)
+
+	// Evaluate the operands first
+	var operandValues [][]bool
+	for _, operand := range formula.Operands {
+		operandValue, err := evaluate(operand, word, loopStart)
+		if err != nil {
+			return nil, err
+		}
+		operandValues = append(operandValues, operandValue)
+	}
+
+	switch formula.Operator {
+	case OpTrue:
+		for i := range values {
+			values[i] = true
+		}
+
+	case OpFalse:
+
+	case OpAtom:
+		for i, letter := range word {
+			values[i] = formula.Atom.In(letter)
+		}
+
+	case OpNot:
+		for i := range values {
+			values[i] = !operandValues[0][i]
+		}
+
+	case OpAnd, OpOr:
+		for i := range values {
+			values[i] = formula.Operator == OpAnd
+			for _, operandValue := range operandValues {
+				if formula.Operator == OpAnd {
+					values[i] = values[i] && operandValue[i]
+				} else {
+					values[i] = values[i] || operandValue[i]
+				}
+			}
+		}
+
+	case OpImplies:
+		for i := range values {
+			values[i] = !operandValues[0][i] || operandValues[1][i]
+		}
+
+	case OpNext, OpWeakNext:
+		for i := range values {
+			if successor := nextPosition(i, n, loopStart); successor >= 0 {
+				values[i] = operandValues[0][successor]
+			} else {
+				values[i] = formula.Operator == OpWeakNext
+			}
+		}
+
+	case OpEventually:
+		values = fixedPoint(constantValues(n, true), operandValues[0], loopStart, false)
+
+	case OpAlways:
+		values = fixedPoint(constantValues(n, false), operandValues[0], loopStart, true)
+
+	case OpUntil:
+		values = fixedPoint(operandValues[0], operandValues[1], loopStart, false)
+
+	case OpRelease:
+		values = fixedPoint(operandValues[0], operandValues[1], loopStart, true)
+
+	case OpWeakUntil:
+		// a W b = b R (a | b)
+		leftOrRight := make([]bool, n)
+		for i := range leftOrRight {
+			leftOrRight[i] = operandValues[0][i] || operandValues[1][i]
+		}
+		values = fixedPoint(operandValues[1], leftOrRight, loopStart, true)
+
+	default:
+		return nil, fmt.Errorf("Unrecognized LTL operator %v.", formula.Operator)
+	}
+
+	return values, nil
+}
+
+/*
+fixedPoint
+Description:
+	Computes left U right (release = false, least fixed point of right | (left & X Z)) or
+	left R right (release = true, greatest fixed point of right & (left | X Z)) at every position.
+	On a finite word Z is false (for U) or true (for R) after the last position.
+*/
+func fixedPoint(left []bool, right []bool, loopStart int, release bool) []bool {
+	n := len(left)
+	values := make([]bool, n)
+	step := func(i int, nextValue bool) bool {
+		if release {
+			return right[i] && (left[i] || nextValue)
+		}
+		return right[i] || (left[i] && nextValue)
+	}
+
+	if loopStart < 0 {
+		nextValue := release
+		for i := n - 1; i >= 0; i-- {
+			values[i] = step(i, nextValue)
+			nextValue = values[i]
+		}
+		return values
+	}
+
+	// The cycle: start from the bottom (or top) and go around twice
+	for i := loopStart; i < n; i++ {
+		values[i] = release
+	}
+	for pass := 0; pass < 2; pass++ {
+		for i := n - 1; i >= loopStart; i-- {
+			values[i] = step(i, values[nextPosition(i, n, loopStart)])
+		}
+	}
+
+	// The prefix
+	for i := loopStart - 1; i >= 0; i-- {
+		values[i] = step(i, values[i+1])
+	}
+
+	return values
+}
+
+/*
+nextPosition
+Description:
+	Returns the position after i, or -1 if i is the last position of a finite word.
+*/
+func nextPosition(i int, n int, loopStart int) int {
+	if i+1 < n {
+		return i + 1
+	}
+	return loopStart
+}
+
+/*
+constantValues
+Description:
+	Returns n copies of value.
+*/
+func constantValues(n int, value bool) []bool {
+	values := make([]bool, n)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
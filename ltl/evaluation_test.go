@@ -0,0 +1,236 @@
+/*
+evaluation_test.go
+Description:
+
+	Tests for the evaluation of LTL formulas defined in evaluation.go
+*/
+package ltl
+
+import (
+	"math/rand"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+getWord
+Description:
+
+	Creates a word in which each letter is the set of propositions named by the characters of a string.
+	For example, getWord("ab", "", "b") is {a,b} {} {b}.
+*/
+func getWord(letters ...string) [][]mc.AtomicProposition {
+	word := [][]mc.AtomicProposition{}
+	for _, letter := range letters {
+		set := []mc.AtomicProposition{}
+		for _, r := range letter {
+			set = append(set, mc.AtomicProposition{Name: string(r)})
+		}
+		word = append(word, set)
+	}
+	return word
+}
+
+/*
+TestEvaluateLasso1
+Description:
+
+	Evaluates a few formulas on the word {} {a} ({b} {a,b})^omega.
+*/
+func TestEvaluateLasso1(t *testing.T) {
+	prefix := getWord("", "a")
+	cycle := getWord("b", "ab")
+
+	testCases := map[string]bool{
+		"a":              false,
+		"X a":            true,
+		"N a":            true,
+		"X X b":          true,
+		"F a":            true,
+		"G F a":          true,
+		"F G b":          true,
+		"G b":            false,
+		"G (a -> F b)":   true,
+		"!a U b":         false,
+		"true U (a & b)": true,
+		"X (a U b)":      true,
+		"a R b":          false,
+		"X X (a R b)":    true,
+		"X X G b":        true,
+		"!b W a":         true,
+		"G (!b W a)":     false,
+		"F G (a | b)":    true,
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := Parse(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		satisfied, err := EvaluateLasso(formula, prefix, cycle)
+		if err != nil {
+			t.Errorf("Unexpected error while evaluating \"%v\": %v", formulaString, err)
+		}
+
+		if satisfied != expected {
+			t.Errorf("Expected \"%v\" to evaluate to %v, but found %v.", formulaString, expected, satisfied)
+		}
+	}
+}
+
+/*
+TestEvaluateLasso2
+Description:
+
+	A lasso needs a nonempty cycle.
+*/
+func TestEvaluateLasso2(t *testing.T) {
+	_, err := EvaluateLasso(Atom("a"), getWord("a"), getWord())
+	if err == nil {
+		t.Errorf("Expected an error for the empty cycle.")
+	}
+
+	if err.Error() != "The cycle of an infinite word must contain at least one letter." {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+/*
+TestEvaluateLasso3
+Description:
+
+	Random formulas have the same value on different representations of the same infinite word:
+	u (v)^omega, u v (v v)^omega and u v_1 (v_2 ... v_n v_1)^omega.
+*/
+func TestEvaluateLasso3(t *testing.T) {
+	random := rand.New(rand.NewSource(40))
+	letters := []string{"", "a", "b", "ab"}
+
+	for trial := 0; trial < 200; trial++ {
+		var prefix, cycle []string
+		for i := random.Intn(3); i > 0; i-- {
+			prefix = append(prefix, letters[random.Intn(len(letters))])
+		}
+		for i := 1 + random.Intn(3); i > 0; i-- {
+			cycle = append(cycle, letters[random.Intn(len(letters))])
+		}
+
+		formula := randomFormula(random, 4)
+
+		expected, err := EvaluateLasso(formula, getWord(prefix...), getWord(cycle...))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		unrolledPrefix := append(append([]string{}, prefix...), cycle...)
+		unrolledCycle := append(append([]string{}, cycle...), cycle...)
+		unrolled, _ := EvaluateLasso(formula, getWord(unrolledPrefix...), getWord(unrolledCycle...))
+
+		rotatedPrefix := append(append([]string{}, prefix...), cycle[0])
+		rotatedCycle := append(append([]string{}, cycle[1:]...), cycle[0])
+		rotated, _ := EvaluateLasso(formula, getWord(rotatedPrefix...), getWord(rotatedCycle...))
+
+		if unrolled != expected || rotated != expected {
+			t.Errorf(
+				"Expected %v to have the same value on %v (%v)^omega and its other representations, but found %v, %v and %v.",
+				formula, prefix, cycle, expected, unrolled, rotated,
+			)
+		}
+	}
+}
+
+/*
+randomFormula
+Description:
+
+	Creates a random formula over the atoms a and b with at most the given depth.
+*/
+func randomFormula(random *rand.Rand, depth int) Formula {
+	if depth == 0 || random.Intn(4) == 0 {
+		return Atom([]string{"a", "b"}[random.Intn(2)])
+	}
+
+	switch random.Intn(10) {
+	case 0:
+		return Not(randomFormula(random, depth-1))
+	case 1:
+		return And(randomFormula(random, depth-1), randomFormula(random, depth-1))
+	case 2:
+		return Or(randomFormula(random, depth-1), randomFormula(random, depth-1))
+	case 3:
+		return Next(randomFormula(random, depth-1))
+	case 4:
+		return Always(randomFormula(random, depth-1))
+	case 5:
+		return Eventually(randomFormula(random, depth-1))
+	case 6:
+		return Until(randomFormula(random, depth-1), randomFormula(random, depth-1))
+	case 7:
+		return Release(randomFormula(random, depth-1), randomFormula(random, depth-1))
+	case 8:
+		return WeakUntil(randomFormula(random, depth-1), randomFormula(random, depth-1))
+	default:
+		return Implies(randomFormula(random, depth-1), randomFormula(random, depth-1))
+	}
+}
+
+/*
+TestEvaluateFinite1
+Description:
+
+	Evaluates a few formulas on the finite word {a} {a} {b} with the LTLf semantics.
+*/
+func TestEvaluateFinite1(t *testing.T) {
+	word := getWord("a", "a", "b")
+
+	testCases := map[string]bool{
+		"a U b":       true,
+		"G a":         false,
+		"F b":         true,
+		"X X b":       true,
+		"X X X b":     false,
+		"X X N b":     true,
+		"X X N false": true,
+		"X X X true":  false,
+		"F G b":       true,
+		"G F a":       false,
+		"a R a":       true,
+		"b R a":       false,
+		"a W false":   false,
+		"G (a | b)":   true,
+		"X X G b":     true,
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := Parse(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		satisfied, err := EvaluateFinite(formula, word)
+		if err != nil {
+			t.Errorf("Unexpected error while evaluating \"%v\": %v", formulaString, err)
+		}
+
+		if satisfied != expected {
+			t.Errorf("Expected \"%v\" to evaluate to %v, but found %v.", formulaString, expected, satisfied)
+		}
+	}
+}
+
+/*
+TestEvaluateFinite2
+Description:
+
+	LTLf formulas are not evaluated on the empty word.
+*/
+func TestEvaluateFinite2(t *testing.T) {
+	_, err := EvaluateFinite(True(), getWord())
+	if err == nil {
+		t.Errorf("Expected an error for the empty word.")
+	}
+}
@@ -0,0 +1,288 @@
+/*
+formula.go
+Description:
+	Formulas of Linear Temporal Logic (LTL) over the atomic propositions of the modelchecking package.
+	The temporal operators are X (next), N (weak next), G (always), F (eventually), U (until), R (release)
+	and W (weak until). On infinite words N is the same as X; on finite words (LTLf) X requires a next
+	position and N holds at the last position.
+*/
+
+package ltl
+
+import (
+	"fmt"
+	"strings"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+type Operator int
+
+const (
+	OpTrue Operator = iota
+	OpFalse
+	OpAtom
+	OpNot
+	OpAnd
+	OpOr
+	OpImplies
+	OpNext
+	OpWeakNext
+	OpAlways
+	OpEventually
+	OpUntil
+	OpRelease
+	OpWeakUntil
+)
+
+/*
+Formula
+Description:
+	A node of the syntax tree of an LTL formula. Atom is only used by OpAtom.
+*/
+type Formula struct {
+	Operator Operator
+	Atom     mc.AtomicProposition
+	Operands []Formula
+}
+
+/*
+Constructors
+*/
+
+/*
+True
+Description:
+	The formula which holds at every position.
+*/
+func True() Formula {
+	return Formula{Operator: OpTrue}
+}
+
+/*
+False
+Description:
+	The formula which holds at no position.
+*/
+func False() Formula {
+	return Formula{Operator: OpFalse}
+}
+
+/*
+Atom
+Description:
+	The formula which holds at the positions labelled with the atomic proposition of the given name.
+*/
+func Atom(name string) Formula {
+	return Formula{Operator: OpAtom, Atom: mc.AtomicProposition{Name: name}}
+}
+
+/*
+Not
+Description:
+	The negation of the formula.
+*/
+func Not(formula Formula) Formula {
+	return Formula{Operator: OpNot, Operands: []Formula{formula}}
+}
+
+/*
+And
+Description:
+	The conjunction of the formulas (true if there are none).
+*/
+func And(formulas ...Formula) Formula {
+	return Formula{Operator: OpAnd, Operands: formulas}
+}
+
+/*
+Or
+Description:
+	The disjunction of the formulas (false if there are none).
+*/
+func Or(formulas ...Formula) Formula {
+	return Formula{Operator: OpOr, Operands: formulas}
+}
+
+/*
+Implies
+Description:
+	The formula premise -> conclusion.
+*/
+func Implies(premise Formula, conclusion Formula) Formula {
+	return Formula{Operator: OpImplies, Operands: []Formula{premise, conclusion}}
+}
+
+/*
+Next
+Description:
+	X formula: there is a next position and the formula holds there.
+*/
+func Next(formula Formula) Formula {
+	return Formula{Operator: OpNext, Operands: []Formula{formula}}
+}
+
+/*
+WeakNext
+Description:
+	N formula: if there is a next position, then the formula holds there.
+*/
+func WeakNext(formula Formula) Formula {
+	return Formula{Operator: OpWeakNext, Operands: []Formula{formula}}
+}
+
+/*
+Always
+Description:
+	G formula: the formula holds at every position from now on.
+*/
+func Always(formula Formula) Formula {
+	return Formula{Operator: OpAlways, Operands: []Formula{formula}}
+}
+
+/*
+Eventually
+Description:
+	F formula: the formula holds at some position from now on.
+*/
+func Eventually(formula Formula) Formula {
+	return Formula{Operator: OpEventually, Operands: []Formula{formula}}
+}
+
+/*
+Until
+Description:
+	left U right: right holds at some position and left holds at every position before it.
+*/
+func Until(left Formula, right Formula) Formula {
+	return Formula{Operator: OpUntil, Operands: []Formula{left, right}}
+}
+
+/*
+Release
+Description:
+	left R right: right holds up to and including the first position where left holds (or forever).
+*/
+func Release(left Formula, right Formula) Formula {
+	return Formula{Operator: OpRelease, Operands: []Formula{left, right}}
+}
+
+/*
+WeakUntil
+Description:
+	left W right: left U right, or left holds forever.
+*/
+func WeakUntil(left Formula, right Formula) Formula {
+	return Formula{Operator: OpWeakUntil, Operands: []Formula{left, right}}
+}
+
+/*
+Functions for Formula
+*/
+
+/*
+IsTemporal
+Description:
+	Returns true if the top-level operator of the formula is a temporal operator.
+*/
+func (formula Formula) IsTemporal() bool {
+	switch formula.Operator {
+	case OpNext, OpWeakNext, OpAlways, OpEventually, OpUntil, OpRelease, OpWeakUntil:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+Atoms
+Description:
+	Returns the atomic propositions which appear in the formula, in the order of their first appearance.
+*/
+func (formula Formula) Atoms() []mc.AtomicProposition {
+	var atoms []mc.AtomicProposition
+	if formula.Operator == OpAtom {
+		atoms = append(atoms, formula.Atom)
+	}
+	for _, operand := range formula.Operands {
+		for _, ap := range operand.Atoms() {
+			if !ap.In(atoms) {
+				atoms = append(atoms, ap)
+			}
+		}
+	}
+	return atoms
+}
+
+/*
+String
+Description:
+	Prints the formula in the syntax accepted by Parse().
+*/
+func (formula Formula) String() string {
+	switch formula.Operator {
+	case OpTrue:
+		return "true"
+	case OpFalse:
+		return "false"
+	case OpAtom:
+		return nameString(formula.Atom.Name)
+	case OpNot:
+		return fmt.Sprintf("!%v", formula.Operands[0])
+	case OpAnd, OpOr:
+		if len(formula.Operands) == 0 {
+			if formula.Operator == OpAnd {
+				return "true"
+			}
+			return "false"
+		}
+		connective := " & "
+		if formula.Operator == OpOr {
+			connective = " | "
+		}
+		var operandStrings []string
+		for _, operand := range formula.Operands {
+			operandStrings = append(operandStrings, operand.String())
+		}
+		return "(" + strings.Join(operandStrings, connective) + ")"
+	case OpImplies:
+		return fmt.Sprintf("(%v -> %v)", formula.Operands[0], formula.Operands[1])
+	case OpNext:
+		return fmt.Sprintf("X %v", formula.Operands[0])
+	case OpWeakNext:
+		return fmt.Sprintf("N %v", formula.Operands[0])
+	case OpAlways:
+		return fmt.Sprintf("G %v", formula.Operands[0])
+	case OpEventually:
+		return fmt.Sprintf("F %v", formula.Operands[0])
+	case OpUntil:
+		return fmt.Sprintf("(%v U %v)", formula.Operands[0], formula.Operands[1])
+	case OpRelease:
+		return fmt.Sprintf("(%v R %v)", formula.Operands[0], formula.Operands[1])
+	case OpWeakUntil:
+		return fmt.Sprintf("(%v W %v)", formula.Operands[0], formula.Operands[1])
+	default:
+		return "?"
+	}
+}
+
+/*
+nameString
+Description:
+	Prints the name of an atomic proposition, quoting it if it could not be parsed as an identifier.
+*/
+func nameString(name string) string {
+	if name == "" || isKeyword(name) {
+		return fmt.Sprintf("%q", name)
+	}
+	for _, r := range name {
+		if !isIdentifierRune(r) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
@@ -0,0 +1,76 @@
+/*
+formula_test.go
+Description:
+	Tests for the LTL formulas defined in formula.go
+*/
+package ltl
+
+import (
+	"testing"
+)
+
+/*
+TestFormula_String1
+Description:
+	Prints a formula which uses every kind of operator.
+*/
+func TestFormula_String1(t *testing.T) {
+	formula := Implies(
+		And(Atom("a"), Not(Atom("b"))),
+		Or(Next(Atom("c")), WeakNext(True()), Until(Always(Atom("a")), Eventually(False()))),
+	)
+
+	expected := "((a & !b) -> (X c | N true | (G a U F false)))"
+	if formula.String() != expected {
+		t.Errorf("Expected \"%v\", but found \"%v\".", expected, formula.String())
+	}
+}
+
+/*
+TestFormula_String2
+Description:
+	Names which are keywords or contain special characters are quoted.
+*/
+func TestFormula_String2(t *testing.T) {
+	formula := Release(Atom("U"), WeakUntil(Atom("door open"), Atom("x_1.y")))
+
+	expected := "(\"U\" R (\"door open\" W x_1.y))"
+	if formula.String() != expected {
+		t.Errorf("Expected \"%v\", but found \"%v\".", expected, formula.String())
+	}
+}
+
+/*
+TestFormula_IsTemporal1
+Description:
+	Checks the top-level operators of a few formulas.
+*/
+func TestFormula_IsTemporal1(t *testing.T) {
+	if !Always(Atom("a")).IsTemporal() {
+		t.Errorf("Expected G a to be temporal.")
+	}
+
+	if Not(Next(Atom("a"))).IsTemporal() {
+		t.Errorf("Expected !X a to not be temporal at the top level.")
+	}
+}
+
+/*
+TestFormula_Atoms1
+Description:
+	Collects the atoms of a formula in which a is repeated.
+*/
+func TestFormula_Atoms1(t *testing.T) {
+	formula := Until(And(Atom("a"), Atom("b")), Or(Atom("a"), Next(Atom("c"))))
+
+	atoms := formula.Atoms()
+	if len(atoms) != 3 {
+		t.Errorf("Expected 3 atoms, but found %v.", len(atoms))
+	}
+
+	for index, name := range []string{"a", "b", "c"} {
+		if atoms[index].Name != name {
+			t.Errorf("Expected atom %v to be \"%v\", but found \"%v\".", index, name, atoms[index].Name)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+/*
+parser.go
+Description:
+	A parser for LTL formulas such as "G (request -> F grant)".
+	The boolean connectives are !, &, | and -> (or ¬, ∧, ∨ and →), the unary temporal operators are
+	X, N, G and F and the binary temporal operators are U, R and W.
+	Names which contain spaces or operator characters can be written in double quotes.
+*/
+
+package ltl
+
+import (
+	"fmt"
+	"unicode"
+)
+
+/*
+Type Definitions
+*/
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenName
+	tokenLeftParen
+	tokenRightParen
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenImplies
+)
+
+type token struct {
+	Kind     tokenKind
+	Text     string
+	Quoted   bool
+	Position int
+}
+
+var twoCharacterSymbols = map[string]tokenKind{
+	"->": tokenImplies,
+	"&&": tokenAnd,
+	"||": tokenOr,
+}
+
+var oneCharacterSymbols = map[rune]tokenKind{
+	'(': tokenLeftParen,
+	')': tokenRightParen,
+	'!': tokenNot,
+	'¬': tokenNot,
+	'&': tokenAnd,
+	'∧': tokenAnd,
+	'|': tokenOr,
+	'∨': tokenOr,
+	'→': tokenImplies,
+}
+
+var unaryTemporalOperators = map[string]func(Formula) Formula{
+	"X": Next,
+	"N": WeakNext,
+	"G": Always,
+	"F": Eventually,
+}
+
+var binaryTemporalOperators = map[string]func(Formula, Formula) Formula{
+	"U": Until,
+	"R": Release,
+	"W": WeakUntil,
+}
+
+type parser struct {
+	Tokens []token
+	Index  int
+}
+
+/*
+Parse
+Description:
+	Parses an LTL formula. Implication is right-associative and binds weaker than |, which binds weaker than &.
+	The binary temporal operators bind stronger than the boolean connectives and are right-associative,
+	so "a & b U c" is "a & (b U c)".
+Usage:
+	formula, err := Parse("G (request -> F grant)")
+*/
+func Parse(formulaString string) (Formula, error) {
+	tokens, err := tokenize(formulaString)
+	if err != nil {
+		return Formula{}, err
+	}
+
+	p := parser{Tokens: tokens}
+	formula, err := p.parseImplication()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	if next := p.peek(); next.Kind != tokenEnd {
+		return Formula{}, fmt.Errorf("Unexpected \"%v\" at position %v.", next.Text, next.Position)
+	}
+
+	return formula, nil
+}
+
+/*
+tokenize
+Description:
+	Splits the formula string into tokens. Positions are counted in characters, starting from 1.
+*/
+func tokenize(formulaString string) ([]token, error) {
+	runes := []rune(formulaString)
+	var tokens []token
+
+	for index := 0; index < len(runes); {
+		r := runes[index]
+		position := index + 1
+
+		// Two-character symbols
+		if index+1 < len(runes) {
+			pair := string(runes[index : index+2])
+			if kind, isSymbol := twoCharacterSymbols[pair]; isSymbol {
+				tokens = append(tokens, token{Kind: kind, Text: pair, Position: position})
+				index += 2
+				continue
+			}
+		}
+
+		// One-character symbols
+		if kind, isSymbol := oneCharacterSymbols[r]; isSymbol {
+			tokens = append(tokens, token{Kind: kind, Text: string(r), Position: position})
+			index++
+			continue
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			index++
+		case r == '"':
+			// Quoted name
+			end := index + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("The quoted name starting at position %v is never closed.", position)
+			}
+			tokens = append(tokens, token{Kind: tokenName, Text: string(runes[index+1 : end]), Quoted: true, Position: position})
+			index = end + 1
+		case isIdentifierRune(r):
+			end := index
+			for end < len(runes) && isIdentifierRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{Kind: tokenName, Text: string(runes[index:end]), Position: position})
+			index = end
+		default:
+			return nil, fmt.Errorf("Unexpected character '%v' at position %v.", string(r), position)
+		}
+	}
+
+	return append(tokens, token{Kind: tokenEnd, Text: "end of formula", Position: len(runes) + 1}), nil
+}
+
+/*
+isIdentifierRune
+Description:
+	Returns true if the rune can be part of an unquoted name.
+*/
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+/*
+isKeyword
+Description:
+	Returns true if the unquoted name has a special meaning in the parser.
+*/
+func isKeyword(name string) bool {
+	if name == "true" || name == "false" {
+		return true
+	}
+	_, isUnary := unaryTemporalOperators[name]
+	_, isBinary := binaryTemporalOperators[name]
+	return isUnary || isBinary
+}
+
+/*
+peek
+Description:
+	Returns the next token without consuming it.
+*/
+func (p *parser) peek() token {
+	return p.Tokens[p.Index]
+}
+
+/*
+next
+Description:
+	Consumes and returns the next token.
+*/
+func (p *parser) next() token {
+	t := p.Tokens[p.Index]
+	if t.Kind != tokenEnd {
+		p.Index++
+	}
+	return t
+}
+
+/*
+parseImplication
+Description:
+	implication := disjunction [ "->" implication ]
+*/
+func (p *parser) parseImplication() (Formula, error) {
+	premise, err := p.parseDisjunction()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	if p.peek().Kind != tokenImplies {
+		return premise, nil
+	}
+	p.next()
+
+	conclusion, err := p.parseImplication()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	return Implies(premise, conclusion), nil
+}
+
+/*
+parseDisjunction
+Description:
+	disjunction := conjunction { "|" conjunction }
+*/
+func (p *parser) parseDisjunction() (Formula, error) {
+	operand, err := p.parseConjunction()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	operands := []Formula{operand}
+	for p.peek().Kind == tokenOr {
+		p.next()
+		operand, err = p.parseConjunction()
+		if err != nil {
+			return Formula{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return Or(operands...), nil
+}
+
+/*
+parseConjunction
+Description:
+	conjunction := binary { "&" binary }
+*/
+func (p *parser) parseConjunction() (Formula, error) {
+	operand, err := p.parseBinary()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	operands := []Formula{operand}
+	for p.peek().Kind == tokenAnd {
+		p.next()
+		operand, err = p.parseBinary()
+		if err != nil {
+			return Formula{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return And(operands...), nil
+}
+
+/*
+parseBinary
+Description:
+	binary := unary [ ("U" | "R" | "W") binary ]
+*/
+func (p *parser) parseBinary() (Formula, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	next := p.peek()
+	constructor, isBinary := binaryTemporalOperators[next.Text]
+	if next.Kind != tokenName || next.Quoted || !isBinary {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseBinary()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	return constructor(left, right), nil
+}
+
+/*
+parseUnary
+Description:
+	unary := "!" unary | ("X" | "N" | "G" | "F") unary | "true" | "false" | name | "(" implication ")"
+*/
+func (p *parser) parseUnary() (Formula, error) {
+	t := p.next()
+	switch t.Kind {
+	case tokenNot:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return Formula{}, err
+		}
+		return Not(operand), nil
+
+	case tokenLeftParen:
+		formula, err := p.parseImplication()
+		if err != nil {
+			return Formula{}, err
+		}
+		if closing := p.next(); closing.Kind != tokenRightParen {
+			return Formula{}, fmt.Errorf("Expected \")\" at position %v, but found \"%v\".", closing.Position, closing.Text)
+		}
+		return formula, nil
+
+	case tokenName:
+		if t.Quoted {
+			return Atom(t.Text), nil
+		}
+
+		if constructor, isUnary := unaryTemporalOperators[t.Text]; isUnary {
+			operand, err := p.parseUnary()
+			if err != nil {
+				return Formula{}, err
+			}
+			return constructor(operand), nil
+		}
+
+		switch t.Text {
+		case "true":
+			return True(), nil
+		case "false":
+			return False(), nil
+		case "U", "R", "W":
+			return Formula{}, fmt.Errorf("The binary operator \"%v\" at position %v is missing its left operand.", t.Text, t.Position)
+		}
+		return Atom(t.Text), nil
+
+	default:
+		return Formula{}, fmt.Errorf("Expected a formula at position %v, but found \"%v\".", t.Position, t.Text)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+parser_test.go
+Description:
+
+	Tests for the LTL parser defined in parser.go
+*/
+package ltl
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+TestParse1
+Description:
+
+	Parses a response property.
+*/
+func TestParse1(t *testing.T) {
+	formula, err := Parse("G (request -> F grant)")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := Always(Implies(Atom("request"), Eventually(Atom("grant"))))
+	if formula.String() != expected.String() {
+		t.Errorf("Expected %v, but found %v.", expected, formula)
+	}
+}
+
+/*
+TestParse2
+Description:
+
+	Checks the precedence and associativity of the operators.
+*/
+func TestParse2(t *testing.T) {
+	testCases := map[string]string{
+		"a & b | c":       "((a & b) | c)",
+		"a | b & c":       "(a | (b & c))",
+		"a -> b -> c":     "(a -> (b -> c))",
+		"a & b U c":       "(a & (b U c))",
+		"a U b U c":       "(a U (b U c))",
+		"!a U X b":        "(!a U X b)",
+		"G F a & F G b":   "(G F a & F G b)",
+		"a ∧ ¬b ∨ c → d":  "(((a & !b) | c) -> d)",
+		"a && b || c":     "((a & b) | c)",
+		"(a R b) W true":  "((a R b) W true)",
+		"N \"U\" & false": "(N \"U\" & false)",
+		"X (a U b) -> c":  "(X (a U b) -> c)",
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := Parse(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		if formula.String() != expected {
+			t.Errorf("Expected \"%v\" to be parsed as \"%v\", but found \"%v\".", formulaString, expected, formula)
+		}
+	}
+}
+
+/*
+TestParse3
+Description:
+
+	Printing and then parsing a formula returns the same formula.
+*/
+func TestParse3(t *testing.T) {
+	formula := Implies(
+		And(Atom("door open"), Not(Atom("G"))),
+		WeakUntil(Release(Atom("a"), Next(Atom("b"))), Or(WeakNext(Atom("c")), Always(Eventually(Atom("d"))))),
+	)
+
+	parsed, err := Parse(formula.String())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if parsed.String() != formula.String() {
+		t.Errorf("Expected %v, but found %v.", formula, parsed)
+	}
+}
+
+/*
+TestParse4
+Description:
+
+	Malformed formulas produce errors which mention the problem.
+*/
+func TestParse4(t *testing.T) {
+	testCases := map[string]string{
+		"":       "Expected a formula at position 1",
+		"a &":    "Expected a formula at position 4",
+		"(a | b": "Expected \")\" at position 7",
+		"a b":    "Unexpected \"b\" at position 3",
+		"U a":    "The binary operator \"U\" at position 1 is missing its left operand.",
+		"a $ b":  "Unexpected character '$' at position 3.",
+		"\"open": "The quoted name starting at position 1 is never closed.",
+	}
+
+	for formulaString, expected := range testCases {
+		_, err := Parse(formulaString)
+		if err == nil {
+			t.Errorf("Expected an error while parsing \"%v\".", formulaString)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error for \"%v\" to contain \"%v\", but found \"%v\".", formulaString, expected, err)
+		}
+	}
+}
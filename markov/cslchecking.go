@@ -0,0 +1,240 @@
+/*
+cslchecking.go
+Description:
+	Model checking of CSL formulas on continuous-time Markov chains (Baier and Katoen, Section 10.5.2).
+	The formulas are those of pctl.go with time intervals instead of step bounds. Untimed X and U are checked on
+	the embedded DTMC, time-bounded until is reduced to transient analysis of modified chains, and the steady-state
+	operator uses the stationary distributions of the bottom strongly connected components.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+ctmcChecker
+Description:
+	Checks CSL formulas on a CTMC whose embedded and uniformized matrices have been computed once.
+*/
+type ctmcChecker struct {
+	Chain       CTMC
+	Embedded    sparseMatrix
+	Uniformized sparseMatrix
+	Rate        float64
+	Options     SolverOptions
+}
+
+/*
+Functions
+*/
+
+/*
+SatisfyingStates
+Description:
+	Returns the states of the chain which satisfy the CSL state formula, in the order of S.
+Usage:
+	formula, _ := ParseCSLFormula("P>=0.99 [ up U<=10 done ]")
+	states, err := chain.SatisfyingStates(formula, DefaultSolverOptions())
+*/
+func (chain CTMC) SatisfyingStates(formula PCTLFormula, options SolverOptions) ([]CTMCState, error) {
+	sat, err := satisfactionVector(chain.checker(options), formula)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []CTMCState
+	for i, state := range chain.S {
+		if sat[i] {
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+/*
+Satisfies
+Description:
+	Determines if every state with a positive initial probability satisfies the CSL state formula.
+*/
+func (chain CTMC) Satisfies(formula PCTLFormula, options SolverOptions) (bool, error) {
+	sat, err := chain.SatisfyingStates(formula, options)
+	if err != nil {
+		return false, err
+	}
+
+	for _, initialState := range chain.InitialStates() {
+		if !initialState.In(sat) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+/*
+Values
+Description:
+	Returns the probability computed by the outermost P or S operator of the formula in every state,
+	ignoring its bound, e.g. for the query P=? [ F<=10 down ].
+*/
+func (chain CTMC) Values(formula PCTLFormula, options SolverOptions) (map[CTMCState]float64, error) {
+	values, err := valueVector(chain.checker(options), formula)
+	if err != nil {
+		return nil, err
+	}
+
+	valueMap := make(map[CTMCState]float64)
+	for i, state := range chain.S {
+		valueMap[state] = values[i]
+	}
+	return valueMap, nil
+}
+
+/*
+checker
+Description:
+	Returns the CSL checker of the chain.
+*/
+func (chain CTMC) checker(options SolverOptions) ctmcChecker {
+	q := chain.uniformizationRate()
+	return ctmcChecker{
+		Chain:       chain,
+		Embedded:    chain.embeddedMatrix(),
+		Uniformized: chain.uniformizedMatrix(q, make([]bool, len(chain.S))),
+		Rate:        q,
+		Options:     options,
+	}
+}
+
+/*
+labels
+Description:
+	Returns the labels of the states of the chain, in the order of S.
+*/
+func (checker ctmcChecker) labels() [][]mc.AtomicProposition {
+	labels := make([][]mc.AtomicProposition, len(checker.Chain.S))
+	for i, state := range checker.Chain.S {
+		labels[i] = checker.Chain.L[state]
+	}
+	return labels
+}
+
+/*
+probabilityVector
+Description:
+	Returns, for each index of S, the probability that a path from the state satisfies the CSL path formula.
+	X is evaluated on the embedded DTMC, in which absorbing states have a self-loop.
+*/
+func (checker ctmcChecker) probabilityVector(pathFormula PCTLFormula) ([]float64, error) {
+	if !pathFormula.IsPathFormula() {
+		return nil, fmt.Errorf("P[...] must contain a path formula (X, U, F or G), but received \"%v\".", pathFormula)
+	}
+
+	if pathFormula.Operator != PCTLOpNext && pathFormula.StepBound >= 0 {
+		return nil, fmt.Errorf("The step-bounded path formula \"%v\" can only be checked on a DTMC; CSL formulas use time bounds.", pathFormula)
+	}
+
+	operandSats, err := operandSatisfaction(checker, pathFormula.Operands)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(checker.Chain.S)
+	switch pathFormula.Operator {
+	case PCTLOpNext:
+		return checker.Embedded.multiply(indicator(operandSats[0])), nil
+
+	case PCTLOpUntil:
+		return checker.untilProbabilities(operandSats[0], operandSats[1], pathFormula.TimeBound)
+
+	case PCTLOpEventually:
+		return checker.untilProbabilities(allStates(n), operandSats[0], pathFormula.TimeBound)
+
+	default:
+		// G phi = !F !phi
+		eventually, err := checker.untilProbabilities(allStates(n), complement(operandSats[0]), pathFormula.TimeBound)
+		if err != nil {
+			return nil, err
+		}
+		return oneMinus(eventually), nil
+	}
+}
+
+/*
+untilProbabilities
+Description:
+	Computes the probabilities of left U[t1,t2] right (or left U right if interval is nil) for every state.
+	From time t1 on, the path must satisfy left U[0,t2-t1] right, which is the probability of being in a right state
+	at time t2-t1 when the states which satisfy right or violate left are made absorbing. Before t1 the path must
+	stay in left states, so the states which violate left are made absorbing and lose their value.
+*/
+func (checker ctmcChecker) untilProbabilities(left []bool, right []bool, interval *TimeInterval) ([]float64, error) {
+	if interval == nil {
+		return untilProbabilities(checker.Embedded, left, right, -1, checker.Options)
+	}
+
+	if math.IsInf(interval.Lower, 1) || interval.Lower > interval.Upper {
+		return nil, fmt.Errorf("The time interval [%v,%v] must have a finite lower bound which is at most its upper bound.", interval.Lower, interval.Upper)
+	}
+
+	// Phase from t1 to t2
+	var values []float64
+	var err error
+	if math.IsInf(interval.Upper, 1) {
+		values, err = untilProbabilities(checker.Embedded, left, right, -1, checker.Options)
+	} else {
+		absorbing := make([]bool, len(left))
+		for i := range absorbing {
+			absorbing[i] = right[i] || !left[i]
+		}
+		values, err = checker.transientValues(indicator(right), absorbing, interval.Upper-interval.Lower)
+	}
+	if err != nil || interval.Lower == 0 {
+		return values, err
+	}
+
+	// Phase from 0 to t1
+	for i := range values {
+		if !left[i] {
+			values[i] = 0
+		}
+	}
+	return checker.transientValues(values, complement(left), interval.Lower)
+}
+
+/*
+transientValues
+Description:
+	Returns, for each state, the expected value of x at time t when the states of the set absorbing are made absorbing.
+*/
+func (checker ctmcChecker) transientValues(x []float64, absorbing []bool, t float64) ([]float64, error) {
+	weights, err := foxGlynn(checker.Rate*t, checker.Options.Tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := checker.Chain.uniformizedMatrix(checker.Rate, absorbing)
+	return weights.sum(x, matrix.multiply), nil
+}
+
+/*
+steadyStateVector
+Description:
+	Returns, for each index of S, the long-run probability of being in a state which satisfies the formula
+	when starting from that state. The uniformized DTMC has the same stationary distributions as the CTMC.
+*/
+func (checker ctmcChecker) steadyStateVector(formula PCTLFormula) ([]float64, error) {
+	sat, err := satisfactionVector(checker, formula)
+	if err != nil {
+		return nil, err
+	}
+	return steadyStateValues(checker.Uniformized, sat, checker.Options)
+}
@@ -0,0 +1,135 @@
+/*
+cslchecking_test.go
+Description:
+
+	Tests for the CSL model checking defined in cslchecking.go
+*/
+package markov
+
+import (
+	"math"
+	"testing"
+)
+
+/*
+cslValuesOf
+Description:
+
+	Parses the CSL formula and returns its values by state name.
+*/
+func cslValuesOf(t *testing.T, chain CTMC, formulaString string) map[string]float64 {
+	formula, err := ParseCSLFormula(formulaString)
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+	}
+
+	values, err := chain.Values(formula, DefaultSolverOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error while computing \"%v\": %v", formulaString, err)
+	}
+
+	valuesByName := make(map[string]float64)
+	for state, value := range values {
+		valuesByName[state.Name] = value
+	}
+	return valuesByName
+}
+
+/*
+TestCTMC_Values1
+Description:
+
+	Compares the time-bounded probabilities of the repairable machine with their closed forms.
+*/
+func TestCTMC_Values1(t *testing.T) {
+	chain := GetRepairableMachine()
+	upAt1 := 2.0/3 + math.Exp(-3)/3
+
+	testCases := []struct {
+		Formula string
+		Up      float64
+		Down    float64
+	}{
+		{"P=? [ F<=2 down ]", 1 - math.Exp(-2), 1},
+		{"P=? [ G<=1.5 up ]", math.Exp(-1.5), 0},
+		{"P=? [ up U[1,2] down ]", math.Exp(-1) * (1 - math.Exp(-1)), 0},
+		{"P=? [ F[1,2] down ]", 1 - upAt1*math.Exp(-1), 1 - (2.0/3-2*math.Exp(-3)/3)*math.Exp(-1)},
+		{"P=? [ up U>=1 down ]", math.Exp(-1), 0},
+		{"P=? [ F[0,0] down ]", 0, 1},
+		{"P=? [ X down ]", 1, 0},
+		{"P=? [ F down ]", 1, 1},
+		{"S=? [ up ]", 2.0 / 3, 2.0 / 3},
+	}
+
+	for _, testCase := range testCases {
+		values := cslValuesOf(t, chain, testCase.Formula)
+		if math.Abs(values["up"]-testCase.Up) > 1e-8 || math.Abs(values["down"]-testCase.Down) > 1e-8 {
+			t.Errorf("Expected %v to be %v in up and %v in down, but found %v.", testCase.Formula, testCase.Up, testCase.Down, values)
+		}
+	}
+}
+
+/*
+TestCTMC_Values2
+Description:
+
+	In the race, b is reached within time t with probability 3/4 (1 - e^(-4t)); in the long run the chain is
+	in b with probability 3/4.
+*/
+func TestCTMC_Values2(t *testing.T) {
+	chain := GetRaceCTMC()
+
+	values := cslValuesOf(t, chain, "P=? [ !a U<=0.5 b ]")
+	if expected := 0.75 * (1 - math.Exp(-2)); math.Abs(values["s0"]-expected) > 1e-8 || values["a"] != 0 || math.Abs(values["b"]-1) > 1e-12 {
+		t.Errorf("Expected %v in s0, 0 in a and 1 in b, but found %v.", expected, values)
+	}
+
+	values = cslValuesOf(t, chain, "S=? [ b ]")
+	if math.Abs(values["s0"]-0.75) > 1e-8 || values["a"] != 0 || math.Abs(values["b"]-1) > 1e-12 {
+		t.Errorf("Expected 3/4 in s0, 0 in a and 1 in b, but found %v.", values)
+	}
+}
+
+/*
+TestCTMC_SatisfyingStates1
+Description:
+
+	Checks nested CSL formulas and the errors for step bounds and queries.
+*/
+func TestCTMC_SatisfyingStates1(t *testing.T) {
+	chain := GetRepairableMachine()
+
+	formula, _ := ParseCSLFormula("P>=0.5 [ F<=1 P>0.9 [ G<=0.01 down ] ]")
+	states, err := chain.SatisfyingStates(formula, DefaultSolverOptions())
+	if err != nil || len(states) != 2 {
+		t.Errorf("Expected both states to satisfy %v, but found %v (error %v).", formula, states, err)
+	}
+
+	formula, _ = ParseCSLFormula("S>0.6 [ up ] & P<0.5 [ G<=1 up ]")
+	if holds, err := chain.Satisfies(formula, DefaultSolverOptions()); err != nil || !holds {
+		t.Errorf("Expected the machine to satisfy %v (error %v).", formula, err)
+	}
+
+	formula, _ = ParsePCTLFormula("P>=0.5 [ F<=1 down ]")
+	if _, err := chain.SatisfyingStates(formula, DefaultSolverOptions()); err == nil || err.Error() != "The step-bounded path formula \"F<=1 down\" can only be checked on a DTMC; CSL formulas use time bounds." {
+		t.Errorf("Expected an error for the step bound, but found %v.", err)
+	}
+
+	formula, _ = ParseCSLFormula("P=? [ F<=1 down ]")
+	if _, err := chain.Satisfies(formula, DefaultSolverOptions()); err == nil || err.Error() != "The query \"P=? [F<=1 down]\" has no truth value; use Values() to compute it." {
+		t.Errorf("Expected an error for the query, but found %v.", err)
+	}
+}
+
+/*
+TestDTMC_Values4
+Description:
+
+	DTMCs reject time-bounded CSL formulas.
+*/
+func TestDTMC_Values4(t *testing.T) {
+	formula, _ := ParseCSLFormula("P=? [ F<=1 done ]")
+	if _, err := GetKnuthYaoDie().Values(formula, DefaultSolverOptions()); err == nil || err.Error() != "The time-bounded path formula \"F<=1 done\" can only be checked on a CTMC." {
+		t.Errorf("Expected an error for the time bound, but found %v.", err)
+	}
+}
@@ -0,0 +1,522 @@
+/*
+ctmc.go
+Description:
+	Continuous-time Markov chains (Baier and Katoen, Section 10.5). The transitions of a DTMC are replaced by
+	rates: a state s with exit rate E(s) is left after an exponentially distributed delay with mean 1/E(s),
+	and moves to t with probability R(s,t)/E(s). States without outgoing rates are absorbing.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+CTMC
+Description:
+	A continuous-time Markov chain. R[s][t] is the rate of the transition from s to t (missing entries are zero)
+	and I[s] is the probability of starting in s.
+*/
+type CTMC struct {
+	S  []CTMCState
+	R  map[CTMCState]map[CTMCState]float64
+	I  map[CTMCState]float64
+	AP []mc.AtomicProposition
+	L  map[CTMCState][]mc.AtomicProposition
+}
+
+/*
+CTMCState
+Description:
+	A state of a continuous-time Markov chain.
+*/
+type CTMCState struct {
+	Name   string
+	System *CTMC
+}
+
+/*
+Functions
+*/
+
+/*
+GetCTMC
+Description:
+	Creates a CTMC from the names of its states, the rates of its transitions, its initial distribution,
+	its atomic propositions and its labels.
+Usage:
+	chain, err := GetCTMC(
+		[]string{"up", "down"},
+		map[string]map[string]float64{
+			"up":   {"down": 0.01},
+			"down": {"up": 2.0},
+		},
+		map[string]float64{"up": 1.0},
+		[]string{"failed"},
+		map[string][]string{"down": {"failed"}},
+	)
+*/
+func GetCTMC(stateNames []string, rateMap map[string]map[string]float64, initialDistribution map[string]float64, atomicPropositionsList []string, labelMap map[string][]string) (CTMC, error) {
+	chain := CTMC{
+		AP: mc.StringSliceToAPs(atomicPropositionsList),
+	}
+
+	for _, stateName := range stateNames {
+		chain.S = append(chain.S, CTMCState{Name: stateName, System: &chain})
+	}
+
+	// Create the rates
+	chain.R = make(map[CTMCState]map[CTMCState]float64)
+	for sourceName, row := range rateMap {
+		source := CTMCState{Name: sourceName, System: &chain}
+		chain.R[source] = make(map[CTMCState]float64)
+		for targetName, rate := range row {
+			chain.R[source][CTMCState{Name: targetName, System: &chain}] = rate
+		}
+	}
+
+	// Create the initial distribution
+	chain.I = make(map[CTMCState]float64)
+	for stateName, probability := range initialDistribution {
+		chain.I[CTMCState{Name: stateName, System: &chain}] = probability
+	}
+
+	// Create the labels
+	chain.L = make(map[CTMCState][]mc.AtomicProposition)
+	for stateName, apNames := range labelMap {
+		chain.L[CTMCState{Name: stateName, System: &chain}] = mc.StringSliceToAPs(apNames)
+	}
+
+	if err := chain.Check(); err != nil {
+		return chain, err
+	}
+
+	return chain, nil
+}
+
+/*
+Check
+Description:
+	Checks that every state mentioned by R, I and L is in S, that every rate is finite and nonnegative and that
+	the initial distribution sums to one.
+*/
+func (chain CTMC) Check() error {
+	for source, row := range chain.R {
+		if !source.In(chain.S) {
+			return fmt.Errorf("The state \"%v\" has transitions, but it is not in the state set.", source)
+		}
+
+		for target, rate := range row {
+			if !target.In(chain.S) {
+				return fmt.Errorf("The state \"%v\" has a transition to \"%v\", which is not in the state set.", source, target)
+			}
+			if rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+				return fmt.Errorf("The transition from \"%v\" to \"%v\" has the rate %v, which is not a finite nonnegative number.", source, target, rate)
+			}
+		}
+	}
+
+	if err := checkDistribution(chain.I, chain.S, "The initial distribution"); err != nil {
+		return err
+	}
+
+	for state, labels := range chain.L {
+		if !state.In(chain.S) {
+			return fmt.Errorf("The state \"%v\" has labels, but it is not in the state set.", state)
+		}
+		for _, ap := range labels {
+			if !ap.In(chain.AP) {
+				return fmt.Errorf("The state \"%v\" is labelled with \"%v\", which is not an atomic proposition of the chain.", state, ap)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+StatesNamed
+Description:
+	Returns the states of the chain with the given names, in the same order. Unknown names are skipped.
+*/
+func (chain CTMC) StatesNamed(names ...string) []CTMCState {
+	var states []CTMCState
+	for _, name := range names {
+		for _, state := range chain.S {
+			if state.Name == name {
+				states = append(states, state)
+			}
+		}
+	}
+	return states
+}
+
+/*
+Rate
+Description:
+	Returns the rate of the transition from source to target.
+*/
+func (chain CTMC) Rate(source CTMCState, target CTMCState) float64 {
+	return chain.R[source][target]
+}
+
+/*
+ExitRate
+Description:
+	Returns the total rate E(s) of the transitions of the state, including a self-loop. It is zero for absorbing states.
+*/
+func (chain CTMC) ExitRate(state CTMCState) float64 {
+	exitRate := 0.0
+	for _, rate := range chain.R[state] {
+		exitRate += rate
+	}
+	return exitRate
+}
+
+/*
+Post
+Description:
+	Returns the states which can follow the state, i.e. the targets of its positive rates, in the order of S.
+*/
+func (chain CTMC) Post(state CTMCState) []CTMCState {
+	var successors []CTMCState
+	for _, target := range chain.S {
+		if chain.R[state][target] > 0 {
+			successors = append(successors, target)
+		}
+	}
+	return successors
+}
+
+/*
+InitialStates
+Description:
+	Returns the states with a positive initial probability, in the order of S.
+*/
+func (chain CTMC) InitialStates() []CTMCState {
+	var initialStates []CTMCState
+	for _, state := range chain.S {
+		if chain.I[state] > 0 {
+			initialStates = append(initialStates, state)
+		}
+	}
+	return initialStates
+}
+
+/*
+Labels
+Description:
+	Returns the atomic propositions which hold in the state.
+*/
+func (chain CTMC) Labels(state CTMCState) []mc.AtomicProposition {
+	return chain.L[state]
+}
+
+/*
+EmbeddedDTMC
+Description:
+	Returns the embedded DTMC, which moves from s to t with probability R(s,t)/E(s). Absorbing states get a self-loop.
+*/
+func (chain CTMC) EmbeddedDTMC() DTMC {
+	return chain.toDTMC(chain.embeddedMatrix())
+}
+
+/*
+UniformizedDTMC
+Description:
+	Returns the uniformized DTMC with the rate q, which must be at least the rate at which any state is left
+	(self-loops do not count). It moves from s to t != s with probability R(s,t)/q and stays in s with the remaining
+	probability. The state of the CTMC at time t has the distribution of the uniformized DTMC after a Poisson(q t)
+	distributed number of steps.
+*/
+func (chain CTMC) UniformizedDTMC(q float64) (DTMC, error) {
+	if maxRate := chain.uniformizationRate(); q < maxRate || math.IsInf(q, 0) {
+		return DTMC{}, fmt.Errorf("The uniformization rate %v must be finite and at least the largest rate %v at which a state is left.", q, maxRate)
+	}
+	return chain.toDTMC(chain.uniformizedMatrix(q, make([]bool, len(chain.S)))), nil
+}
+
+/*
+TransientDistribution
+Description:
+	Returns the distribution of the state at time t, starting from the initial distribution. It is computed by
+	uniformization; the Poisson probabilities are truncated with the Fox-Glynn method so that the error is at most
+	options.Tolerance.
+Usage:
+	distribution, err := chain.TransientDistribution(10.0, DefaultSolverOptions())
+*/
+func (chain CTMC) TransientDistribution(t float64, options SolverOptions) (map[CTMCState]float64, error) {
+	if !(t >= 0) || math.IsInf(t, 1) {
+		return nil, fmt.Errorf("The time %v must be finite and nonnegative.", t)
+	}
+
+	q := chain.uniformizationRate()
+	weights, err := foxGlynn(q*t, options.Tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	initial := make([]float64, len(chain.S))
+	for i, state := range chain.S {
+		initial[i] = chain.I[state]
+	}
+
+	matrix := chain.uniformizedMatrix(q, make([]bool, len(chain.S)))
+	distribution := weights.sum(initial, matrix.transposeMultiply)
+
+	distributionMap := make(map[CTMCState]float64)
+	for i, state := range chain.S {
+		distributionMap[state] = distribution[i]
+	}
+	return distributionMap, nil
+}
+
+/*
+SteadyStateDistribution
+Description:
+	Returns the long-run distribution of the state, starting from the initial distribution. Each bottom strongly
+	connected component contributes its stationary distribution, weighted by the probability of reaching it.
+*/
+func (chain CTMC) SteadyStateDistribution(options SolverOptions) (map[CTMCState]float64, error) {
+	n := len(chain.S)
+	matrix := chain.uniformizedMatrix(chain.uniformizationRate(), make([]bool, n))
+
+	distribution := make([]float64, n)
+	for _, component := range matrix.bottomComponents() {
+		stationary, err := stationaryDistribution(matrix, component, options)
+		if err != nil {
+			return nil, err
+		}
+
+		inComponent := make([]bool, n)
+		for _, i := range component {
+			inComponent[i] = true
+		}
+		reach, err := untilProbabilities(matrix, allStates(n), inComponent, -1, options)
+		if err != nil {
+			return nil, err
+		}
+
+		reachProbability := 0.0
+		for i, state := range chain.S {
+			reachProbability += chain.I[state] * reach[i]
+		}
+		for position, i := range component {
+			distribution[i] += reachProbability * stationary[position]
+		}
+	}
+
+	distributionMap := make(map[CTMCState]float64)
+	for i, state := range chain.S {
+		distributionMap[state] = distribution[i]
+	}
+	return distributionMap, nil
+}
+
+/*
+toDTMC
+Description:
+	Creates a DTMC with the states, initial distribution and labels of the chain and the given transition matrix.
+*/
+func (chain CTMC) toDTMC(matrix sparseMatrix) DTMC {
+	transitionMap := make(map[string]map[string]float64)
+	for i, state := range chain.S {
+		transitionMap[state.Name] = make(map[string]float64)
+		for _, entry := range matrix[i] {
+			transitionMap[state.Name][chain.S[entry.Column].Name] += entry.Value
+		}
+	}
+
+	initialDistribution := make(map[string]float64)
+	for state, probability := range chain.I {
+		initialDistribution[state.Name] = probability
+	}
+
+	labelMap := make(map[string][]string)
+	for state, labels := range chain.L {
+		labelMap[state.Name] = apNames(labels)
+	}
+
+	dtmc, _ := GetDTMC(chain.stateNames(), transitionMap, initialDistribution, apNames(chain.AP), labelMap)
+	return dtmc
+}
+
+/*
+rateMatrix
+Description:
+	Returns the rates as a sparse matrix whose rows and columns follow the order of S.
+*/
+func (chain CTMC) rateMatrix() sparseMatrix {
+	index := chain.stateIndex()
+	matrix := make(sparseMatrix, len(chain.S))
+	for i, source := range chain.S {
+		for target, rate := range chain.R[source] {
+			if rate > 0 {
+				matrix[i] = append(matrix[i], sparseEntry{Column: index[target.Name], Value: rate})
+			}
+		}
+		sort.Slice(matrix[i], func(a, b int) bool { return matrix[i][a].Column < matrix[i][b].Column })
+	}
+	return matrix
+}
+
+/*
+embeddedMatrix
+Description:
+	Returns the transition matrix of the embedded DTMC.
+*/
+func (chain CTMC) embeddedMatrix() sparseMatrix {
+	matrix := chain.rateMatrix()
+	for i, row := range matrix {
+		if len(row) == 0 {
+			matrix[i] = []sparseEntry{{Column: i, Value: 1}}
+			continue
+		}
+
+		exitRate := 0.0
+		for _, entry := range row {
+			exitRate += entry.Value
+		}
+		for position := range row {
+			row[position].Value /= exitRate
+		}
+	}
+	return matrix
+}
+
+/*
+uniformizedMatrix
+Description:
+	Returns the transition matrix of the uniformized DTMC with rate q, in which the states of the set absorbing
+	are made absorbing.
+*/
+func (chain CTMC) uniformizedMatrix(q float64, absorbing []bool) sparseMatrix {
+	matrix := chain.rateMatrix()
+	for i, row := range matrix {
+		if absorbing[i] || len(row) == 0 {
+			matrix[i] = []sparseEntry{{Column: i, Value: 1}}
+			continue
+		}
+
+		stay := 1.0
+		var uniformized []sparseEntry
+		for _, entry := range row {
+			if entry.Column != i {
+				uniformized = append(uniformized, sparseEntry{Column: entry.Column, Value: entry.Value / q})
+				stay -= entry.Value / q
+			}
+		}
+		if stay > 0 {
+			uniformized = append(uniformized, sparseEntry{Column: i, Value: stay})
+			sort.Slice(uniformized, func(a, b int) bool { return uniformized[a].Column < uniformized[b].Column })
+		}
+		matrix[i] = uniformized
+	}
+	return matrix
+}
+
+/*
+uniformizationRate
+Description:
+	Returns the largest rate at which a state is left (self-loops do not count), or 1 if no state can be left.
+*/
+func (chain CTMC) uniformizationRate() float64 {
+	q := 0.0
+	for _, state := range chain.S {
+		exitRate := 0.0
+		for target, rate := range chain.R[state] {
+			if !target.Equals(state) {
+				exitRate += rate
+			}
+		}
+		q = math.Max(q, exitRate)
+	}
+
+	if q == 0 {
+		return 1
+	}
+	return q
+}
+
+/*
+stateNames
+Description:
+	Returns the names of the states, in the order of S.
+*/
+func (chain CTMC) stateNames() []string {
+	names := make([]string, len(chain.S))
+	for i, state := range chain.S {
+		names[i] = state.Name
+	}
+	return names
+}
+
+/*
+stateIndex
+Description:
+	Maps the name of each state to its index in S.
+*/
+func (chain CTMC) stateIndex() map[string]int {
+	index := make(map[string]int)
+	for i, state := range chain.S {
+		index[state.Name] = i
+	}
+	return index
+}
+
+/*
+Functions for CTMCState
+*/
+
+/*
+String
+Description:
+	Returns the name of the state.
+*/
+func (stateIn CTMCState) String() string {
+	return stateIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the two states have the same name.
+*/
+func (stateIn CTMCState) Equals(state2 CTMCState) bool {
+	return stateIn.Name == state2.Name
+}
+
+/*
+In
+Description:
+	Determines if the state is in the slice of states.
+*/
+func (stateIn CTMCState) In(stateSlice []CTMCState) bool {
+	for _, tempState := range stateSlice {
+		if stateIn.Equals(tempState) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+AppendIfUniqueTo
+Description:
+	Appends the state to the slice if it is not already in it.
+*/
+func (stateIn CTMCState) AppendIfUniqueTo(sliceIn []CTMCState) []CTMCState {
+	if stateIn.In(sliceIn) {
+		return sliceIn
+	}
+	return append(sliceIn, stateIn)
+}
@@ -0,0 +1,189 @@
+/*
+ctmc_test.go
+Description:
+
+	Tests for the continuous-time Markov chains defined in ctmc.go
+*/
+package markov
+
+import (
+	"math"
+	"testing"
+)
+
+/*
+GetRepairableMachine
+Description:
+
+	A machine which fails with rate 1 and is repaired with rate 2. Starting up, it is up at time t with probability
+	2/3 + e^(-3t)/3, and it is up two thirds of the time in the long run.
+*/
+func GetRepairableMachine() CTMC {
+	chain, _ := GetCTMC(
+		[]string{"up", "down"},
+		map[string]map[string]float64{
+			"up":   {"down": 1},
+			"down": {"up": 2},
+		},
+		map[string]float64{"up": 1},
+		[]string{"up", "down"},
+		map[string][]string{"up": {"up"}, "down": {"down"}},
+	)
+	return chain
+}
+
+/*
+GetRaceCTMC
+Description:
+
+	A race between the rates 1 and 3 of s0, which is won by a with probability 1/4 and by b with probability 3/4.
+	Both a and b are absorbing.
+*/
+func GetRaceCTMC() CTMC {
+	chain, _ := GetCTMC(
+		[]string{"s0", "a", "b"},
+		map[string]map[string]float64{
+			"s0": {"a": 1, "b": 3},
+		},
+		map[string]float64{"s0": 1},
+		[]string{"a", "b"},
+		map[string][]string{"a": {"a"}, "b": {"b"}},
+	)
+	return chain
+}
+
+/*
+TestGetCTMC1
+Description:
+
+	Builds the repairable machine and checks its rates, exit rates and initial states.
+*/
+func TestGetCTMC1(t *testing.T) {
+	chain := GetRepairableMachine()
+	up, down := chain.S[0], chain.S[1]
+
+	if chain.Rate(up, down) != 1 || chain.ExitRate(down) != 2 || chain.Rate(up, up) != 0 {
+		t.Errorf("Expected the rates 1 and 2, but found %v.", chain.R)
+	}
+
+	if post := chain.Post(down); len(post) != 1 || post[0].Name != "up" {
+		t.Errorf("Expected up to follow down, but found %v.", post)
+	}
+
+	if initial := chain.InitialStates(); len(initial) != 1 || !initial[0].Equals(up) {
+		t.Errorf("Expected up to be the only initial state, but found %v.", initial)
+	}
+
+	if race := GetRaceCTMC(); race.ExitRate(race.S[1]) != 0 || len(race.Post(race.S[1])) != 0 {
+		t.Errorf("Expected a to be absorbing.")
+	}
+}
+
+/*
+TestGetCTMC2
+Description:
+
+	Negative rates, unknown states and initial distributions which do not sum to one are rejected.
+*/
+func TestGetCTMC2(t *testing.T) {
+	_, err := GetCTMC([]string{"a"}, map[string]map[string]float64{"a": {"a": -1}}, map[string]float64{"a": 1}, nil, nil)
+	if err == nil || err.Error() != "The transition from \"a\" to \"a\" has the rate -1, which is not a finite nonnegative number." {
+		t.Errorf("Expected an error for the rate, but found %v.", err)
+	}
+
+	_, err = GetCTMC([]string{"a"}, map[string]map[string]float64{"a": {"b": 1}}, map[string]float64{"a": 1}, nil, nil)
+	if err == nil || err.Error() != "The state \"a\" has a transition to \"b\", which is not in the state set." {
+		t.Errorf("Expected an error for the target, but found %v.", err)
+	}
+
+	_, err = GetCTMC([]string{"a"}, nil, map[string]float64{"a": 0.5}, nil, nil)
+	if err == nil || err.Error() != "The initial distribution sums to 0.5 instead of 1." {
+		t.Errorf("Expected an error for the initial distribution, but found %v.", err)
+	}
+}
+
+/*
+TestCTMC_EmbeddedDTMC1
+Description:
+
+	The embedded DTMC of the race moves to b with probability 3/4 and keeps the absorbing states with self-loops.
+*/
+func TestCTMC_EmbeddedDTMC1(t *testing.T) {
+	chain := GetRaceCTMC().EmbeddedDTMC()
+	s0, a, b := chain.S[0], chain.S[1], chain.S[2]
+
+	if chain.Probability(s0, b) != 0.75 || chain.Probability(a, a) != 1 || !chain.Labels(b)[0].Equals(chain.AP[1]) {
+		t.Errorf("Expected the embedded probabilities 1/4 and 3/4, but found %v.", chain.P)
+	}
+}
+
+/*
+TestCTMC_UniformizedDTMC1
+Description:
+
+	Uniformizing the repairable machine with rate 4 gives it a self-loop in each state.
+*/
+func TestCTMC_UniformizedDTMC1(t *testing.T) {
+	machine := GetRepairableMachine()
+
+	chain, err := machine.UniformizedDTMC(4)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	up, down := chain.S[0], chain.S[1]
+	if chain.Probability(up, down) != 0.25 || chain.Probability(up, up) != 0.75 || chain.Probability(down, down) != 0.5 {
+		t.Errorf("Expected the uniformized probabilities, but found %v.", chain.P)
+	}
+
+	if _, err := machine.UniformizedDTMC(1.5); err == nil || err.Error() != "The uniformization rate 1.5 must be finite and at least the largest rate 2 at which a state is left." {
+		t.Errorf("Expected an error for the rate, but found %v.", err)
+	}
+}
+
+/*
+TestCTMC_TransientDistribution1
+Description:
+
+	Compares the transient distribution of the repairable machine with its closed form.
+*/
+func TestCTMC_TransientDistribution1(t *testing.T) {
+	chain := GetRepairableMachine()
+	up := chain.S[0]
+
+	for _, time := range []float64{0, 0.1, 1, 10} {
+		distribution, err := chain.TransientDistribution(time, DefaultSolverOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := 2.0/3 + math.Exp(-3*time)/3
+		if math.Abs(distribution[up]-expected) > 1e-8 || math.Abs(distribution[up]+distribution[chain.S[1]]-1) > 1e-8 {
+			t.Errorf("Expected the machine to be up with probability %v at time %v, but found %v.", expected, time, distribution)
+		}
+	}
+
+	if _, err := chain.TransientDistribution(-1, DefaultSolverOptions()); err == nil || err.Error() != "The time -1 must be finite and nonnegative." {
+		t.Errorf("Expected an error for the time, but found %v.", err)
+	}
+}
+
+/*
+TestCTMC_SteadyStateDistribution1
+Description:
+
+	The machine is up two thirds of the time; the race ends in b with probability 3/4.
+*/
+func TestCTMC_SteadyStateDistribution1(t *testing.T) {
+	machine := GetRepairableMachine()
+	distribution, err := machine.SteadyStateDistribution(DefaultSolverOptions())
+	if err != nil || math.Abs(distribution[machine.S[0]]-2.0/3) > 1e-8 {
+		t.Errorf("Expected the machine to be up 2/3 of the time, but found %v (error %v).", distribution, err)
+	}
+
+	race := GetRaceCTMC()
+	distribution, err = race.SteadyStateDistribution(DefaultSolverOptions())
+	if err != nil || math.Abs(distribution[race.S[1]]-0.25) > 1e-8 || math.Abs(distribution[race.S[2]]-0.75) > 1e-8 || distribution[race.S[0]] != 0 {
+		t.Errorf("Expected the distribution 0, 1/4, 3/4, but found %v (error %v).", distribution, err)
+	}
+}
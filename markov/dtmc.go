@@ -0,0 +1,335 @@
+/*
+dtmc.go
+Description:
+	Discrete-time Markov chains (Baier and Katoen, Chapter 10). The states, atomic propositions and labels
+	follow the conventions of modelchecking.TransitionSystem; the transitions and the initial states are
+	replaced by probability distributions.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+DTMC
+Description:
+	A discrete-time Markov chain. P[s][t] is the probability of moving from s to t (missing entries are zero)
+	and I[s] is the probability of starting in s.
+*/
+type DTMC struct {
+	S  []DTMCState
+	P  map[DTMCState]map[DTMCState]float64
+	I  map[DTMCState]float64
+	AP []mc.AtomicProposition
+	L  map[DTMCState][]mc.AtomicProposition
+}
+
+/*
+DTMCState
+Description:
+	A state of a discrete-time Markov chain.
+*/
+type DTMCState struct {
+	Name   string
+	System *DTMC
+}
+
+// ProbabilityTolerance is the largest rounding error allowed when checking that probabilities sum to one.
+const ProbabilityTolerance = 1e-9
+
+/*
+Functions
+*/
+
+/*
+GetDTMC
+Description:
+	Creates a DTMC from the names of its states, the probabilities of its transitions, its initial distribution,
+	its atomic propositions and its labels.
+Usage:
+	chain, err := GetDTMC(
+		[]string{"try", "sent", "lost"},
+		map[string]map[string]float64{
+			"try":  {"sent": 0.9, "lost": 0.1},
+			"sent": {"sent": 1.0},
+			"lost": {"try": 1.0},
+		},
+		map[string]float64{"try": 1.0},
+		[]string{"delivered"},
+		map[string][]string{"sent": {"delivered"}},
+	)
+*/
+func GetDTMC(stateNames []string, transitionMap map[string]map[string]float64, initialDistribution map[string]float64, atomicPropositionsList []string, labelMap map[string][]string) (DTMC, error) {
+	chain := DTMC{
+		AP: mc.StringSliceToAPs(atomicPropositionsList),
+	}
+
+	for _, stateName := range stateNames {
+		chain.S = append(chain.S, DTMCState{Name: stateName, System: &chain})
+	}
+
+	// Create the transition probabilities
+	chain.P = make(map[DTMCState]map[DTMCState]float64)
+	for sourceName, row := range transitionMap {
+		source := DTMCState{Name: sourceName, System: &chain}
+		chain.P[source] = make(map[DTMCState]float64)
+		for targetName, probability := range row {
+			chain.P[source][DTMCState{Name: targetName, System: &chain}] = probability
+		}
+	}
+
+	// Create the initial distribution
+	chain.I = make(map[DTMCState]float64)
+	for stateName, probability := range initialDistribution {
+		chain.I[DTMCState{Name: stateName, System: &chain}] = probability
+	}
+
+	// Create the labels
+	chain.L = make(map[DTMCState][]mc.AtomicProposition)
+	for stateName, apNames := range labelMap {
+		chain.L[DTMCState{Name: stateName, System: &chain}] = mc.StringSliceToAPs(apNames)
+	}
+
+	if err := chain.Check(); err != nil {
+		return chain, err
+	}
+
+	return chain, nil
+}
+
+/*
+Check
+Description:
+	Checks that every state mentioned by P, I and L is in S, that every probability is in [0,1] and that
+	the initial distribution and the distribution of successors of each state sum to one.
+*/
+func (chain DTMC) Check() error {
+	for source, row := range chain.P {
+		if !source.In(chain.S) {
+			return fmt.Errorf("The state \"%v\" has transitions, but it is not in the state set.", source)
+		}
+
+		if err := checkDistribution(row, chain.S, fmt.Sprintf("The distribution of successors of \"%v\"", source)); err != nil {
+			return err
+		}
+	}
+
+	for _, state := range chain.S {
+		if _, hasRow := chain.P[state]; !hasRow {
+			return fmt.Errorf("The state \"%v\" has no outgoing transitions; add a self-loop to make it absorbing.", state)
+		}
+	}
+
+	if err := checkDistribution(chain.I, chain.S, "The initial distribution"); err != nil {
+		return err
+	}
+
+	for state, labels := range chain.L {
+		if !state.In(chain.S) {
+			return fmt.Errorf("The state \"%v\" has labels, but it is not in the state set.", state)
+		}
+		for _, ap := range labels {
+			if !ap.In(chain.AP) {
+				return fmt.Errorf("The state \"%v\" is labelled with \"%v\", which is not an atomic proposition of the chain.", state, ap)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+checkDistribution
+Description:
+	Checks that the distribution only uses states of S, has probabilities in [0,1] and sums to one.
+	It is shared by the models of this package, whose states all have an In method.
+*/
+func checkDistribution[State interface {
+	comparable
+	In([]State) bool
+}](distribution map[State]float64, S []State, description string) error {
+	total := 0.0
+	for state, probability := range distribution {
+		if !state.In(S) {
+			return fmt.Errorf("%v uses the state \"%v\", which is not in the state set.", description, state)
+		}
+		if probability < 0 || probability > 1 || math.IsNaN(probability) {
+			return fmt.Errorf("%v gives \"%v\" the probability %v, which is not in [0,1].", description, state, probability)
+		}
+		total += probability
+	}
+
+	if math.Abs(total-1) > ProbabilityTolerance {
+		return fmt.Errorf("%v sums to %v instead of 1.", description, total)
+	}
+
+	return nil
+}
+
+/*
+StatesNamed
+Description:
+	Returns the states of the chain with the given names, in the same order. Unknown names are skipped.
+*/
+func (chain DTMC) StatesNamed(names ...string) []DTMCState {
+	var states []DTMCState
+	for _, name := range names {
+		for _, state := range chain.S {
+			if state.Name == name {
+				states = append(states, state)
+			}
+		}
+	}
+	return states
+}
+
+/*
+Probability
+Description:
+	Returns the probability of moving from source to target in one step.
+*/
+func (chain DTMC) Probability(source DTMCState, target DTMCState) float64 {
+	return chain.P[source][target]
+}
+
+/*
+Post
+Description:
+	Returns the states which can follow the state with positive probability, in the order of S.
+*/
+func (chain DTMC) Post(state DTMCState) []DTMCState {
+	var successors []DTMCState
+	for _, target := range chain.S {
+		if chain.P[state][target] > 0 {
+			successors = append(successors, target)
+		}
+	}
+	return successors
+}
+
+/*
+InitialStates
+Description:
+	Returns the states with a positive initial probability, in the order of S.
+*/
+func (chain DTMC) InitialStates() []DTMCState {
+	var initialStates []DTMCState
+	for _, state := range chain.S {
+		if chain.I[state] > 0 {
+			initialStates = append(initialStates, state)
+		}
+	}
+	return initialStates
+}
+
+/*
+Labels
+Description:
+	Returns the atomic propositions which hold in the state.
+*/
+func (chain DTMC) Labels(state DTMCState) []mc.AtomicProposition {
+	return chain.L[state]
+}
+
+/*
+matrix
+Description:
+	Returns the transition probabilities as a sparse matrix whose rows and columns follow the order of S.
+*/
+func (chain DTMC) matrix() sparseMatrix {
+	index := chain.stateIndex()
+	matrix := make(sparseMatrix, len(chain.S))
+	for i, source := range chain.S {
+		for target, probability := range chain.P[source] {
+			if probability > 0 {
+				matrix[i] = append(matrix[i], sparseEntry{Column: index[target.Name], Value: probability})
+			}
+		}
+		sort.Slice(matrix[i], func(a, b int) bool { return matrix[i][a].Column < matrix[i][b].Column })
+	}
+	return matrix
+}
+
+/*
+stateNames
+Description:
+	Returns the names of the states, in the order of S.
+*/
+func (chain DTMC) stateNames() []string {
+	names := make([]string, len(chain.S))
+	for i, state := range chain.S {
+		names[i] = state.Name
+	}
+	return names
+}
+
+/*
+stateIndex
+Description:
+	Maps the name of each state to its index in S.
+*/
+func (chain DTMC) stateIndex() map[string]int {
+	index := make(map[string]int)
+	for i, state := range chain.S {
+		index[state.Name] = i
+	}
+	return index
+}
+
+/*
+Functions for DTMCState
+*/
+
+/*
+String
+Description:
+	Returns the name of the state.
+*/
+func (stateIn DTMCState) String() string {
+	return stateIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the two states have the same name.
+*/
+func (stateIn DTMCState) Equals(state2 DTMCState) bool {
+	return stateIn.Name == state2.Name
+}
+
+/*
+In
+Description:
+	Determines if the state is in the slice of states.
+*/
+func (stateIn DTMCState) In(stateSlice []DTMCState) bool {
+	for _, tempState := range stateSlice {
+		if stateIn.Equals(tempState) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+AppendIfUniqueTo
+Description:
+	Appends the state to the slice if it is not already in it.
+*/
+func (stateIn DTMCState) AppendIfUniqueTo(sliceIn []DTMCState) []DTMCState {
+	if stateIn.In(sliceIn) {
+		return sliceIn
+	}
+	return append(sliceIn, stateIn)
+}
@@ -0,0 +1,165 @@
+/*
+dtmc_test.go
+Description:
+
+	Tests for the discrete-time Markov chains defined in dtmc.go
+*/
+package markov
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+GetKnuthYaoDie
+Description:
+
+	The Knuth-Yao simulation of a fair die with a fair coin (Baier and Katoen, Example 10.3).
+	The states d1, ..., d6 are absorbing and labelled with done and the value of the die.
+*/
+func GetKnuthYaoDie() DTMC {
+	chain, _ := GetDTMC(
+		[]string{"s0", "s1", "s2", "s3", "s4", "s5", "s6", "d1", "d2", "d3", "d4", "d5", "d6"},
+		map[string]map[string]float64{
+			"s0": {"s1": 0.5, "s2": 0.5},
+			"s1": {"s3": 0.5, "s4": 0.5},
+			"s2": {"s5": 0.5, "s6": 0.5},
+			"s3": {"s1": 0.5, "d1": 0.5},
+			"s4": {"d2": 0.5, "d3": 0.5},
+			"s5": {"d4": 0.5, "d5": 0.5},
+			"s6": {"s2": 0.5, "d6": 0.5},
+			"d1": {"d1": 1},
+			"d2": {"d2": 1},
+			"d3": {"d3": 1},
+			"d4": {"d4": 1},
+			"d5": {"d5": 1},
+			"d6": {"d6": 1},
+		},
+		map[string]float64{"s0": 1},
+		[]string{"done", "one", "two", "three", "four", "five", "six"},
+		map[string][]string{
+			"d1": {"done", "one"},
+			"d2": {"done", "two"},
+			"d3": {"done", "three"},
+			"d4": {"done", "four"},
+			"d5": {"done", "five"},
+			"d6": {"done", "six"},
+		},
+	)
+	return chain
+}
+
+/*
+TestGetDTMC1
+Description:
+
+	Builds the Knuth-Yao die and checks its transitions, initial states and labels.
+*/
+func TestGetDTMC1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	if len(chain.S) != 13 {
+		t.Errorf("Expected 13 states, but found %v.", len(chain.S))
+	}
+
+	s3 := chain.StatesNamed("s3")[0]
+	post := chain.Post(s3)
+	if len(post) != 2 || post[0].Name != "s1" || post[1].Name != "d1" {
+		t.Errorf("Expected the successors of s3 to be [s1 d1], but found %v.", post)
+	}
+
+	if p := chain.Probability(s3, chain.StatesNamed("d1")[0]); p != 0.5 {
+		t.Errorf("Expected the probability 0.5, but found %v.", p)
+	}
+
+	if initialStates := chain.InitialStates(); len(initialStates) != 1 || initialStates[0].Name != "s0" {
+		t.Errorf("Expected the initial states [s0], but found %v.", initialStates)
+	}
+
+	if labels := chain.Labels(chain.StatesNamed("d6")[0]); len(labels) != 2 {
+		t.Errorf("Expected d6 to have 2 labels, but found %v.", labels)
+	}
+}
+
+/*
+TestGetDTMC2
+Description:
+
+	Invalid chains produce errors.
+*/
+func TestGetDTMC2(t *testing.T) {
+	testCases := []struct {
+		Transitions map[string]map[string]float64
+		Initial     map[string]float64
+		Labels      map[string][]string
+		Expected    string
+	}{
+		{
+			map[string]map[string]float64{"a": {"a": 0.5, "b": 0.4}, "b": {"b": 1}},
+			map[string]float64{"a": 1},
+			map[string][]string{},
+			"The distribution of successors of \"a\" sums to 0.9 instead of 1.",
+		},
+		{
+			map[string]map[string]float64{"a": {"a": 1}},
+			map[string]float64{"a": 1},
+			map[string][]string{},
+			"The state \"b\" has no outgoing transitions; add a self-loop to make it absorbing.",
+		},
+		{
+			map[string]map[string]float64{"a": {"c": 1}, "b": {"b": 1}},
+			map[string]float64{"a": 1},
+			map[string][]string{},
+			"The distribution of successors of \"a\" uses the state \"c\", which is not in the state set.",
+		},
+		{
+			map[string]map[string]float64{"a": {"a": 1.5, "b": -0.5}, "b": {"b": 1}},
+			map[string]float64{"a": 1},
+			map[string][]string{},
+			"which is not in [0,1].",
+		},
+		{
+			map[string]map[string]float64{"a": {"a": 1}, "b": {"b": 1}},
+			map[string]float64{"a": 0.5},
+			map[string][]string{},
+			"The initial distribution sums to 0.5 instead of 1.",
+		},
+		{
+			map[string]map[string]float64{"a": {"a": 1}, "b": {"b": 1}},
+			map[string]float64{"a": 1},
+			map[string][]string{"a": {"q"}},
+			"The state \"a\" is labelled with \"q\", which is not an atomic proposition of the chain.",
+		},
+	}
+
+	for index, testCase := range testCases {
+		_, err := GetDTMC([]string{"a", "b"}, testCase.Transitions, testCase.Initial, []string{"p"}, testCase.Labels)
+		if err == nil {
+			t.Errorf("Expected an error in test case %v.", index)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), testCase.Expected) {
+			t.Errorf("Expected the error of test case %v to contain \"%v\", but found \"%v\".", index, testCase.Expected, err)
+		}
+	}
+}
+
+/*
+TestDTMCState_AppendIfUniqueTo1
+Description:
+
+	States with the same name are only appended once.
+*/
+func TestDTMCState_AppendIfUniqueTo1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	states := chain.StatesNamed("s0", "s1")
+	states = chain.S[0].AppendIfUniqueTo(states)
+	states = chain.S[2].AppendIfUniqueTo(states)
+
+	if len(states) != 3 || !chain.S[2].In(states) || states[0].String() != "s0" {
+		t.Errorf("Expected [s0 s1 s2], but found %v.", states)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+linearsolver.go
+Description:
+	Iterative solvers for the linear equation systems x = A x + b which arise in probabilistic model checking.
+	Jacobi computes every component of the next iterate from the previous iterate; Gauss-Seidel uses the
+	components of the next iterate as soon as they are available and usually needs fewer iterations.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+Type Definitions
+*/
+
+type LinearSolver int
+
+const (
+	Jacobi LinearSolver = iota
+	GaussSeidel
+)
+
+/*
+SolverOptions
+Description:
+	Configures the iterative solvers. The iteration stops when no component changes by more than Tolerance,
+	and fails after MaxIterations iterations.
+*/
+type SolverOptions struct {
+	Method        LinearSolver
+	Tolerance     float64
+	MaxIterations int
+}
+
+/*
+Functions
+*/
+
+/*
+DefaultSolverOptions
+Description:
+	Gauss-Seidel with a tolerance of 1e-10 and at most 100000 iterations.
+*/
+func DefaultSolverOptions() SolverOptions {
+	return SolverOptions{Method: GaussSeidel, Tolerance: 1e-10, MaxIterations: 100000}
+}
+
+/*
+String
+Description:
+	Returns the name of the solver.
+*/
+func (method LinearSolver) String() string {
+	switch method {
+	case Jacobi:
+		return "Jacobi"
+	case GaussSeidel:
+		return "Gauss-Seidel"
+	default:
+		return fmt.Sprintf("LinearSolver(%v)", int(method))
+	}
+}
+
+/*
+Check
+Description:
+	Checks that the options describe a solver which can terminate.
+*/
+func (options SolverOptions) Check() error {
+	if options.Method != Jacobi && options.Method != GaussSeidel {
+		return fmt.Errorf("Unrecognized linear solver %v.", options.Method)
+	}
+
+	if !(options.Tolerance > 0) {
+		return fmt.Errorf("The tolerance of the solver must be positive, but it is %v.", options.Tolerance)
+	}
+
+	if options.MaxIterations <= 0 {
+		return fmt.Errorf("The maximum number of iterations must be positive, but it is %v.", options.MaxIterations)
+	}
+
+	return nil
+}
+
+/*
+solve
+Description:
+	Solves x_i = sum_j A_ij x_j + b_i for the unknown components i, starting from the given x.
+	The other components of x are kept fixed. Entries on the diagonal of A are moved to the left-hand side.
+*/
+func (options SolverOptions) solve(A sparseMatrix, b []float64, x []float64, unknown []bool) ([]float64, error) {
+	if err := options.Check(); err != nil {
+		return nil, err
+	}
+
+	current := append([]float64{}, x...)
+	next := append([]float64{}, x...)
+
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		source := current
+		if options.Method == GaussSeidel {
+			source = next
+		}
+
+		maxChange := 0.0
+		for i, row := range A {
+			if !unknown[i] {
+				continue
+			}
+
+			diagonal := 0.0
+			value := b[i]
+			for _, entry := range row {
+				if entry.Column == i {
+					diagonal += entry.Value
+				} else {
+					value += entry.Value * source[entry.Column]
+				}
+			}
+			if diagonal < 1 {
+				value /= 1 - diagonal
+			}
+
+			maxChange = math.Max(maxChange, math.Abs(value-current[i]))
+			next[i] = value
+		}
+
+		copy(current, next)
+		if maxChange <= options.Tolerance {
+			return current, nil
+		}
+	}
+
+	return nil, fmt.Errorf("The %v solver did not converge to the tolerance %v within %v iterations.", options.Method, options.Tolerance, options.MaxIterations)
+}
@@ -0,0 +1,92 @@
+/*
+linearsolver_test.go
+Description:
+
+	Tests for the iterative linear solvers defined in linearsolver.go
+*/
+package markov
+
+import (
+	"math"
+	"testing"
+)
+
+/*
+TestSolverOptions_solve1
+Description:
+
+	Solves x0 = 0.5 x1 + 0.25, x1 = 0.5 x0 + 0.5 x2 with x2 = 1 fixed, whose solution is x0 = 2/3 and x1 = 5/6.
+	Gauss-Seidel needs fewer iterations than Jacobi, so it converges with a budget that is too small for Jacobi.
+*/
+func TestSolverOptions_solve1(t *testing.T) {
+	A := sparseMatrix{
+		{{Column: 1, Value: 0.5}},
+		{{Column: 0, Value: 0.5}, {Column: 2, Value: 0.5}},
+		{},
+	}
+	b := []float64{0.25, 0, 0}
+	unknown := []bool{true, true, false}
+
+	for _, method := range []LinearSolver{Jacobi, GaussSeidel} {
+		options := SolverOptions{Method: method, Tolerance: 1e-12, MaxIterations: 1000}
+		x, err := options.solve(A, b, []float64{0, 0, 1}, unknown)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", method, err)
+			continue
+		}
+
+		if math.Abs(x[0]-2.0/3) > 1e-10 || math.Abs(x[1]-5.0/6) > 1e-10 || x[2] != 1 {
+			t.Errorf("Expected [2/3 5/6 1] with %v, but found %v.", method, x)
+		}
+	}
+
+	_, errJacobi := SolverOptions{Method: Jacobi, Tolerance: 1e-12, MaxIterations: 30}.solve(A, b, []float64{0, 0, 1}, unknown)
+	_, errGaussSeidel := SolverOptions{Method: GaussSeidel, Tolerance: 1e-12, MaxIterations: 30}.solve(A, b, []float64{0, 0, 1}, unknown)
+	if errJacobi == nil || errGaussSeidel != nil {
+		t.Errorf("Expected only Jacobi to fail within 30 iterations, but found %v and %v.", errJacobi, errGaussSeidel)
+	}
+}
+
+/*
+TestSolverOptions_solve2
+Description:
+
+	A self-loop is moved to the left-hand side: x0 = 0.5 x0 + 0.5 has the solution 1.
+*/
+func TestSolverOptions_solve2(t *testing.T) {
+	A := sparseMatrix{{{Column: 0, Value: 0.5}}}
+
+	x, err := DefaultSolverOptions().solve(A, []float64{0.5}, []float64{0}, []bool{true})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if math.Abs(x[0]-1) > 1e-12 {
+		t.Errorf("Expected 1, but found %v.", x[0])
+	}
+}
+
+/*
+TestSolverOptions_Check1
+Description:
+
+	Invalid options produce errors.
+*/
+func TestSolverOptions_Check1(t *testing.T) {
+	testCases := map[string]SolverOptions{
+		"Unrecognized linear solver LinearSolver(5).":                     {Method: 5, Tolerance: 1, MaxIterations: 1},
+		"The tolerance of the solver must be positive, but it is 0.":      {Method: Jacobi, Tolerance: 0, MaxIterations: 1},
+		"The maximum number of iterations must be positive, but it is 0.": {Method: Jacobi, Tolerance: 1, MaxIterations: 0},
+	}
+
+	for expected, options := range testCases {
+		err := options.Check()
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected the error \"%v\", but found %v.", expected, err)
+		}
+	}
+
+	if err := DefaultSolverOptions().Check(); err != nil {
+		t.Errorf("Unexpected error for the default options: %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+/*
+lumping.go
+Description:
+	Lumping of Markov chains by partition refinement (Buchholz, "Exact and ordinary lumpability in finite Markov
+	chains", 1994). The states are first grouped by their labels, and the blocks are split until every block
+	satisfies the lumping condition:
+	- Ordinary lumping: the states of a block move into each block with the same probability (or rate). This is
+	  probabilistic bisimulation, so every PCTL (or CSL) formula has the same value in a state and in its block.
+	- Exact lumping: the states of a block are entered from each block with the same probability (or rate), and
+	  they have the same initial probability. The probability of a block at any time is then the sum of the
+	  probabilities of its states, which are equal to each other.
+	CTMCs are lumped through their uniformized DTMC, which has the same lumpable partitions.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+type LumpingMethod int
+
+const (
+	OrdinaryLumping LumpingMethod = iota
+	ExactLumping
+)
+
+/*
+Functions
+*/
+
+/*
+String
+Description:
+	Returns the name of the lumping method.
+*/
+func (method LumpingMethod) String() string {
+	switch method {
+	case OrdinaryLumping:
+		return "ordinary lumping"
+	case ExactLumping:
+		return "exact lumping"
+	default:
+		return fmt.Sprintf("LumpingMethod(%v)", int(method))
+	}
+}
+
+/*
+Lump
+Description:
+	Returns the coarsest lumping of the chain for the method, along with the map from each state to its block.
+	The blocks are the states of the lumped chain; they are named after their states, e.g. "{s1,s2}", and
+	ordered by their first state in S.
+Usage:
+	lumped, blockOf, err := chain.Lump(OrdinaryLumping)
+	values, err := lumped.Values(formula, DefaultSolverOptions())
+	valueOfS := values[blockOf[s]]
+*/
+func (chain DTMC) Lump(method LumpingMethod) (DTMC, map[DTMCState]DTMCState, error) {
+	labels := make([]string, len(chain.S))
+	initial := make([]float64, len(chain.S))
+	for i, state := range chain.S {
+		labels[i] = labelKey(chain.L[state])
+		initial[i] = chain.I[state]
+	}
+
+	matrix := chain.matrix()
+	blocks, err := lumpingPartition(matrix, labels, initial, method)
+	if err != nil {
+		return DTMC{}, nil, err
+	}
+
+	lumpedMatrix := blocks.lumpedMatrix(matrix, method)
+	stateNames := blocks.names(chain.stateNames())
+	transitionMap := make(map[string]map[string]float64)
+	for b, row := range lumpedMatrix {
+		transitionMap[stateNames[b]] = make(map[string]float64)
+		for _, entry := range row {
+			transitionMap[stateNames[b]][stateNames[entry.Column]] = entry.Value
+		}
+	}
+
+	initialDistribution := make(map[string]float64)
+	labelMap := make(map[string][]string)
+	for i, state := range chain.S {
+		if chain.I[state] > 0 {
+			initialDistribution[stateNames[blocks.BlockOf[i]]] += chain.I[state]
+		}
+		labelMap[stateNames[blocks.BlockOf[i]]] = apNames(chain.L[state])
+	}
+
+	lumped, err := GetDTMC(stateNames, transitionMap, initialDistribution, apNames(chain.AP), labelMap)
+	if err != nil {
+		return DTMC{}, nil, fmt.Errorf("There was an issue creating the lumped chain: %v", err)
+	}
+
+	blockOf := make(map[DTMCState]DTMCState)
+	for i, state := range chain.S {
+		blockOf[state] = lumped.S[blocks.BlockOf[i]]
+	}
+	return lumped, blockOf, nil
+}
+
+/*
+Lump
+Description:
+	Returns the coarsest lumping of the chain for the method, along with the map from each state to its block.
+	The rate between two blocks is the rate from (ordinary) or the average rate from (exact) the states of the
+	first block into the second block.
+*/
+func (chain CTMC) Lump(method LumpingMethod) (CTMC, map[CTMCState]CTMCState, error) {
+	labels := make([]string, len(chain.S))
+	initial := make([]float64, len(chain.S))
+	for i, state := range chain.S {
+		labels[i] = labelKey(chain.L[state])
+		initial[i] = chain.I[state]
+	}
+
+	q := chain.uniformizationRate()
+	matrix := chain.uniformizedMatrix(q, make([]bool, len(chain.S)))
+	blocks, err := lumpingPartition(matrix, labels, initial, method)
+	if err != nil {
+		return CTMC{}, nil, err
+	}
+
+	lumpedMatrix := blocks.lumpedMatrix(matrix, method)
+	stateNames := blocks.names(chain.stateNames())
+	rateMap := make(map[string]map[string]float64)
+	for b, row := range lumpedMatrix {
+		for _, entry := range row {
+			if entry.Column == b {
+				continue
+			}
+			if rateMap[stateNames[b]] == nil {
+				rateMap[stateNames[b]] = make(map[string]float64)
+			}
+			rateMap[stateNames[b]][stateNames[entry.Column]] = q * entry.Value
+		}
+	}
+
+	initialDistribution := make(map[string]float64)
+	labelMap := make(map[string][]string)
+	for i, state := range chain.S {
+		if chain.I[state] > 0 {
+			initialDistribution[stateNames[blocks.BlockOf[i]]] += chain.I[state]
+		}
+		labelMap[stateNames[blocks.BlockOf[i]]] = apNames(chain.L[state])
+	}
+
+	lumped, err := GetCTMC(stateNames, rateMap, initialDistribution, apNames(chain.AP), labelMap)
+	if err != nil {
+		return CTMC{}, nil, fmt.Errorf("There was an issue creating the lumped chain: %v", err)
+	}
+
+	blockOf := make(map[CTMCState]CTMCState)
+	for i, state := range chain.S {
+		blockOf[state] = lumped.S[blocks.BlockOf[i]]
+	}
+	return lumped, blockOf, nil
+}
+
+/*
+partition
+Description:
+	A partition of the state indices into blocks. BlockOf maps each state to its block, and the states of each
+	block are listed in increasing order.
+*/
+type partition struct {
+	BlockOf []int
+	Blocks  [][]int
+}
+
+/*
+lumpingPartition
+Description:
+	Computes the coarsest lumpable partition of the matrix which only groups states with the same labels (and,
+	for exact lumping, the same initial probability). Each round computes the signature of every state, i.e.
+	its block and the probability of moving into (ordinary) or being entered from (exact) each block, and splits
+	the blocks by signature; the rounds stop when no block is split. Probabilities are compared up to
+	ProbabilityTolerance.
+*/
+func lumpingPartition(matrix sparseMatrix, labels []string, initial []float64, method LumpingMethod) (partition, error) {
+	if method != OrdinaryLumping && method != ExactLumping {
+		return partition{}, fmt.Errorf("Unrecognized lumping method %v.", method)
+	}
+
+	n := len(matrix)
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = labels[i]
+		if method == ExactLumping {
+			keys[i] += fmt.Sprintf("|%v", roundedProbability(initial[i]))
+		}
+	}
+	blocks := partitionByKey(keys)
+
+	for {
+		sums := make([]map[int]float64, n)
+		for i := range sums {
+			sums[i] = make(map[int]float64)
+		}
+		for i, row := range matrix {
+			for _, entry := range row {
+				if method == OrdinaryLumping {
+					sums[i][blocks.BlockOf[entry.Column]] += entry.Value
+				} else {
+					sums[entry.Column][blocks.BlockOf[i]] += entry.Value
+				}
+			}
+		}
+
+		for i := range keys {
+			keys[i] = fmt.Sprintf("%v|%v", blocks.BlockOf[i], signature(sums[i]))
+		}
+
+		refined := partitionByKey(keys)
+		if len(refined.Blocks) == len(blocks.Blocks) {
+			return blocks, nil
+		}
+		blocks = refined
+	}
+}
+
+/*
+partitionByKey
+Description:
+	Groups the states with the same key. The blocks are numbered in the order of their first state.
+*/
+func partitionByKey(keys []string) partition {
+	blockOfKey := make(map[string]int)
+	blocks := partition{BlockOf: make([]int, len(keys))}
+	for i, key := range keys {
+		b, seen := blockOfKey[key]
+		if !seen {
+			b = len(blocks.Blocks)
+			blockOfKey[key] = b
+			blocks.Blocks = append(blocks.Blocks, nil)
+		}
+		blocks.BlockOf[i] = b
+		blocks.Blocks[b] = append(blocks.Blocks[b], i)
+	}
+	return blocks
+}
+
+/*
+signature
+Description:
+	Prints the nonzero sums by block in increasing order of the blocks, rounded to ProbabilityTolerance.
+*/
+func signature(sums map[int]float64) string {
+	var blockIndices []int
+	for b, sum := range sums {
+		if roundedProbability(sum) != 0 {
+			blockIndices = append(blockIndices, b)
+		}
+	}
+	sort.Ints(blockIndices)
+
+	var parts []string
+	for _, b := range blockIndices {
+		parts = append(parts, fmt.Sprintf("%v:%v", b, roundedProbability(sums[b])))
+	}
+	return strings.Join(parts, ",")
+}
+
+/*
+roundedProbability
+Description:
+	Returns the probability as a multiple of ProbabilityTolerance.
+*/
+func roundedProbability(probability float64) int64 {
+	return int64(math.Round(probability / ProbabilityTolerance))
+}
+
+/*
+lumpedMatrix
+Description:
+	Returns the transition matrix between the blocks. For ordinary lumping, the row of a block is the probability
+	of moving from its first state into each block; for exact lumping, it is the average over its states.
+*/
+func (blocks partition) lumpedMatrix(matrix sparseMatrix, method LumpingMethod) sparseMatrix {
+	lumped := make(sparseMatrix, len(blocks.Blocks))
+	for b, block := range blocks.Blocks {
+		members := block
+		weight := 1 / float64(len(block))
+		if method == OrdinaryLumping {
+			members, weight = block[:1], 1
+		}
+
+		sums := make(map[int]float64)
+		for _, i := range members {
+			for _, entry := range matrix[i] {
+				sums[blocks.BlockOf[entry.Column]] += weight * entry.Value
+			}
+		}
+
+		for c, sum := range sums {
+			lumped[b] = append(lumped[b], sparseEntry{Column: c, Value: sum})
+		}
+		sort.Slice(lumped[b], func(x, y int) bool { return lumped[b][x].Column < lumped[b][y].Column })
+	}
+	return lumped
+}
+
+/*
+names
+Description:
+	Names each block after its states, e.g. "{s1,s2}".
+*/
+func (blocks partition) names(stateNames []string) []string {
+	names := make([]string, len(blocks.Blocks))
+	for b, block := range blocks.Blocks {
+		var memberNames []string
+		for _, i := range block {
+			memberNames = append(memberNames, stateNames[i])
+		}
+		names[b] = "{" + strings.Join(memberNames, ",") + "}"
+	}
+	return names
+}
+
+/*
+labelKey
+Description:
+	Prints a set of labels independently of their order.
+*/
+func labelKey(labels []mc.AtomicProposition) string {
+	names := apNames(labels)
+	sort.Strings(names)
+	return fmt.Sprintf("%q", names)
+}
+
+/*
+apNames
+Description:
+	Returns the names of the atomic propositions.
+*/
+func apNames(aps []mc.AtomicProposition) []string {
+	var names []string
+	for _, ap := range aps {
+		names = append(names, ap.Name)
+	}
+	return names
+}
@@ -0,0 +1,174 @@
+/*
+lumping_test.go
+Description:
+
+	Tests for the lumping of Markov chains defined in lumping.go
+*/
+package markov
+
+import (
+	"math"
+	"testing"
+)
+
+/*
+GetUnlabelledDie
+Description:
+
+	The Knuth-Yao die in which the outcomes are only labelled with done. The outcomes lump into one block,
+	and so do the mirrored states s1 and s2, s3 and s6, and s4 and s5.
+*/
+func GetUnlabelledDie() DTMC {
+	die := GetKnuthYaoDie()
+	transitionMap := make(map[string]map[string]float64)
+	for source, row := range die.P {
+		transitionMap[source.Name] = make(map[string]float64)
+		for target, probability := range row {
+			transitionMap[source.Name][target.Name] = probability
+		}
+	}
+
+	labelMap := make(map[string][]string)
+	for _, name := range []string{"d1", "d2", "d3", "d4", "d5", "d6"} {
+		labelMap[name] = []string{"done"}
+	}
+
+	chain, _ := GetDTMC(die.stateNames(), transitionMap, map[string]float64{"s0": 1}, []string{"done"}, labelMap)
+	return chain
+}
+
+/*
+GetExactlyLumpableDTMC
+Description:
+
+	A chain in which v1 and v2 are entered with the same probabilities, although they leave to u with
+	different probabilities. The block {v1,v2} is exactly but not ordinarily lumpable.
+*/
+func GetExactlyLumpableDTMC() DTMC {
+	chain, _ := GetDTMC(
+		[]string{"u", "v1", "v2"},
+		map[string]map[string]float64{
+			"u":  {"v1": 0.5, "v2": 0.5},
+			"v1": {"u": 0.2, "v1": 0.4, "v2": 0.4},
+			"v2": {"u": 0.6, "v1": 0.2, "v2": 0.2},
+		},
+		map[string]float64{"u": 1},
+		[]string{"home"},
+		map[string][]string{"u": {"home"}},
+	)
+	return chain
+}
+
+/*
+TestDTMC_Lump1
+Description:
+
+	Ordinary lumping of the unlabelled die gives 5 blocks, and PCTL values are preserved.
+*/
+func TestDTMC_Lump1(t *testing.T) {
+	chain := GetUnlabelledDie()
+
+	lumped, blockOf, err := chain.Lump(OrdinaryLumping)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(lumped.S) != 5 {
+		t.Errorf("Expected 5 blocks, but found %v.", lumped.S)
+	}
+
+	s1, s2, s3, s6 := chain.StatesNamed("s1")[0], chain.StatesNamed("s2")[0], chain.StatesNamed("s3")[0], chain.StatesNamed("s6")[0]
+	if blockOf[s1] != blockOf[s2] || blockOf[s3] != blockOf[s6] || blockOf[s1] == blockOf[s3] || blockOf[s1].Name != "{s1,s2}" {
+		t.Errorf("Expected the blocks {s1,s2} and {s3,s6}, but found %v and %v.", blockOf[s1], blockOf[s3])
+	}
+
+	if done := blockOf[chain.StatesNamed("d4")[0]]; lumped.Probability(done, done) != 1 || !lumped.Labels(done)[0].Equals(lumped.AP[0]) {
+		t.Errorf("Expected the outcomes to form an absorbing block labelled with done, but found %v.", done)
+	}
+
+	for _, formulaString := range []string{"P=? [ F<=3 done ]", "P=? [ G<=2 !done ]", "P=? [ F done ]"} {
+		original := valuesOf(t, chain, formulaString, DefaultSolverOptions())
+		reduced := valuesOf(t, lumped, formulaString, DefaultSolverOptions())
+		for _, state := range chain.S {
+			if math.Abs(original[state.Name]-reduced[blockOf[state].Name]) > 1e-9 {
+				t.Errorf("Expected %v to have the value %v in %v and its block, but found %v.", formulaString, original[state.Name], state, reduced[blockOf[state].Name])
+			}
+		}
+	}
+}
+
+/*
+TestDTMC_Lump2
+Description:
+
+	Exact lumping merges v1 and v2, whose block is left with their average probability 0.4; ordinary lumping cannot.
+*/
+func TestDTMC_Lump2(t *testing.T) {
+	chain := GetExactlyLumpableDTMC()
+
+	if lumped, _, err := chain.Lump(OrdinaryLumping); err != nil || len(lumped.S) != 3 {
+		t.Errorf("Expected ordinary lumping to keep the 3 states, but found %v (error %v).", lumped.S, err)
+	}
+
+	lumped, blockOf, err := chain.Lump(ExactLumping)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	u, v := blockOf[chain.S[0]], blockOf[chain.S[1]]
+	if len(lumped.S) != 2 || blockOf[chain.S[2]] != v || v.Name != "{v1,v2}" || lumped.I[u] != 1 {
+		t.Errorf("Expected the blocks {u} and {v1,v2}, but found %v.", lumped.S)
+	}
+
+	if math.Abs(lumped.Probability(v, u)-0.4) > 1e-12 || math.Abs(lumped.Probability(v, v)-0.6) > 1e-12 || lumped.Probability(u, v) != 1 {
+		t.Errorf("Expected the lumped probabilities 1, 0.4 and 0.6, but found %v.", lumped.P)
+	}
+
+	// The probability of being home after 2 steps is 0.4 in both chains
+	if home := lumped.Probability(u, v) * lumped.Probability(v, u); math.Abs(home-0.4) > 1e-12 {
+		t.Errorf("Expected to be home with probability 0.4 after 2 steps, but found %v.", home)
+	}
+}
+
+/*
+TestCTMC_Lump1
+Description:
+
+	Two identical components which fail with rate 1 and are repaired with rate 2 lump into one block, which is
+	entered with rate 2. The transient probabilities are preserved.
+*/
+func TestCTMC_Lump1(t *testing.T) {
+	chain, _ := GetCTMC(
+		[]string{"ok", "a", "b"},
+		map[string]map[string]float64{
+			"ok": {"a": 1, "b": 1},
+			"a":  {"ok": 2},
+			"b":  {"ok": 2},
+		},
+		map[string]float64{"ok": 1},
+		[]string{"ok"},
+		map[string][]string{"ok": {"ok"}},
+	)
+
+	for _, method := range []LumpingMethod{OrdinaryLumping, ExactLumping} {
+		lumped, blockOf, err := chain.Lump(method)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		ok, failed := blockOf[chain.S[0]], blockOf[chain.S[1]]
+		if len(lumped.S) != 2 || lumped.Rate(ok, failed) != 2 || lumped.Rate(failed, ok) != 2 {
+			t.Errorf("Expected %v to give the rates 2 and 2, but found %v.", method, lumped.R)
+		}
+
+		formula, _ := ParseCSLFormula("P=? [ G<=0.5 ok ]")
+		values, err := lumped.Values(formula, DefaultSolverOptions())
+		if err != nil || math.Abs(values[ok]-math.Exp(-1)) > 1e-9 {
+			t.Errorf("Expected %v to preserve P=? [ G<=0.5 ok ] = e^-1, but found %v (error %v).", method, values, err)
+		}
+	}
+
+	if _, _, err := chain.Lump(LumpingMethod(4)); err == nil || err.Error() != "Unrecognized lumping method LumpingMethod(4)." {
+		t.Errorf("Expected an error for the method, but found %v.", err)
+	}
+}
@@ -0,0 +1,448 @@
+/*
+mdp.go
+Description:
+	Markov decision processes (Baier and Katoen, Section 10.6). An MDP has the states, actions, atomic
+	propositions and labels of a modelchecking.TransitionSystem, but each enabled action of a state leads
+	to a probability distribution over successors instead of a set of successors.
+	A memoryless scheduler resolves the nondeterminism by fixing one action per state, which turns the MDP
+	into a DTMC.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+MDP
+Description:
+	A Markov decision process. P[s][a][t] is the probability of moving from s to t when the action a is
+	chosen in s (missing entries are zero); the actions of P[s] are the actions enabled in s.
+	I[s] is the probability of starting in s.
+*/
+type MDP struct {
+	S   []MDPState
+	Act []string
+	P   map[MDPState]map[string]map[MDPState]float64
+	I   map[MDPState]float64
+	AP  []mc.AtomicProposition
+	L   map[MDPState][]mc.AtomicProposition
+}
+
+/*
+MDPState
+Description:
+	A state of a Markov decision process.
+*/
+type MDPState struct {
+	Name   string
+	System *MDP
+}
+
+/*
+MemorylessScheduler
+Description:
+	Chooses the action of each state of an MDP, independently of how the state was reached.
+*/
+type MemorylessScheduler map[MDPState]string
+
+/*
+EndComponent
+Description:
+	A set of states of an MDP, together with the actions of each state which never leave the set, such that
+	a scheduler which only uses those actions can stay in the set forever and visit each of its states
+	infinitely often.
+*/
+type EndComponent struct {
+	States  []MDPState
+	Actions map[MDPState][]string
+}
+
+/*
+mdpChoice
+Description:
+	One enabled action of a state: its distribution of successors over the indices of S and the reward
+	which is collected when it is chosen.
+*/
+type mdpChoice struct {
+	Action  string
+	Entries []sparseEntry
+	Reward  float64
+}
+
+/*
+mdpMatrix
+Description:
+	The enabled actions of each state of an MDP, in the order of S and then of Act.
+*/
+type mdpMatrix [][]mdpChoice
+
+/*
+Functions
+*/
+
+/*
+GetMDP
+Description:
+	Creates an MDP from the names of its states and actions, the probabilities of its transitions,
+	its initial distribution, its atomic propositions and its labels.
+Usage:
+	mdp, err := GetMDP(
+		[]string{"start", "goal", "fail"},
+		[]string{"safe", "risky", "stay"},
+		map[string]map[string]map[string]float64{
+			"start": {"safe": {"start": 0.5, "goal": 0.5}, "risky": {"goal": 0.9, "fail": 0.1}},
+			"goal":  {"stay": {"goal": 1}},
+			"fail":  {"stay": {"fail": 1}},
+		},
+		map[string]float64{"start": 1},
+		[]string{"reached"},
+		map[string][]string{"goal": {"reached"}},
+	)
+*/
+func GetMDP(stateNames []string, actionNames []string, transitionMap map[string]map[string]map[string]float64, initialDistribution map[string]float64, atomicPropositionsList []string, labelMap map[string][]string) (MDP, error) {
+	mdp := MDP{
+		Act: actionNames,
+		AP:  mc.StringSliceToAPs(atomicPropositionsList),
+	}
+
+	for _, stateName := range stateNames {
+		mdp.S = append(mdp.S, MDPState{Name: stateName, System: &mdp})
+	}
+
+	// Create the transition probabilities
+	mdp.P = make(map[MDPState]map[string]map[MDPState]float64)
+	for sourceName, actionMap := range transitionMap {
+		source := MDPState{Name: sourceName, System: &mdp}
+		mdp.P[source] = make(map[string]map[MDPState]float64)
+		for actionName, row := range actionMap {
+			mdp.P[source][actionName] = make(map[MDPState]float64)
+			for targetName, probability := range row {
+				mdp.P[source][actionName][MDPState{Name: targetName, System: &mdp}] = probability
+			}
+		}
+	}
+
+	// Create the initial distribution
+	mdp.I = make(map[MDPState]float64)
+	for stateName, probability := range initialDistribution {
+		mdp.I[MDPState{Name: stateName, System: &mdp}] = probability
+	}
+
+	// Create the labels
+	mdp.L = make(map[MDPState][]mc.AtomicProposition)
+	for stateName, apNames := range labelMap {
+		mdp.L[MDPState{Name: stateName, System: &mdp}] = mc.StringSliceToAPs(apNames)
+	}
+
+	if err := mdp.Check(); err != nil {
+		return mdp, err
+	}
+
+	return mdp, nil
+}
+
+/*
+Check
+Description:
+	Checks that every state and action mentioned by P, I and L belongs to the MDP, that every state has
+	an enabled action and that the initial distribution and the distribution of each enabled action sum to one.
+*/
+func (mdp MDP) Check() error {
+	for source, actionMap := range mdp.P {
+		if !source.In(mdp.S) {
+			return fmt.Errorf("The state \"%v\" has transitions, but it is not in the state set.", source)
+		}
+
+		for action, row := range actionMap {
+			if !slices.Contains(mdp.Act, action) {
+				return fmt.Errorf("The state \"%v\" enables the action \"%v\", which is not in the action set.", source, action)
+			}
+
+			err := checkDistribution(row, mdp.S, fmt.Sprintf("The distribution of successors of \"%v\" under \"%v\"", source, action))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, state := range mdp.S {
+		if len(mdp.P[state]) == 0 {
+			return fmt.Errorf("The state \"%v\" has no enabled actions; add a self-loop to make it absorbing.", state)
+		}
+	}
+
+	if err := checkDistribution(mdp.I, mdp.S, "The initial distribution"); err != nil {
+		return err
+	}
+
+	for state, labels := range mdp.L {
+		if !state.In(mdp.S) {
+			return fmt.Errorf("The state \"%v\" has labels, but it is not in the state set.", state)
+		}
+		for _, ap := range labels {
+			if !ap.In(mdp.AP) {
+				return fmt.Errorf("The state \"%v\" is labelled with \"%v\", which is not an atomic proposition of the MDP.", state, ap)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+StatesNamed
+Description:
+	Returns the states of the MDP with the given names, in the same order. Unknown names are skipped.
+*/
+func (mdp MDP) StatesNamed(names ...string) []MDPState {
+	var states []MDPState
+	for _, name := range names {
+		for _, state := range mdp.S {
+			if state.Name == name {
+				states = append(states, state)
+			}
+		}
+	}
+	return states
+}
+
+/*
+EnabledActions
+Description:
+	Returns the actions which are enabled in the state, in the order of Act.
+*/
+func (mdp MDP) EnabledActions(state MDPState) []string {
+	var actions []string
+	for _, action := range mdp.Act {
+		if _, isEnabled := mdp.P[state][action]; isEnabled {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+/*
+Probability
+Description:
+	Returns the probability of moving from source to target when the action is chosen in source.
+*/
+func (mdp MDP) Probability(source MDPState, action string, target MDPState) float64 {
+	return mdp.P[source][action][target]
+}
+
+/*
+Post
+Description:
+	Returns the states which can follow the state with positive probability under the action, in the order of S.
+*/
+func (mdp MDP) Post(state MDPState, action string) []MDPState {
+	var successors []MDPState
+	for _, target := range mdp.S {
+		if mdp.P[state][action][target] > 0 {
+			successors = append(successors, target)
+		}
+	}
+	return successors
+}
+
+/*
+InitialStates
+Description:
+	Returns the states with a positive initial probability, in the order of S.
+*/
+func (mdp MDP) InitialStates() []MDPState {
+	var initialStates []MDPState
+	for _, state := range mdp.S {
+		if mdp.I[state] > 0 {
+			initialStates = append(initialStates, state)
+		}
+	}
+	return initialStates
+}
+
+/*
+Labels
+Description:
+	Returns the atomic propositions which hold in the state.
+*/
+func (mdp MDP) Labels(state MDPState) []mc.AtomicProposition {
+	return mdp.L[state]
+}
+
+/*
+InducedDTMC
+Description:
+	Returns the DTMC which is obtained by always choosing the action of the scheduler.
+	The states, initial distribution and labels of the chain have the same names as those of the MDP.
+Usage:
+	result, _ := mdp.ReachabilityProbabilities(mdp.StatesNamed("goal"), Maximize, DefaultMDPSolverOptions())
+	chain, err := mdp.InducedDTMC(result.Scheduler)
+*/
+func (mdp MDP) InducedDTMC(scheduler MemorylessScheduler) (DTMC, error) {
+	var stateNames []string
+	transitionMap := make(map[string]map[string]float64)
+	for _, state := range mdp.S {
+		stateNames = append(stateNames, state.Name)
+
+		action, isScheduled := scheduler[state]
+		if !isScheduled {
+			return DTMC{}, fmt.Errorf("The scheduler does not choose an action in the state \"%v\".", state)
+		}
+
+		row, isEnabled := mdp.P[state][action]
+		if !isEnabled {
+			return DTMC{}, fmt.Errorf("The scheduler chooses the action \"%v\" in the state \"%v\", where it is not enabled.", action, state)
+		}
+
+		transitionMap[state.Name] = make(map[string]float64)
+		for target, probability := range row {
+			transitionMap[state.Name][target.Name] = probability
+		}
+	}
+
+	initialDistribution := make(map[string]float64)
+	for state, probability := range mdp.I {
+		initialDistribution[state.Name] = probability
+	}
+
+	var apNames []string
+	for _, ap := range mdp.AP {
+		apNames = append(apNames, ap.Name)
+	}
+
+	labelMap := make(map[string][]string)
+	for state, labels := range mdp.L {
+		for _, ap := range labels {
+			labelMap[state.Name] = append(labelMap[state.Name], ap.Name)
+		}
+	}
+
+	return GetDTMC(stateNames, transitionMap, initialDistribution, apNames, labelMap)
+}
+
+/*
+MaximalEndComponents
+Description:
+	Returns the maximal end components of the MDP, which are disjoint. Every state which is visited infinitely
+	often by a run with positive probability belongs to one of them.
+*/
+func (mdp MDP) MaximalEndComponents() []EndComponent {
+	matrix := mdp.matrix(nil)
+
+	var components []EndComponent
+	for _, component := range matrix.maximalEndComponents(allStates(len(mdp.S)), nil) {
+		components = append(components, mdp.endComponent(component, matrix))
+	}
+	return components
+}
+
+/*
+endComponent
+Description:
+	Converts an end component over the indices of S into states and action names.
+*/
+func (mdp MDP) endComponent(component endComponent, matrix mdpMatrix) EndComponent {
+	converted := EndComponent{Actions: make(map[MDPState][]string)}
+	for _, i := range component.States {
+		converted.States = append(converted.States, mdp.S[i])
+		for _, c := range component.Choices[i] {
+			converted.Actions[mdp.S[i]] = append(converted.Actions[mdp.S[i]], matrix[i][c].Action)
+		}
+	}
+	return converted
+}
+
+/*
+matrix
+Description:
+	Returns the enabled actions of every state with their distributions over the indices of S.
+	The reward of a choice is rewards[s][a] (rewards may be nil).
+*/
+func (mdp MDP) matrix(rewards map[MDPState]map[string]float64) mdpMatrix {
+	index := mdp.stateIndex()
+	matrix := make(mdpMatrix, len(mdp.S))
+	for i, source := range mdp.S {
+		for _, action := range mdp.EnabledActions(source) {
+			choice := mdpChoice{Action: action, Reward: rewards[source][action]}
+			for target, probability := range mdp.P[source][action] {
+				if probability > 0 {
+					choice.Entries = append(choice.Entries, sparseEntry{Column: index[target.Name], Value: probability})
+				}
+			}
+			sort.Slice(choice.Entries, func(a, b int) bool { return choice.Entries[a].Column < choice.Entries[b].Column })
+			matrix[i] = append(matrix[i], choice)
+		}
+	}
+	return matrix
+}
+
+/*
+stateIndex
+Description:
+	Maps the name of each state to its index in S.
+*/
+func (mdp MDP) stateIndex() map[string]int {
+	index := make(map[string]int)
+	for i, state := range mdp.S {
+		index[state.Name] = i
+	}
+	return index
+}
+
+/*
+Functions for MDPState
+*/
+
+/*
+String
+Description:
+	Returns the name of the state.
+*/
+func (stateIn MDPState) String() string {
+	return stateIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the two states have the same name.
+*/
+func (stateIn MDPState) Equals(state2 MDPState) bool {
+	return stateIn.Name == state2.Name
+}
+
+/*
+In
+Description:
+	Determines if the state is in the slice of states.
+*/
+func (stateIn MDPState) In(stateSlice []MDPState) bool {
+	for _, tempState := range stateSlice {
+		if stateIn.Equals(tempState) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+AppendIfUniqueTo
+Description:
+	Appends the state to the slice if it is not already in it.
+*/
+func (stateIn MDPState) AppendIfUniqueTo(sliceIn []MDPState) []MDPState {
+	if stateIn.In(sliceIn) {
+		return sliceIn
+	}
+	return append(sliceIn, stateIn)
+}
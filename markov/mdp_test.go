@@ -0,0 +1,159 @@
+/*
+mdp_test.go
+Description:
+
+	Tests for the Markov decision processes defined in mdp.go
+*/
+package markov
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+GetDetourMDP
+Description:
+
+	An MDP in which s0 and s1 form an end component. The best way to reach goal from s0 and s1 is to move to s1
+	and gamble with d until s2 is reached, but moving back and forth with a is just as good on paper and never
+	reaches goal.
+*/
+func GetDetourMDP() MDP {
+	mdp, _ := GetMDP(
+		[]string{"s0", "s1", "s2", "goal", "fail"},
+		[]string{"a", "b", "c", "d", "stay"},
+		map[string]map[string]map[string]float64{
+			"s0":   {"a": {"s1": 1}, "b": {"goal": 0.5, "fail": 0.5}},
+			"s1":   {"a": {"s0": 1}, "c": {"goal": 0.7, "fail": 0.3}, "d": {"s1": 0.5, "s2": 0.5}},
+			"s2":   {"a": {"goal": 0.9, "fail": 0.1}},
+			"goal": {"stay": {"goal": 1}},
+			"fail": {"stay": {"fail": 1}},
+		},
+		map[string]float64{"s0": 1},
+		[]string{"goal", "fail"},
+		map[string][]string{"goal": {"goal"}, "fail": {"fail"}},
+	)
+	return mdp
+}
+
+/*
+TestGetMDP1
+Description:
+
+	Builds the detour MDP and checks its actions, successors and initial states.
+*/
+func TestGetMDP1(t *testing.T) {
+	mdp := GetDetourMDP()
+	s1 := mdp.S[1]
+
+	if actions := mdp.EnabledActions(s1); strings.Join(actions, " ") != "a c d" {
+		t.Errorf("Expected the actions a c d to be enabled in s1, but found %v.", actions)
+	}
+
+	if successors := mdp.Post(s1, "d"); len(successors) != 2 || successors[0].Name != "s1" || successors[1].Name != "s2" {
+		t.Errorf("Expected the successors s1 and s2, but found %v.", successors)
+	}
+
+	if p := mdp.Probability(s1, "c", mdp.S[3]); p != 0.7 {
+		t.Errorf("Expected the probability 0.7, but found %v.", p)
+	}
+
+	if initial := mdp.InitialStates(); len(initial) != 1 || initial[0].Name != "s0" {
+		t.Errorf("Expected the initial state s0, but found %v.", initial)
+	}
+
+	if labels := mdp.Labels(mdp.S[3]); len(labels) != 1 || labels[0].Name != "goal" {
+		t.Errorf("Expected the label goal, but found %v.", labels)
+	}
+}
+
+/*
+TestGetMDP2
+Description:
+
+	Invalid MDPs produce errors.
+*/
+func TestGetMDP2(t *testing.T) {
+	testCases := map[string]map[string]map[string]map[string]float64{
+		"The state \"y\" has no enabled actions; add a self-loop to make it absorbing.": {
+			"x": {"go": {"y": 1}},
+		},
+		"The state \"x\" enables the action \"jump\", which is not in the action set.": {
+			"x": {"jump": {"y": 1}},
+			"y": {"go": {"y": 1}},
+		},
+		"The distribution of successors of \"x\" under \"go\" sums to 0.9 instead of 1.": {
+			"x": {"go": {"y": 0.9}},
+			"y": {"go": {"y": 1}},
+		},
+		"The distribution of successors of \"x\" under \"go\" uses the state \"z\", which is not in the state set.": {
+			"x": {"go": {"z": 1}},
+			"y": {"go": {"y": 1}},
+		},
+	}
+
+	for expected, transitionMap := range testCases {
+		_, err := GetMDP([]string{"x", "y"}, []string{"go"}, transitionMap, map[string]float64{"x": 1}, []string{}, map[string][]string{})
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected the error \"%v\", but found %v.", expected, err)
+		}
+	}
+}
+
+/*
+TestMDP_InducedDTMC1
+Description:
+
+	Fixing the action of every state of the detour MDP gives a DTMC with the same states and labels.
+*/
+func TestMDP_InducedDTMC1(t *testing.T) {
+	mdp := GetDetourMDP()
+	scheduler := MemorylessScheduler{
+		mdp.S[0]: "b", mdp.S[1]: "d", mdp.S[2]: "a", mdp.S[3]: "stay", mdp.S[4]: "stay",
+	}
+
+	chain, err := mdp.InducedDTMC(scheduler)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	s0, goal := chain.StatesNamed("s0")[0], chain.StatesNamed("goal")[0]
+	if chain.Probability(s0, goal) != 0.5 || chain.I[s0] != 1 || !chain.Labels(goal)[0].Equals(mdp.AP[0]) {
+		t.Errorf("Expected the chain to follow b in s0, but found %v.", chain.P[s0])
+	}
+
+	delete(scheduler, mdp.S[4])
+	if _, err := mdp.InducedDTMC(scheduler); err == nil || err.Error() != "The scheduler does not choose an action in the state \"fail\"." {
+		t.Errorf("Expected an error for the missing state, but found %v.", err)
+	}
+
+	scheduler[mdp.S[4]] = "a"
+	if _, err := mdp.InducedDTMC(scheduler); err == nil || err.Error() != "The scheduler chooses the action \"a\" in the state \"fail\", where it is not enabled." {
+		t.Errorf("Expected an error for the action which is not enabled, but found %v.", err)
+	}
+}
+
+/*
+TestMDP_MaximalEndComponents1
+Description:
+	The detour MDP has the maximal end components {s0, s1} (using a), {goal} and {fail}.
+*/
+func TestMDP_MaximalEndComponents1(t *testing.T) {
+	mdp := GetDetourMDP()
+
+	components := mdp.MaximalEndComponents()
+	if len(components) != 3 {
+		t.Errorf("Expected 3 end components, but found %v.", components)
+		return
+	}
+
+	first := components[0]
+	if len(first.States) != 2 || first.States[0].Name != "s0" || first.States[1].Name != "s1" {
+		t.Errorf("Expected the end component {s0, s1}, but found %v.", first.States)
+	}
+
+	if actions := first.Actions[mdp.S[1]]; len(actions) != 1 || actions[0] != "a" {
+		t.Errorf("Expected only a to stay in {s0, s1} from s1, but found %v.", actions)
+	}
+}
@@ -0,0 +1,754 @@
+/*
+mdpchecking.go
+Description:
+	Optimal reachability probabilities and expected total rewards of Markov decision processes, together with
+	memoryless schedulers which attain them. Three methods are available:
+	- value iteration applies the Bellman operator until the values stop changing,
+	- interval iteration also iterates an upper bound, so that the true values are guaranteed to lie between
+	  the two (Haddad and Monmege; Baier, Klein, Leuschner, Parker and Wunderlich), and
+	- policy iteration evaluates a scheduler exactly and improves it until no choice is better.
+	The states whose values are 0, 1 or infinite are found with graph algorithms first.
+*/
+
+package markov
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+/*
+Type Definitions
+*/
+
+type Direction int
+
+const (
+	Maximize Direction = iota
+	Minimize
+)
+
+type MDPMethod int
+
+const (
+	ValueIteration MDPMethod = iota
+	IntervalIteration
+	PolicyIteration
+)
+
+/*
+MDPSolverOptions
+Description:
+	Configures the MDP solvers. Value iteration stops when no value changes by more than Tolerance, interval
+	iteration stops when the bounds of every state are at most Tolerance apart, and policy iteration only
+	switches to a choice which is better by more than Tolerance. Every method fails after MaxIterations
+	iterations. Policy iteration evaluates each scheduler with LinearSolver.
+*/
+type MDPSolverOptions struct {
+	Method        MDPMethod
+	Tolerance     float64
+	MaxIterations int
+	LinearSolver  LinearSolver
+}
+
+/*
+MDPResult
+Description:
+	The optimal value of each state and a memoryless scheduler which attains it (up to the tolerance).
+	Interval iteration also returns the sound bounds Lower and Upper, between which the optimal values lie;
+	the other methods leave them nil.
+*/
+type MDPResult struct {
+	Values     map[MDPState]float64
+	Lower      map[MDPState]float64
+	Upper      map[MDPState]float64
+	Scheduler  MemorylessScheduler
+	Iterations int
+}
+
+/*
+mdpProblem
+Description:
+	An optimization problem on the indices of S, after the graph precomputations. The values of the states which
+	are not Unknown are fixed, Lower and Upper bound the values of the Unknown states and Choices holds the
+	choices of the known states (and the initial scheduler of policy iteration).
+	For interval iteration, the value of each component is also bounded by the value of its best exit.
+	When extracting a scheduler, optimal choices are attracted to Goal, if it is not nil, so that the scheduler
+	cannot stay forever in an end component whose value is only attained by leaving it.
+*/
+type mdpProblem struct {
+	Matrix     mdpMatrix
+	Maximize   bool
+	Unknown    []bool
+	Lower      []float64
+	Upper      []float64
+	Choices    []int
+	Components []endComponent
+	Goal       []bool
+}
+
+/*
+Functions
+*/
+
+/*
+DefaultMDPSolverOptions
+Description:
+	Interval iteration with a tolerance of 1e-10 and at most 100000 iterations.
+*/
+func DefaultMDPSolverOptions() MDPSolverOptions {
+	return MDPSolverOptions{Method: IntervalIteration, Tolerance: 1e-10, MaxIterations: 100000, LinearSolver: GaussSeidel}
+}
+
+/*
+String
+Description:
+	Returns "max" or "min".
+*/
+func (direction Direction) String() string {
+	switch direction {
+	case Maximize:
+		return "max"
+	case Minimize:
+		return "min"
+	default:
+		return fmt.Sprintf("Direction(%v)", int(direction))
+	}
+}
+
+/*
+String
+Description:
+	Returns the name of the method.
+*/
+func (method MDPMethod) String() string {
+	switch method {
+	case ValueIteration:
+		return "value iteration"
+	case IntervalIteration:
+		return "interval iteration"
+	case PolicyIteration:
+		return "policy iteration"
+	default:
+		return fmt.Sprintf("MDPMethod(%v)", int(method))
+	}
+}
+
+/*
+Check
+Description:
+	Checks that the options describe a solver which can terminate.
+*/
+func (options MDPSolverOptions) Check() error {
+	if options.Method != ValueIteration && options.Method != IntervalIteration && options.Method != PolicyIteration {
+		return fmt.Errorf("Unrecognized MDP method %v.", options.Method)
+	}
+
+	return options.linear().Check()
+}
+
+/*
+linear
+Description:
+	Returns the options of the linear solver which evaluates schedulers.
+*/
+func (options MDPSolverOptions) linear() SolverOptions {
+	return SolverOptions{Method: options.LinearSolver, Tolerance: options.Tolerance, MaxIterations: options.MaxIterations}
+}
+
+/*
+ReachabilityProbabilities
+Description:
+	Computes, for every state, the maximal or minimal probability of eventually reaching one of the targets,
+	together with a memoryless scheduler which attains it.
+Usage:
+	result, err := mdp.ReachabilityProbabilities(mdp.StatesNamed("goal"), Maximize, DefaultMDPSolverOptions())
+	probability := result.Values[mdp.S[0]]
+*/
+func (mdp MDP) ReachabilityProbabilities(targets []MDPState, direction Direction, options MDPSolverOptions) (MDPResult, error) {
+	if err := options.Check(); err != nil {
+		return MDPResult{}, err
+	}
+
+	targetSet, err := mdp.stateSet(targets)
+	if err != nil {
+		return MDPResult{}, err
+	}
+
+	maximize, err := direction.maximize()
+	if err != nil {
+		return MDPResult{}, err
+	}
+
+	return mdp.solve(reachabilityProblem(mdp.matrix(nil), targetSet, maximize), options)
+}
+
+/*
+ExpectedTotalReward
+Description:
+	Computes, for every state, the maximal or minimal expected reward which is collected before one of the
+	targets is reached. Choosing the action a in the state s collects rewards[s][a] (missing rewards are zero).
+	As in PRISM, a scheduler which reaches the targets with probability less than 1 collects an infinite reward,
+	so the value of a state is +Inf if some scheduler (for the maximum) or every scheduler (for the minimum)
+	misses the targets with positive probability.
+Usage:
+	rewards := map[MDPState]map[string]float64{mdp.S[0]: {"safe": 1, "risky": 3}}
+	result, err := mdp.ExpectedTotalReward(rewards, mdp.StatesNamed("goal"), Minimize, DefaultMDPSolverOptions())
+*/
+func (mdp MDP) ExpectedTotalReward(rewards map[MDPState]map[string]float64, targets []MDPState, direction Direction, options MDPSolverOptions) (MDPResult, error) {
+	if err := options.Check(); err != nil {
+		return MDPResult{}, err
+	}
+
+	for state, actionRewards := range rewards {
+		if !state.In(mdp.S) {
+			return MDPResult{}, fmt.Errorf("The state \"%v\" has rewards, but it is not in the state set.", state)
+		}
+		for action, reward := range actionRewards {
+			if _, isEnabled := mdp.P[state][action]; !isEnabled {
+				return MDPResult{}, fmt.Errorf("The action \"%v\" has a reward in the state \"%v\", where it is not enabled.", action, state)
+			}
+			if reward < 0 || math.IsNaN(reward) || math.IsInf(reward, 1) {
+				return MDPResult{}, fmt.Errorf("The reward of \"%v\" in the state \"%v\" is %v, but rewards must be finite and nonnegative.", action, state, reward)
+			}
+		}
+	}
+
+	targetSet, err := mdp.stateSet(targets)
+	if err != nil {
+		return MDPResult{}, err
+	}
+
+	maximize, err := direction.maximize()
+	if err != nil {
+		return MDPResult{}, err
+	}
+
+	problem, err := rewardProblem(mdp.matrix(rewards), targetSet, maximize, options)
+	if err != nil {
+		return MDPResult{}, err
+	}
+
+	return mdp.solve(problem, options)
+}
+
+/*
+maximize
+Description:
+	Returns true for Maximize and false for Minimize.
+*/
+func (direction Direction) maximize() (bool, error) {
+	switch direction {
+	case Maximize:
+		return true, nil
+	case Minimize:
+		return false, nil
+	default:
+		return false, fmt.Errorf("Unrecognized direction %v.", direction)
+	}
+}
+
+/*
+stateSet
+Description:
+	Converts a slice of states into a set over the indices of S.
+*/
+func (mdp MDP) stateSet(states []MDPState) ([]bool, error) {
+	index := mdp.stateIndex()
+	set := make([]bool, len(mdp.S))
+	for _, state := range states {
+		i, isState := index[state.Name]
+		if !isState {
+			return nil, fmt.Errorf("The target \"%v\" is not a state of the MDP.", state)
+		}
+		set[i] = true
+	}
+	return set, nil
+}
+
+/*
+reachabilityProblem
+Description:
+	Sets up the computation of the optimal probabilities of reaching the targets.
+	For the maximum, the states with probability 0 cannot reach the targets at all and the states with
+	probability 1 are found with existsAlmostSure; the end components of the remaining states must be
+	deflated for the upper bound to converge. For the minimum, the remaining states have no end components.
+*/
+func reachabilityProblem(matrix mdpMatrix, targets []bool, maximize bool) mdpProblem {
+	n := len(matrix)
+
+	var canReach, one []bool
+	if maximize {
+		canReach = matrix.graph().backwardReachable(targets, allStates(n))
+		one = matrix.existsAlmostSure(targets)
+	} else {
+		canReach = matrix.forallReach(targets)
+		one = matrix.forallAlmostSure(targets)
+	}
+
+	problem := mdpProblem{
+		Matrix:   matrix,
+		Maximize: maximize,
+		Unknown:  make([]bool, n),
+		Lower:    make([]float64, n),
+		Upper:    make([]float64, n),
+		Choices:  make([]int, n),
+	}
+	zero := make([]bool, n)
+	for i := range matrix {
+		switch {
+		case one[i]:
+			problem.Lower[i], problem.Upper[i] = 1, 1
+		case !canReach[i]:
+			zero[i] = true
+		default:
+			problem.Unknown[i] = true
+			problem.Upper[i] = 1
+		}
+	}
+
+	if maximize {
+		// Reach the targets almost surely without leaving the states with probability 1
+		oneNotTarget := make([]bool, n)
+		for i := range oneNotTarget {
+			oneNotTarget[i] = one[i] && !targets[i]
+		}
+		attractor := matrix.attractor(targets, oneNotTarget, func(i int, c int) bool { return matrix[i][c].staysIn(one) })
+		for i, c := range attractor {
+			if c >= 0 {
+				problem.Choices[i] = c
+			}
+		}
+
+		problem.Components = matrix.maximalEndComponents(problem.Unknown, nil)
+		problem.Goal = one
+	} else {
+		// Avoid the targets forever
+		for i := range matrix {
+			if !zero[i] {
+				continue
+			}
+			for c, choice := range matrix[i] {
+				if choice.staysIn(zero) {
+					problem.Choices[i] = c
+					break
+				}
+			}
+		}
+	}
+
+	return problem
+}
+
+/*
+rewardProblem
+Description:
+	Sets up the computation of the optimal expected rewards collected before reaching the targets.
+	The states from which the targets are missed with positive probability (by some scheduler for the maximum,
+	by every scheduler for the minimum) have an infinite value.
+	For the maximum, the remaining states have no end components and the upper bound follows from the probability
+	of reaching the targets within |S| steps. For the minimum, only the choices which stay in the finite states
+	are used, the upper bound is the value of a scheduler which reaches the targets almost surely and the
+	end components without rewards must be inflated for the lower bound to converge.
+*/
+func rewardProblem(matrix mdpMatrix, targets []bool, maximize bool, options MDPSolverOptions) (mdpProblem, error) {
+	n := len(matrix)
+
+	var finite []bool
+	if maximize {
+		finite = matrix.forallAlmostSure(targets)
+	} else {
+		finite = matrix.existsAlmostSure(targets)
+	}
+
+	problem := mdpProblem{
+		Maximize: maximize,
+		Unknown:  make([]bool, n),
+		Lower:    make([]float64, n),
+		Upper:    make([]float64, n),
+		Choices:  make([]int, n),
+	}
+	infinite := make([]bool, n)
+	for i := range matrix {
+		switch {
+		case targets[i]:
+		case !finite[i]:
+			infinite[i] = true
+			problem.Lower[i], problem.Upper[i] = math.Inf(1), math.Inf(1)
+		default:
+			problem.Unknown[i] = true
+		}
+	}
+
+	if maximize {
+		problem.Matrix = matrix
+
+		// Miss the targets with positive probability: reach the states from which they can be avoided forever
+		avoiding := matrix.forallReach(targets)
+		for i := range avoiding {
+			avoiding[i] = !avoiding[i]
+		}
+		attractor := matrix.attractor(avoiding, infinite, func(i int, c int) bool { return true })
+		for i := range matrix {
+			switch {
+			case avoiding[i]:
+				for c, choice := range matrix[i] {
+					if choice.staysIn(avoiding) {
+						problem.Choices[i] = c
+						break
+					}
+				}
+			case attractor[i] >= 0:
+				problem.Choices[i] = attractor[i]
+			}
+		}
+
+		bound, err := maximalRewardBound(matrix, targets, problem.Unknown)
+		if err != nil {
+			return mdpProblem{}, err
+		}
+		for i, isUnknown := range problem.Unknown {
+			if isUnknown {
+				problem.Upper[i] = bound
+			}
+		}
+
+		return problem, nil
+	}
+
+	// Only use the choices which keep the rewards finite
+	problem.Matrix = make(mdpMatrix, n)
+	for i, choices := range matrix {
+		for _, choice := range choices {
+			if !problem.Unknown[i] || choice.staysIn(finite) {
+				problem.Matrix[i] = append(problem.Matrix[i], choice)
+			}
+		}
+	}
+
+	// A scheduler which reaches the targets almost surely gives the upper bound
+	attractor := problem.Matrix.attractor(targets, problem.Unknown, func(i int, c int) bool { return true })
+	for i, c := range attractor {
+		if c >= 0 {
+			problem.Choices[i] = c
+		}
+	}
+	upper, err := problem.evaluate(problem.Choices, options)
+	if err != nil {
+		return mdpProblem{}, err
+	}
+	problem.Upper = upper
+
+	problem.Components = problem.Matrix.maximalEndComponents(problem.Unknown, func(i int, choice mdpChoice) bool { return choice.Reward == 0 })
+	problem.Goal = targets
+
+	return problem, nil
+}
+
+/*
+maximalRewardBound
+Description:
+	Returns an upper bound on the expected reward collected before reaching the targets from the unknown states,
+	from which every scheduler reaches the targets almost surely. If p is the smallest probability of reaching
+	the targets within n = |unknown| steps, then the expected number of steps is at most n / p, and each step
+	collects at most the largest reward.
+*/
+func maximalRewardBound(matrix mdpMatrix, targets []bool, unknown []bool) (float64, error) {
+	n, maxReward := 0, 0.0
+	for i, isUnknown := range unknown {
+		if !isUnknown {
+			continue
+		}
+		n++
+		for _, choice := range matrix[i] {
+			maxReward = math.Max(maxReward, choice.Reward)
+		}
+	}
+	if n == 0 || maxReward == 0 {
+		return 0, nil
+	}
+
+	// Minimal probability of reaching the targets within n steps
+	probabilities := indicator(targets)
+	for step := 0; step < n; step++ {
+		next := append([]float64{}, probabilities...)
+		for i, isUnknown := range unknown {
+			if isUnknown {
+				next[i], _ = matrix.best(i, probabilities, false)
+			}
+		}
+		probabilities = next
+	}
+
+	p := 1.0
+	for i, isUnknown := range unknown {
+		if isUnknown {
+			p = math.Min(p, probabilities[i])
+		}
+	}
+	if p == 0 {
+		return 0, errors.New("Every scheduler should reach the targets almost surely from the states with finite rewards; this is a bug.")
+	}
+
+	return maxReward * float64(n) / p, nil
+}
+
+/*
+solve
+Description:
+	Runs the method of the options on the problem and converts the result back to the states of the MDP.
+*/
+func (mdp MDP) solve(problem mdpProblem, options MDPSolverOptions) (MDPResult, error) {
+	var values, lower, upper []float64
+	var choices []int
+	var iterations int
+	var err error
+
+	switch options.Method {
+	case ValueIteration:
+		values, iterations, err = problem.valueIteration(options)
+	case IntervalIteration:
+		lower, upper, iterations, err = problem.intervalIteration(options)
+		values = make([]float64, len(lower))
+		for i := range values {
+			values[i] = lower[i] + (upper[i]-lower[i])/2
+			if lower[i] == upper[i] {
+				values[i] = lower[i]
+			}
+		}
+	case PolicyIteration:
+		values, choices, iterations, err = problem.policyIteration(options)
+	}
+	if err != nil {
+		return MDPResult{}, err
+	}
+
+	if choices == nil {
+		choices = problem.extractScheduler(values, options)
+	}
+
+	result := MDPResult{
+		Values:     make(map[MDPState]float64),
+		Scheduler:  make(MemorylessScheduler),
+		Iterations: iterations,
+	}
+	if lower != nil {
+		result.Lower = make(map[MDPState]float64)
+		result.Upper = make(map[MDPState]float64)
+	}
+	for i, state := range mdp.S {
+		result.Values[state] = values[i]
+		result.Scheduler[state] = problem.Matrix[i][choices[i]].Action
+		if lower != nil {
+			result.Lower[state], result.Upper[state] = lower[i], upper[i]
+		}
+	}
+
+	return result, nil
+}
+
+/*
+valueIteration
+Description:
+	Applies the Bellman operator to the unknown states, updating the values in place, until no value changes by
+	more than the tolerance. The iteration starts from the lower bound when maximizing and from the upper bound
+	when minimizing, which converges to the optimal values even when there are end components.
+*/
+func (problem mdpProblem) valueIteration(options MDPSolverOptions) ([]float64, int, error) {
+	values := append([]float64{}, problem.Lower...)
+	if !problem.Maximize {
+		values = append([]float64{}, problem.Upper...)
+	}
+
+	for iteration := 1; iteration <= options.MaxIterations; iteration++ {
+		maxChange := 0.0
+		for i, isUnknown := range problem.Unknown {
+			if !isUnknown {
+				continue
+			}
+			value, _ := problem.Matrix.best(i, values, problem.Maximize)
+			maxChange = math.Max(maxChange, math.Abs(value-values[i]))
+			values[i] = value
+		}
+
+		if maxChange <= options.Tolerance {
+			return values, iteration, nil
+		}
+	}
+
+	return nil, options.MaxIterations, fmt.Errorf("The %v did not converge to the tolerance %v within %v iterations.", options.Method, options.Tolerance, options.MaxIterations)
+}
+
+/*
+intervalIteration
+Description:
+	Applies the Bellman operator to a lower and an upper bound of the values until they are at most the tolerance
+	apart. After each iteration, the bounds of the states of each component are limited by the best exit of the
+	component, which removes the spurious fixed points that end components would otherwise create.
+*/
+func (problem mdpProblem) intervalIteration(options MDPSolverOptions) ([]float64, []float64, int, error) {
+	lower := append([]float64{}, problem.Lower...)
+	upper := append([]float64{}, problem.Upper...)
+
+	for iteration := 1; iteration <= options.MaxIterations; iteration++ {
+		maxGap := 0.0
+		for i, isUnknown := range problem.Unknown {
+			if !isUnknown {
+				continue
+			}
+			lower[i], _ = problem.Matrix.best(i, lower, problem.Maximize)
+			upper[i], _ = problem.Matrix.best(i, upper, problem.Maximize)
+		}
+
+		if problem.Maximize {
+			problem.boundByExits(upper)
+		} else {
+			problem.boundByExits(lower)
+		}
+
+		for i, isUnknown := range problem.Unknown {
+			if isUnknown {
+				maxGap = math.Max(maxGap, upper[i]-lower[i])
+			}
+		}
+		if maxGap <= options.Tolerance {
+			return lower, upper, iteration, nil
+		}
+	}
+
+	return nil, nil, options.MaxIterations, fmt.Errorf("The %v did not converge to the tolerance %v within %v iterations.", options.Method, options.Tolerance, options.MaxIterations)
+}
+
+/*
+boundByExits
+Description:
+	A scheduler can move freely inside an end component, so the optimal value of each of its states is the
+	value of the best choice which leaves it. This lowers (when maximizing) or raises (when minimizing) the
+	values of each component to the value of its best exit.
+*/
+func (problem mdpProblem) boundByExits(values []float64) {
+	for _, component := range problem.Components {
+		bestExit, hasExit := 0.0, false
+		for _, i := range component.States {
+			inside := make(map[int]bool)
+			for _, c := range component.Choices[i] {
+				inside[c] = true
+			}
+
+			for c, choice := range problem.Matrix[i] {
+				if inside[c] {
+					continue
+				}
+				value := choice.value(values)
+				if !hasExit || (problem.Maximize && value > bestExit) || (!problem.Maximize && value < bestExit) {
+					bestExit, hasExit = value, true
+				}
+			}
+		}
+
+		if !hasExit {
+			continue
+		}
+		for _, i := range component.States {
+			if problem.Maximize {
+				values[i] = math.Min(values[i], bestExit)
+			} else {
+				values[i] = math.Max(values[i], bestExit)
+			}
+		}
+	}
+}
+
+/*
+policyIteration
+Description:
+	Evaluates the current scheduler and switches every unknown state to its best choice if that choice is better
+	than the current one by more than the tolerance, until no state switches. The initial scheduler is
+	problem.Choices, which reaches the targets almost surely when minimizing rewards; the improvements keep
+	this property.
+*/
+func (problem mdpProblem) policyIteration(options MDPSolverOptions) ([]float64, []int, int, error) {
+	choices := append([]int{}, problem.Choices...)
+
+	for iteration := 1; iteration <= options.MaxIterations; iteration++ {
+		values, err := problem.evaluate(choices, options)
+		if err != nil {
+			return nil, nil, iteration, err
+		}
+
+		improved := false
+		for i, isUnknown := range problem.Unknown {
+			if !isUnknown {
+				continue
+			}
+
+			current := problem.Matrix[i][choices[i]].value(values)
+			bestValue, bestChoice := problem.Matrix.best(i, values, problem.Maximize)
+			if (problem.Maximize && bestValue > current+options.Tolerance) || (!problem.Maximize && bestValue < current-options.Tolerance) {
+				choices[i] = bestChoice
+				improved = true
+			}
+		}
+
+		if !improved {
+			return values, choices, iteration, nil
+		}
+	}
+
+	return nil, nil, options.MaxIterations, fmt.Errorf("The %v did not converge to the tolerance %v within %v iterations.", options.Method, options.Tolerance, options.MaxIterations)
+}
+
+/*
+evaluate
+Description:
+	Computes the values of the unknown states under the scheduler by solving the linear equation system of the
+	induced chain, starting from the lower bound. Starting from below gives the least solution, which is the
+	correct one when the scheduler stays forever in a set of unknown states.
+*/
+func (problem mdpProblem) evaluate(choices []int, options MDPSolverOptions) ([]float64, error) {
+	n := len(problem.Matrix)
+	A := make(sparseMatrix, n)
+	b := make([]float64, n)
+	for i, isUnknown := range problem.Unknown {
+		if isUnknown {
+			A[i] = problem.Matrix[i][choices[i]].Entries
+			b[i] = problem.Matrix[i][choices[i]].Reward
+		}
+	}
+
+	return options.linear().solve(A, b, problem.Lower, problem.Unknown)
+}
+
+/*
+extractScheduler
+Description:
+	Chooses an optimal choice in every unknown state. When there is a goal, the choices whose values are
+	within the tolerance of the optimum are attracted to it, so that the scheduler makes progress out of end
+	components instead of staying in them.
+*/
+func (problem mdpProblem) extractScheduler(values []float64, options MDPSolverOptions) []int {
+	choices := append([]int{}, problem.Choices...)
+	bestValues := make([]float64, len(values))
+	for i, isUnknown := range problem.Unknown {
+		if isUnknown {
+			bestValues[i], choices[i] = problem.Matrix.best(i, values, problem.Maximize)
+		}
+	}
+
+	if problem.Goal == nil {
+		return choices
+	}
+
+	tolerance := 10 * options.Tolerance
+	isOptimal := func(i int, c int) bool {
+		return math.Abs(problem.Matrix[i][c].value(values)-bestValues[i]) <= tolerance
+	}
+	for i, c := range problem.Matrix.attractor(problem.Goal, problem.Unknown, isOptimal) {
+		if c >= 0 {
+			choices[i] = c
+		}
+	}
+
+	return choices
+}
@@ -0,0 +1,282 @@
+/*
+mdpchecking_test.go
+Description:
+
+	Tests for the MDP solvers defined in mdpchecking.go
+*/
+package markov
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+/*
+getMethodOptions
+Description:
+
+	Returns the default options of each MDP method.
+*/
+func getMethodOptions() []MDPSolverOptions {
+	var allOptions []MDPSolverOptions
+	for _, method := range []MDPMethod{ValueIteration, IntervalIteration, PolicyIteration} {
+		options := DefaultMDPSolverOptions()
+		options.Method = method
+		allOptions = append(allOptions, options)
+	}
+	return allOptions
+}
+
+/*
+checkResult
+Description:
+
+	Compares the values of a result with the expected values, given by state name.
+*/
+func checkResult(t *testing.T, description string, result MDPResult, expected map[string]float64) {
+	for state, value := range result.Values {
+		if math.IsInf(expected[state.Name], 1) && math.IsInf(value, 1) {
+			continue
+		}
+		if math.Abs(value-expected[state.Name]) > 1e-8 {
+			t.Errorf("Expected the %v of %v to be %v, but found %v.", description, state, expected[state.Name], value)
+		}
+	}
+}
+
+/*
+TestMDP_ReachabilityProbabilities1
+Description:
+
+	The maximal probability of reaching goal in the detour MDP is 0.9, and the optimal scheduler must leave the
+	end component {s0, s1} through d. The induced DTMC attains the same probabilities.
+*/
+func TestMDP_ReachabilityProbabilities1(t *testing.T) {
+	mdp := GetDetourMDP()
+
+	for _, options := range getMethodOptions() {
+		result, err := mdp.ReachabilityProbabilities(mdp.StatesNamed("goal"), Maximize, options)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", options.Method, err)
+			continue
+		}
+
+		checkResult(t, "maximal probability ("+options.Method.String()+")", result, map[string]float64{
+			"s0": 0.9, "s1": 0.9, "s2": 0.9, "goal": 1, "fail": 0,
+		})
+
+		if result.Scheduler[mdp.S[0]] != "a" || result.Scheduler[mdp.S[1]] != "d" {
+			t.Errorf("Expected the scheduler to choose a in s0 and d in s1 with %v, but found %v.", options.Method, result.Scheduler)
+		}
+
+		chain, err := mdp.InducedDTMC(result.Scheduler)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		formula, _ := ParsePCTLFormula("P=? [ F goal ]")
+		values, _ := chain.Values(formula, DefaultSolverOptions())
+		if math.Abs(values[chain.S[0]]-0.9) > 1e-8 {
+			t.Errorf("Expected the induced chain to reach goal with probability 0.9, but found %v.", values[chain.S[0]])
+		}
+	}
+}
+
+/*
+TestMDP_ReachabilityProbabilities2
+Description:
+
+	The minimal probability of reaching goal is 0 in the end component {s0, s1}, where a scheduler can stay forever.
+*/
+func TestMDP_ReachabilityProbabilities2(t *testing.T) {
+	mdp := GetDetourMDP()
+
+	for _, options := range getMethodOptions() {
+		result, err := mdp.ReachabilityProbabilities(mdp.StatesNamed("goal"), Minimize, options)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", options.Method, err)
+			continue
+		}
+
+		checkResult(t, "minimal probability ("+options.Method.String()+")", result, map[string]float64{
+			"s0": 0, "s1": 0, "s2": 0.9, "goal": 1, "fail": 0,
+		})
+
+		if result.Scheduler[mdp.S[0]] != "a" || result.Scheduler[mdp.S[1]] != "a" {
+			t.Errorf("Expected the scheduler to choose a in s0 and s1 with %v, but found %v.", options.Method, result.Scheduler)
+		}
+	}
+}
+
+/*
+TestMDP_ReachabilityProbabilities3
+Description:
+
+	Interval iteration returns bounds which contain the values and are within the tolerance of each other.
+	The other methods do not return bounds.
+*/
+func TestMDP_ReachabilityProbabilities3(t *testing.T) {
+	mdp := GetDetourMDP()
+	options := DefaultMDPSolverOptions()
+	options.Tolerance = 1e-4
+
+	result, err := mdp.ReachabilityProbabilities(mdp.StatesNamed("goal"), Maximize, options)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, state := range mdp.S {
+		lower, upper := result.Lower[state], result.Upper[state]
+		if !(lower <= result.Values[state] && result.Values[state] <= upper && upper-lower <= 1e-4) {
+			t.Errorf("Expected the bounds of %v to be tight around %v, but found [%v, %v].", state, result.Values[state], lower, upper)
+		}
+		if state.Name != "goal" && state.Name != "fail" && (lower > 0.9 || upper < 0.9) {
+			t.Errorf("Expected the bounds of %v to contain 0.9, but found [%v, %v].", state, lower, upper)
+		}
+	}
+
+	options.Method = ValueIteration
+	result, _ = mdp.ReachabilityProbabilities(mdp.StatesNamed("goal"), Maximize, options)
+	if result.Lower != nil || result.Upper != nil {
+		t.Errorf("Expected value iteration not to return bounds.")
+	}
+}
+
+/*
+TestMDP_ExpectedTotalReward1
+Description:
+
+	In the detour MDP, a costs nothing, so moving back and forth between s0 and s1 is free but never terminates.
+	The minimal reward is attained by c, and the maximal reward is infinite wherever a scheduler can stay in {s0, s1}.
+*/
+func TestMDP_ExpectedTotalReward1(t *testing.T) {
+	mdp := GetDetourMDP()
+	s0, s1, s2 := mdp.S[0], mdp.S[1], mdp.S[2]
+	rewards := map[MDPState]map[string]float64{
+		s0: {"b": 2},
+		s1: {"c": 1, "d": 1},
+		s2: {"a": 1},
+	}
+	absorbing := mdp.StatesNamed("goal", "fail")
+
+	for _, options := range getMethodOptions() {
+		minimal, err := mdp.ExpectedTotalReward(rewards, absorbing, Minimize, options)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", options.Method, err)
+			continue
+		}
+
+		checkResult(t, "minimal reward ("+options.Method.String()+")", minimal, map[string]float64{
+			"s0": 1, "s1": 1, "s2": 1, "goal": 0, "fail": 0,
+		})
+
+		if minimal.Scheduler[s0] != "a" || minimal.Scheduler[s1] != "c" {
+			t.Errorf("Expected the scheduler to choose a in s0 and c in s1 with %v, but found %v.", options.Method, minimal.Scheduler)
+		}
+
+		maximal, err := mdp.ExpectedTotalReward(rewards, absorbing, Maximize, options)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", options.Method, err)
+			continue
+		}
+
+		checkResult(t, "maximal reward ("+options.Method.String()+")", maximal, map[string]float64{
+			"s0": math.Inf(1), "s1": math.Inf(1), "s2": 1, "goal": 0, "fail": 0,
+		})
+
+		if maximal.Scheduler[s0] != "a" || maximal.Scheduler[s1] != "a" {
+			t.Errorf("Expected the scheduler to stay in {s0, s1} with %v, but found %v.", options.Method, maximal.Scheduler)
+		}
+	}
+}
+
+/*
+TestMDP_ExpectedTotalReward2
+Description:
+
+	In t0, x collects 2 and may move to t1, while y collects 3 and terminates. From t1, x collects 1 and may move
+	back to t0. Repeating x is worth 10/3 from t0, which is more than y.
+*/
+func TestMDP_ExpectedTotalReward2(t *testing.T) {
+	mdp, err := GetMDP(
+		[]string{"t0", "t1", "done"},
+		[]string{"x", "y"},
+		map[string]map[string]map[string]float64{
+			"t0":   {"x": {"t1": 0.5, "done": 0.5}, "y": {"done": 1}},
+			"t1":   {"x": {"t0": 0.5, "done": 0.5}},
+			"done": {"x": {"done": 1}},
+		},
+		map[string]float64{"t0": 1},
+		[]string{},
+		map[string][]string{},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	t0, t1 := mdp.S[0], mdp.S[1]
+	rewards := map[MDPState]map[string]float64{t0: {"x": 2, "y": 3}, t1: {"x": 1}}
+
+	for _, options := range getMethodOptions() {
+		maximal, err := mdp.ExpectedTotalReward(rewards, mdp.StatesNamed("done"), Maximize, options)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", options.Method, err)
+			continue
+		}
+		checkResult(t, "maximal reward ("+options.Method.String()+")", maximal, map[string]float64{"t0": 10.0 / 3, "t1": 8.0 / 3, "done": 0})
+
+		minimal, err := mdp.ExpectedTotalReward(rewards, mdp.StatesNamed("done"), Minimize, options)
+		if err != nil {
+			t.Errorf("Unexpected error with %v: %v", options.Method, err)
+			continue
+		}
+		checkResult(t, "minimal reward ("+options.Method.String()+")", minimal, map[string]float64{"t0": 3, "t1": 2.5, "done": 0})
+
+		if maximal.Scheduler[t0] != "x" || minimal.Scheduler[t0] != "y" {
+			t.Errorf("Expected the schedulers to choose x and y in t0 with %v, but found %v and %v.", options.Method, maximal.Scheduler, minimal.Scheduler)
+		}
+	}
+}
+
+/*
+TestMDP_ExpectedTotalReward3
+Description:
+
+	Invalid rewards, targets and options produce errors.
+*/
+func TestMDP_ExpectedTotalReward3(t *testing.T) {
+	mdp := GetDetourMDP()
+	goal := mdp.StatesNamed("goal")
+
+	_, err := mdp.ExpectedTotalReward(map[MDPState]map[string]float64{mdp.S[0]: {"c": 1}}, goal, Minimize, DefaultMDPSolverOptions())
+	if err == nil || err.Error() != "The action \"c\" has a reward in the state \"s0\", where it is not enabled." {
+		t.Errorf("Expected an error for the reward of c, but found %v.", err)
+	}
+
+	_, err = mdp.ExpectedTotalReward(map[MDPState]map[string]float64{mdp.S[0]: {"a": -1}}, goal, Minimize, DefaultMDPSolverOptions())
+	if err == nil || !strings.Contains(err.Error(), "rewards must be finite and nonnegative") {
+		t.Errorf("Expected an error for the negative reward, but found %v.", err)
+	}
+
+	_, err = mdp.ReachabilityProbabilities([]MDPState{{Name: "nowhere"}}, Maximize, DefaultMDPSolverOptions())
+	if err == nil || err.Error() != "The target \"nowhere\" is not a state of the MDP." {
+		t.Errorf("Expected an error for the unknown target, but found %v.", err)
+	}
+
+	_, err = mdp.ReachabilityProbabilities(goal, Direction(2), DefaultMDPSolverOptions())
+	if err == nil || err.Error() != "Unrecognized direction Direction(2)." {
+		t.Errorf("Expected an error for the direction, but found %v.", err)
+	}
+
+	options := DefaultMDPSolverOptions()
+	options.Method = MDPMethod(7)
+	if _, err = mdp.ReachabilityProbabilities(goal, Maximize, options); err == nil || err.Error() != "Unrecognized MDP method MDPMethod(7)." {
+		t.Errorf("Expected an error for the method, but found %v.", err)
+	}
+
+	options = MDPSolverOptions{Method: ValueIteration, Tolerance: 1e-12, MaxIterations: 3}
+	_, err = mdp.ReachabilityProbabilities(goal, Maximize, options)
+	if err == nil || err.Error() != "The value iteration did not converge to the tolerance 1e-12 within 3 iterations." {
+		t.Errorf("Expected an error for the iterations, but found %v.", err)
+	}
+}
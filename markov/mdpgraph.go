@@ -0,0 +1,339 @@
+/*
+mdpgraph.go
+Description:
+	Graph algorithms on Markov decision processes (Baier and Katoen, Section 10.6). They find the states whose
+	optimal reachability probabilities are 0 or 1 without any numerical computation, the maximal end components
+	in which a scheduler can stay forever, and the attractor choices which lead towards a set of states.
+*/
+
+package markov
+
+/*
+Type Definitions
+*/
+
+/*
+endComponent
+Description:
+	A set of states, together with the choices of each state which never leave the set, such that a scheduler
+	which only uses those choices can visit every state of the set infinitely often.
+*/
+type endComponent struct {
+	States  []int
+	Choices map[int][]int
+}
+
+/*
+Functions
+*/
+
+/*
+value
+Description:
+	Returns the reward of the choice plus the expected value of its successor.
+*/
+func (choice mdpChoice) value(values []float64) float64 {
+	value := choice.Reward
+	for _, entry := range choice.Entries {
+		value += entry.Value * values[entry.Column]
+	}
+	return value
+}
+
+/*
+staysIn
+Description:
+	Returns true if every successor of the choice is in the set.
+*/
+func (choice mdpChoice) staysIn(set []bool) bool {
+	for _, entry := range choice.Entries {
+		if !set[entry.Column] {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+reaches
+Description:
+	Returns true if some successor of the choice is in the set.
+*/
+func (choice mdpChoice) reaches(set []bool) bool {
+	for _, entry := range choice.Entries {
+		if set[entry.Column] {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+best
+Description:
+	Returns the largest (or smallest) value of the choices of state i and the index of the first choice
+	which attains it.
+*/
+func (matrix mdpMatrix) best(i int, values []float64, maximize bool) (float64, int) {
+	bestValue, bestChoice := 0.0, -1
+	for c, choice := range matrix[i] {
+		value := choice.value(values)
+		if bestChoice < 0 || (maximize && value > bestValue) || (!maximize && value < bestValue) {
+			bestValue, bestChoice = value, c
+		}
+	}
+	return bestValue, bestChoice
+}
+
+/*
+graph
+Description:
+	Returns the union of the transitions of all choices as a sparse matrix (the values are not probabilities).
+*/
+func (matrix mdpMatrix) graph() sparseMatrix {
+	graph := make(sparseMatrix, len(matrix))
+	for i, choices := range matrix {
+		for _, choice := range choices {
+			graph[i] = append(graph[i], choice.Entries...)
+		}
+	}
+	return graph
+}
+
+/*
+forallReach
+Description:
+	Returns the states from which every scheduler reaches a target with positive probability.
+	Their complement is the set of states whose minimal reachability probability is 0.
+*/
+func (matrix mdpMatrix) forallReach(targets []bool) []bool {
+	reach := append([]bool{}, targets...)
+	for changed := true; changed; {
+		changed = false
+		for i, choices := range matrix {
+			if reach[i] {
+				continue
+			}
+
+			allChoicesReach := true
+			for _, choice := range choices {
+				allChoicesReach = allChoicesReach && choice.reaches(reach)
+			}
+			if allChoicesReach {
+				reach[i] = true
+				changed = true
+			}
+		}
+	}
+	return reach
+}
+
+/*
+existsAlmostSure
+Description:
+	Returns the states from which some scheduler reaches a target with probability 1 (the maximal reachability
+	probability is 1). The outer iteration removes the states which cannot reach the targets while staying in
+	the current candidate set.
+*/
+func (matrix mdpMatrix) existsAlmostSure(targets []bool) []bool {
+	candidates := allStates(len(matrix))
+	for {
+		reach := append([]bool{}, targets...)
+		for changed := true; changed; {
+			changed = false
+			for i, choices := range matrix {
+				if reach[i] || !candidates[i] {
+					continue
+				}
+				for _, choice := range choices {
+					if choice.staysIn(candidates) && choice.reaches(reach) {
+						reach[i] = true
+						changed = true
+						break
+					}
+				}
+			}
+		}
+
+		stable := true
+		for i := range candidates {
+			stable = stable && candidates[i] == reach[i]
+		}
+		if stable {
+			return reach
+		}
+		candidates = reach
+	}
+}
+
+/*
+forallAlmostSure
+Description:
+	Returns the states from which every scheduler reaches a target with probability 1 (the minimal reachability
+	probability is 1). A state is not in this set exactly when some scheduler can reach, without passing through
+	a target, a state from which the targets can be avoided forever.
+*/
+func (matrix mdpMatrix) forallAlmostSure(targets []bool) []bool {
+	avoiding := matrix.forallReach(targets)
+	nonTargets := make([]bool, len(matrix))
+	for i := range avoiding {
+		avoiding[i] = !avoiding[i]
+		nonTargets[i] = !targets[i]
+	}
+
+	canAvoid := matrix.graph().backwardReachable(avoiding, nonTargets)
+	for i := range canAvoid {
+		canAvoid[i] = !canAvoid[i]
+	}
+	return canAvoid
+}
+
+/*
+attractor
+Description:
+	Chooses, for each state of the region which is not a target, a candidate choice with a successor which is
+	strictly closer to the targets. States which cannot be attracted get -1.
+	A scheduler which follows these choices reaches the targets with positive probability from every attracted state.
+*/
+func (matrix mdpMatrix) attractor(targets []bool, region []bool, candidate func(i int, c int) bool) []int {
+	attracted := append([]bool{}, targets...)
+	choices := make([]int, len(matrix))
+	for i := range choices {
+		choices[i] = -1
+	}
+
+	for {
+		var newlyAttracted []int
+		for i, stateChoices := range matrix {
+			if attracted[i] || !region[i] {
+				continue
+			}
+			for c, choice := range stateChoices {
+				if candidate(i, c) && choice.reaches(attracted) {
+					choices[i] = c
+					newlyAttracted = append(newlyAttracted, i)
+					break
+				}
+			}
+		}
+
+		if len(newlyAttracted) == 0 {
+			return choices
+		}
+		for _, i := range newlyAttracted {
+			attracted[i] = true
+		}
+	}
+}
+
+/*
+maximalEndComponents
+Description:
+	Returns the maximal end components of the MDP restricted to the allowed states and to the choices accepted
+	by keep (nil keeps every choice). Each round splits the current candidate sets into strongly connected
+	components, using only the choices which stay in their candidate set, and removes the states which have
+	no such choice left; the rounds stop when nothing changes (Baier and Katoen, Section 10.6.3).
+*/
+func (matrix mdpMatrix) maximalEndComponents(allowed []bool, keep func(i int, choice mdpChoice) bool) []endComponent {
+	n := len(matrix)
+	component := make([]int, n)
+	for i := range component {
+		component[i] = -1
+		if allowed[i] {
+			component[i] = 0
+		}
+	}
+
+	staysInComponent := func(i int, choice mdpChoice, component []int) bool {
+		if keep != nil && !keep(i, choice) {
+			return false
+		}
+		for _, entry := range choice.Entries {
+			if component[entry.Column] != component[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	numberOfComponents := 1
+	for {
+		// Strongly connected components of the choices which stay in their candidate set
+		graph := make(sparseMatrix, n)
+		remaining := make([]bool, n)
+		for i, choices := range matrix {
+			if component[i] < 0 {
+				continue
+			}
+			remaining[i] = true
+			for _, choice := range choices {
+				if staysInComponent(i, choice, component) {
+					graph[i] = append(graph[i], choice.Entries...)
+				}
+			}
+		}
+
+		sccs := graph.stronglyConnectedComponents(remaining)
+		for c, scc := range sccs {
+			for _, i := range scc {
+				component[i] = c
+			}
+		}
+		changed := len(sccs) != numberOfComponents
+
+		// Remove the states which must leave their component
+		next := append([]int{}, component...)
+		for i, choices := range matrix {
+			if component[i] < 0 {
+				continue
+			}
+			canStay := false
+			for _, choice := range choices {
+				canStay = canStay || staysInComponent(i, choice, component)
+			}
+			if !canStay {
+				next[i] = -1
+				changed = true
+			}
+		}
+		component = next
+
+		remainingComponents := make(map[int]bool)
+		for _, c := range component {
+			if c >= 0 {
+				remainingComponents[c] = true
+			}
+		}
+		numberOfComponents = len(remainingComponents)
+
+		if !changed {
+			break
+		}
+	}
+
+	// Collect the components and the choices which stay in them
+	byIndex := make(map[int]*endComponent)
+	var order []int
+	for i, choices := range matrix {
+		c := component[i]
+		if c < 0 {
+			continue
+		}
+		if _, seen := byIndex[c]; !seen {
+			byIndex[c] = &endComponent{Choices: make(map[int][]int)}
+			order = append(order, c)
+		}
+		byIndex[c].States = append(byIndex[c].States, i)
+		for choiceIndex, choice := range choices {
+			if staysInComponent(i, choice, component) {
+				byIndex[c].Choices[i] = append(byIndex[c].Choices[i], choiceIndex)
+			}
+		}
+	}
+
+	var components []endComponent
+	for _, c := range order {
+		components = append(components, *byIndex[c])
+	}
+	return components
+}
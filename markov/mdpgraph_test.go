@@ -0,0 +1,105 @@
+/*
+mdpgraph_test.go
+Description:
+
+	Tests for the graph algorithms on MDPs defined in mdpgraph.go
+*/
+package markov
+
+import (
+	"fmt"
+	"testing"
+)
+
+/*
+setString
+Description:
+
+	Prints the indices of a set of states.
+*/
+func setString(set []bool) string {
+	var members []int
+	for i, isMember := range set {
+		if isMember {
+			members = append(members, i)
+		}
+	}
+	return fmt.Sprint(members)
+}
+
+/*
+TestMdpMatrix_almostSure1
+Description:
+
+	In the detour MDP, goal can be missed from every state except goal itself, can be avoided forever from
+	s0 and s1, and cannot be reached almost surely from anywhere else.
+*/
+func TestMdpMatrix_almostSure1(t *testing.T) {
+	matrix := GetDetourMDP().matrix(nil)
+	goal := []bool{false, false, false, true, false}
+
+	if reach := setString(matrix.forallReach(goal)); reach != "[2 3]" {
+		t.Errorf("Expected every scheduler to reach goal with positive probability from [2 3], but found %v.", reach)
+	}
+
+	if one := setString(matrix.existsAlmostSure(goal)); one != "[3]" {
+		t.Errorf("Expected some scheduler to reach goal almost surely only from [3], but found %v.", one)
+	}
+
+	if one := setString(matrix.forallAlmostSure(goal)); one != "[3]" {
+		t.Errorf("Expected every scheduler to reach goal almost surely only from [3], but found %v.", one)
+	}
+
+	// Both absorbing states are reached almost surely by some scheduler, but not by every scheduler
+	absorbing := []bool{false, false, false, true, true}
+	if one := setString(matrix.existsAlmostSure(absorbing)); one != "[0 1 2 3 4]" {
+		t.Errorf("Expected every state to reach the absorbing states almost surely, but found %v.", one)
+	}
+
+	if one := setString(matrix.forallAlmostSure(absorbing)); one != "[2 3 4]" {
+		t.Errorf("Expected only [2 3 4] to reach the absorbing states under every scheduler, but found %v.", one)
+	}
+}
+
+/*
+TestMdpMatrix_maximalEndComponents1
+Description:
+
+	The detour MDP has the end components {s0, s1}, {goal} and {fail}. Without the action a, {s0, s1} is not
+	an end component, since d leaves s1 with probability 1/2.
+*/
+func TestMdpMatrix_maximalEndComponents1(t *testing.T) {
+	matrix := GetDetourMDP().matrix(nil)
+
+	components := matrix.maximalEndComponents(allStates(5), nil)
+	if fmt.Sprint(components) != "[{[0 1] map[0:[0] 1:[0]]} {[3] map[3:[0]]} {[4] map[4:[0]]}]" {
+		t.Errorf("Expected the components {s0, s1}, {goal} and {fail}, but found %v.", components)
+	}
+
+	withoutA := matrix.maximalEndComponents(
+		allStates(5),
+		func(i int, choice mdpChoice) bool { return choice.Action != "a" },
+	)
+	if fmt.Sprint(withoutA) != "[{[3] map[3:[0]]} {[4] map[4:[0]]}]" {
+		t.Errorf("Expected the components {goal} and {fail}, but found %v.", withoutA)
+	}
+}
+
+/*
+TestMdpMatrix_attractor1
+Description:
+
+	Without the action a of s1, s0 and s1 are attracted to s2 through a and d.
+*/
+func TestMdpMatrix_attractor1(t *testing.T) {
+	matrix := GetDetourMDP().matrix(nil)
+
+	choices := matrix.attractor(
+		[]bool{false, false, true, false, false},
+		[]bool{true, true, false, false, false},
+		func(i int, c int) bool { return matrix[i][c].Action != "c" && !(i == 1 && c == 0) },
+	)
+	if fmt.Sprint(choices) != "[0 2 -1 -1 -1]" {
+		t.Errorf("Expected the choices [0 2 -1 -1 -1], but found %v.", choices)
+	}
+}
@@ -0,0 +1,1050 @@
+/*
+pctl.go
+Description:
+	Formulas of Probabilistic Computation Tree Logic (PCTL) with the steady-state operator, and a parser for them.
+	The probabilistic operator is written P>=0.9 [ path ] and the steady-state operator S<0.01 [ state ], where the
+	comparison is one of <, <=, >, >= (or ≤, ≥) followed by a probability, or =? to ask for the probability itself.
+	The path formulas are X φ, φ U φ, φ U<=k φ, F φ, F<=k φ, G φ and G<=k φ. The boolean connectives are
+	!, &, | and -> (or ¬, ∧, ∨ and →). Names which contain spaces or operator characters can be written in double quotes.
+	The same formulas describe the Continuous Stochastic Logic (CSL) properties of continuous-time Markov chains, where
+	U, F and G take a time interval instead of a step bound: U[t1,t2], U<=t and U>=t (see ParseCSLFormula()).
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+type PCTLOperator int
+
+const (
+	PCTLOpTrue PCTLOperator = iota
+	PCTLOpFalse
+	PCTLOpAtom
+	PCTLOpNot
+	PCTLOpAnd
+	PCTLOpOr
+	PCTLOpImplies
+	PCTLOpProbability
+	PCTLOpSteadyState
+	PCTLOpNext
+	PCTLOpUntil
+	PCTLOpEventually
+	PCTLOpAlways
+)
+
+/*
+Comparison
+Description:
+	The comparison of a probabilistic or steady-state operator with its bound.
+	CompareQuery (=?) asks for the probability instead of comparing it.
+*/
+type Comparison int
+
+const (
+	CompareLess Comparison = iota
+	CompareLessOrEqual
+	CompareGreater
+	CompareGreaterOrEqual
+	CompareQuery
+)
+
+/*
+PCTLFormula
+Description:
+	A node of the syntax tree of a PCTL or CSL state or path formula.
+	Atom is only used by PCTLOpAtom, Comparison and Bound only by PCTLOpProbability and PCTLOpSteadyState,
+	and StepBound and TimeBound only by PCTLOpUntil, PCTLOpEventually and PCTLOpAlways (a negative StepBound
+	means that there is no step bound, and a nil TimeBound that there is no time bound).
+*/
+type PCTLFormula struct {
+	Operator   PCTLOperator
+	Atom       mc.AtomicProposition
+	Operands   []PCTLFormula
+	Comparison Comparison
+	Bound      float64
+	StepBound  int
+	TimeBound  *TimeInterval
+}
+
+/*
+TimeInterval
+Description:
+	The time interval [Lower, Upper] of a CSL path formula. Upper is math.Inf(1) for U>=t.
+*/
+type TimeInterval struct {
+	Lower float64
+	Upper float64
+}
+
+/*
+Constructors
+*/
+
+/*
+PCTLTrue
+Description:
+	The formula which holds in every state.
+*/
+func PCTLTrue() PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpTrue}
+}
+
+/*
+PCTLFalse
+Description:
+	The formula which holds in no state.
+*/
+func PCTLFalse() PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpFalse}
+}
+
+/*
+PCTLAtom
+Description:
+	The formula which holds in the states labelled with the atomic proposition apName.
+*/
+func PCTLAtom(apName string) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpAtom, Atom: mc.AtomicProposition{Name: apName}}
+}
+
+/*
+PCTLNot
+Description:
+	The negation of the state formula.
+*/
+func PCTLNot(formula PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpNot, Operands: []PCTLFormula{formula}}
+}
+
+/*
+PCTLAnd
+Description:
+	The conjunction of the state formulas. The conjunction of no formulas is true.
+*/
+func PCTLAnd(formulas ...PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpAnd, Operands: formulas}
+}
+
+/*
+PCTLOr
+Description:
+	The disjunction of the state formulas. The disjunction of no formulas is false.
+*/
+func PCTLOr(formulas ...PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpOr, Operands: formulas}
+}
+
+/*
+PCTLImplies
+Description:
+	The state formula premise -> conclusion.
+*/
+func PCTLImplies(premise PCTLFormula, conclusion PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpImplies, Operands: []PCTLFormula{premise, conclusion}}
+}
+
+/*
+PCTLProbability
+Description:
+	The state formula P⋈bound [ pathFormula ].
+*/
+func PCTLProbability(comparison Comparison, bound float64, pathFormula PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpProbability, Comparison: comparison, Bound: bound, Operands: []PCTLFormula{pathFormula}}
+}
+
+/*
+PCTLSteadyState
+Description:
+	The state formula S⋈bound [ formula ]: the long-run probability of being in a state which satisfies formula.
+*/
+func PCTLSteadyState(comparison Comparison, bound float64, formula PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpSteadyState, Comparison: comparison, Bound: bound, Operands: []PCTLFormula{formula}}
+}
+
+/*
+PCTLNext
+Description:
+	The path formula X formula.
+*/
+func PCTLNext(formula PCTLFormula) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpNext, Operands: []PCTLFormula{formula}}
+}
+
+/*
+PCTLUntil
+Description:
+	The path formula left U right.
+*/
+func PCTLUntil(left PCTLFormula, right PCTLFormula) PCTLFormula {
+	return PCTLBoundedUntil(left, right, -1)
+}
+
+/*
+PCTLBoundedUntil
+Description:
+	The path formula left U<=k right: right holds within k steps and left holds before.
+*/
+func PCTLBoundedUntil(left PCTLFormula, right PCTLFormula, k int) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpUntil, Operands: []PCTLFormula{left, right}, StepBound: k}
+}
+
+/*
+PCTLEventually
+Description:
+	The path formula F formula, i.e. true U formula.
+*/
+func PCTLEventually(formula PCTLFormula) PCTLFormula {
+	return PCTLBoundedEventually(formula, -1)
+}
+
+/*
+PCTLBoundedEventually
+Description:
+	The path formula F<=k formula.
+*/
+func PCTLBoundedEventually(formula PCTLFormula, k int) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpEventually, Operands: []PCTLFormula{formula}, StepBound: k}
+}
+
+/*
+PCTLAlways
+Description:
+	The path formula G formula, i.e. !F !formula.
+*/
+func PCTLAlways(formula PCTLFormula) PCTLFormula {
+	return PCTLBoundedAlways(formula, -1)
+}
+
+/*
+PCTLBoundedAlways
+Description:
+	The path formula G<=k formula.
+*/
+func PCTLBoundedAlways(formula PCTLFormula, k int) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpAlways, Operands: []PCTLFormula{formula}, StepBound: k}
+}
+
+/*
+Functions for Comparison
+*/
+
+/*
+String
+Description:
+	Prints the comparison as it is written in formulas.
+*/
+func (comparison Comparison) String() string {
+	switch comparison {
+	case CompareLess:
+		return "<"
+	case CompareLessOrEqual:
+		return "<="
+	case CompareGreater:
+		return ">"
+	case CompareGreaterOrEqual:
+		return ">="
+	case CompareQuery:
+		return "=?"
+	default:
+		return "?"
+	}
+}
+
+/*
+Holds
+Description:
+	Compares the value with the bound. Queries hold for every value.
+*/
+func (comparison Comparison) Holds(value float64, bound float64) bool {
+	switch comparison {
+	case CompareLess:
+		return value < bound
+	case CompareLessOrEqual:
+		return value <= bound
+	case CompareGreater:
+		return value > bound
+	case CompareGreaterOrEqual:
+		return value >= bound
+	default:
+		return true
+	}
+}
+
+/*
+CSLTimeBoundedUntil
+Description:
+	The CSL path formula left U[lower,upper] right: right holds at some time in [lower,upper] and left holds before.
+*/
+func CSLTimeBoundedUntil(left PCTLFormula, right PCTLFormula, lower float64, upper float64) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpUntil, Operands: []PCTLFormula{left, right}, StepBound: -1, TimeBound: &TimeInterval{Lower: lower, Upper: upper}}
+}
+
+/*
+CSLTimeBoundedEventually
+Description:
+	The CSL path formula F[lower,upper] formula.
+*/
+func CSLTimeBoundedEventually(formula PCTLFormula, lower float64, upper float64) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpEventually, Operands: []PCTLFormula{formula}, StepBound: -1, TimeBound: &TimeInterval{Lower: lower, Upper: upper}}
+}
+
+/*
+CSLTimeBoundedAlways
+Description:
+	The CSL path formula G[lower,upper] formula: formula holds at every time in [lower,upper].
+*/
+func CSLTimeBoundedAlways(formula PCTLFormula, lower float64, upper float64) PCTLFormula {
+	return PCTLFormula{Operator: PCTLOpAlways, Operands: []PCTLFormula{formula}, StepBound: -1, TimeBound: &TimeInterval{Lower: lower, Upper: upper}}
+}
+
+/*
+Functions for PCTLFormula
+*/
+
+/*
+IsPathFormula
+Description:
+	Returns true if the formula is a path formula (X, U, F or G), which can only appear inside P[...].
+*/
+func (formula PCTLFormula) IsPathFormula() bool {
+	switch formula.Operator {
+	case PCTLOpNext, PCTLOpUntil, PCTLOpEventually, PCTLOpAlways:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+IsQuery
+Description:
+	Returns true if the formula is P=? [...] or S=? [...].
+*/
+func (formula PCTLFormula) IsQuery() bool {
+	return (formula.Operator == PCTLOpProbability || formula.Operator == PCTLOpSteadyState) && formula.Comparison == CompareQuery
+}
+
+/*
+String
+Description:
+	Prints the formula in the syntax accepted by ParsePCTLFormula(), or by ParseCSLFormula() if it has time bounds.
+*/
+func (formula PCTLFormula) String() string {
+	switch formula.Operator {
+	case PCTLOpTrue:
+		return "true"
+	case PCTLOpFalse:
+		return "false"
+	case PCTLOpAtom:
+		return pctlNameString(formula.Atom.Name)
+	case PCTLOpNot:
+		return fmt.Sprintf("!%v", formula.Operands[0])
+	case PCTLOpAnd, PCTLOpOr:
+		if len(formula.Operands) == 0 {
+			if formula.Operator == PCTLOpAnd {
+				return "true"
+			}
+			return "false"
+		}
+		connective := " & "
+		if formula.Operator == PCTLOpOr {
+			connective = " | "
+		}
+		var operandStrings []string
+		for _, operand := range formula.Operands {
+			operandStrings = append(operandStrings, operand.String())
+		}
+		return "(" + strings.Join(operandStrings, connective) + ")"
+	case PCTLOpImplies:
+		return fmt.Sprintf("(%v -> %v)", formula.Operands[0], formula.Operands[1])
+	case PCTLOpProbability, PCTLOpSteadyState:
+		operatorName := "P"
+		if formula.Operator == PCTLOpSteadyState {
+			operatorName = "S"
+		}
+		return fmt.Sprintf("%v%v [%v]", operatorName, pctlBoundString(formula.Comparison, formula.Bound), formula.Operands[0])
+	case PCTLOpNext:
+		return fmt.Sprintf("X %v", formula.Operands[0])
+	case PCTLOpUntil:
+		return fmt.Sprintf("%v U%v %v", formula.Operands[0], formula.boundString(), formula.Operands[1])
+	case PCTLOpEventually:
+		return fmt.Sprintf("F%v %v", formula.boundString(), formula.Operands[0])
+	case PCTLOpAlways:
+		return fmt.Sprintf("G%v %v", formula.boundString(), formula.Operands[0])
+	default:
+		return "?"
+	}
+}
+
+/*
+pctlBoundString
+Description:
+	Prints a comparison with its bound, e.g. >=0.5 or =?.
+*/
+func pctlBoundString(comparison Comparison, bound float64) string {
+	if comparison == CompareQuery {
+		return comparison.String()
+	}
+	return comparison.String() + strconv.FormatFloat(bound, 'f', -1, 64)
+}
+
+/*
+boundString
+Description:
+	Prints the step bound as <=k or the time bound as <=t, >=t or [t1,t2], or nothing if the operator is unbounded.
+*/
+func (formula PCTLFormula) boundString() string {
+	if interval := formula.TimeBound; interval != nil {
+		lower := strconv.FormatFloat(interval.Lower, 'g', -1, 64)
+		upper := strconv.FormatFloat(interval.Upper, 'g', -1, 64)
+		switch {
+		case interval.Lower == 0 && !math.IsInf(interval.Upper, 1):
+			return "<=" + upper
+		case math.IsInf(interval.Upper, 1):
+			return ">=" + lower
+		default:
+			return fmt.Sprintf("[%v,%v]", lower, upper)
+		}
+	}
+
+	if formula.StepBound < 0 {
+		return ""
+	}
+	return fmt.Sprintf("<=%v", formula.StepBound)
+}
+
+/*
+pctlNameString
+Description:
+	Prints the name of an atomic proposition, quoting it if it could not be parsed as an identifier.
+*/
+func pctlNameString(name string) string {
+	if name == "" || pctlIsKeyword(name) {
+		return fmt.Sprintf("%q", name)
+	}
+	for _, r := range name {
+		if !pctlIsIdentifierRune(r) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+/*
+Parsing
+*/
+
+type pctlTokenKind int
+
+const (
+	pctlTokenEnd pctlTokenKind = iota
+	pctlTokenName
+	pctlTokenLeftParen
+	pctlTokenRightParen
+	pctlTokenLeftBracket
+	pctlTokenRightBracket
+	pctlTokenNot
+	pctlTokenAnd
+	pctlTokenOr
+	pctlTokenImplies
+	pctlTokenComparison
+	pctlTokenComma
+)
+
+type pctlToken struct {
+	Kind     pctlTokenKind
+	Text     string
+	Quoted   bool
+	Position int
+}
+
+var pctlTwoCharacterSymbols = map[string]pctlTokenKind{
+	"->": pctlTokenImplies,
+	"&&": pctlTokenAnd,
+	"||": pctlTokenOr,
+	"<=": pctlTokenComparison,
+	">=": pctlTokenComparison,
+	"=?": pctlTokenComparison,
+}
+
+var pctlOneCharacterSymbols = map[rune]pctlTokenKind{
+	'(': pctlTokenLeftParen,
+	')': pctlTokenRightParen,
+	'[': pctlTokenLeftBracket,
+	']': pctlTokenRightBracket,
+	',': pctlTokenComma,
+	'!': pctlTokenNot,
+	'¬': pctlTokenNot,
+	'&': pctlTokenAnd,
+	'∧': pctlTokenAnd,
+	'|': pctlTokenOr,
+	'∨': pctlTokenOr,
+	'→': pctlTokenImplies,
+	'<': pctlTokenComparison,
+	'>': pctlTokenComparison,
+	'≤': pctlTokenComparison,
+	'≥': pctlTokenComparison,
+}
+
+var pctlComparisons = map[string]Comparison{
+	"<":  CompareLess,
+	"<=": CompareLessOrEqual,
+	"≤":  CompareLessOrEqual,
+	">":  CompareGreater,
+	">=": CompareGreaterOrEqual,
+	"≥":  CompareGreaterOrEqual,
+	"=?": CompareQuery,
+}
+
+/*
+pctlParser
+Description:
+	A recursive descent parser over the tokens of a formula. When Continuous is true, the bounds of U, F and G
+	are read as time intervals (CSL); otherwise they are step bounds (PCTL).
+*/
+type pctlParser struct {
+	Tokens     []pctlToken
+	Index      int
+	Continuous bool
+}
+
+/*
+ParsePCTLFormula
+Description:
+	Parses a PCTL state formula such as "P>=0.99 [ !fail U<=10 delivered ]".
+	Implication is right-associative and binds weaker than |, which binds weaker than &.
+Usage:
+	formula, err := ParsePCTLFormula("S<0.01 [ down ]")
+*/
+func ParsePCTLFormula(formulaString string) (PCTLFormula, error) {
+	return parseProbabilisticFormula(formulaString, false)
+}
+
+/*
+ParseCSLFormula
+Description:
+	Parses a CSL state formula such as "P>=0.9 [ up U[1,2.5] done ]". The syntax is that of ParsePCTLFormula(),
+	except that the bounds of U, F and G are time intervals: [t1,t2], <=t for [0,t] and >=t for [t,∞).
+Usage:
+	formula, err := ParseCSLFormula("P=? [ F<=10 down ]")
+*/
+func ParseCSLFormula(formulaString string) (PCTLFormula, error) {
+	return parseProbabilisticFormula(formulaString, true)
+}
+
+/*
+parseProbabilisticFormula
+Description:
+	Parses a PCTL formula, or a CSL formula if continuous is true.
+*/
+func parseProbabilisticFormula(formulaString string, continuous bool) (PCTLFormula, error) {
+	tokens, err := pctlTokenize(formulaString)
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	parser := pctlParser{Tokens: tokens, Continuous: continuous}
+	formula, err := parser.parseImplication()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	if next := parser.peek(); next.Kind != pctlTokenEnd {
+		return PCTLFormula{}, fmt.Errorf("Unexpected \"%v\" at position %v.", next.Text, next.Position)
+	}
+
+	return formula, nil
+}
+
+/*
+pctlTokenize
+Description:
+	Splits the formula string into tokens. Positions are counted in characters, starting from 1.
+	Numbers are read as names and converted by the parser.
+*/
+func pctlTokenize(formulaString string) ([]pctlToken, error) {
+	runes := []rune(formulaString)
+	var tokens []pctlToken
+
+	for index := 0; index < len(runes); {
+		r := runes[index]
+		position := index + 1
+
+		// Two-character symbols
+		if index+1 < len(runes) {
+			pair := string(runes[index : index+2])
+			if kind, isSymbol := pctlTwoCharacterSymbols[pair]; isSymbol {
+				tokens = append(tokens, pctlToken{Kind: kind, Text: pair, Position: position})
+				index += 2
+				continue
+			}
+		}
+
+		// One-character symbols
+		if kind, isSymbol := pctlOneCharacterSymbols[r]; isSymbol {
+			tokens = append(tokens, pctlToken{Kind: kind, Text: string(r), Position: position})
+			index++
+			continue
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			index++
+		case r == '"':
+			// Quoted name
+			end := index + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("The quoted name starting at position %v is never closed.", position)
+			}
+			tokens = append(tokens, pctlToken{Kind: pctlTokenName, Text: string(runes[index+1 : end]), Quoted: true, Position: position})
+			index = end + 1
+		case pctlIsIdentifierRune(r):
+			end := index
+			for end < len(runes) && pctlIsIdentifierRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, pctlToken{Kind: pctlTokenName, Text: string(runes[index:end]), Position: position})
+			index = end
+		default:
+			return nil, fmt.Errorf("Unexpected character '%v' at position %v.", string(r), position)
+		}
+	}
+
+	return append(tokens, pctlToken{Kind: pctlTokenEnd, Text: "end of formula", Position: len(runes) + 1}), nil
+}
+
+/*
+pctlIsIdentifierRune
+Description:
+	Returns true if the rune can be part of an unquoted name (or number).
+*/
+func pctlIsIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+/*
+pctlIsKeyword
+Description:
+	Returns true if the unquoted name has a special meaning in the parser.
+*/
+func pctlIsKeyword(name string) bool {
+	switch name {
+	case "true", "false", "P", "S", "X", "U", "F", "G":
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+peek
+Description:
+	Returns the next token without consuming it.
+*/
+func (parser *pctlParser) peek() pctlToken {
+	return parser.Tokens[parser.Index]
+}
+
+/*
+next
+Description:
+	Consumes and returns the next token.
+*/
+func (parser *pctlParser) next() pctlToken {
+	token := parser.Tokens[parser.Index]
+	if token.Kind != pctlTokenEnd {
+		parser.Index++
+	}
+	return token
+}
+
+/*
+expect
+Description:
+	Consumes the next token and returns an error if it is not of the given kind.
+*/
+func (parser *pctlParser) expect(kind pctlTokenKind, description string) (pctlToken, error) {
+	token := parser.next()
+	if token.Kind != kind {
+		return token, fmt.Errorf("Expected %v at position %v, but found \"%v\".", description, token.Position, token.Text)
+	}
+	return token, nil
+}
+
+/*
+isKeyword
+Description:
+	Returns true if the token is the unquoted keyword.
+*/
+func (token pctlToken) isKeyword(keyword string) bool {
+	return token.Kind == pctlTokenName && !token.Quoted && token.Text == keyword
+}
+
+/*
+parseImplication
+Description:
+	implication := disjunction [ "->" implication ]
+*/
+func (parser *pctlParser) parseImplication() (PCTLFormula, error) {
+	premise, err := parser.parseDisjunction()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	if parser.peek().Kind != pctlTokenImplies {
+		return premise, nil
+	}
+	parser.next()
+
+	conclusion, err := parser.parseImplication()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	return PCTLImplies(premise, conclusion), nil
+}
+
+/*
+parseDisjunction
+Description:
+	disjunction := conjunction { "|" conjunction }
+*/
+func (parser *pctlParser) parseDisjunction() (PCTLFormula, error) {
+	operand, err := parser.parseConjunction()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	operands := []PCTLFormula{operand}
+	for parser.peek().Kind == pctlTokenOr {
+		parser.next()
+		operand, err = parser.parseConjunction()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return PCTLOr(operands...), nil
+}
+
+/*
+parseConjunction
+Description:
+	conjunction := unary { "&" unary }
+*/
+func (parser *pctlParser) parseConjunction() (PCTLFormula, error) {
+	operand, err := parser.parseUnary()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	operands := []PCTLFormula{operand}
+	for parser.peek().Kind == pctlTokenAnd {
+		parser.next()
+		operand, err = parser.parseUnary()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return PCTLAnd(operands...), nil
+}
+
+/*
+parseUnary
+Description:
+	unary := "!" unary | "P" bound "[" path "]" | "S" bound "[" implication "]"
+		| "true" | "false" | name | "(" implication ")"
+*/
+func (parser *pctlParser) parseUnary() (PCTLFormula, error) {
+	token := parser.next()
+	switch token.Kind {
+	case pctlTokenNot:
+		operand, err := parser.parseUnary()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		return PCTLNot(operand), nil
+
+	case pctlTokenLeftParen:
+		formula, err := parser.parseImplication()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		if _, err = parser.expect(pctlTokenRightParen, "\")\""); err != nil {
+			return PCTLFormula{}, err
+		}
+		return formula, nil
+
+	case pctlTokenName:
+		if !token.Quoted {
+			switch token.Text {
+			case "true":
+				return PCTLTrue(), nil
+			case "false":
+				return PCTLFalse(), nil
+			case "P", "S":
+				if parser.peek().Kind == pctlTokenComparison {
+					return parser.parseProbabilistic(token)
+				}
+			case "X", "U", "F", "G":
+				return PCTLFormula{}, fmt.Errorf("The path operator \"%v\" at position %v must appear inside P[...].", token.Text, token.Position)
+			}
+		}
+		return PCTLAtom(token.Text), nil
+
+	default:
+		return PCTLFormula{}, fmt.Errorf("Expected a formula at position %v, but found \"%v\".", token.Position, token.Text)
+	}
+}
+
+/*
+parseProbabilistic
+Description:
+	Parses the bound and the bracketed operand of a P or S operator, after the operator itself.
+*/
+func (parser *pctlParser) parseProbabilistic(operatorToken pctlToken) (PCTLFormula, error) {
+	comparisonToken := parser.next()
+	comparison, isComparison := pctlComparisons[comparisonToken.Text]
+	if !isComparison {
+		return PCTLFormula{}, fmt.Errorf("Unrecognized comparison \"%v\" at position %v.", comparisonToken.Text, comparisonToken.Position)
+	}
+
+	bound := 0.0
+	if comparison != CompareQuery {
+		boundToken, err := parser.expect(pctlTokenName, "a probability")
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		bound, err = strconv.ParseFloat(boundToken.Text, 64)
+		if err != nil || bound < 0 || bound > 1 {
+			return PCTLFormula{}, fmt.Errorf("The bound \"%v\" at position %v is not a probability in [0,1].", boundToken.Text, boundToken.Position)
+		}
+	}
+
+	if _, err := parser.expect(pctlTokenLeftBracket, "\"[\""); err != nil {
+		return PCTLFormula{}, err
+	}
+
+	var operand PCTLFormula
+	var err error
+	if operatorToken.Text == "P" {
+		operand, err = parser.parsePath()
+	} else {
+		operand, err = parser.parseImplication()
+	}
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	if _, err = parser.expect(pctlTokenRightBracket, "\"]\""); err != nil {
+		return PCTLFormula{}, err
+	}
+
+	if operatorToken.Text == "P" {
+		return PCTLProbability(comparison, bound, operand), nil
+	}
+	return PCTLSteadyState(comparison, bound, operand), nil
+}
+
+/*
+parsePath
+Description:
+	path := "X" implication | ("F" | "G") [ bound ] implication | implication "U" [ bound ] implication
+*/
+func (parser *pctlParser) parsePath() (PCTLFormula, error) {
+	token := parser.peek()
+
+	switch {
+	case token.isKeyword("X"):
+		parser.next()
+		operand, err := parser.parseImplication()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		return PCTLNext(operand), nil
+
+	case token.isKeyword("F"), token.isKeyword("G"):
+		parser.next()
+		stepBound, timeBound, err := parser.parseBound()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		operand, err := parser.parseImplication()
+		if err != nil {
+			return PCTLFormula{}, err
+		}
+		formula := PCTLBoundedAlways(operand, stepBound)
+		if token.Text == "F" {
+			formula = PCTLBoundedEventually(operand, stepBound)
+		}
+		formula.TimeBound = timeBound
+		return formula, nil
+	}
+
+	left, err := parser.parseImplication()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	untilToken := parser.next()
+	if !untilToken.isKeyword("U") {
+		return PCTLFormula{}, fmt.Errorf("Expected a path formula (X, U, F or G) at position %v, but found \"%v\".", untilToken.Position, untilToken.Text)
+	}
+
+	stepBound, timeBound, err := parser.parseBound()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	right, err := parser.parseImplication()
+	if err != nil {
+		return PCTLFormula{}, err
+	}
+
+	formula := PCTLBoundedUntil(left, right, stepBound)
+	formula.TimeBound = timeBound
+	return formula, nil
+}
+
+/*
+parseBound
+Description:
+	Parses the optional bound of U, F or G: a step bound for PCTL formulas and a time interval for CSL formulas.
+*/
+func (parser *pctlParser) parseBound() (int, *TimeInterval, error) {
+	if !parser.Continuous {
+		if token := parser.peek(); token.Kind == pctlTokenLeftBracket {
+			return -1, nil, fmt.Errorf("The time interval at position %v can only be used in CSL formulas.", token.Position)
+		}
+		stepBound, err := parser.parseStepBound()
+		return stepBound, nil, err
+	}
+
+	timeBound, err := parser.parseTimeBound()
+	return -1, timeBound, err
+}
+
+/*
+parseStepBound
+Description:
+	Parses an optional step bound "<=k" and returns -1 if there is none.
+*/
+func (parser *pctlParser) parseStepBound() (int, error) {
+	token := parser.peek()
+	if token.Kind != pctlTokenComparison {
+		return -1, nil
+	}
+	parser.next()
+
+	if token.Text != "<=" && token.Text != "≤" {
+		return -1, fmt.Errorf("Step bounds are written <=k, but found \"%v\" at position %v.", token.Text, token.Position)
+	}
+
+	boundToken, err := parser.expect(pctlTokenName, "a number of steps")
+	if err != nil {
+		return -1, err
+	}
+
+	stepBound, err := strconv.Atoi(boundToken.Text)
+	if err != nil || stepBound < 0 {
+		return -1, fmt.Errorf("The step bound \"%v\" at position %v is not a nonnegative integer.", boundToken.Text, boundToken.Position)
+	}
+
+	return stepBound, nil
+}
+
+/*
+parseTimeBound
+Description:
+	Parses an optional time bound "[t1,t2]", "<=t" or ">=t" and returns nil if there is none.
+*/
+func (parser *pctlParser) parseTimeBound() (*TimeInterval, error) {
+	token := parser.peek()
+	switch {
+	case token.Kind == pctlTokenLeftBracket:
+		parser.next()
+		lower, err := parser.parseTime()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = parser.expect(pctlTokenComma, "\",\""); err != nil {
+			return nil, err
+		}
+		upper, err := parser.parseTime()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := parser.expect(pctlTokenRightBracket, "\"]\"")
+		if err != nil {
+			return nil, err
+		}
+		if lower > upper {
+			return nil, fmt.Errorf("The time interval ending at position %v is empty: %v > %v.", closing.Position, lower, upper)
+		}
+		return &TimeInterval{Lower: lower, Upper: upper}, nil
+
+	case token.Kind == pctlTokenComparison && (token.Text == "<=" || token.Text == "≤"):
+		parser.next()
+		upper, err := parser.parseTime()
+		if err != nil {
+			return nil, err
+		}
+		return &TimeInterval{Lower: 0, Upper: upper}, nil
+
+	case token.Kind == pctlTokenComparison && (token.Text == ">=" || token.Text == "≥"):
+		parser.next()
+		lower, err := parser.parseTime()
+		if err != nil {
+			return nil, err
+		}
+		return &TimeInterval{Lower: lower, Upper: math.Inf(1)}, nil
+
+	case token.Kind == pctlTokenComparison:
+		return nil, fmt.Errorf("Time bounds are written [t1,t2], <=t or >=t, but found \"%v\" at position %v.", token.Text, token.Position)
+
+	default:
+		return nil, nil
+	}
+}
+
+/*
+parseTime
+Description:
+	Parses a nonnegative time, which may be infinite.
+*/
+func (parser *pctlParser) parseTime() (float64, error) {
+	token, err := parser.expect(pctlTokenName, "a time")
+	if err != nil {
+		return 0, err
+	}
+
+	time, err := strconv.ParseFloat(token.Text, 64)
+	if err != nil || time < 0 || math.IsNaN(time) {
+		return 0, fmt.Errorf("The time \"%v\" at position %v is not a nonnegative number.", token.Text, token.Position)
+	}
+	return time, nil
+}
@@ -0,0 +1,201 @@
+/*
+pctl_test.go
+Description:
+
+	Tests for the PCTL formulas and parser defined in pctl.go
+*/
+package markov
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+/*
+TestParsePCTLFormula1
+Description:
+
+	Parses formulas with every operator and prints them again.
+*/
+func TestParsePCTLFormula1(t *testing.T) {
+	testCases := map[string]string{
+		"P>=0.99 [ F delivered ]":          "P>=0.99 [F delivered]",
+		"P<0.1 [ !fail U<=10 delivered ]":  "P<0.1 [!fail U<=10 delivered]",
+		"P=? [ X (a & b) ]":                "P=? [X (a & b)]",
+		"S≤0.01 [ down ]":                  "S<=0.01 [down]",
+		"P>0.5 [ G<=3 ok ] -> S>=0.9 [ok]": "(P>0.5 [G<=3 ok] -> S>=0.9 [ok])",
+		"!P>=1 [ a U P>0 [X b] ] | \"P\"":  "(!P>=1 [a U P>0 [X b]] | \"P\")",
+		"P ∧ S":                            "(\"P\" & \"S\")",
+		"P>=0.5 [ F<=0 true ] && false":    "(P>=0.5 [F<=0 true] & false)",
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := ParsePCTLFormula(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		if formula.String() != expected {
+			t.Errorf("Expected \"%v\" to be parsed as \"%v\", but found \"%v\".", formulaString, expected, formula)
+		}
+
+		reparsed, err := ParsePCTLFormula(formula.String())
+		if err != nil || reparsed.String() != formula.String() {
+			t.Errorf("Expected \"%v\" to be parsed back into itself, but found \"%v\" (error: %v).", formula, reparsed, err)
+		}
+	}
+}
+
+/*
+TestParsePCTLFormula2
+Description:
+
+	Checks the structure of a parsed bounded until formula.
+*/
+func TestParsePCTLFormula2(t *testing.T) {
+	formula, err := ParsePCTLFormula("P>=0.25 [ a U<=7 b ]")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if formula.Operator != PCTLOpProbability || formula.Comparison != CompareGreaterOrEqual || formula.Bound != 0.25 {
+		t.Errorf("Expected P>=0.25, but found %v.", formula)
+	}
+
+	path := formula.Operands[0]
+	if path.Operator != PCTLOpUntil || path.StepBound != 7 || !path.IsPathFormula() {
+		t.Errorf("Expected a U<=7 b, but found %v.", path)
+	}
+
+	if formula.IsQuery() || formula.IsPathFormula() {
+		t.Errorf("Expected a state formula which is not a query.")
+	}
+
+	if PCTLEventually(PCTLAtom("a")).StepBound != -1 {
+		t.Errorf("Expected F a to be unbounded.")
+	}
+}
+
+/*
+TestParsePCTLFormula3
+Description:
+
+	Malformed formulas produce errors which mention the problem.
+*/
+func TestParsePCTLFormula3(t *testing.T) {
+	testCases := map[string]string{
+		"F a":                "The path operator \"F\" at position 1 must appear inside P[...].",
+		"P>=1.5 [ F a ]":     "The bound \"1.5\" at position 4 is not a probability in [0,1].",
+		"P>=0.5 [ a ]":       "Expected a path formula (X, U, F or G) at position 12",
+		"P>=0.5 [ a U<2 b ]": "Step bounds are written <=k",
+		"P>=0.5 [ F<=x b ]":  "The step bound \"x\" at position 13 is not a nonnegative integer.",
+		"P>=0.5 ( F a )":     "Expected \"[\" at position 8",
+		"S>=0.5 [ a":         "Expected \"]\" at position 11",
+		"a $ b":              "Unexpected character '$' at position 3.",
+		"a b":                "Unexpected \"b\" at position 3.",
+	}
+
+	for formulaString, expected := range testCases {
+		_, err := ParsePCTLFormula(formulaString)
+		if err == nil {
+			t.Errorf("Expected an error while parsing \"%v\".", formulaString)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error for \"%v\" to contain \"%v\", but found \"%v\".", formulaString, expected, err)
+		}
+	}
+}
+
+/*
+TestComparison_Holds1
+Description:
+
+	Checks each comparison at its bound.
+*/
+func TestComparison_Holds1(t *testing.T) {
+	expected := map[Comparison]bool{
+		CompareLess:           false,
+		CompareLessOrEqual:    true,
+		CompareGreater:        false,
+		CompareGreaterOrEqual: true,
+		CompareQuery:          true,
+	}
+
+	for comparison, holds := range expected {
+		if comparison.Holds(0.5, 0.5) != holds {
+			t.Errorf("Expected 0.5 %v 0.5 to be %v.", comparison, holds)
+		}
+	}
+}
+
+/*
+TestParseCSLFormula1
+Description:
+
+	Parses CSL formulas with the three forms of time bounds and prints them back.
+*/
+func TestParseCSLFormula1(t *testing.T) {
+	testCases := map[string]string{
+		"P>=0.9 [ up U[1,2.5] done ]": "P>=0.9 [up U[1,2.5] done]",
+		"P=? [ F<=10 down ]":          "P=? [F<=10 down]",
+		"P<0.1 [ G>=3 up ]":           "P<0.1 [G>=3 up]",
+		"P=? [ F[0,0.5] down ]":       "P=? [F<=0.5 down]",
+		"P=? [ X down ] & S>0.9 [up]": "(P=? [X down] & S>0.9 [up])",
+		"P=? [ up U down ]":           "P=? [up U down]",
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := ParseCSLFormula(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		if formula.String() != expected {
+			t.Errorf("Expected \"%v\" to be parsed as \"%v\", but found \"%v\".", formulaString, expected, formula)
+		}
+
+		reparsed, err := ParseCSLFormula(formula.String())
+		if err != nil || reparsed.String() != formula.String() {
+			t.Errorf("Expected \"%v\" to be parsed back into itself, but found \"%v\" (error: %v).", formula, reparsed, err)
+		}
+	}
+
+	formula, _ := ParseCSLFormula("P<0.1 [ G>=3 up ]")
+	interval := formula.Operands[0].TimeBound
+	if interval == nil || interval.Lower != 3 || !math.IsInf(interval.Upper, 1) || formula.Operands[0].StepBound != -1 {
+		t.Errorf("Expected the time interval [3,Inf), but found %v.", interval)
+	}
+}
+
+/*
+TestParseCSLFormula2
+Description:
+
+	Checks the errors of malformed time bounds, and that PCTL formulas do not accept time intervals.
+*/
+func TestParseCSLFormula2(t *testing.T) {
+	testCases := map[string]string{
+		"P>=0.5 [ F[2,1] a ]":   "The time interval ending at position 15 is empty: 2 > 1.",
+		"P>=0.5 [ F[1 2] a ]":   "Expected \",\" at position 14",
+		"P>=0.5 [ a U<2 b ]":    "Time bounds are written [t1,t2], <=t or >=t",
+		"P>=0.5 [ F<=x b ]":     "The time \"x\" at position 13 is not a nonnegative number.",
+		"P>=0.5 [ F[0,nan] b ]": "The time \"nan\" at position 14 is not a nonnegative number.",
+	}
+
+	for formulaString, expected := range testCases {
+		_, err := ParseCSLFormula(formulaString)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error for \"%v\" to contain \"%v\", but found \"%v\".", formulaString, expected, err)
+		}
+	}
+
+	_, err := ParsePCTLFormula("P>=0.5 [ F[0,1] a ]")
+	if err == nil || err.Error() != "The time interval at position 11 can only be used in CSL formulas." {
+		t.Errorf("Expected an error for the time interval, but found %v.", err)
+	}
+}
@@ -0,0 +1,463 @@
+/*
+pctlchecking.go
+Description:
+	Model checking of PCTL formulas on discrete-time Markov chains (Baier and Katoen, Section 10.2).
+	The states whose probability of satisfying an until formula is 0 or 1 are found from the graph of the chain,
+	and the remaining probabilities are computed with an iterative linear solver.
+	The steady-state probabilities are computed per bottom strongly connected component.
+*/
+
+package markov
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+stateFormulaModel
+Description:
+	A model on whose states formulas are checked. The boolean connectives and the comparisons of the P and S
+	operators are shared by the checkers of this package; the values of P and S are computed by the model.
+*/
+type stateFormulaModel interface {
+	labels() [][]mc.AtomicProposition
+	probabilityVector(pathFormula PCTLFormula) ([]float64, error)
+	steadyStateVector(formula PCTLFormula) ([]float64, error)
+}
+
+/*
+dtmcChecker
+Description:
+	Checks PCTL formulas on a DTMC whose transition matrix has been computed once.
+*/
+type dtmcChecker struct {
+	Chain   DTMC
+	Matrix  sparseMatrix
+	Options SolverOptions
+}
+
+/*
+Functions
+*/
+
+/*
+SatisfyingStates
+Description:
+	Returns the states of the chain which satisfy the state formula, in the order of S.
+Usage:
+	formula, _ := ParsePCTLFormula("P>=0.99 [ F delivered ]")
+	states, err := chain.SatisfyingStates(formula, DefaultSolverOptions())
+*/
+func (chain DTMC) SatisfyingStates(formula PCTLFormula, options SolverOptions) ([]DTMCState, error) {
+	sat, err := satisfactionVector(chain.checker(options), formula)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []DTMCState
+	for i, state := range chain.S {
+		if sat[i] {
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+/*
+Satisfies
+Description:
+	Determines if every state with a positive initial probability satisfies the state formula.
+*/
+func (chain DTMC) Satisfies(formula PCTLFormula, options SolverOptions) (bool, error) {
+	sat, err := chain.SatisfyingStates(formula, options)
+	if err != nil {
+		return false, err
+	}
+
+	for _, initialState := range chain.InitialStates() {
+		if !initialState.In(sat) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+/*
+Values
+Description:
+	Returns the probability computed by the outermost P or S operator of the formula in every state,
+	ignoring its bound. This is how queries such as P=? [ F done ] are answered.
+*/
+func (chain DTMC) Values(formula PCTLFormula, options SolverOptions) (map[DTMCState]float64, error) {
+	values, err := valueVector(chain.checker(options), formula)
+	if err != nil {
+		return nil, err
+	}
+
+	valueMap := make(map[DTMCState]float64)
+	for i, state := range chain.S {
+		valueMap[state] = values[i]
+	}
+	return valueMap, nil
+}
+
+/*
+checker
+Description:
+	Returns the PCTL checker of the chain.
+*/
+func (chain DTMC) checker(options SolverOptions) dtmcChecker {
+	return dtmcChecker{Chain: chain, Matrix: chain.matrix(), Options: options}
+}
+
+/*
+valueVector
+Description:
+	Returns, for each state of the model, the value of the outermost P or S operator of the formula.
+*/
+func valueVector(model stateFormulaModel, formula PCTLFormula) ([]float64, error) {
+	switch formula.Operator {
+	case PCTLOpProbability:
+		return model.probabilityVector(formula.Operands[0])
+	case PCTLOpSteadyState:
+		return model.steadyStateVector(formula.Operands[0])
+	default:
+		return nil, fmt.Errorf("Only P and S formulas have values, but received \"%v\".", formula)
+	}
+}
+
+/*
+satisfactionVector
+Description:
+	Returns, for each state of the model, whether the state satisfies the state formula.
+*/
+func satisfactionVector(model stateFormulaModel, formula PCTLFormula) ([]bool, error) {
+	labels := model.labels()
+	n := len(labels)
+	sat := make([]bool, n)
+
+	switch formula.Operator {
+	case PCTLOpTrue:
+		return allStates(n), nil
+
+	case PCTLOpFalse:
+		return sat, nil
+
+	case PCTLOpAtom:
+		for i := range sat {
+			sat[i] = formula.Atom.In(labels[i])
+		}
+		return sat, nil
+
+	case PCTLOpNot, PCTLOpAnd, PCTLOpOr, PCTLOpImplies:
+		operandSats, err := operandSatisfaction(model, formula.Operands)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range sat {
+			switch formula.Operator {
+			case PCTLOpNot:
+				sat[i] = !operandSats[0][i]
+			case PCTLOpAnd:
+				sat[i] = true
+				for _, operandSat := range operandSats {
+					sat[i] = sat[i] && operandSat[i]
+				}
+			case PCTLOpOr:
+				for _, operandSat := range operandSats {
+					sat[i] = sat[i] || operandSat[i]
+				}
+			case PCTLOpImplies:
+				sat[i] = !operandSats[0][i] || operandSats[1][i]
+			}
+		}
+		return sat, nil
+
+	case PCTLOpProbability, PCTLOpSteadyState:
+		if formula.Comparison == CompareQuery {
+			return nil, fmt.Errorf("The query \"%v\" has no truth value; use Values() to compute it.", formula)
+		}
+
+		values, err := valueVector(model, formula)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, value := range values {
+			sat[i] = formula.Comparison.Holds(value, formula.Bound)
+		}
+		return sat, nil
+
+	case PCTLOpNext, PCTLOpUntil, PCTLOpEventually, PCTLOpAlways:
+		return nil, fmt.Errorf("The path formula \"%v\" must appear inside P[...].", formula)
+
+	default:
+		return nil, fmt.Errorf("Unrecognized PCTL operator %v.", formula.Operator)
+	}
+}
+
+/*
+operandSatisfaction
+Description:
+	Returns the satisfaction vectors of the operands, in order.
+*/
+func operandSatisfaction(model stateFormulaModel, operands []PCTLFormula) ([][]bool, error) {
+	var operandSats [][]bool
+	for _, operand := range operands {
+		operandSat, err := satisfactionVector(model, operand)
+		if err != nil {
+			return nil, err
+		}
+		operandSats = append(operandSats, operandSat)
+	}
+	return operandSats, nil
+}
+
+/*
+labels
+Description:
+	Returns the labels of the states of the chain, in the order of S.
+*/
+func (checker dtmcChecker) labels() [][]mc.AtomicProposition {
+	labels := make([][]mc.AtomicProposition, len(checker.Chain.S))
+	for i, state := range checker.Chain.S {
+		labels[i] = checker.Chain.L[state]
+	}
+	return labels
+}
+
+/*
+probabilityVector
+Description:
+	Returns, for each index of S, the probability that a path from the state satisfies the path formula.
+*/
+func (checker dtmcChecker) probabilityVector(pathFormula PCTLFormula) ([]float64, error) {
+	if !pathFormula.IsPathFormula() {
+		return nil, fmt.Errorf("P[...] must contain a path formula (X, U, F or G), but received \"%v\".", pathFormula)
+	}
+
+	if pathFormula.TimeBound != nil {
+		return nil, fmt.Errorf("The time-bounded path formula \"%v\" can only be checked on a CTMC.", pathFormula)
+	}
+
+	operandSats, err := operandSatisfaction(checker, pathFormula.Operands)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix, options := checker.Matrix, checker.Options
+	n := len(checker.Chain.S)
+	switch pathFormula.Operator {
+	case PCTLOpNext:
+		return matrix.multiply(indicator(operandSats[0])), nil
+
+	case PCTLOpUntil:
+		return untilProbabilities(matrix, operandSats[0], operandSats[1], pathFormula.StepBound, options)
+
+	case PCTLOpEventually:
+		return untilProbabilities(matrix, allStates(n), operandSats[0], pathFormula.StepBound, options)
+
+	default:
+		// G phi = !F !phi
+		eventually, err := untilProbabilities(matrix, allStates(n), complement(operandSats[0]), pathFormula.StepBound, options)
+		if err != nil {
+			return nil, err
+		}
+		return oneMinus(eventually), nil
+	}
+}
+
+/*
+untilProbabilities
+Description:
+	Computes the probabilities of left U<=k right (or left U right if k is negative) for every state.
+	The states which cannot reach right through left states have probability 0; for the unbounded operator,
+	the states which cannot reach one of those through left states without right have probability 1.
+*/
+func untilProbabilities(matrix sparseMatrix, left []bool, right []bool, k int, options SolverOptions) ([]float64, error) {
+	n := len(matrix)
+
+	canReach := matrix.backwardReachable(right, left)
+
+	if k >= 0 {
+		values := indicator(right)
+		for step := 0; step < k; step++ {
+			product := matrix.multiply(values)
+			for i := range values {
+				switch {
+				case right[i]:
+					values[i] = 1
+				case canReach[i]:
+					values[i] = product[i]
+				default:
+					values[i] = 0
+				}
+			}
+		}
+		return values, nil
+	}
+
+	// Precomputation of the states with probability 0 and 1
+	probabilityZero := make([]bool, n)
+	leftNotRight := make([]bool, n)
+	for i := range probabilityZero {
+		probabilityZero[i] = !canReach[i]
+		leftNotRight[i] = left[i] && !right[i]
+	}
+	canFail := matrix.backwardReachable(probabilityZero, leftNotRight)
+
+	values := make([]float64, n)
+	unknown := make([]bool, n)
+	for i := range values {
+		switch {
+		case !canFail[i]:
+			values[i] = 1
+		case !probabilityZero[i]:
+			unknown[i] = true
+		}
+	}
+
+	return options.solve(matrix, make([]float64, n), values, unknown)
+}
+
+/*
+steadyStateVector
+Description:
+	Returns, for each index of S, the long-run probability of being in a state which satisfies the formula
+	when starting from that state.
+*/
+func (checker dtmcChecker) steadyStateVector(formula PCTLFormula) ([]float64, error) {
+	sat, err := satisfactionVector(checker, formula)
+	if err != nil {
+		return nil, err
+	}
+	return steadyStateValues(checker.Matrix, sat, checker.Options)
+}
+
+/*
+steadyStateValues
+Description:
+	Returns, for each state, the long-run probability of being in the set sat when starting from that state.
+	Inside each bottom strongly connected component this is the stationary probability of the set; the other
+	states take the average over the components they reach.
+*/
+func steadyStateValues(matrix sparseMatrix, sat []bool, options SolverOptions) ([]float64, error) {
+	n := len(matrix)
+	values := make([]float64, n)
+	transient := allStates(n)
+	for _, component := range matrix.bottomComponents() {
+		distribution, err := stationaryDistribution(matrix, component, options)
+		if err != nil {
+			return nil, err
+		}
+
+		componentValue := 0.0
+		for index, i := range component {
+			if sat[i] {
+				componentValue += distribution[index]
+			}
+		}
+
+		for _, i := range component {
+			values[i] = componentValue
+			transient[i] = false
+		}
+	}
+
+	return options.solve(matrix, make([]float64, n), values, transient)
+}
+
+/*
+stationaryDistribution
+Description:
+	Computes the stationary distribution of the chain restricted to a bottom strongly connected component,
+	in the order of component. The power method is applied to the lazy chain (I + P) / 2, which has the same
+	stationary distribution but is aperiodic, so the iteration converges.
+*/
+func stationaryDistribution(matrix sparseMatrix, component []int, options SolverOptions) ([]float64, error) {
+	if err := options.Check(); err != nil {
+		return nil, err
+	}
+
+	positionOf := make(map[int]int)
+	for position, i := range component {
+		positionOf[i] = position
+	}
+
+	distribution := make([]float64, len(component))
+	for position := range distribution {
+		distribution[position] = 1 / float64(len(component))
+	}
+
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		next := make([]float64, len(component))
+		for position, i := range component {
+			next[position] += distribution[position] / 2
+			for _, entry := range matrix[i] {
+				next[positionOf[entry.Column]] += distribution[position] * entry.Value / 2
+			}
+		}
+
+		maxChange := 0.0
+		for position := range next {
+			maxChange = math.Max(maxChange, math.Abs(next[position]-distribution[position]))
+		}
+		distribution = next
+
+		if maxChange <= options.Tolerance {
+			return distribution, nil
+		}
+	}
+
+	return nil, errors.New("The stationary distribution of a bottom strongly connected component did not converge; increase MaxIterations or the tolerance.")
+}
+
+/*
+indicator
+Description:
+	Converts a set of states into a vector of zeros and ones.
+*/
+func indicator(set []bool) []float64 {
+	values := make([]float64, len(set))
+	for i, isMember := range set {
+		if isMember {
+			values[i] = 1
+		}
+	}
+	return values
+}
+
+/*
+complement
+Description:
+	Returns the states which are not in the set.
+*/
+func complement(set []bool) []bool {
+	result := make([]bool, len(set))
+	for i, isMember := range set {
+		result[i] = !isMember
+	}
+	return result
+}
+
+/*
+oneMinus
+Description:
+	Returns the vector whose components are one minus those of values.
+*/
+func oneMinus(values []float64) []float64 {
+	result := make([]float64, len(values))
+	for i, value := range values {
+		result[i] = 1 - value
+	}
+	return result
+}
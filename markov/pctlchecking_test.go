@@ -0,0 +1,232 @@
+/*
+pctlchecking_test.go
+Description:
+
+	Tests for the PCTL model checking functions defined in pctlchecking.go
+*/
+package markov
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+/*
+valuesOf
+Description:
+
+	Parses the formula and computes its values on the chain, failing the test on errors.
+*/
+func valuesOf(t *testing.T, chain DTMC, formulaString string, options SolverOptions) map[string]float64 {
+	formula, err := ParsePCTLFormula(formulaString)
+	if err != nil {
+		t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+		return nil
+	}
+
+	values, err := chain.Values(formula, options)
+	if err != nil {
+		t.Errorf("Unexpected error while computing \"%v\": %v", formulaString, err)
+		return nil
+	}
+
+	valuesByName := make(map[string]float64)
+	for state, value := range values {
+		valuesByName[state.Name] = value
+	}
+	return valuesByName
+}
+
+/*
+TestDTMC_Values1
+Description:
+
+	Each face of the Knuth-Yao die has probability 1/6, with both solvers.
+*/
+func TestDTMC_Values1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	for _, method := range []LinearSolver{Jacobi, GaussSeidel} {
+		options := DefaultSolverOptions()
+		options.Method = method
+
+		for _, face := range []string{"one", "two", "three", "four", "five", "six"} {
+			values := valuesOf(t, chain, "P=? [ F "+face+" ]", options)
+			if math.Abs(values["s0"]-1.0/6) > 1e-8 {
+				t.Errorf("Expected the probability of %v to be 1/6 with %v, but found %v.", face, method, values["s0"])
+			}
+		}
+
+		values := valuesOf(t, chain, "P=? [ !s2 U one ]", options)
+		if math.Abs(values["s1"]-1.0/3) > 1e-8 {
+			t.Errorf("Expected the probability of one from s1 to be 1/3 with %v, but found %v.", method, values["s1"])
+		}
+	}
+}
+
+/*
+TestDTMC_Values2
+Description:
+
+	Bounded until, next and bounded always on the Knuth-Yao die.
+*/
+func TestDTMC_Values2(t *testing.T) {
+	chain := GetKnuthYaoDie()
+	options := DefaultSolverOptions()
+
+	testCases := []struct {
+		Formula  string
+		State    string
+		Expected float64
+	}{
+		{"P=? [ F<=3 done ]", "s0", 0.75},
+		{"P=? [ F<=5 done ]", "s0", 0.75 + 0.25*0.75},
+		{"P=? [ F<=0 done ]", "d3", 1},
+		{"P=? [ true U<=2 done ]", "s0", 0},
+		{"P=? [ X done ]", "s3", 0.5},
+		{"P=? [ G<=2 !done ]", "s1", 0.25},
+		{"P=? [ G !done ]", "s0", 0},
+		{"P=? [ X P>=0.5 [ X done ] ]", "s1", 1},
+		{"P=? [ X P>=0.5 [ X done ] ]", "s0", 0},
+	}
+
+	for _, testCase := range testCases {
+		values := valuesOf(t, chain, testCase.Formula, options)
+		if math.Abs(values[testCase.State]-testCase.Expected) > 1e-9 {
+			t.Errorf("Expected %v in %v to be %v, but found %v.", testCase.Formula, testCase.State, testCase.Expected, values[testCase.State])
+		}
+	}
+}
+
+/*
+TestDTMC_Values3
+Description:
+
+	Steady-state probabilities of a periodic chain, of a chain with two bottom components and of the die.
+*/
+func TestDTMC_Values3(t *testing.T) {
+	options := DefaultSolverOptions()
+
+	periodic, err := GetDTMC(
+		[]string{"a", "b", "c"},
+		map[string]map[string]float64{"a": {"b": 1}, "b": {"c": 1}, "c": {"a": 1}},
+		map[string]float64{"a": 1},
+		[]string{"up"},
+		map[string][]string{"a": {"up"}, "b": {"up"}},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if values := valuesOf(t, periodic, "S=? [ up ]", options); math.Abs(values["a"]-2.0/3) > 1e-8 {
+		t.Errorf("Expected the long-run probability of up to be 2/3, but found %v.", values["a"])
+	}
+
+	split, _ := GetDTMC(
+		[]string{"start", "left", "right1", "right2"},
+		map[string]map[string]float64{
+			"start":  {"left": 0.25, "right1": 0.75},
+			"left":   {"left": 1},
+			"right1": {"right1": 0.5, "right2": 0.5},
+			"right2": {"right1": 0.2, "right2": 0.8},
+		},
+		map[string]float64{"start": 1},
+		[]string{"goal"},
+		map[string][]string{"left": {"goal"}, "right2": {"goal"}},
+	)
+
+	// In the right component, the stationary distribution is (2/7, 5/7)
+	values := valuesOf(t, split, "S=? [ goal ]", options)
+	if expected := 0.25 + 0.75*5.0/7; math.Abs(values["start"]-expected) > 1e-8 {
+		t.Errorf("Expected the long-run probability of goal to be %v, but found %v.", expected, values["start"])
+	}
+
+	if values := valuesOf(t, GetKnuthYaoDie(), "S=? [ six ]", options); math.Abs(values["s0"]-1.0/6) > 1e-8 {
+		t.Errorf("Expected the long-run probability of six to be 1/6, but found %v.", values["s0"])
+	}
+}
+
+/*
+TestDTMC_SatisfyingStates1
+Description:
+
+	Finds the states of the die from which the result is at least 1/2 likely to be odd within 2 steps.
+*/
+func TestDTMC_SatisfyingStates1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	formula, _ := ParsePCTLFormula("P>=0.5 [ F<=2 (one | three | five) ]")
+	states, err := chain.SatisfyingStates(formula, DefaultSolverOptions())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, state := range states {
+		names = append(names, state.Name)
+	}
+
+	if strings.Join(names, " ") != "s1 s3 s4 s5 d1 d3 d5" {
+		t.Errorf("Expected the states s1 s3 s4 s5 d1 d3 d5, but found %v.", names)
+	}
+}
+
+/*
+TestDTMC_Satisfies1
+Description:
+
+	The die terminates almost surely, but not within 3 steps.
+*/
+func TestDTMC_Satisfies1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	testCases := map[string]bool{
+		"P>=1 [ F done ]":                true,
+		"P>=1 [ F<=3 done ]":             false,
+		"P<0.2 [ F six ] & S>0.16 [six]": true,
+	}
+
+	for formulaString, expected := range testCases {
+		formula, _ := ParsePCTLFormula(formulaString)
+		satisfied, err := chain.Satisfies(formula, DefaultSolverOptions())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if satisfied != expected {
+			t.Errorf("Expected \"%v\" to be %v, but found %v.", formulaString, expected, satisfied)
+		}
+	}
+}
+
+/*
+TestDTMC_Satisfies2
+Description:
+
+	Queries, path formulas outside of P and solvers which do not converge produce errors.
+*/
+func TestDTMC_Satisfies2(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	_, err := chain.Satisfies(PCTLProbability(CompareQuery, 0, PCTLEventually(PCTLAtom("done"))), DefaultSolverOptions())
+	if err == nil || !strings.Contains(err.Error(), "has no truth value") {
+		t.Errorf("Expected an error for the query, but found %v.", err)
+	}
+
+	_, err = chain.Satisfies(PCTLNext(PCTLAtom("done")), DefaultSolverOptions())
+	if err == nil || err.Error() != "The path formula \"X done\" must appear inside P[...]." {
+		t.Errorf("Expected an error for the path formula, but found %v.", err)
+	}
+
+	_, err = chain.Values(PCTLAtom("done"), DefaultSolverOptions())
+	if err == nil {
+		t.Errorf("Expected an error for the values of an atom.")
+	}
+
+	options := SolverOptions{Method: Jacobi, Tolerance: 1e-12, MaxIterations: 2}
+	_, err = chain.Values(PCTLProbability(CompareQuery, 0, PCTLEventually(PCTLAtom("one"))), options)
+	if err == nil || !strings.Contains(err.Error(), "did not converge") {
+		t.Errorf("Expected an error for the solver which does not converge, but found %v.", err)
+	}
+}
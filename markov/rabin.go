@@ -0,0 +1,484 @@
+/*
+rabin.go
+Description:
+	Maximal probabilities of Rabin objectives, and so of LTL formulas, on Markov decision processes
+	(Baier and Katoen, Section 10.6.4). The MDP is combined with a Deterministic Rabin Automaton into a product MDP.
+	An end component of the product which avoids E and contains a state of F for some pair (E,F) of the automaton
+	can be used to satisfy the Rabin condition with probability 1, so the maximal probability of the condition
+	is the maximal probability of reaching one of these accepting end components.
+*/
+
+package markov
+
+import (
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/adaptive"
+)
+
+/*
+Constants
+*/
+
+const (
+	// The automaton state of the product which is reached when the automaton has no transition. It is rejecting.
+	RejectingSinkName = "(sink)"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+RabinProduct
+Description:
+	The product of an MDP with a Deterministic Rabin Automaton. The product state (s,q) is reached when the MDP
+	enters s and the automaton moves to q after reading the label of s. State and Memory give the two components
+	of each product state, Start gives the product state in which a run from each state of the MDP begins and
+	Pairs lifts the pairs (E,F) of the automaton's Omega to the product.
+*/
+type RabinProduct struct {
+	MDP    MDP
+	State  map[MDPState]MDPState
+	Memory map[MDPState]string
+	Start  map[MDPState]MDPState
+	Pairs  [][2][]MDPState
+}
+
+/*
+FiniteMemoryScheduler
+Description:
+	Chooses the action of an MDP from the current state and a memory state. The memory starts in
+	InitialMemory[s] when the first state is s and moves to NextMemory[m][t] when the MDP enters t.
+	Actions[s][m] is the action chosen in the state s with the memory m.
+*/
+type FiniteMemoryScheduler struct {
+	InitialMemory map[MDPState]string
+	NextMemory    map[string]map[MDPState]string
+	Actions       map[MDPState]map[string]string
+}
+
+/*
+RabinResult
+Description:
+	The maximal probability of satisfying a Rabin condition from each state of an MDP, the maximal probability
+	from the initial distribution and a finite-memory scheduler which attains them. The memory of the scheduler
+	is the state of the automaton.
+*/
+type RabinResult struct {
+	Values      map[MDPState]float64
+	Probability float64
+	Scheduler   FiniteMemoryScheduler
+	Product     RabinProduct
+}
+
+/*
+Functions
+*/
+
+/*
+GetRabinProduct
+Description:
+	Builds the product of the MDP with the automaton. Only the product states which can be reached from a pair
+	(s, q) with q the successor of the initial automaton state under the label of s are created.
+	Each state of the MDP must be labelled with exactly one atomic proposition of the automaton's alphabet.
+	If the automaton has no transition for a label, the product moves to the automaton state RejectingSinkName,
+	which belongs to no pair and is never left. The product states are named (s,q); an error is returned
+	if two of them would receive the same name.
+Usage:
+	product, err := GetRabinProduct(mdp, dra)
+*/
+func GetRabinProduct(mdp MDP, dra adaptive.DeterministicRabinAutomaton) (RabinProduct, error) {
+	automaton := dra.ToAutomaton()
+	if len(automaton.StatesNamed(RejectingSinkName)) > 0 {
+		return RabinProduct{}, fmt.Errorf("The automaton has a state named \"%v\", which is reserved for the rejecting sink of the product.", RejectingSinkName)
+	}
+
+	letters := make(map[MDPState]mc.AtomicProposition)
+	for _, state := range mdp.S {
+		var stateLetters []mc.AtomicProposition
+		for _, ap := range mdp.L[state] {
+			if ap.In(automaton.Alphabet) {
+				stateLetters = append(stateLetters, ap)
+			}
+		}
+
+		if len(stateLetters) != 1 {
+			return RabinProduct{}, fmt.Errorf("The state \"%v\" must be labelled with exactly one symbol of the automaton's alphabet, but it has the labels %v.", state, stateLetters)
+		}
+		letters[state] = stateLetters[0]
+	}
+
+	// step returns the automaton state after entering state from the automaton state q
+	step := func(q string, state MDPState) string {
+		if q == RejectingSinkName {
+			return q
+		}
+		successors := automaton.Post(automaton.StatesNamed(q)[0], letters[state])
+		if len(successors) == 0 {
+			return RejectingSinkName
+		}
+		return successors[0].Name
+	}
+	nameOf := func(state MDPState, q string) string {
+		return fmt.Sprintf("(%v,%v)", state.Name, q)
+	}
+
+	type productState struct {
+		State MDPState
+		Q     string
+	}
+
+	// Explore the product from every state of the MDP
+	var productNames []string
+	names := make(map[productState]string)
+	productStates := make(map[string]productState)
+	var queue []productState
+	var nameErr error
+	visit := func(ps productState) string {
+		if name, seen := names[ps]; seen {
+			return name
+		}
+
+		name := nameOf(ps.State, ps.Q)
+		if other, taken := productStates[name]; taken && nameErr == nil {
+			nameErr = fmt.Errorf("The product states of (%v, %v) and (%v, %v) would both be named \"%v\".", other.State, other.Q, ps.State, ps.Q, name)
+		}
+		names[ps] = name
+		productStates[name] = ps
+		productNames = append(productNames, name)
+		queue = append(queue, ps)
+		return name
+	}
+
+	q0 := automaton.Q0[0].Name
+	startNames := make(map[MDPState]string)
+	initialDistribution := make(map[string]float64)
+	for _, state := range mdp.S {
+		name := visit(productState{State: state, Q: step(q0, state)})
+		startNames[state] = name
+		if mdp.I[state] > 0 {
+			initialDistribution[name] += mdp.I[state]
+		}
+	}
+
+	transitionMap := make(map[string]map[string]map[string]float64)
+	for len(queue) > 0 {
+		ps := queue[0]
+		queue = queue[1:]
+
+		actionMap := make(map[string]map[string]float64)
+		for action, row := range mdp.P[ps.State] {
+			actionMap[action] = make(map[string]float64)
+			for target, probability := range row {
+				if probability > 0 {
+					actionMap[action][visit(productState{State: target, Q: step(ps.Q, target)})] += probability
+				}
+			}
+		}
+		transitionMap[names[ps]] = actionMap
+	}
+
+	if nameErr != nil {
+		return RabinProduct{}, nameErr
+	}
+
+	var apNames []string
+	for _, ap := range mdp.AP {
+		apNames = append(apNames, ap.Name)
+	}
+	labelMap := make(map[string][]string)
+	for name, ps := range productStates {
+		for _, ap := range mdp.L[ps.State] {
+			labelMap[name] = append(labelMap[name], ap.Name)
+		}
+	}
+
+	productMDP, err := GetMDP(productNames, mdp.Act, transitionMap, initialDistribution, apNames, labelMap)
+	if err != nil {
+		return RabinProduct{}, fmt.Errorf("There was an issue building the product MDP: %v", err)
+	}
+
+	product := RabinProduct{
+		MDP:    productMDP,
+		State:  make(map[MDPState]MDPState),
+		Memory: make(map[MDPState]string),
+		Start:  make(map[MDPState]MDPState),
+	}
+	for _, state := range productMDP.S {
+		product.State[state] = productStates[state.Name].State
+		product.Memory[state] = productStates[state.Name].Q
+	}
+	for state, name := range startNames {
+		product.Start[state] = productMDP.StatesNamed(name)[0]
+	}
+
+	for _, pair := range dra.Omega {
+		var productPair [2][]MDPState
+		for side := range productPair {
+			for _, state := range productMDP.S {
+				if (adaptive.DRAState{Name: product.Memory[state]}).In(pair[side]) {
+					productPair[side] = append(productPair[side], state)
+				}
+			}
+		}
+		product.Pairs = append(product.Pairs, productPair)
+	}
+
+	return product, nil
+}
+
+/*
+AcceptingEndComponents
+Description:
+	Returns, for each pair (E,F) in order, the maximal end components of the product without the states of E
+	which contain a state of F. A run which stays in one of them and visits all of its states infinitely often
+	satisfies the Rabin condition.
+*/
+func (product RabinProduct) AcceptingEndComponents() [][]EndComponent {
+	matrix := product.MDP.matrix(nil)
+
+	var accepting [][]EndComponent
+	for _, pairComponents := range product.acceptingEndComponents(matrix) {
+		accepting = append(accepting, nil)
+		for _, ec := range pairComponents {
+			accepting[len(accepting)-1] = append(accepting[len(accepting)-1], product.MDP.endComponent(ec, matrix))
+		}
+	}
+	return accepting
+}
+
+/*
+acceptingEndComponents
+Description:
+	Returns the accepting end components of each pair over the indices of the product's states.
+*/
+func (product RabinProduct) acceptingEndComponents(matrix mdpMatrix) [][]endComponent {
+	var accepting [][]endComponent
+	for _, pair := range product.Pairs {
+		avoidsE := allStates(len(product.MDP.S))
+		for i, state := range product.MDP.S {
+			avoidsE[i] = !state.In(pair[0])
+		}
+
+		var pairComponents []endComponent
+		for _, component := range matrix.maximalEndComponents(avoidsE, nil) {
+			for _, i := range component.States {
+				if product.MDP.S[i].In(pair[1]) {
+					pairComponents = append(pairComponents, component)
+					break
+				}
+			}
+		}
+		accepting = append(accepting, pairComponents)
+	}
+	return accepting
+}
+
+/*
+MaxRabinProbability
+Description:
+	Computes the maximal probability that the word of labels of a run of the MDP is accepted by the automaton,
+	from each state and from the initial distribution, and a finite-memory scheduler which attains it.
+	Outside of the accepting end components, the scheduler maximizes the probability of reaching them; inside one,
+	it only uses the actions of the component and moves towards the states of F, so that they are visited
+	infinitely often.
+Usage:
+	result, err := mdp.MaxRabinProbability(dra, DefaultMDPSolverOptions())
+	action := result.Scheduler.Actions[state][memory]
+*/
+func (mdp MDP) MaxRabinProbability(dra adaptive.DeterministicRabinAutomaton, options MDPSolverOptions) (RabinResult, error) {
+	if err := options.Check(); err != nil {
+		return RabinResult{}, err
+	}
+
+	product, err := GetRabinProduct(mdp, dra)
+	if err != nil {
+		return RabinResult{}, err
+	}
+
+	n := len(product.MDP.S)
+	matrix := product.MDP.matrix(nil)
+
+	// Each state of an accepting end component follows the first component which contains it
+	accepting := make([]bool, n)
+	choices := make([]int, n)
+	for pairIndex, pairComponents := range product.acceptingEndComponents(matrix) {
+		for _, component := range pairComponents {
+			// Move towards F, or towards the states which already follow an earlier component
+			inComponent := make([]bool, n)
+			goal := make([]bool, n)
+			for _, i := range component.States {
+				inComponent[i] = !accepting[i]
+				goal[i] = accepting[i] || product.MDP.S[i].In(product.Pairs[pairIndex][1])
+			}
+
+			isComponentChoice := func(i int, c int) bool {
+				for _, componentChoice := range component.Choices[i] {
+					if componentChoice == c {
+						return true
+					}
+				}
+				return false
+			}
+			attractor := matrix.attractor(goal, inComponent, isComponentChoice)
+			for _, i := range component.States {
+				if !inComponent[i] {
+					continue
+				}
+				accepting[i] = true
+				choices[i] = component.Choices[i][0]
+				if attractor[i] >= 0 {
+					choices[i] = attractor[i]
+				}
+			}
+		}
+	}
+
+	reachability, err := product.MDP.solve(reachabilityProblem(matrix, accepting, true), options)
+	if err != nil {
+		return RabinResult{}, err
+	}
+
+	// Convert the memoryless scheduler of the product into a finite-memory scheduler of the MDP
+	result := RabinResult{
+		Values: make(map[MDPState]float64),
+		Scheduler: FiniteMemoryScheduler{
+			InitialMemory: make(map[MDPState]string),
+			NextMemory:    make(map[string]map[MDPState]string),
+			Actions:       make(map[MDPState]map[string]string),
+		},
+		Product: product,
+	}
+
+	for i, productState := range product.MDP.S {
+		state, memory := product.State[productState], product.Memory[productState]
+
+		action := reachability.Scheduler[productState]
+		if accepting[i] {
+			action = matrix[i][choices[i]].Action
+		}
+		if result.Scheduler.Actions[state] == nil {
+			result.Scheduler.Actions[state] = make(map[string]string)
+		}
+		result.Scheduler.Actions[state][memory] = action
+
+		for _, successor := range matrix[i] {
+			for _, entry := range successor.Entries {
+				target := product.MDP.S[entry.Column]
+				if result.Scheduler.NextMemory[memory] == nil {
+					result.Scheduler.NextMemory[memory] = make(map[MDPState]string)
+				}
+				result.Scheduler.NextMemory[memory][product.State[target]] = product.Memory[target]
+			}
+		}
+	}
+
+	for _, state := range mdp.S {
+		start := product.Start[state]
+		result.Scheduler.InitialMemory[state] = product.Memory[start]
+		result.Values[state] = reachability.Values[start]
+		result.Probability += mdp.I[state] * reachability.Values[start]
+	}
+
+	return result, nil
+}
+
+/*
+Functions for FiniteMemoryScheduler
+*/
+
+/*
+InducedDTMC
+Description:
+	Returns the DTMC which is obtained by following the scheduler on the MDP. Its states are the pairs (s,m)
+	of a state and a memory state which can be reached from the initial distribution, and they keep the labels of s.
+	An error is returned if two of these pairs would receive the same name.
+Usage:
+	result, _ := mdp.MaxRabinProbability(dra, DefaultMDPSolverOptions())
+	chain, err := result.Scheduler.InducedDTMC(mdp)
+*/
+func (scheduler FiniteMemoryScheduler) InducedDTMC(mdp MDP) (DTMC, error) {
+	type chainState struct {
+		State  MDPState
+		Memory string
+	}
+	nameOf := func(cs chainState) string {
+		return fmt.Sprintf("(%v,%v)", cs.State.Name, cs.Memory)
+	}
+
+	var stateNames []string
+	names := make(map[chainState]string)
+	states := make(map[string]chainState)
+	var queue []chainState
+	var nameErr error
+	visit := func(cs chainState) string {
+		if name, seen := names[cs]; seen {
+			return name
+		}
+
+		name := nameOf(cs)
+		if other, taken := states[name]; taken && nameErr == nil {
+			nameErr = fmt.Errorf("The states of (%v, %v) and (%v, %v) would both be named \"%v\".", other.State, other.Memory, cs.State, cs.Memory, name)
+		}
+		names[cs] = name
+		states[name] = cs
+		stateNames = append(stateNames, name)
+		queue = append(queue, cs)
+		return name
+	}
+
+	initialDistribution := make(map[string]float64)
+	for _, state := range mdp.InitialStates() {
+		memory, hasMemory := scheduler.InitialMemory[state]
+		if !hasMemory {
+			return DTMC{}, fmt.Errorf("The scheduler has no initial memory for the state \"%v\".", state)
+		}
+		initialDistribution[visit(chainState{State: state, Memory: memory})] += mdp.I[state]
+	}
+
+	transitionMap := make(map[string]map[string]float64)
+	for len(queue) > 0 {
+		cs := queue[0]
+		queue = queue[1:]
+
+		action, isScheduled := scheduler.Actions[cs.State][cs.Memory]
+		if !isScheduled {
+			return DTMC{}, fmt.Errorf("The scheduler does not choose an action in the state \"%v\" with the memory \"%v\".", cs.State, cs.Memory)
+		}
+		row, isEnabled := mdp.P[cs.State][action]
+		if !isEnabled {
+			return DTMC{}, fmt.Errorf("The scheduler chooses the action \"%v\" in the state \"%v\", where it is not enabled.", action, cs.State)
+		}
+
+		transitionMap[names[cs]] = make(map[string]float64)
+		for target, probability := range row {
+			if probability == 0 {
+				continue
+			}
+			nextMemory, hasMemory := scheduler.NextMemory[cs.Memory][target]
+			if !hasMemory {
+				return DTMC{}, fmt.Errorf("The scheduler has no memory update from \"%v\" when entering \"%v\".", cs.Memory, target)
+			}
+			transitionMap[names[cs]][visit(chainState{State: target, Memory: nextMemory})] += probability
+		}
+	}
+
+	if nameErr != nil {
+		return DTMC{}, nameErr
+	}
+
+	var apNames []string
+	for _, ap := range mdp.AP {
+		apNames = append(apNames, ap.Name)
+	}
+	labelMap := make(map[string][]string)
+	for name, cs := range states {
+		for _, ap := range mdp.L[cs.State] {
+			labelMap[name] = append(labelMap[name], ap.Name)
+		}
+	}
+
+	return GetDTMC(stateNames, transitionMap, initialDistribution, apNames, labelMap)
+}
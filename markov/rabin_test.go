@@ -0,0 +1,308 @@
+/*
+rabin_test.go
+Description:
+
+	Tests for the products of MDPs with Deterministic Rabin Automata defined in rabin.go
+*/
+package markov
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/kwesiRutledge/ModelChecking/adaptive"
+)
+
+/*
+GetRedBlueMDP
+Description:
+
+	In r0, safe moves to a red or a blue state forever, while risky commits to red forever with probability 0.6
+	and to blue forever otherwise.
+*/
+func GetRedBlueMDP() MDP {
+	mdp, _ := GetMDP(
+		[]string{"r0", "b0", "r1", "b1"},
+		[]string{"safe", "risky", "go", "stay"},
+		map[string]map[string]map[string]float64{
+			"r0": {"safe": {"r0": 0.5, "b0": 0.5}, "risky": {"r1": 0.6, "b1": 0.4}},
+			"b0": {"go": {"r0": 1}},
+			"r1": {"stay": {"r1": 1}},
+			"b1": {"stay": {"b1": 1}},
+		},
+		map[string]float64{"r0": 1},
+		[]string{"red", "blue"},
+		map[string][]string{"r0": {"red"}, "b0": {"blue"}, "r1": {"red"}, "b1": {"blue"}},
+	)
+	return mdp
+}
+
+/*
+GetEventuallyAlwaysRedDRA
+Description:
+
+	Creates a DRA over {red,blue} which accepts the words with finitely many blue's.
+*/
+func GetEventuallyAlwaysRedDRA() adaptive.DeterministicRabinAutomaton {
+	dra, _ := adaptive.GetDRA(
+		[]string{"sawRed", "sawBlue"}, "sawRed", []string{"red", "blue"},
+		map[string]map[string]string{
+			"sawRed":  {"red": "sawRed", "blue": "sawBlue"},
+			"sawBlue": {"red": "sawRed", "blue": "sawBlue"},
+		},
+		[][2][]string{{{"sawBlue"}, {"sawRed"}}},
+	)
+	return dra
+}
+
+/*
+TestGetRabinProduct1
+Description:
+
+	The product of the red and blue MDP with the automaton for F G red, and its accepting end component.
+*/
+func TestGetRabinProduct1(t *testing.T) {
+	mdp := GetRedBlueMDP()
+
+	product, err := GetRabinProduct(mdp, GetEventuallyAlwaysRedDRA())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, state := range product.MDP.S {
+		names = append(names, state.Name)
+	}
+	if strings.Join(names, " ") != "(r0,sawRed) (b0,sawBlue) (r1,sawRed) (b1,sawBlue)" {
+		t.Errorf("Unexpected product states %v.", names)
+	}
+
+	start := product.Start[mdp.S[0]]
+	if start.Name != "(r0,sawRed)" || product.MDP.I[start] != 1 || !product.State[start].Equals(mdp.S[0]) || product.Memory[start] != "sawRed" {
+		t.Errorf("Expected the product to start in (r0,sawRed), but found %v.", start)
+	}
+
+	if len(product.Pairs) != 1 || len(product.Pairs[0][0]) != 2 || len(product.Pairs[0][1]) != 2 {
+		t.Errorf("Expected the pair to contain the two states of each automaton state, but found %v.", product.Pairs)
+	}
+
+	accepting := product.AcceptingEndComponents()
+	if len(accepting) != 1 || len(accepting[0]) != 1 || len(accepting[0][0].States) != 1 || accepting[0][0].States[0].Name != "(r1,sawRed)" {
+		t.Errorf("Expected the accepting end component {(r1,sawRed)}, but found %v.", accepting)
+	}
+}
+
+/*
+TestGetRabinProduct2
+Description:
+
+	Every state must be labelled with exactly one symbol of the alphabet.
+*/
+func TestGetRabinProduct2(t *testing.T) {
+	mdp, _ := GetMDP(
+		[]string{"x"}, []string{"stay"},
+		map[string]map[string]map[string]float64{"x": {"stay": {"x": 1}}},
+		map[string]float64{"x": 1},
+		[]string{"red", "blue"},
+		map[string][]string{"x": {"red", "blue"}},
+	)
+
+	_, err := GetRabinProduct(mdp, GetEventuallyAlwaysRedDRA())
+	if err == nil || err.Error() != "The state \"x\" must be labelled with exactly one symbol of the automaton's alphabet, but it has the labels [red blue]." {
+		t.Errorf("Expected an error for the labels, but found %v.", err)
+	}
+}
+
+/*
+TestGetRabinProduct3
+Description:
+
+	Product states whose names would collide are rejected, and so is an automaton with a state named like the sink.
+*/
+func TestGetRabinProduct3(t *testing.T) {
+	mdp, _ := GetMDP(
+		[]string{"x", "x,a"}, []string{"go"},
+		map[string]map[string]map[string]float64{"x": {"go": {"x,a": 1}}, "x,a": {"go": {"x": 1}}},
+		map[string]float64{"x": 1},
+		[]string{"red"},
+		map[string][]string{"x": {"red"}, "x,a": {"red"}},
+	)
+	dra, _ := adaptive.GetDRA(
+		[]string{"a", "a,a"}, "a", []string{"red"},
+		map[string]map[string]string{"a": {"red": "a,a"}, "a,a": {"red": "a"}},
+		[][2][]string{{{}, {"a"}}},
+	)
+
+	_, err := GetRabinProduct(mdp, dra)
+	if err == nil || !strings.Contains(err.Error(), "would both be named \"(x,a,a)\"") {
+		t.Errorf("Expected an error for the colliding names, but found %v.", err)
+	}
+
+	dra, _ = adaptive.GetDRA(
+		[]string{"a", RejectingSinkName}, "a", []string{"red"},
+		map[string]map[string]string{"a": {"red": RejectingSinkName}},
+		[][2][]string{{{}, {RejectingSinkName}}},
+	)
+
+	_, err = GetRabinProduct(mdp, dra)
+	if err == nil || !strings.Contains(err.Error(), "reserved for the rejecting sink") {
+		t.Errorf("Expected an error for the state named %v, but found %v.", RejectingSinkName, err)
+	}
+}
+
+/*
+TestMDP_MaxRabinProbability1
+Description:
+
+	F G red is satisfied with probability 0.6 by choosing risky in r0, while safe visits blue infinitely often.
+	An automaton for G red without transitions for blue gives the same probability through the rejecting sink.
+*/
+func TestMDP_MaxRabinProbability1(t *testing.T) {
+	mdp := GetRedBlueMDP()
+
+	alwaysRed, _ := adaptive.GetDRA(
+		[]string{"q"}, "q", []string{"red", "blue"},
+		map[string]map[string]string{"q": {"red": "q"}},
+		[][2][]string{{{}, {"q"}}},
+	)
+
+	testCases := []struct {
+		DRA      adaptive.DeterministicRabinAutomaton
+		Expected map[string]float64
+	}{
+		{GetEventuallyAlwaysRedDRA(), map[string]float64{"r0": 0.6, "b0": 0.6, "r1": 1, "b1": 0}},
+		{alwaysRed, map[string]float64{"r0": 0.6, "b0": 0, "r1": 1, "b1": 0}},
+	}
+
+	for _, options := range getMethodOptions() {
+		for _, testCase := range testCases {
+			result, err := mdp.MaxRabinProbability(testCase.DRA, options)
+			if err != nil {
+				t.Errorf("Unexpected error with %v: %v", options.Method, err)
+				continue
+			}
+
+			for state, value := range result.Values {
+				if math.Abs(value-testCase.Expected[state.Name]) > 1e-8 {
+					t.Errorf("Expected the probability of %v to be %v with %v, but found %v.", state, testCase.Expected[state.Name], options.Method, value)
+				}
+			}
+
+			if math.Abs(result.Probability-0.6) > 1e-8 {
+				t.Errorf("Expected the probability 0.6 from the initial distribution, but found %v.", result.Probability)
+			}
+
+			r0 := mdp.S[0]
+			if action := result.Scheduler.Actions[r0][result.Scheduler.InitialMemory[r0]]; action != "risky" {
+				t.Errorf("Expected the scheduler to choose risky in r0, but found %v.", action)
+			}
+		}
+	}
+
+	sinkResult, _ := mdp.MaxRabinProbability(alwaysRed, DefaultMDPSolverOptions())
+	if sink := sinkResult.Product.MDP.StatesNamed("(b1,(sink))"); len(sink) != 1 {
+		t.Errorf("Expected the product to contain the state (b1,(sink)).")
+	}
+}
+
+/*
+TestMDP_MaxRabinProbability2
+Description:
+
+	Visiting a and b infinitely often requires memory: from c, the scheduler must alternate between toA and toB.
+	The induced DTMC visits both states with a positive long-run probability.
+*/
+func TestMDP_MaxRabinProbability2(t *testing.T) {
+	mdp, _ := GetMDP(
+		[]string{"c", "a", "b"},
+		[]string{"toA", "toB", "back"},
+		map[string]map[string]map[string]float64{
+			"c": {"toA": {"a": 1}, "toB": {"b": 1}},
+			"a": {"back": {"c": 1}},
+			"b": {"back": {"c": 1}},
+		},
+		map[string]float64{"c": 1},
+		[]string{"a", "b", "c"},
+		map[string][]string{"a": {"a"}, "b": {"b"}, "c": {"c"}},
+	)
+
+	// G F a & G F b: wait for a, then for b, then accept
+	dra, err := adaptive.GetDRA(
+		[]string{"waitA", "waitB", "accept"}, "waitA", []string{"a", "b", "c"},
+		map[string]map[string]string{
+			"waitA":  {"a": "waitB", "b": "waitA", "c": "waitA"},
+			"waitB":  {"a": "waitB", "b": "accept", "c": "waitB"},
+			"accept": {"a": "waitB", "b": "waitA", "c": "waitA"},
+		},
+		[][2][]string{{{}, {"accept"}}},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	result, err := mdp.MaxRabinProbability(dra, DefaultMDPSolverOptions())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if math.Abs(result.Probability-1) > 1e-8 {
+		t.Errorf("Expected the probability 1, but found %v.", result.Probability)
+	}
+
+	c := mdp.S[0]
+	if result.Scheduler.Actions[c]["waitA"] != "toA" || result.Scheduler.Actions[c]["waitB"] != "toB" {
+		t.Errorf("Expected the scheduler to alternate in c, but found %v.", result.Scheduler.Actions[c])
+	}
+
+	chain, err := result.Scheduler.InducedDTMC(mdp)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, atom := range []string{"a", "b"} {
+		formula, _ := ParsePCTLFormula("S=? [ " + atom + " ]")
+		values, err := chain.Values(formula, DefaultSolverOptions())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if value := values[chain.InitialStates()[0]]; math.Abs(value-0.25) > 1e-8 {
+			t.Errorf("Expected the induced chain to be in %v a quarter of the time, but found %v.", atom, value)
+		}
+	}
+}
+
+/*
+TestFiniteMemoryScheduler_InducedDTMC1
+Description:
+
+	Schedulers which are missing memory updates or actions produce errors.
+*/
+func TestFiniteMemoryScheduler_InducedDTMC1(t *testing.T) {
+	mdp := GetRedBlueMDP()
+	r0, r1, b1 := mdp.S[0], mdp.S[2], mdp.S[3]
+
+	scheduler := FiniteMemoryScheduler{
+		InitialMemory: map[MDPState]string{r0: "m"},
+		NextMemory:    map[string]map[MDPState]string{"m": {r1: "m"}},
+		Actions:       map[MDPState]map[string]string{r0: {"m": "risky"}, r1: {"m": "stay"}, b1: {"m": "stay"}},
+	}
+
+	_, err := scheduler.InducedDTMC(mdp)
+	if err == nil || err.Error() != "The scheduler has no memory update from \"m\" when entering \"b1\"." {
+		t.Errorf("Expected an error for the memory update, but found %v.", err)
+	}
+
+	scheduler.NextMemory["m"][b1] = "n"
+	_, err = scheduler.InducedDTMC(mdp)
+	if err == nil || err.Error() != "The scheduler does not choose an action in the state \"b1\" with the memory \"n\"." {
+		t.Errorf("Expected an error for the action, but found %v.", err)
+	}
+
+	scheduler.Actions[b1]["n"] = "stay"
+	scheduler.NextMemory["n"] = map[MDPState]string{b1: "n"}
+	chain, err := scheduler.InducedDTMC(mdp)
+	if err != nil || len(chain.S) != 3 {
+		t.Errorf("Expected a chain with 3 states, but found %v (error: %v).", chain.S, err)
+	}
+}
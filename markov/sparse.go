@@ -0,0 +1,169 @@
+/*
+sparse.go
+Description:
+	Sparse matrices over the indices of the states of a model, and the graph algorithms which the
+	numerical methods use to find the states whose values are known without any computation.
+*/
+
+package markov
+
+import (
+	"github.com/kwesiRutledge/ModelChecking/internal/graph"
+)
+
+/*
+Type Definitions
+*/
+
+type sparseEntry struct {
+	Column int
+	Value  float64
+}
+
+/*
+sparseMatrix
+Description:
+	A square matrix stored as one slice of nonzero entries per row.
+*/
+type sparseMatrix [][]sparseEntry
+
+/*
+Functions
+*/
+
+/*
+multiply
+Description:
+	Returns the product of the matrix and the column vector x.
+*/
+func (matrix sparseMatrix) multiply(x []float64) []float64 {
+	product := make([]float64, len(matrix))
+	for i, row := range matrix {
+		for _, entry := range row {
+			product[i] += entry.Value * x[entry.Column]
+		}
+	}
+	return product
+}
+
+/*
+transposeMultiply
+Description:
+	Returns the product of the row vector x and the matrix, i.e. the distribution after one step when x is
+	a distribution and the matrix holds transition probabilities.
+*/
+func (matrix sparseMatrix) transposeMultiply(x []float64) []float64 {
+	product := make([]float64, len(matrix))
+	for i, row := range matrix {
+		for _, entry := range row {
+			product[entry.Column] += x[i] * entry.Value
+		}
+	}
+	return product
+}
+
+/*
+predecessors
+Description:
+	Returns, for each column, the rows which have a nonzero entry in it.
+*/
+func (matrix sparseMatrix) predecessors() [][]int {
+	predecessors := make([][]int, len(matrix))
+	for i, row := range matrix {
+		for _, entry := range row {
+			predecessors[entry.Column] = append(predecessors[entry.Column], i)
+		}
+	}
+	return predecessors
+}
+
+/*
+backwardReachable
+Description:
+	Returns the states which can reach a target state while only passing through allowed states
+	(the targets themselves are always included).
+*/
+func (matrix sparseMatrix) backwardReachable(targets []bool, allowed []bool) []bool {
+	predecessors := matrix.predecessors()
+	reachable := make([]bool, len(matrix))
+
+	var stack []int
+	for i, isTarget := range targets {
+		if isTarget {
+			reachable[i] = true
+			stack = append(stack, i)
+		}
+	}
+
+	for len(stack) > 0 {
+		j := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, i := range predecessors[j] {
+			if !reachable[i] && allowed[i] {
+				reachable[i] = true
+				stack = append(stack, i)
+			}
+		}
+	}
+
+	return reachable
+}
+
+/*
+stronglyConnectedComponents
+Description:
+	The strongly connected components of the graph of the nonzero entries of the matrix, restricted to the allowed states.
+*/
+func (matrix sparseMatrix) stronglyConnectedComponents(allowed []bool) [][]int {
+	successors := make([][]int, len(matrix))
+	for i, row := range matrix {
+		for _, entry := range row {
+			successors[i] = append(successors[i], entry.Column)
+		}
+	}
+	return graph.StronglyConnectedComponents(successors, allowed)
+}
+
+/*
+bottomComponents
+Description:
+	Returns the bottom strongly connected components of the matrix (the components which cannot be left).
+*/
+func (matrix sparseMatrix) bottomComponents() [][]int {
+	components := matrix.stronglyConnectedComponents(allStates(len(matrix)))
+
+	componentOf := make([]int, len(matrix))
+	for c, component := range components {
+		for _, v := range component {
+			componentOf[v] = c
+		}
+	}
+
+	var bottom [][]int
+	for c, component := range components {
+		isBottom := true
+		for _, v := range component {
+			for _, entry := range matrix[v] {
+				isBottom = isBottom && componentOf[entry.Column] == c
+			}
+		}
+		if isBottom {
+			bottom = append(bottom, component)
+		}
+	}
+
+	return bottom
+}
+
+/*
+allStates
+Description:
+	Returns a slice of n true values.
+*/
+func allStates(n int) []bool {
+	states := make([]bool, n)
+	for i := range states {
+		states[i] = true
+	}
+	return states
+}
@@ -0,0 +1,67 @@
+/*
+sparse_test.go
+Description:
+
+	Tests for the sparse matrices and graph algorithms defined in sparse.go
+*/
+package markov
+
+import (
+	"sort"
+	"testing"
+)
+
+/*
+TestSparseMatrix_bottomComponents1
+Description:
+
+	The die has the six absorbing states as bottom components.
+*/
+func TestSparseMatrix_bottomComponents1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	components := chain.matrix().bottomComponents()
+	if len(components) != 6 {
+		t.Errorf("Expected 6 bottom components, but found %v.", components)
+	}
+
+	for _, component := range components {
+		if len(component) != 1 || component[0] < 7 {
+			t.Errorf("Expected every bottom component to be one of the states d1, ..., d6, but found %v.", component)
+		}
+	}
+}
+
+/*
+TestSparseMatrix_backwardReachable1
+Description:
+
+	The states which can reach d1 without passing through s2 are s0, s1, s3 and d1.
+*/
+func TestSparseMatrix_backwardReachable1(t *testing.T) {
+	chain := GetKnuthYaoDie()
+
+	targets := make([]bool, len(chain.S))
+	targets[7] = true
+	allowed := allStates(len(chain.S))
+	allowed[2] = false
+
+	var reachable []int
+	for i, canReach := range chain.matrix().backwardReachable(targets, allowed) {
+		if canReach {
+			reachable = append(reachable, i)
+		}
+	}
+	sort.Ints(reachable)
+
+	expected := []int{0, 1, 3, 7}
+	if len(reachable) != len(expected) {
+		t.Errorf("Expected the states %v, but found %v.", expected, reachable)
+		return
+	}
+	for index := range expected {
+		if reachable[index] != expected[index] {
+			t.Errorf("Expected the states %v, but found %v.", expected, reachable)
+		}
+	}
+}
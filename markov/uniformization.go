@@ -0,0 +1,123 @@
+/*
+uniformization.go
+Description:
+	Transient analysis of continuous-time Markov chains by uniformization (Jensen's method). The state of a CTMC
+	at time t has the distribution of its uniformized DTMC after a number of steps which is Poisson distributed
+	with parameter q t. The infinite sum over the number of steps is truncated as proposed by Fox and Glynn:
+	the Poisson weights are computed from the mode outwards, starting from an arbitrary scale to avoid underflow,
+	until the remaining tails are provably below the requested error.
+*/
+
+package markov
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+poissonWeights
+Description:
+	The normalised Poisson probabilities of the steps Left, Left+1, ..., Right of a truncated sum.
+*/
+type poissonWeights struct {
+	Left    int
+	Weights []float64
+}
+
+/*
+Functions
+*/
+
+/*
+foxGlynn
+Description:
+	Returns the Poisson(lambda) probabilities between the left and right truncation points, such that the
+	probability mass outside of them is at most epsilon. Starting from the weight 1 at the mode, the weights
+	follow from w(k+1) = w(k) lambda / (k+1). Beyond the mode the ratios of consecutive weights decrease, so the
+	tails are bounded by geometric series; a side stops growing once its bound is below epsilon/2 times the total
+	weight so far. The weights are finally divided by their total.
+*/
+func foxGlynn(lambda float64, epsilon float64) (poissonWeights, error) {
+	if !(lambda >= 0) || math.IsInf(lambda, 1) {
+		return poissonWeights{}, fmt.Errorf("The Poisson rate %v must be finite and nonnegative.", lambda)
+	}
+
+	if !(epsilon > 0 && epsilon < 1) {
+		return poissonWeights{}, fmt.Errorf("The truncation error %v must be in (0,1).", epsilon)
+	}
+
+	mode := int(math.Floor(lambda))
+	right := []float64{1}
+	total := 1.0
+
+	// Right tail: sum_{j>k} w(j) <= w(k+1) / (1 - lambda/(k+2))
+	for k := mode; ; k++ {
+		next := right[len(right)-1] * lambda / float64(k+1)
+		if next/(1-lambda/float64(k+2)) <= epsilon/2*total {
+			break
+		}
+		right = append(right, next)
+		total += next
+	}
+
+	// Left tail: sum_{j<k} w(j) <= w(k-1) / (1 - (k-1)/lambda)
+	var left []float64
+	weight := 1.0
+	k := mode
+	for ; k > 0; k-- {
+		previous := weight * float64(k) / lambda
+		if previous/(1-float64(k-1)/lambda) <= epsilon/2*total {
+			break
+		}
+		left = append(left, previous)
+		total += previous
+		weight = previous
+	}
+
+	weights := poissonWeights{Left: k}
+	for position := len(left) - 1; position >= 0; position-- {
+		weights.Weights = append(weights.Weights, left[position]/total)
+	}
+	for _, w := range right {
+		weights.Weights = append(weights.Weights, w/total)
+	}
+	return weights, nil
+}
+
+/*
+Right
+Description:
+	Returns the right truncation point.
+*/
+func (weights poissonWeights) Right() int {
+	return weights.Left + len(weights.Weights) - 1
+}
+
+/*
+sum
+Description:
+	Returns sum_k w(k) step^k(x) over the steps k between the truncation points. With step = matrix.multiply,
+	this gives the expected value of x after the Poisson distributed number of steps from every state; with
+	step = matrix.transposeMultiply, it gives the distribution after those steps when x is the initial distribution.
+*/
+func (weights poissonWeights) sum(x []float64, step func([]float64) []float64) []float64 {
+	result := make([]float64, len(x))
+	current := x
+	for k := 0; k <= weights.Right(); k++ {
+		if k >= weights.Left {
+			w := weights.Weights[k-weights.Left]
+			for i, value := range current {
+				result[i] += w * value
+			}
+		}
+		if k < weights.Right() {
+			current = step(current)
+		}
+	}
+	return result
+}
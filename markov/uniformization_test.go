@@ -0,0 +1,77 @@
+/*
+uniformization_test.go
+Description:
+
+	Tests for the Fox-Glynn truncation defined in uniformization.go
+*/
+package markov
+
+import (
+	"math"
+	"testing"
+)
+
+/*
+poissonProbability
+Description:
+
+	Computes the Poisson(lambda) probability of k in logarithmic form.
+*/
+func poissonProbability(lambda float64, k int) float64 {
+	logFactorial, _ := math.Lgamma(float64(k + 1))
+	return math.Exp(float64(k)*math.Log(lambda) - lambda - logFactorial)
+}
+
+/*
+TestFoxGlynn1
+Description:
+
+	The truncated weights sum to one and match the Poisson probabilities, also for rates whose probabilities
+	underflow when computed naively from e^(-lambda).
+*/
+func TestFoxGlynn1(t *testing.T) {
+	for _, lambda := range []float64{0.5, 10, 1000, 100000} {
+		weights, err := foxGlynn(lambda, 1e-10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		total := 0.0
+		for position, w := range weights.Weights {
+			total += w
+			k := weights.Left + position
+			if expected := poissonProbability(lambda, k); math.Abs(w-expected) > 1e-9 {
+				t.Errorf("Expected the weight %v of %v for lambda %v, but found %v.", expected, k, lambda, w)
+			}
+		}
+
+		if math.Abs(total-1) > 1e-12 || weights.Left > int(lambda) || weights.Right() < int(lambda) {
+			t.Errorf("Expected weights around %v which sum to 1, but found [%v,%v] with total %v.", lambda, weights.Left, weights.Right(), total)
+		}
+	}
+
+	if weights, _ := foxGlynn(100000, 1e-10); weights.Left < 90000 || weights.Right() > 110000 {
+		t.Errorf("Expected a narrow window around 100000, but found [%v,%v].", weights.Left, weights.Right())
+	}
+}
+
+/*
+TestFoxGlynn2
+Description:
+
+	A rate of zero gives all weight to zero steps, and invalid parameters are rejected.
+*/
+func TestFoxGlynn2(t *testing.T) {
+	weights, err := foxGlynn(0, 1e-10)
+	if err != nil || weights.Left != 0 || len(weights.Weights) != 1 || weights.Weights[0] != 1 {
+		t.Errorf("Expected the single weight 1, but found %v (error %v).", weights, err)
+	}
+
+	if _, err := foxGlynn(math.Inf(1), 1e-10); err == nil || err.Error() != "The Poisson rate +Inf must be finite and nonnegative." {
+		t.Errorf("Expected an error for the rate, but found %v.", err)
+	}
+
+	if _, err := foxGlynn(1, 0); err == nil || err.Error() != "The truncation error 0 must be in (0,1)." {
+		t.Errorf("Expected an error for the truncation error, but found %v.", err)
+	}
+}
@@ -0,0 +1,484 @@
+/*
+compile.go
+Description:
+	Compiles an LTL formula into an LTL3 monitor automaton (Bauer, Leucker and Schallhart, 2011).
+	The formula is rewritten with true, atoms, !, &, X and U only, and the elementary sets (atoms) of its closure
+	form a generalized Büchi automaton (Baier and Katoen, Section 5.2). The elementary sets from which an
+	accepting run exists are kept, and a subset construction which tracks the runs for the formula and for its
+	negation at the same time produces a deterministic automaton. Its states are labelled with the verdict:
+	violated if no run for the formula remains, satisfied if no run for the negation remains.
+*/
+
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/internal/graph"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Automaton
+Description:
+	A deterministic LTL3 monitor. The letters are the subsets of Atoms, written as bit masks
+	(bit i is set if Atoms[i] holds), so Next[q][mask] is the state reached from q when reading that letter.
+	An Automaton is never modified after Compile(), so it can be shared by any number of Monitors.
+*/
+type Automaton struct {
+	Formula  ltl.Formula
+	Atoms    []mc.AtomicProposition
+	Initial  int
+	Next     [][]int
+	Verdicts []Verdict
+	bits     map[string]int
+}
+
+// MaxBasicSubformulas bounds the number of atoms, X and U subformulas of a compiled formula,
+// since the tableau has up to 2^MaxBasicSubformulas elementary sets.
+const MaxBasicSubformulas = 16
+
+type coreOperator int
+
+const (
+	coreTrue coreOperator = iota
+	coreAtom
+	coreNot
+	coreAnd
+	coreNext
+	coreUntil
+)
+
+type coreNode struct {
+	Operator coreOperator
+	Atom     string
+	Operands []int
+}
+
+/*
+closure
+Description:
+	The subformulas of the rewritten formula. Nodes are shared and stored so that the operands of a node
+	always come before the node itself.
+*/
+type closure struct {
+	Nodes []coreNode
+	index map[string]int
+}
+
+/*
+tableau
+Description:
+	The elementary sets of a closure. Values[b][n] is true if node n is in elementary set b,
+	Letters[b] is the mask of the atoms in b and Successors[b] lists the elementary sets which may follow b.
+*/
+type tableau struct {
+	Values     [][]bool
+	Letters    []int
+	Successors [][]int
+}
+
+/*
+Functions
+*/
+
+/*
+Compile
+Description:
+	Builds the LTL3 monitor automaton of the formula. On infinite words N is the same as X.
+Usage:
+	formula, _ := ltl.Parse("G (request -> F grant)")
+	automaton, err := Compile(formula)
+*/
+func Compile(formula ltl.Formula) (Automaton, error) {
+	// Rewrite the formula
+	cl := closure{index: make(map[string]int)}
+	root, err := cl.add(formula)
+	if err != nil {
+		return Automaton{}, err
+	}
+
+	var atomNames []string
+	for _, node := range cl.Nodes {
+		if node.Operator == coreAtom {
+			atomNames = append(atomNames, node.Atom)
+		}
+	}
+	sort.Strings(atomNames)
+
+	automaton := Automaton{
+		Formula: formula,
+		Atoms:   mc.StringSliceToAPs(atomNames),
+		bits:    make(map[string]int),
+	}
+	for bit, name := range atomNames {
+		automaton.bits[name] = bit
+	}
+
+	// Build the tableau and keep the elementary sets with an accepting run
+	tab, err := cl.tableau(automaton.bits)
+	if err != nil {
+		return Automaton{}, err
+	}
+	good := cl.nonemptyElementarySets(tab)
+
+	var positive, negative []int
+	for b, values := range tab.Values {
+		if !good[b] {
+			continue
+		}
+		if values[root] {
+			positive = append(positive, b)
+		} else {
+			negative = append(negative, b)
+		}
+	}
+
+	// Subset construction
+	letterCount := 1 << len(atomNames)
+	stateIndex := make(map[string]int)
+	var queue [][2][]int
+
+	addState := func(sets [2][]int) int {
+		key := fmt.Sprintf("%v|%v", sets[0], sets[1])
+		if q, found := stateIndex[key]; found {
+			return q
+		}
+		q := len(automaton.Verdicts)
+		stateIndex[key] = q
+		queue = append(queue, sets)
+		automaton.Next = append(automaton.Next, make([]int, letterCount))
+		switch {
+		case len(sets[0]) == 0:
+			automaton.Verdicts = append(automaton.Verdicts, Violated)
+		case len(sets[1]) == 0:
+			automaton.Verdicts = append(automaton.Verdicts, Satisfied)
+		default:
+			automaton.Verdicts = append(automaton.Verdicts, Inconclusive)
+		}
+		return q
+	}
+
+	automaton.Initial = addState([2][]int{positive, negative})
+	for q := 0; q < len(queue); q++ {
+		for letter := 0; letter < letterCount; letter++ {
+			var successors [2][]int
+			for side, sets := range queue[q] {
+				successors[side] = tab.step(sets, letter, good)
+			}
+			automaton.Next[q][letter] = addState(successors)
+		}
+	}
+
+	return automaton, nil
+}
+
+/*
+NumStates
+Description:
+	Returns the number of states of the monitor automaton.
+*/
+func (automaton Automaton) NumStates() int {
+	return len(automaton.Verdicts)
+}
+
+/*
+Letter
+Description:
+	Returns the mask of the atoms of the automaton which are in labels. Other propositions are ignored.
+*/
+func (automaton Automaton) Letter(labels []mc.AtomicProposition) int {
+	letter := 0
+	for _, ap := range labels {
+		if bit, found := automaton.bits[ap.Name]; found {
+			letter |= 1 << bit
+		}
+	}
+	return letter
+}
+
+/*
+add
+Description:
+	Adds the rewritten formula to the closure and returns the index of its node.
+*/
+func (cl *closure) add(formula ltl.Formula) (int, error) {
+	var operands []int
+	for _, operand := range formula.Operands {
+		index, err := cl.add(operand)
+		if err != nil {
+			return -1, err
+		}
+		operands = append(operands, index)
+	}
+
+	switch formula.Operator {
+	case ltl.OpTrue:
+		return cl.node(coreNode{Operator: coreTrue}), nil
+	case ltl.OpFalse:
+		return cl.not(cl.node(coreNode{Operator: coreTrue})), nil
+	case ltl.OpAtom:
+		return cl.node(coreNode{Operator: coreAtom, Atom: formula.Atom.Name}), nil
+	case ltl.OpNot:
+		return cl.not(operands[0]), nil
+	case ltl.OpAnd:
+		return cl.and(operands...), nil
+	case ltl.OpOr:
+		return cl.or(operands...), nil
+	case ltl.OpImplies:
+		return cl.or(cl.not(operands[0]), operands[1]), nil
+	case ltl.OpNext, ltl.OpWeakNext:
+		return cl.node(coreNode{Operator: coreNext, Operands: operands}), nil
+	case ltl.OpEventually:
+		return cl.until(cl.node(coreNode{Operator: coreTrue}), operands[0]), nil
+	case ltl.OpAlways:
+		return cl.not(cl.until(cl.node(coreNode{Operator: coreTrue}), cl.not(operands[0]))), nil
+	case ltl.OpUntil:
+		return cl.until(operands[0], operands[1]), nil
+	case ltl.OpRelease:
+		// a R b = !(!a U !b)
+		return cl.not(cl.until(cl.not(operands[0]), cl.not(operands[1]))), nil
+	case ltl.OpWeakUntil:
+		// a W b = !(!b U (!a & !b))
+		return cl.not(cl.until(cl.not(operands[1]), cl.and(cl.not(operands[0]), cl.not(operands[1])))), nil
+	default:
+		return -1, fmt.Errorf("Unrecognized LTL operator %v.", formula.Operator)
+	}
+}
+
+/*
+node
+Description:
+	Returns the index of the node, adding it to the closure if it is new.
+*/
+func (cl *closure) node(n coreNode) int {
+	key := fmt.Sprintf("%v %q %v", n.Operator, n.Atom, n.Operands)
+	if index, found := cl.index[key]; found {
+		return index
+	}
+	cl.Nodes = append(cl.Nodes, n)
+	cl.index[key] = len(cl.Nodes) - 1
+	return len(cl.Nodes) - 1
+}
+
+func (cl *closure) not(operand int) int {
+	if cl.Nodes[operand].Operator == coreNot {
+		return cl.Nodes[operand].Operands[0]
+	}
+	return cl.node(coreNode{Operator: coreNot, Operands: []int{operand}})
+}
+
+func (cl *closure) and(operands ...int) int {
+	if len(operands) == 0 {
+		return cl.node(coreNode{Operator: coreTrue})
+	}
+	conjunction := operands[0]
+	for _, operand := range operands[1:] {
+		conjunction = cl.node(coreNode{Operator: coreAnd, Operands: []int{conjunction, operand}})
+	}
+	return conjunction
+}
+
+func (cl *closure) or(operands ...int) int {
+	var negated []int
+	for _, operand := range operands {
+		negated = append(negated, cl.not(operand))
+	}
+	return cl.not(cl.and(negated...))
+}
+
+func (cl *closure) until(left int, right int) int {
+	return cl.node(coreNode{Operator: coreUntil, Operands: []int{left, right}})
+}
+
+/*
+tableau
+Description:
+	Enumerates the elementary sets of the closure. The atoms, X and U subformulas are chosen freely; the other
+	subformulas follow from them, and the sets which are not locally consistent with the U subformulas
+	(right ⇒ left U right ⇒ left or right) are discarded.
+*/
+func (cl *closure) tableau(bits map[string]int) (tableau, error) {
+	var basic []int
+	for index, node := range cl.Nodes {
+		if node.Operator == coreAtom || node.Operator == coreNext || node.Operator == coreUntil {
+			basic = append(basic, index)
+		}
+	}
+	if len(basic) > MaxBasicSubformulas {
+		return tableau{}, fmt.Errorf(
+			"The formula has %v atoms, X and U subformulas, but at most %v can be compiled into a monitor.",
+			len(basic), MaxBasicSubformulas,
+		)
+	}
+
+	var tab tableau
+	for choice := 0; choice < 1<<len(basic); choice++ {
+		values := make([]bool, len(cl.Nodes))
+		for bit, index := range basic {
+			values[index] = choice&(1<<bit) != 0
+		}
+
+		consistent := true
+		letter := 0
+		for index, node := range cl.Nodes {
+			switch node.Operator {
+			case coreTrue:
+				values[index] = true
+			case coreAtom:
+				if values[index] {
+					letter |= 1 << bits[node.Atom]
+				}
+			case coreNot:
+				values[index] = !values[node.Operands[0]]
+			case coreAnd:
+				values[index] = values[node.Operands[0]] && values[node.Operands[1]]
+			case coreUntil:
+				left, right := values[node.Operands[0]], values[node.Operands[1]]
+				if (right && !values[index]) || (values[index] && !left && !right) {
+					consistent = false
+				}
+			}
+		}
+
+		if consistent {
+			tab.Values = append(tab.Values, values)
+			tab.Letters = append(tab.Letters, letter)
+		}
+	}
+
+	// Transitions
+	tab.Successors = make([][]int, len(tab.Values))
+	for b, values := range tab.Values {
+		for b2, values2 := range tab.Values {
+			if cl.canFollow(values, values2) {
+				tab.Successors[b] = append(tab.Successors[b], b2)
+			}
+		}
+	}
+
+	return tab, nil
+}
+
+/*
+canFollow
+Description:
+	Determines if the elementary set values2 can follow the elementary set values.
+*/
+func (cl *closure) canFollow(values []bool, values2 []bool) bool {
+	for index, node := range cl.Nodes {
+		switch node.Operator {
+		case coreNext:
+			if values[index] != values2[node.Operands[0]] {
+				return false
+			}
+		case coreUntil:
+			holds := values[node.Operands[1]] || (values[node.Operands[0]] && values2[index])
+			if values[index] != holds {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+/*
+nonemptyElementarySets
+Description:
+	Marks the elementary sets from which an accepting run starts, i.e. which can reach a nontrivial strongly
+	connected component that contains, for each U subformula, a set without it or with its right operand.
+*/
+func (cl *closure) nonemptyElementarySets(tab tableau) []bool {
+	components := graph.StronglyConnectedComponents(tab.Successors, nil)
+
+	good := make([]bool, len(tab.Values))
+	var stack []int
+	for _, component := range components {
+		if !graph.IsNontrivial(component, tab.Successors) {
+			continue
+		}
+
+		accepting := true
+		for index, node := range cl.Nodes {
+			if node.Operator != coreUntil {
+				continue
+			}
+			fulfilled := false
+			for _, b := range component {
+				fulfilled = fulfilled || !tab.Values[b][index] || tab.Values[b][node.Operands[1]]
+			}
+			accepting = accepting && fulfilled
+		}
+
+		if accepting {
+			for _, b := range component {
+				good[b] = true
+				stack = append(stack, b)
+			}
+		}
+	}
+
+	// Backwards reachability
+	predecessors := make([][]int, len(tab.Values))
+	for b, successors := range tab.Successors {
+		for _, b2 := range successors {
+			predecessors[b2] = append(predecessors[b2], b)
+		}
+	}
+	for len(stack) > 0 {
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, b2 := range predecessors[b] {
+			if !good[b2] {
+				good[b2] = true
+				stack = append(stack, b2)
+			}
+		}
+	}
+
+	return good
+}
+
+/*
+step
+Description:
+	Reads a letter in the subset construction: keeps the elementary sets which are labelled with the letter
+	and returns their good successors, sorted and without repetitions.
+*/
+func (tab tableau) step(sets []int, letter int, good []bool) []int {
+	seen := make(map[int]bool)
+	var successors []int
+	for _, b := range sets {
+		if tab.Letters[b] != letter {
+			continue
+		}
+		for _, b2 := range tab.Successors[b] {
+			if good[b2] && !seen[b2] {
+				seen[b2] = true
+				successors = append(successors, b2)
+			}
+		}
+	}
+	sort.Ints(successors)
+	return successors
+}
+
+/*
+String
+Description:
+	Describes the size of the automaton.
+*/
+func (automaton Automaton) String() string {
+	var names []string
+	for _, ap := range automaton.Atoms {
+		names = append(names, ap.Name)
+	}
+	return fmt.Sprintf("LTL3 monitor for %v with %v states over {%v}", automaton.Formula, automaton.NumStates(), strings.Join(names, ", "))
+}
@@ -0,0 +1,219 @@
+/*
+compile_test.go
+Description:
+
+	Tests for the compilation of LTL3 monitor automata defined in compile.go
+*/
+package monitor
+
+import (
+	"math/rand"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+)
+
+/*
+getLetters
+Description:
+
+	Creates a sequence of label sets in which each set contains the propositions named by the characters of a string.
+*/
+func getLetters(letters ...string) [][]mc.AtomicProposition {
+	word := [][]mc.AtomicProposition{}
+	for _, letter := range letters {
+		set := []mc.AtomicProposition{}
+		for _, r := range letter {
+			set = append(set, mc.AtomicProposition{Name: string(r)})
+		}
+		word = append(word, set)
+	}
+	return word
+}
+
+/*
+TestCompile1
+Description:
+
+	The monitor of G a has an inconclusive initial state and a violated sink.
+*/
+func TestCompile1(t *testing.T) {
+	automaton, err := Compile(ltl.Always(ltl.Atom("a")))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(automaton.Atoms) != 1 || automaton.Atoms[0].Name != "a" {
+		t.Errorf("Expected the atoms [a], but found %v.", automaton.Atoms)
+	}
+
+	if automaton.NumStates() != 2 {
+		t.Errorf("Expected 2 states, but found %v.", automaton.NumStates())
+	}
+
+	if automaton.Verdicts[automaton.Initial] != Inconclusive {
+		t.Errorf("Expected the initial verdict to be inconclusive, but found %v.", automaton.Verdicts[automaton.Initial])
+	}
+
+	violated := automaton.Next[automaton.Initial][0]
+	if automaton.Verdicts[violated] != Violated || automaton.Next[violated][1] != violated {
+		t.Errorf("Expected reading {} to lead to a violated sink.")
+	}
+}
+
+/*
+TestCompile2
+Description:
+
+	Tautologies and contradictions are decided before any event is read.
+*/
+func TestCompile2(t *testing.T) {
+	testCases := map[string]Verdict{
+		"true":                    Satisfied,
+		"a | !a":                  Satisfied,
+		"G F a | F G !a":          Satisfied,
+		"a & !a":                  Violated,
+		"G a & F !a":              Violated,
+		"X false":                 Violated,
+		"(a U b) -> F b":          Satisfied,
+		"G (a -> X a) & a & F !a": Violated,
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := ltl.Parse(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		automaton, err := Compile(formula)
+		if err != nil {
+			t.Errorf("Unexpected error while compiling \"%v\": %v", formulaString, err)
+			continue
+		}
+
+		if verdict := automaton.Verdicts[automaton.Initial]; verdict != expected {
+			t.Errorf("Expected the initial verdict of \"%v\" to be %v, but found %v.", formulaString, expected, verdict)
+		}
+	}
+}
+
+/*
+TestCompile3
+Description:
+
+	Conclusive verdicts are sound: for random formulas and random prefixes, every lasso which extends a prefix with
+	a Satisfied (resp. Violated) verdict satisfies (resp. violates) the formula.
+	Every prefix also has a satisfying or violating extension among the lassos which are tried.
+*/
+func TestCompile3(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	letters := []string{"", "a", "b", "ab"}
+	randomWord := func(minLength int, maxLength int) []string {
+		var word []string
+		for i := minLength + random.Intn(maxLength-minLength+1); i > 0; i-- {
+			word = append(word, letters[random.Intn(len(letters))])
+		}
+		return word
+	}
+
+	for trial := 0; trial < 100; trial++ {
+		formula := randomFormula(random, 3)
+		automaton, err := Compile(formula)
+		if err != nil {
+			t.Errorf("Unexpected error while compiling %v: %v", formula, err)
+			continue
+		}
+
+		prefix := randomWord(0, 4)
+		m := automaton.NewMonitor()
+		for _, labels := range getLetters(prefix...) {
+			m.Step(labels)
+		}
+		verdict := m.Verdict()
+
+		for extension := 0; extension < 20; extension++ {
+			extendedPrefix := append(append([]string{}, prefix...), randomWord(0, 3)...)
+			satisfied, err := ltl.EvaluateLasso(formula, getLetters(extendedPrefix...), getLetters(randomWord(1, 3)...))
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if (verdict == Satisfied && !satisfied) || (verdict == Violated && satisfied) {
+				t.Errorf("The verdict of %v after %v is %v, but the extension %v does not agree.", formula, prefix, verdict, extendedPrefix)
+			}
+		}
+	}
+}
+
+/*
+randomFormula
+Description:
+
+	Creates a random formula over the atoms a and b with at most the given depth.
+*/
+func randomFormula(random *rand.Rand, depth int) ltl.Formula {
+	if depth == 0 || random.Intn(4) == 0 {
+		return ltl.Atom([]string{"a", "b"}[random.Intn(2)])
+	}
+
+	operand := func() ltl.Formula { return randomFormula(random, depth-1) }
+	switch random.Intn(11) {
+	case 0:
+		return ltl.Not(operand())
+	case 1:
+		return ltl.And(operand(), operand())
+	case 2:
+		return ltl.Or(operand(), operand())
+	case 3:
+		return ltl.Next(operand())
+	case 4:
+		return ltl.Always(operand())
+	case 5:
+		return ltl.Eventually(operand())
+	case 6:
+		return ltl.Until(operand(), operand())
+	case 7:
+		return ltl.Release(operand(), operand())
+	case 8:
+		return ltl.WeakUntil(operand(), operand())
+	case 9:
+		return ltl.WeakNext(operand())
+	default:
+		return ltl.Implies(operand(), operand())
+	}
+}
+
+/*
+TestCompile4
+Description:
+
+	Formulas with too many temporal subformulas are rejected.
+*/
+func TestCompile4(t *testing.T) {
+	formula := ltl.Atom("a")
+	for i := 0; i < MaxBasicSubformulas; i++ {
+		formula = ltl.Next(formula)
+	}
+
+	_, err := Compile(formula)
+	if err == nil {
+		t.Errorf("Expected an error for a formula with %v X operators.", MaxBasicSubformulas)
+	}
+}
+
+/*
+TestAutomaton_Letter1
+Description:
+
+	Propositions which are not in the formula are ignored.
+*/
+func TestAutomaton_Letter1(t *testing.T) {
+	formula, _ := ltl.Parse("a U b")
+	automaton, _ := Compile(formula)
+
+	if letter := automaton.Letter(getLetters("bc")[0]); letter != 2 {
+		t.Errorf("Expected the letter 2 ({b}), but found %v.", letter)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+monitor.go
+Description:
+	Online monitoring of LTL formulas over streams of label sets. A Monitor follows one stream through a compiled
+	LTL3 Automaton. Each Step() takes constant time in the length of the stream (one table lookup) and Monitors
+	can be used by several goroutines at the same time.
+*/
+
+package monitor
+
+import (
+	"fmt"
+	"sync"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Verdict
+Description:
+	The LTL3 verdict for the events seen so far. Satisfied (resp. Violated) means that every (resp. no)
+	infinite continuation of the stream satisfies the formula; these verdicts never change once reached.
+*/
+type Verdict int
+
+const (
+	Inconclusive Verdict = iota
+	Satisfied
+	Violated
+)
+
+/*
+Monitor
+Description:
+	Watches a single stream of events. Use New() or Automaton.NewMonitor() to create one.
+*/
+type Monitor struct {
+	automaton Automaton
+	mutex     sync.Mutex
+	state     int
+	steps     int
+}
+
+/*
+Functions
+*/
+
+/*
+String
+Description:
+	Returns the name of the verdict.
+*/
+func (verdict Verdict) String() string {
+	switch verdict {
+	case Inconclusive:
+		return "inconclusive"
+	case Satisfied:
+		return "satisfied"
+	case Violated:
+		return "violated"
+	default:
+		return fmt.Sprintf("Verdict(%v)", int(verdict))
+	}
+}
+
+/*
+IsConclusive
+Description:
+	Returns true if the verdict is Satisfied or Violated.
+*/
+func (verdict Verdict) IsConclusive() bool {
+	return verdict == Satisfied || verdict == Violated
+}
+
+/*
+New
+Description:
+	Compiles the formula and returns a monitor for it.
+Usage:
+	formula, _ := ltl.Parse("G !error")
+	m, err := New(formula)
+	verdict := m.Step(labels)
+*/
+func New(formula ltl.Formula) (*Monitor, error) {
+	automaton, err := Compile(formula)
+	if err != nil {
+		return nil, err
+	}
+
+	return automaton.NewMonitor(), nil
+}
+
+/*
+NewMonitor
+Description:
+	Returns a new monitor in the initial state of the automaton. Several monitors can share one automaton.
+*/
+func (automaton Automaton) NewMonitor() *Monitor {
+	return &Monitor{automaton: automaton, state: automaton.Initial}
+}
+
+/*
+Step
+Description:
+	Consumes the set of atomic propositions which hold at the next position of the stream and
+	returns the new verdict. Propositions which do not appear in the formula are ignored.
+*/
+func (m *Monitor) Step(labels []mc.AtomicProposition) Verdict {
+	letter := m.automaton.Letter(labels)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state = m.automaton.Next[m.state][letter]
+	m.steps++
+	return m.automaton.Verdicts[m.state]
+}
+
+/*
+Verdict
+Description:
+	Returns the verdict for the events consumed so far.
+*/
+func (m *Monitor) Verdict() Verdict {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.automaton.Verdicts[m.state]
+}
+
+/*
+Steps
+Description:
+	Returns the number of events consumed since the monitor was created or reset.
+*/
+func (m *Monitor) Steps() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.steps
+}
+
+/*
+Reset
+Description:
+	Returns the monitor to the initial state, e.g. to watch a new stream.
+*/
+func (m *Monitor) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state = m.automaton.Initial
+	m.steps = 0
+}
+
+/*
+Automaton
+Description:
+	Returns the automaton which the monitor follows.
+*/
+func (m *Monitor) Automaton() Automaton {
+	return m.automaton
+}
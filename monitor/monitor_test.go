@@ -0,0 +1,125 @@
+/*
+monitor_test.go
+Description:
+
+	Tests for the runtime monitors defined in monitor.go
+*/
+package monitor
+
+import (
+	"sync"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+)
+
+/*
+TestMonitor_Step1
+Description:
+
+	Follows the verdicts of several formulas on the stream {a} {} {b} {a,b}.
+*/
+func TestMonitor_Step1(t *testing.T) {
+	stream := getLetters("a", "", "b", "ab")
+
+	testCases := map[string][]Verdict{
+		"G a":                 {Inconclusive, Violated, Violated, Violated},
+		"F b":                 {Inconclusive, Inconclusive, Satisfied, Satisfied},
+		"a U b":               {Inconclusive, Violated, Violated, Violated},
+		"X !a":                {Inconclusive, Satisfied, Satisfied, Satisfied},
+		"G (a -> F b)":        {Inconclusive, Inconclusive, Inconclusive, Inconclusive},
+		"!b W a":              {Satisfied, Satisfied, Satisfied, Satisfied},
+		"X X X (a & b)":       {Inconclusive, Inconclusive, Inconclusive, Satisfied},
+		"G F a | F (b & X a)": {Inconclusive, Inconclusive, Inconclusive, Satisfied},
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := ltl.Parse(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+
+		m, err := New(formula)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+
+		for index, labels := range stream {
+			if verdict := m.Step(labels); verdict != expected[index] {
+				t.Errorf("Expected the verdict of \"%v\" after %v events to be %v, but found %v.", formulaString, index+1, expected[index], verdict)
+			}
+		}
+	}
+}
+
+/*
+TestMonitor_Reset1
+Description:
+
+	A violated monitor returns to the inconclusive initial state after a reset.
+*/
+func TestMonitor_Reset1(t *testing.T) {
+	m, _ := New(ltl.Always(ltl.Not(ltl.Atom("error"))))
+
+	m.Step([]mc.AtomicProposition{{Name: "error"}})
+	if m.Verdict() != Violated || m.Steps() != 1 {
+		t.Errorf("Expected the monitor to be violated after 1 step, but found %v after %v steps.", m.Verdict(), m.Steps())
+	}
+
+	m.Reset()
+	if m.Verdict() != Inconclusive || m.Steps() != 0 {
+		t.Errorf("Expected the reset monitor to be inconclusive after 0 steps, but found %v after %v steps.", m.Verdict(), m.Steps())
+	}
+}
+
+/*
+TestMonitor_Step2
+Description:
+
+	Many goroutines step one monitor at the same time; every event is counted and none is lost.
+*/
+func TestMonitor_Step2(t *testing.T) {
+	automaton, _ := Compile(ltl.Always(ltl.Atom("alive")))
+	m := automaton.NewMonitor()
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				m.Step([]mc.AtomicProposition{{Name: "alive"}, {Name: "other"}})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.Steps() != 8000 || m.Verdict() != Inconclusive {
+		t.Errorf("Expected 8000 inconclusive steps, but found %v steps with verdict %v.", m.Steps(), m.Verdict())
+	}
+
+	// A second monitor which shares the automaton is independent
+	m2 := m.Automaton().NewMonitor()
+	if m2.Step([]mc.AtomicProposition{}) != Violated || m.Verdict() != Inconclusive {
+		t.Errorf("Expected only the second monitor to be violated.")
+	}
+}
+
+/*
+TestVerdict_String1
+Description:
+
+	Checks the names of the verdicts.
+*/
+func TestVerdict_String1(t *testing.T) {
+	if Satisfied.String() != "satisfied" || Violated.String() != "violated" || Inconclusive.String() != "inconclusive" {
+		t.Errorf("Unexpected names for the verdicts.")
+	}
+
+	if Inconclusive.IsConclusive() || !Violated.IsConclusive() {
+		t.Errorf("Unexpected result of IsConclusive().")
+	}
+}
@@ -0,0 +1,688 @@
+/*
+acceptance.go
+Description:
+	Defines the acceptance conditions that can be attached to an Automaton.
+	Every acceptance condition is evaluated on the set of states that a run visits infinitely often
+	and can be rewritten as an Emerson-Lei condition.
+*/
+
+package omega
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+AcceptanceCondition
+Description:
+	The interface that every acceptance condition satisfies.
+	- IsSatisfiedBy() determines if a run which visits the states inf infinitely often is accepting.
+	- ToEmersonLei() rewrites the condition as an Emerson-Lei condition.
+	- Check() verifies that the condition only refers to states in Q.
+*/
+type AcceptanceCondition interface {
+	IsSatisfiedBy(inf []State) bool
+	ToEmersonLei() EmersonLei
+	Check(Q []State) error
+	String() string
+}
+
+/*
+Buchi
+Description:
+	A run is accepting if it visits F infinitely often.
+*/
+type Buchi struct {
+	F []State
+}
+
+/*
+GeneralizedBuchi
+Description:
+	A run is accepting if it visits every set in F infinitely often.
+*/
+type GeneralizedBuchi struct {
+	F [][]State
+}
+
+/*
+CoBuchi
+Description:
+	A run is accepting if it visits F only finitely often.
+*/
+type CoBuchi struct {
+	F []State
+}
+
+/*
+AcceptancePair
+Description:
+	A pair of sets of states (E,F) that is used by the Rabin and Streett conditions.
+	This matches the pairs in the Omega member of the DeterministicRabinAutomaton.
+*/
+type AcceptancePair struct {
+	E []State
+	F []State
+}
+
+/*
+Rabin
+Description:
+	A run is accepting if for some pair (E,F) it visits E finitely often and F infinitely often.
+*/
+type Rabin struct {
+	Pairs []AcceptancePair
+}
+
+/*
+Streett
+Description:
+	A run is accepting if for every pair (E,F), visiting F infinitely often implies visiting E infinitely often.
+	Streett(pairs) is the complement of Rabin(pairs).
+*/
+type Streett struct {
+	Pairs []AcceptancePair
+}
+
+/*
+Parity
+Description:
+	A run is accepting if the largest priority that it visits infinitely often is even.
+	States without a priority are treated as having priority 0.
+*/
+type Parity struct {
+	Priority map[State]int
+}
+
+/*
+EmersonLei
+Description:
+	A run is accepting if it satisfies the boolean combination of Inf and Fin conditions in Formula.
+*/
+type EmersonLei struct {
+	Formula AcceptanceFormula
+}
+
+/*
+AcceptanceOperator
+Description:
+	The operators that can appear in an AcceptanceFormula.
+*/
+type AcceptanceOperator int
+
+const (
+	AcceptTrue AcceptanceOperator = iota
+	AcceptFalse
+	AcceptInf
+	AcceptFin
+	AcceptAnd
+	AcceptOr
+)
+
+/*
+AcceptanceFormula
+Description:
+	A boolean formula over Inf(Set) and Fin(Set). Inf(Set) holds if Set is visited infinitely often
+	and Fin(Set) holds if Set is visited finitely often.
+*/
+type AcceptanceFormula struct {
+	Operator AcceptanceOperator
+	Set      []State
+	Operands []AcceptanceFormula
+}
+
+/*
+AcceptanceConjunct
+Description:
+	One disjunct of an AcceptanceFormula in disjunctive normal form:
+	the run must visit the states in Fin finitely often and every set in Inf infinitely often.
+*/
+type AcceptanceConjunct struct {
+	Fin []State
+	Inf [][]State
+}
+
+/*
+Functions for AcceptanceFormula
+*/
+
+/*
+Inf
+Description:
+	Creates the formula which holds when setIn is visited infinitely often.
+*/
+func Inf(setIn []State) AcceptanceFormula {
+	return AcceptanceFormula{Operator: AcceptInf, Set: setIn}
+}
+
+/*
+Fin
+Description:
+	Creates the formula which holds when setIn is visited finitely often.
+*/
+func Fin(setIn []State) AcceptanceFormula {
+	return AcceptanceFormula{Operator: AcceptFin, Set: setIn}
+}
+
+/*
+And
+Description:
+	Creates the conjunction of the formulas. The empty conjunction is true.
+*/
+func And(formulas ...AcceptanceFormula) AcceptanceFormula {
+	return AcceptanceFormula{Operator: AcceptAnd, Operands: formulas}
+}
+
+/*
+Or
+Description:
+	Creates the disjunction of the formulas. The empty disjunction is false.
+*/
+func Or(formulas ...AcceptanceFormula) AcceptanceFormula {
+	return AcceptanceFormula{Operator: AcceptOr, Operands: formulas}
+}
+
+/*
+True
+Description:
+	Creates the formula which accepts every run.
+*/
+func True() AcceptanceFormula {
+	return AcceptanceFormula{Operator: AcceptTrue}
+}
+
+/*
+False
+Description:
+	Creates the formula which rejects every run.
+*/
+func False() AcceptanceFormula {
+	return AcceptanceFormula{Operator: AcceptFalse}
+}
+
+/*
+IsSatisfiedBy
+Description:
+	Evaluates the formula for a run which visits the states inf infinitely often.
+*/
+func (formula AcceptanceFormula) IsSatisfiedBy(inf []State) bool {
+	switch formula.Operator {
+	case AcceptTrue:
+		return true
+	case AcceptFalse:
+		return false
+	case AcceptInf:
+		return intersects(formula.Set, inf)
+	case AcceptFin:
+		return !intersects(formula.Set, inf)
+	case AcceptAnd:
+		for _, operand := range formula.Operands {
+			if !operand.IsSatisfiedBy(inf) {
+				return false
+			}
+		}
+		return true
+	case AcceptOr:
+		for _, operand := range formula.Operands {
+			if operand.IsSatisfiedBy(inf) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+/*
+Negate
+Description:
+	Returns the formula which accepts exactly the runs that formula rejects.
+*/
+func (formula AcceptanceFormula) Negate() AcceptanceFormula {
+	switch formula.Operator {
+	case AcceptTrue:
+		return False()
+	case AcceptFalse:
+		return True()
+	case AcceptInf:
+		return Fin(formula.Set)
+	case AcceptFin:
+		return Inf(formula.Set)
+	}
+
+	var negatedOperands []AcceptanceFormula
+	for _, operand := range formula.Operands {
+		negatedOperands = append(negatedOperands, operand.Negate())
+	}
+
+	if formula.Operator == AcceptAnd {
+		return Or(negatedOperands...)
+	}
+	return And(negatedOperands...)
+}
+
+/*
+DNF
+Description:
+	Rewrites the formula in disjunctive normal form. Every conjunct collects the union of its Fin sets,
+	since Fin(A) and Fin(B) is equivalent to Fin(A union B).
+	The size of the result can be exponential in the size of the formula.
+*/
+func (formula AcceptanceFormula) DNF() []AcceptanceConjunct {
+	switch formula.Operator {
+	case AcceptTrue:
+		return []AcceptanceConjunct{{}}
+	case AcceptFalse:
+		return []AcceptanceConjunct{}
+	case AcceptInf:
+		return []AcceptanceConjunct{{Inf: [][]State{formula.Set}}}
+	case AcceptFin:
+		return []AcceptanceConjunct{{Fin: formula.Set}}
+	case AcceptOr:
+		var conjuncts []AcceptanceConjunct
+		for _, operand := range formula.Operands {
+			conjuncts = append(conjuncts, operand.DNF()...)
+		}
+		return conjuncts
+	case AcceptAnd:
+		conjuncts := []AcceptanceConjunct{{}}
+		for _, operand := range formula.Operands {
+			var nextConjuncts []AcceptanceConjunct
+			for _, left := range conjuncts {
+				for _, right := range operand.DNF() {
+					nextConjuncts = append(nextConjuncts, left.and(right))
+				}
+			}
+			conjuncts = nextConjuncts
+		}
+		return conjuncts
+	}
+
+	return []AcceptanceConjunct{}
+}
+
+/*
+and
+Description:
+	Computes the conjunction of two conjuncts.
+*/
+func (conjunct AcceptanceConjunct) and(conjunct2 AcceptanceConjunct) AcceptanceConjunct {
+	var fin []State
+	for _, q := range conjunct.Fin {
+		fin = q.AppendIfUniqueTo(fin)
+	}
+	for _, q := range conjunct2.Fin {
+		fin = q.AppendIfUniqueTo(fin)
+	}
+
+	var inf [][]State
+	inf = append(inf, conjunct.Inf...)
+	inf = append(inf, conjunct2.Inf...)
+
+	return AcceptanceConjunct{Fin: fin, Inf: inf}
+}
+
+/*
+Check
+Description:
+	Verifies that every set in the formula is a subset of Q.
+*/
+func (formula AcceptanceFormula) Check(Q []State) error {
+	switch formula.Operator {
+	case AcceptTrue, AcceptFalse:
+		return nil
+	case AcceptInf, AcceptFin:
+		return checkSubset(formula.Set, Q)
+	case AcceptAnd, AcceptOr:
+		for _, operand := range formula.Operands {
+			if err := operand.Check(Q); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Unexpected acceptance operator: %v", formula.Operator)
+}
+
+/*
+String
+Description:
+	Prints the formula, e.g. "(Fin({q0}) & Inf({q1}))".
+*/
+func (formula AcceptanceFormula) String() string {
+	switch formula.Operator {
+	case AcceptTrue:
+		return "t"
+	case AcceptFalse:
+		return "f"
+	case AcceptInf:
+		return fmt.Sprintf("Inf(%v)", setString(formula.Set))
+	case AcceptFin:
+		return fmt.Sprintf("Fin(%v)", setString(formula.Set))
+	}
+
+	var operandStrings []string
+	for _, operand := range formula.Operands {
+		operandStrings = append(operandStrings, operand.String())
+	}
+
+	if formula.Operator == AcceptAnd {
+		if len(operandStrings) == 0 {
+			return "t"
+		}
+		return fmt.Sprintf("(%v)", strings.Join(operandStrings, " & "))
+	}
+	if len(operandStrings) == 0 {
+		return "f"
+	}
+	return fmt.Sprintf("(%v)", strings.Join(operandStrings, " | "))
+}
+
+/*
+Functions for the Acceptance Conditions
+*/
+
+func (cond Buchi) IsSatisfiedBy(inf []State) bool {
+	return intersects(cond.F, inf)
+}
+
+func (cond Buchi) ToEmersonLei() EmersonLei {
+	return EmersonLei{Formula: Inf(cond.F)}
+}
+
+func (cond Buchi) Check(Q []State) error {
+	return checkSubset(cond.F, Q)
+}
+
+func (cond Buchi) String() string {
+	return fmt.Sprintf("Buchi(%v)", setString(cond.F))
+}
+
+func (cond GeneralizedBuchi) IsSatisfiedBy(inf []State) bool {
+	return cond.ToEmersonLei().IsSatisfiedBy(inf)
+}
+
+func (cond GeneralizedBuchi) ToEmersonLei() EmersonLei {
+	var operands []AcceptanceFormula
+	for _, F := range cond.F {
+		operands = append(operands, Inf(F))
+	}
+	return EmersonLei{Formula: And(operands...)}
+}
+
+func (cond GeneralizedBuchi) Check(Q []State) error {
+	for _, F := range cond.F {
+		if err := checkSubset(F, Q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cond GeneralizedBuchi) String() string {
+	var setStrings []string
+	for _, F := range cond.F {
+		setStrings = append(setStrings, setString(F))
+	}
+	return fmt.Sprintf("GeneralizedBuchi(%v)", strings.Join(setStrings, ", "))
+}
+
+func (cond CoBuchi) IsSatisfiedBy(inf []State) bool {
+	return !intersects(cond.F, inf)
+}
+
+func (cond CoBuchi) ToEmersonLei() EmersonLei {
+	return EmersonLei{Formula: Fin(cond.F)}
+}
+
+func (cond CoBuchi) Check(Q []State) error {
+	return checkSubset(cond.F, Q)
+}
+
+func (cond CoBuchi) String() string {
+	return fmt.Sprintf("CoBuchi(%v)", setString(cond.F))
+}
+
+func (cond Rabin) IsSatisfiedBy(inf []State) bool {
+	return cond.ToEmersonLei().IsSatisfiedBy(inf)
+}
+
+func (cond Rabin) ToEmersonLei() EmersonLei {
+	var operands []AcceptanceFormula
+	for _, pair := range cond.Pairs {
+		operands = append(operands, And(Fin(pair.E), Inf(pair.F)))
+	}
+	return EmersonLei{Formula: Or(operands...)}
+}
+
+func (cond Rabin) Check(Q []State) error {
+	return checkPairs(cond.Pairs, Q)
+}
+
+func (cond Rabin) String() string {
+	return fmt.Sprintf("Rabin(%v)", pairsString(cond.Pairs))
+}
+
+func (cond Streett) IsSatisfiedBy(inf []State) bool {
+	return cond.ToEmersonLei().IsSatisfiedBy(inf)
+}
+
+func (cond Streett) ToEmersonLei() EmersonLei {
+	var operands []AcceptanceFormula
+	for _, pair := range cond.Pairs {
+		operands = append(operands, Or(Inf(pair.E), Fin(pair.F)))
+	}
+	return EmersonLei{Formula: And(operands...)}
+}
+
+func (cond Streett) Check(Q []State) error {
+	return checkPairs(cond.Pairs, Q)
+}
+
+func (cond Streett) String() string {
+	return fmt.Sprintf("Streett(%v)", pairsString(cond.Pairs))
+}
+
+/*
+PriorityOf
+Description:
+	Returns the priority of the state q (0 if it has none).
+*/
+func (cond Parity) PriorityOf(q State) int {
+	if priority, found := cond.Priority[q]; found {
+		return priority
+	}
+	for tempState, priority := range cond.Priority {
+		if tempState.Equals(q) {
+			return priority
+		}
+	}
+	return 0
+}
+
+/*
+MaxPriority
+Description:
+	Returns the largest priority of the condition (0 if there are none).
+*/
+func (cond Parity) MaxPriority() int {
+	maxPriority := 0
+	for _, priority := range cond.Priority {
+		if priority > maxPriority {
+			maxPriority = priority
+		}
+	}
+	return maxPriority
+}
+
+/*
+StatesWithPriority
+Description:
+	Returns the states of Q whose priority satisfies the given test.
+*/
+func (cond Parity) StatesWithPriority(Q []State, test func(int) bool) []State {
+	var statesOut []State
+	for _, q := range Q {
+		if test(cond.PriorityOf(q)) {
+			statesOut = append(statesOut, q)
+		}
+	}
+	return statesOut
+}
+
+func (cond Parity) IsSatisfiedBy(inf []State) bool {
+	maxPriority := -1
+	for _, q := range inf {
+		if priority := cond.PriorityOf(q); priority > maxPriority {
+			maxPriority = priority
+		}
+	}
+	return maxPriority >= 0 && maxPriority%2 == 0
+}
+
+func (cond Parity) ToEmersonLei() EmersonLei {
+	var statesWithPriority []State
+	for q := range cond.Priority {
+		statesWithPriority = append(statesWithPriority, q)
+	}
+
+	// States without a priority have priority 0, so the disjunct for priority 0 only
+	// requires that every positive priority is visited finitely often.
+	var operands []AcceptanceFormula
+	for p := 0; p <= cond.MaxPriority(); p += 2 {
+		evenPriority := p
+		higherPriorities := Fin(cond.StatesWithPriority(statesWithPriority, func(priority int) bool { return priority > evenPriority }))
+		if evenPriority == 0 {
+			operands = append(operands, higherPriorities)
+			continue
+		}
+		operands = append(operands,
+			And(
+				Inf(cond.StatesWithPriority(statesWithPriority, func(priority int) bool { return priority == evenPriority })),
+				higherPriorities,
+			),
+		)
+	}
+
+	return EmersonLei{Formula: Or(operands...)}
+}
+
+func (cond Parity) Check(Q []State) error {
+	for q, priority := range cond.Priority {
+		if !q.In(Q) {
+			return fmt.Errorf("The state \"%v\" is not in the state space.", q)
+		}
+		if priority < 0 {
+			return fmt.Errorf("The state \"%v\" has a negative priority %v.", q, priority)
+		}
+	}
+	return nil
+}
+
+func (cond Parity) String() string {
+	var entries []string
+	for q, priority := range cond.Priority {
+		entries = append(entries, fmt.Sprintf("%v:%v", q, priority))
+	}
+	sort.Strings(entries)
+	return fmt.Sprintf("Parity({%v})", strings.Join(entries, ", "))
+}
+
+func (cond EmersonLei) IsSatisfiedBy(inf []State) bool {
+	return cond.Formula.IsSatisfiedBy(inf)
+}
+
+func (cond EmersonLei) ToEmersonLei() EmersonLei {
+	return cond
+}
+
+func (cond EmersonLei) Check(Q []State) error {
+	return cond.Formula.Check(Q)
+}
+
+func (cond EmersonLei) String() string {
+	return fmt.Sprintf("EmersonLei(%v)", cond.Formula)
+}
+
+/*
+Helper Functions
+*/
+
+/*
+intersects
+Description:
+	Determines if the two slices of states share a state.
+*/
+func intersects(set1, set2 []State) bool {
+	for _, q := range set1 {
+		if q.In(set2) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+checkSubset
+Description:
+	Returns an error if setIn is not a subset of Q.
+*/
+func checkSubset(setIn, Q []State) error {
+	for _, q := range setIn {
+		if !q.In(Q) {
+			return fmt.Errorf("The state \"%v\" is not in the state space.", q)
+		}
+	}
+	return nil
+}
+
+/*
+checkPairs
+Description:
+	Returns an error if one of the sets in the pairs is not a subset of Q.
+*/
+func checkPairs(pairs []AcceptancePair, Q []State) error {
+	for pairIndex, pair := range pairs {
+		if err := checkSubset(pair.E, Q); err != nil {
+			return fmt.Errorf("The %vth pair's first element is not a subset of the state space: %v", pairIndex, err)
+		}
+		if err := checkSubset(pair.F, Q); err != nil {
+			return fmt.Errorf("The %vth pair's second element is not a subset of the state space: %v", pairIndex, err)
+		}
+	}
+	return nil
+}
+
+/*
+setString
+Description:
+	Prints a set of states as {q0,q1}.
+*/
+func setString(setIn []State) string {
+	var names []string
+	for _, q := range setIn {
+		names = append(names, q.Name)
+	}
+	return fmt.Sprintf("{%v}", strings.Join(names, ","))
+}
+
+/*
+pairsString
+Description:
+	Prints a slice of pairs as ({E},{F}), ...
+*/
+func pairsString(pairs []AcceptancePair) string {
+	var pairStrings []string
+	for _, pair := range pairs {
+		pairStrings = append(pairStrings, fmt.Sprintf("(%v,%v)", setString(pair.E), setString(pair.F)))
+	}
+	return strings.Join(pairStrings, ", ")
+}
@@ -0,0 +1,110 @@
+/*
+acceptance_test.go
+Description:
+	Tests the functions and objects created in acceptance.go
+*/
+
+package omega
+
+import (
+	"testing"
+)
+
+/*
+TestAcceptance_IsSatisfiedBy1
+Description:
+	Evaluates each acceptance condition on the same set of infinitely visited states.
+*/
+func TestAcceptance_IsSatisfiedBy1(t *testing.T) {
+	// Constants
+	q0, q1, q2 := State{Name: "q0"}, State{Name: "q1"}, State{Name: "q2"}
+	inf := []State{q0, q1}
+
+	conditions := []AcceptanceCondition{
+		Buchi{F: []State{q1}},
+		GeneralizedBuchi{F: [][]State{{q0}, {q1, q2}}},
+		CoBuchi{F: []State{q2}},
+		Rabin{Pairs: []AcceptancePair{{E: []State{q0}, F: []State{q1}}, {E: []State{q2}, F: []State{q0}}}},
+		Streett{Pairs: []AcceptancePair{{E: []State{q0}, F: []State{q1}}}},
+		Parity{Priority: map[State]int{q0: 2, q1: 1, q2: 3}},
+	}
+
+	// Algorithm
+	for _, condition := range conditions {
+		if !condition.IsSatisfiedBy(inf) {
+			t.Errorf("Expected %v to be satisfied by %v.", condition, inf)
+		}
+		if !condition.ToEmersonLei().IsSatisfiedBy(inf) {
+			t.Errorf("Expected the Emerson-Lei form of %v to be satisfied by %v.", condition, inf)
+		}
+		if condition.ToEmersonLei().Formula.Negate().IsSatisfiedBy(inf) {
+			t.Errorf("Expected the negation of %v to be violated by %v.", condition, inf)
+		}
+	}
+}
+
+/*
+TestAcceptance_IsSatisfiedBy2
+Description:
+	Evaluates conditions which are violated by the set of infinitely visited states.
+*/
+func TestAcceptance_IsSatisfiedBy2(t *testing.T) {
+	// Constants
+	q0, q1, q2 := State{Name: "q0"}, State{Name: "q1"}, State{Name: "q2"}
+	inf := []State{q1, q2}
+
+	conditions := []AcceptanceCondition{
+		Buchi{F: []State{q0}},
+		CoBuchi{F: []State{q2}},
+		Rabin{Pairs: []AcceptancePair{{E: []State{q2}, F: []State{q1}}}},
+		Streett{Pairs: []AcceptancePair{{E: []State{q0}, F: []State{q1}}}},
+		Parity{Priority: map[State]int{q0: 4, q1: 2, q2: 3}},
+	}
+
+	// Algorithm
+	for _, condition := range conditions {
+		if condition.IsSatisfiedBy(inf) {
+			t.Errorf("Expected %v to be violated by %v.", condition, inf)
+		}
+		if condition.ToEmersonLei().IsSatisfiedBy(inf) {
+			t.Errorf("Expected the Emerson-Lei form of %v to be violated by %v.", condition, inf)
+		}
+	}
+}
+
+/*
+TestAcceptance_DNF1
+Description:
+	Verifies that a Streett condition with two pairs has four clauses in disjunctive normal form.
+*/
+func TestAcceptance_DNF1(t *testing.T) {
+	// Constants
+	q0, q1, q2, q3 := State{Name: "q0"}, State{Name: "q1"}, State{Name: "q2"}, State{Name: "q3"}
+	condition := Streett{Pairs: []AcceptancePair{{E: []State{q0}, F: []State{q1}}, {E: []State{q2}, F: []State{q3}}}}
+
+	// Algorithm
+	clauses := condition.ToEmersonLei().Formula.DNF()
+	if len(clauses) != 4 {
+		t.Errorf("Expected 4 clauses, but found %v.", len(clauses))
+	}
+
+	if len(clauses[0].Inf) != 2 || len(clauses[3].Fin) != 2 {
+		t.Errorf("Unexpected clauses: %v", clauses)
+	}
+}
+
+/*
+TestAcceptance_Check1
+Description:
+	Verifies that Check() finds a state outside of the state space.
+*/
+func TestAcceptance_Check1(t *testing.T) {
+	// Constants
+	Q := []State{{Name: "q0"}}
+	condition := Rabin{Pairs: []AcceptancePair{{E: []State{}, F: []State{{Name: "q1"}}}}}
+
+	// Algorithm
+	if condition.Check(Q) == nil {
+		t.Errorf("Expected Check() to identify that q1 is not in the state space.")
+	}
+}
@@ -0,0 +1,384 @@
+/*
+automaton.go
+Description:
+	Defines an omega-automaton whose acceptance condition is a pluggable AcceptanceCondition
+	(Buchi, generalized Buchi, co-Buchi, Rabin, Streett, parity or Emerson-Lei).
+	As in the adaptive package, the automaton reads one atomic proposition at a time.
+*/
+
+package omega
+
+import (
+	"errors"
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Automaton
+Description:
+	An omega-automaton with (possibly nondeterministic) transitions Delta and acceptance condition Acceptance.
+*/
+type Automaton struct {
+	Q          []State
+	Q0         []State
+	Alphabet   []mc.AtomicProposition
+	Delta      map[State]map[mc.AtomicProposition][]State
+	Acceptance AcceptanceCondition
+}
+
+/*
+State
+Description:
+	A state of an omega-automaton.
+*/
+type State struct {
+	Name      string
+	Automaton *Automaton
+}
+
+/*
+Functions for State
+*/
+
+/*
+String
+Description:
+	Provides the name of the state.
+*/
+func (stateIn State) String() string {
+	return stateIn.Name
+}
+
+/*
+Equals
+Description:
+	Returns true if the names of the two states are equal.
+*/
+func (stateIn State) Equals(state2 State) bool {
+	return stateIn.Name == state2.Name
+}
+
+/*
+In
+Description:
+	Determines whether or not the state is in a slice of states.
+*/
+func (stateIn State) In(stateList []State) bool {
+	for _, tempState := range stateList {
+		if stateIn.Equals(tempState) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+AppendIfUniqueTo
+Description:
+	Appends the state to sliceIn if and only if it is not already in sliceIn.
+*/
+func (stateIn State) AppendIfUniqueTo(sliceIn []State) []State {
+	if stateIn.In(sliceIn) {
+		return sliceIn
+	}
+	return append(sliceIn, stateIn)
+}
+
+/*
+Functions for Automaton
+*/
+
+/*
+GetAutomaton
+Description:
+	Creates an Automaton from simple strings and maps of strings.
+	The acceptance condition is left empty and should be set afterwards (see StatesNamed()).
+Usage:
+	a, err := GetAutomaton(
+		[]string{"q0", "q1"}, []string{"q0"}, []string{"a", "b"},
+		map[string]map[string][]string{"q0": {"a": {"q1"}}, "q1": {"b": {"q0"}}},
+	)
+	a.Acceptance = Buchi{F: a.StatesNamed("q1")}
+*/
+func GetAutomaton(stateNames []string, initialStateNames []string, alphabetNames []string, transitionMap map[string]map[string][]string) (Automaton, error) {
+	a := Automaton{}
+
+	// Create the state space
+	var Q []State
+	for _, stateName := range stateNames {
+		Q = append(Q, State{Name: stateName, Automaton: &a})
+	}
+	a.Q = Q
+
+	// Create the initial states
+	var Q0 []State
+	for _, stateName := range initialStateNames {
+		Q0 = append(Q0, State{Name: stateName, Automaton: &a})
+	}
+	a.Q0 = Q0
+
+	// Create the alphabet
+	a.Alphabet = mc.StringSliceToAPs(alphabetNames)
+
+	// Create the transitions
+	Delta := make(map[State]map[mc.AtomicProposition][]State)
+	for stateName, apMap := range transitionMap {
+		tempAPMap := make(map[mc.AtomicProposition][]State)
+		for apName, successorNames := range apMap {
+			var successors []State
+			for _, successorName := range successorNames {
+				successors = append(successors, State{Name: successorName, Automaton: &a})
+			}
+			tempAPMap[mc.AtomicProposition{Name: apName}] = successors
+		}
+		Delta[State{Name: stateName, Automaton: &a}] = tempAPMap
+	}
+	a.Delta = Delta
+
+	err := a.CheckQ0()
+	if err != nil {
+		return a, err
+	}
+
+	err = a.CheckDelta()
+	if err != nil {
+		return a, err
+	}
+
+	return a, nil
+}
+
+/*
+StatesNamed
+Description:
+	Returns the states of the automaton with the given names.
+	Names which do not belong to a state of the automaton are ignored.
+*/
+func (a Automaton) StatesNamed(names ...string) []State {
+	var statesOut []State
+	for _, name := range names {
+		for _, q := range a.Q {
+			if q.Name == name {
+				statesOut = append(statesOut, q)
+			}
+		}
+	}
+	return statesOut
+}
+
+/*
+CheckQ0
+Description:
+	Checks that every initial state is in the state space Q.
+*/
+func (a Automaton) CheckQ0() error {
+	for _, q0 := range a.Q0 {
+		if !q0.In(a.Q) {
+			return fmt.Errorf("The initial state \"%v\" was not in the state space Q.", q0)
+		}
+	}
+	return nil
+}
+
+/*
+CheckDelta
+Description:
+	Checks that the transition map only uses states from Q and symbols from the Alphabet.
+*/
+func (a Automaton) CheckDelta() error {
+	for q, apMap := range a.Delta {
+		if !q.In(a.Q) {
+			return fmt.Errorf("The state \"%v\" is not in the state space.", q)
+		}
+
+		for ap, successors := range apMap {
+			if !ap.In(a.Alphabet) {
+				return fmt.Errorf("The atomic proposition \"%v\" is not in the Alphabet.", ap)
+			}
+
+			for _, successor := range successors {
+				if !successor.In(a.Q) {
+					return fmt.Errorf("The state \"%v\" is not in the state space.", successor)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Check
+Description:
+	Checks the initial states, the transition map and the acceptance condition of the automaton.
+*/
+func (a Automaton) Check() error {
+	err := a.CheckQ0()
+	if err != nil {
+		return err
+	}
+
+	err = a.CheckDelta()
+	if err != nil {
+		return err
+	}
+
+	if a.Acceptance == nil {
+		return errors.New("The automaton does not have an acceptance condition.")
+	}
+
+	err = a.Acceptance.Check(a.Q)
+	if err != nil {
+		return fmt.Errorf("There was an issue checking the acceptance condition: %v", err)
+	}
+
+	return nil
+}
+
+/*
+Post
+Description:
+	Returns the successors of the state q under the symbol ap.
+*/
+func (a Automaton) Post(q State, ap mc.AtomicProposition) []State {
+	apMap, found := a.Delta[q]
+	if !found {
+		// Search by name, in case q points to a different copy of the automaton.
+		for tempState, tempAPMap := range a.Delta {
+			if tempState.Equals(q) {
+				apMap = tempAPMap
+			}
+		}
+	}
+
+	var successors []State
+	for _, successor := range apMap[ap] {
+		successors = successor.AppendIfUniqueTo(successors)
+	}
+	return successors
+}
+
+/*
+IsDeterministic
+Description:
+	Determines if the automaton has at most one initial state and at most one successor
+	for every state and symbol.
+*/
+func (a Automaton) IsDeterministic() bool {
+	if len(a.Q0) > 1 {
+		return false
+	}
+
+	for _, q := range a.Q {
+		for _, ap := range a.Alphabet {
+			if len(a.Post(q, ap)) > 1 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+/*
+IsComplete
+Description:
+	Determines if the automaton has an initial state and at least one successor for every state and symbol.
+*/
+func (a Automaton) IsComplete() bool {
+	if len(a.Q0) == 0 {
+		return false
+	}
+
+	for _, q := range a.Q {
+		for _, ap := range a.Alphabet {
+			if len(a.Post(q, ap)) == 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+/*
+AcceptsLasso
+Description:
+	Determines if a deterministic automaton accepts the infinite word prefix (cycle)^omega.
+	A word which leads to a missing transition is rejected.
+*/
+func (a Automaton) AcceptsLasso(prefix []mc.AtomicProposition, cycle []mc.AtomicProposition) (bool, error) {
+	// Input Processing
+	if !a.IsDeterministic() {
+		return false, errors.New("AcceptsLasso() requires a deterministic automaton.")
+	}
+
+	if len(cycle) == 0 {
+		return false, errors.New("The cycle of the lasso must contain at least one symbol.")
+	}
+
+	if a.Acceptance == nil {
+		return false, errors.New("The automaton does not have an acceptance condition.")
+	}
+
+	if len(a.Q0) == 0 {
+		return false, nil
+	}
+
+	// Read the prefix
+	q := a.Q0[0]
+	for _, ap := range prefix {
+		successors := a.Post(q, ap)
+		if len(successors) == 0 {
+			return false, nil
+		}
+		q = successors[0]
+	}
+
+	// Read the cycle until the state at the start of the cycle repeats
+	var cycleStarts []State
+	var visitsAfterStart [][]State
+	for {
+		if found, startIndex := findState(q, cycleStarts); found {
+			// Every state visited since the first visit of q is visited infinitely often.
+			var inf []State
+			for _, visits := range visitsAfterStart[startIndex:] {
+				for _, visited := range visits {
+					inf = visited.AppendIfUniqueTo(inf)
+				}
+			}
+			return a.Acceptance.IsSatisfiedBy(inf), nil
+		}
+
+		cycleStarts = append(cycleStarts, q)
+		var visits []State
+		for _, ap := range cycle {
+			visits = append(visits, q)
+			successors := a.Post(q, ap)
+			if len(successors) == 0 {
+				return false, nil
+			}
+			q = successors[0]
+		}
+		visitsAfterStart = append(visitsAfterStart, visits)
+	}
+}
+
+/*
+findState
+Description:
+	Returns true and the first index of q in stateList if it exists, and false and -1 otherwise.
+*/
+func findState(q State, stateList []State) (bool, int) {
+	for stateIndex, tempState := range stateList {
+		if q.Equals(tempState) {
+			return true, stateIndex
+		}
+	}
+	return false, -1
+}
@@ -0,0 +1,158 @@
+/*
+automaton_test.go
+Description:
+	Tests the functions and objects created in automaton.go
+*/
+
+package omega
+
+import (
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+GetInfinitelyManyAAndB
+Description:
+	Creates a deterministic generalized Buchi automaton over {a,b} which remembers the last symbol read.
+	It accepts the words with infinitely many a's and infinitely many b's.
+*/
+func GetInfinitelyManyAAndB() Automaton {
+	a, _ := GetAutomaton(
+		[]string{"init", "sawA", "sawB"}, []string{"init"}, []string{"a", "b"},
+		map[string]map[string][]string{
+			"init": {"a": {"sawA"}, "b": {"sawB"}},
+			"sawA": {"a": {"sawA"}, "b": {"sawB"}},
+			"sawB": {"a": {"sawA"}, "b": {"sawB"}},
+		},
+	)
+	a.Acceptance = GeneralizedBuchi{F: [][]State{a.StatesNamed("sawA"), a.StatesNamed("sawB")}}
+
+	return a
+}
+
+/*
+GetEventuallyAlwaysA
+Description:
+	Creates a deterministic Rabin automaton over {a,b} which accepts the words with finitely many b's.
+*/
+func GetEventuallyAlwaysA() Automaton {
+	a, _ := GetAutomaton(
+		[]string{"sawA", "sawB"}, []string{"sawA"}, []string{"a", "b"},
+		map[string]map[string][]string{
+			"sawA": {"a": {"sawA"}, "b": {"sawB"}},
+			"sawB": {"a": {"sawA"}, "b": {"sawB"}},
+		},
+	)
+	a.Acceptance = Rabin{Pairs: []AcceptancePair{{E: a.StatesNamed("sawB"), F: a.StatesNamed("sawA")}}}
+
+	return a
+}
+
+/*
+lassoWords
+Description:
+	Enumerates every lasso word over the alphabet with a prefix of length at most maxPrefix
+	and a cycle of length between 1 and maxCycle.
+*/
+func lassoWords(alphabet []mc.AtomicProposition, maxPrefix int, maxCycle int) [][2][]mc.AtomicProposition {
+	words := [][]mc.AtomicProposition{{}}
+	var allWords [][]mc.AtomicProposition
+	allWords = append(allWords, words...)
+	for length := 1; length <= maxPrefix || length <= maxCycle; length++ {
+		var nextWords [][]mc.AtomicProposition
+		for _, word := range words {
+			for _, ap := range alphabet {
+				nextWords = append(nextWords, append(append([]mc.AtomicProposition{}, word...), ap))
+			}
+		}
+		words = nextWords
+		allWords = append(allWords, words...)
+	}
+
+	var lassos [][2][]mc.AtomicProposition
+	for _, prefix := range allWords {
+		for _, cycle := range allWords {
+			if len(prefix) <= maxPrefix && len(cycle) >= 1 && len(cycle) <= maxCycle {
+				lassos = append(lassos, [2][]mc.AtomicProposition{prefix, cycle})
+			}
+		}
+	}
+	return lassos
+}
+
+/*
+TestAutomaton_GetAutomaton1
+Description:
+	Verifies that GetAutomaton() catches a transition into a state that does not exist.
+*/
+func TestAutomaton_GetAutomaton1(t *testing.T) {
+	// Algorithm
+	_, err := GetAutomaton(
+		[]string{"q0"}, []string{"q0"}, []string{"a"},
+		map[string]map[string][]string{
+			"q0": {"a": {"q1"}},
+		},
+	)
+	if err == nil {
+		t.Errorf("Expected an error for the missing state q1.")
+	}
+}
+
+/*
+TestAutomaton_IsDeterministic1
+Description:
+	Verifies that a nondeterministic transition is identified.
+*/
+func TestAutomaton_IsDeterministic1(t *testing.T) {
+	// Constants
+	a, err := GetAutomaton(
+		[]string{"q0", "q1"}, []string{"q0"}, []string{"a"},
+		map[string]map[string][]string{
+			"q0": {"a": {"q0", "q1"}},
+		},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Algorithm
+	if a.IsDeterministic() {
+		t.Errorf("Expected the automaton to be nondeterministic.")
+	}
+
+	if a.IsComplete() {
+		t.Errorf("Expected the automaton to be incomplete, since q1 has no successors.")
+	}
+
+	if !GetEventuallyAlwaysA().IsDeterministic() {
+		t.Errorf("Expected GetEventuallyAlwaysA() to be deterministic.")
+	}
+}
+
+/*
+TestAutomaton_AcceptsLasso1
+Description:
+	Verifies AcceptsLasso() for the automaton which accepts finitely many b's.
+*/
+func TestAutomaton_AcceptsLasso1(t *testing.T) {
+	// Constants
+	a := GetEventuallyAlwaysA()
+	apA := mc.AtomicProposition{Name: "a"}
+	apB := mc.AtomicProposition{Name: "b"}
+
+	// Algorithm
+	tf, err := a.AcceptsLasso([]mc.AtomicProposition{apB, apB}, []mc.AtomicProposition{apA})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !tf {
+		t.Errorf("Expected b b (a)^omega to be accepted.")
+	}
+
+	tf, _ = a.AcceptsLasso([]mc.AtomicProposition{}, []mc.AtomicProposition{apA, apB})
+	if tf {
+		t.Errorf("Expected (a b)^omega to be rejected.")
+	}
+}
@@ -0,0 +1,423 @@
+/*
+conversion.go
+Description:
+	Conversions between the acceptance conditions of omega-automata.
+	- ToRabin() and ToStreett() work for every acceptance condition by rewriting it as an Emerson-Lei
+	  condition in disjunctive (resp. conjunctive) normal form and tracking the Inf sets of each clause with a counter.
+	- ToParity() converts a Rabin automaton into a parity automaton with index appearance records.
+	- Degeneralize() converts a generalized Buchi automaton into a Buchi automaton.
+	All conversions preserve determinism.
+*/
+
+package omega
+
+import (
+	"errors"
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+ToRabin
+Description:
+	Creates an automaton with a Rabin acceptance condition that accepts the same language.
+	If every clause of the acceptance condition's disjunctive normal form has at most one Inf set
+	(e.g. Buchi, co-Buchi, Rabin and parity conditions) then the states of the automaton are unchanged.
+	Otherwise (e.g. Streett or generalized Buchi conditions) the states are paired with counters,
+	which can grow the automaton exponentially in the number of Streett pairs.
+*/
+func (a Automaton) ToRabin() (Automaton, error) {
+	err := a.Check()
+	if err != nil {
+		return Automaton{}, fmt.Errorf("There was an issue checking the automaton: %v", err)
+	}
+
+	product, pairs := a.withClauseCounters(a.Acceptance.ToEmersonLei().Formula.DNF())
+	product.Acceptance = Rabin{Pairs: pairs}
+
+	return *product, nil
+}
+
+/*
+ToStreett
+Description:
+	Creates an automaton with a Streett acceptance condition that accepts the same language.
+	This works like ToRabin() on the conjunctive normal form of the acceptance condition.
+*/
+func (a Automaton) ToStreett() (Automaton, error) {
+	err := a.Check()
+	if err != nil {
+		return Automaton{}, fmt.Errorf("There was an issue checking the automaton: %v", err)
+	}
+
+	// The Rabin pairs of the negated condition are the Streett pairs of the condition.
+	product, pairs := a.withClauseCounters(a.Acceptance.ToEmersonLei().Formula.Negate().DNF())
+	product.Acceptance = Streett{Pairs: pairs}
+
+	return *product, nil
+}
+
+/*
+ToParity
+Description:
+	Creates an automaton with a (max even) Parity acceptance condition that accepts the same language.
+	Automata that do not have a Rabin condition are converted with ToRabin() first.
+	The Rabin automaton is then converted with index appearance records: each state is paired with
+	a permutation of the pair indices, where the pairs whose E set was visited most recently come first.
+*/
+func (a Automaton) ToParity() (Automaton, error) {
+	err := a.Check()
+	if err != nil {
+		return Automaton{}, fmt.Errorf("There was an issue checking the automaton: %v", err)
+	}
+
+	rabinAutomaton := a
+	if _, isRabin := a.Acceptance.(Rabin); !isRabin {
+		rabinAutomaton, err = a.ToRabin()
+		if err != nil {
+			return Automaton{}, err
+		}
+	}
+	pairs := rabinAutomaton.Acceptance.(Rabin).Pairs
+
+	// Create the initial records
+	identity := make([]int, len(pairs))
+	for pairIndex := range identity {
+		identity[pairIndex] = pairIndex
+	}
+
+	b := newAutomatonBuilder(rabinAutomaton.Alphabet)
+	var queue []iarState
+	for _, q0 := range rabinAutomaton.Q0 {
+		initial := iarState{q: q0, record: identity}
+		if b.AddState(initial.Name(), true) {
+			queue = append(queue, initial)
+		}
+	}
+
+	priorities := make(map[string]int)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		priorities[current.Name()] = current.Priority(pairs)
+		nextRecord := current.NextRecord(pairs)
+
+		for _, ap := range rabinAutomaton.Alphabet {
+			for _, successor := range rabinAutomaton.Post(current.q, ap) {
+				next := iarState{q: successor, record: nextRecord}
+				if b.AddState(next.Name(), false) {
+					queue = append(queue, next)
+				}
+				b.AddTransition(current.Name(), ap, next.Name())
+			}
+		}
+	}
+
+	parityAutomaton := b.Build()
+	priorityMap := make(map[State]int)
+	for _, q := range parityAutomaton.Q {
+		priorityMap[q] = priorities[q.Name]
+	}
+	parityAutomaton.Acceptance = Parity{Priority: priorityMap}
+
+	return *parityAutomaton, nil
+}
+
+/*
+Degeneralize
+Description:
+	Creates a Buchi automaton that accepts the same language as a Buchi or generalized Buchi automaton.
+*/
+func (a Automaton) Degeneralize() (Automaton, error) {
+	switch a.Acceptance.(type) {
+	case Buchi, GeneralizedBuchi:
+	default:
+		return Automaton{}, fmt.Errorf("Degeneralize() expects a Buchi or generalized Buchi automaton, but the acceptance condition is %v.", a.Acceptance)
+	}
+
+	rabinAutomaton, err := a.ToRabin()
+	if err != nil {
+		return Automaton{}, err
+	}
+
+	pairs := rabinAutomaton.Acceptance.(Rabin).Pairs
+	if len(pairs) != 1 || len(pairs[0].E) != 0 {
+		return Automaton{}, errors.New("The generalized Buchi condition did not produce a single Rabin pair with an empty E set.")
+	}
+	rabinAutomaton.Acceptance = Buchi{F: pairs[0].F}
+
+	return rabinAutomaton, nil
+}
+
+/*
+withClauseCounters
+Description:
+	Creates a copy of the automaton in which every clause with two or more Inf sets has a counter.
+	The counter of a clause waits for the Inf set with its index and advances (cyclically) when the run
+	leaves a state in that set, so the counter wraps around infinitely often if and only if every Inf set of the
+	clause is visited infinitely often. The returned pairs (E,F) satisfy: the run satisfies clause i if and only if
+	it visits E_i finitely often and F_i infinitely often.
+*/
+func (a Automaton) withClauseCounters(clauses []AcceptanceConjunct) (*Automaton, []AcceptancePair) {
+	// Identify the clauses which need a counter
+	var countedClauses []int
+	for clauseIndex, clause := range clauses {
+		if len(clause.Inf) > 1 {
+			countedClauses = append(countedClauses, clauseIndex)
+		}
+	}
+
+	// Explore the product of the automaton with the counters
+	b := newAutomatonBuilder(a.Alphabet)
+	var queue []counterState
+	for _, q0 := range a.Q0 {
+		initial := counterState{q: q0, counters: make([]int, len(countedClauses))}
+		if b.AddState(initial.Name(), true) {
+			queue = append(queue, initial)
+		}
+	}
+	// Keep the unreachable states when no counters are needed, so that the state space is unchanged.
+	if len(countedClauses) == 0 {
+		for _, q := range a.Q {
+			b.AddState(q.Name, false)
+		}
+		queue = queue[:0]
+		for _, q := range a.Q {
+			queue = append(queue, counterState{q: q})
+		}
+	}
+
+	explored := make(map[string]counterState)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		explored[current.Name()] = current
+
+		// Advance every counter whose current Inf set contains the state that is being left.
+		nextCounters := make([]int, len(countedClauses))
+		for counterIndex, clauseIndex := range countedClauses {
+			infSets := clauses[clauseIndex].Inf
+			nextCounters[counterIndex] = current.counters[counterIndex]
+			if current.q.In(infSets[current.counters[counterIndex]]) {
+				nextCounters[counterIndex] = (current.counters[counterIndex] + 1) % len(infSets)
+			}
+		}
+
+		for _, ap := range a.Alphabet {
+			for _, successor := range a.Post(current.q, ap) {
+				next := counterState{q: successor, counters: nextCounters}
+				if b.AddState(next.Name(), false) {
+					queue = append(queue, next)
+				}
+				b.AddTransition(current.Name(), ap, next.Name())
+			}
+		}
+	}
+
+	product := b.Build()
+
+	// Create the pairs
+	var pairs []AcceptancePair
+	for clauseIndex, clause := range clauses {
+		counterIndex := -1
+		for tempIndex, tempClauseIndex := range countedClauses {
+			if tempClauseIndex == clauseIndex {
+				counterIndex = tempIndex
+			}
+		}
+
+		var pair AcceptancePair
+		for _, productState := range product.Q {
+			current := explored[productState.Name]
+			if current.q.In(clause.Fin) {
+				pair.E = append(pair.E, productState)
+			}
+
+			switch {
+			case len(clause.Inf) == 0:
+				pair.F = append(pair.F, productState)
+			case counterIndex < 0:
+				if current.q.In(clause.Inf[0]) {
+					pair.F = append(pair.F, productState)
+				}
+			default:
+				lastIndex := len(clause.Inf) - 1
+				if current.counters[counterIndex] == lastIndex && current.q.In(clause.Inf[lastIndex]) {
+					pair.F = append(pair.F, productState)
+				}
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return product, pairs
+}
+
+/*
+counterState
+Description:
+	A state of the automaton paired with the values of the clause counters.
+*/
+type counterState struct {
+	q        State
+	counters []int
+}
+
+/*
+Name
+Description:
+	Names the product state "(q,[c1 c2])", or "q" if there are no counters.
+*/
+func (cs counterState) Name() string {
+	if len(cs.counters) == 0 {
+		return cs.q.Name
+	}
+	return fmt.Sprintf("(%v,%v)", cs.q, cs.counters)
+}
+
+/*
+iarState
+Description:
+	A state of the Rabin automaton paired with an index appearance record.
+	The record is the permutation of pair indices before the state q is processed.
+*/
+type iarState struct {
+	q      State
+	record []int
+}
+
+/*
+Name
+Description:
+	Names the state "(q,[i1 i2 ...])".
+*/
+func (is iarState) Name() string {
+	return fmt.Sprintf("(%v,%v)", is.q, is.record)
+}
+
+/*
+Priority
+Description:
+	Computes the priority of the state. Let e (resp. f) be the largest (1-based) position in the record
+	of a pair whose E (resp. F) set contains q, or 0 if there is none.
+	The priority is 2f if f > e and 2e+1 otherwise.
+*/
+func (is iarState) Priority(pairs []AcceptancePair) int {
+	e, f := 0, 0
+	for position, pairIndex := range is.record {
+		if is.q.In(pairs[pairIndex].E) {
+			e = position + 1
+		}
+		if is.q.In(pairs[pairIndex].F) {
+			f = position + 1
+		}
+	}
+
+	if f > e {
+		return 2 * f
+	}
+	return 2*e + 1
+}
+
+/*
+NextRecord
+Description:
+	Moves the pairs whose E set contains q to the front of the record.
+*/
+func (is iarState) NextRecord(pairs []AcceptancePair) []int {
+	var front, back []int
+	for _, pairIndex := range is.record {
+		if is.q.In(pairs[pairIndex].E) {
+			front = append(front, pairIndex)
+		} else {
+			back = append(back, pairIndex)
+		}
+	}
+	return append(front, back...)
+}
+
+/*
+automatonBuilder
+Description:
+	Collects states and transitions by name and then creates an Automaton.
+*/
+type automatonBuilder struct {
+	alphabet     []mc.AtomicProposition
+	stateNames   []string
+	initialNames []string
+	seen         map[string]bool
+	transitions  map[string]map[mc.AtomicProposition][]string
+}
+
+func newAutomatonBuilder(alphabet []mc.AtomicProposition) *automatonBuilder {
+	return &automatonBuilder{
+		alphabet:    alphabet,
+		seen:        make(map[string]bool),
+		transitions: make(map[string]map[mc.AtomicProposition][]string),
+	}
+}
+
+/*
+AddState
+Description:
+	Adds the state if it is new and returns true if it was added.
+*/
+func (b *automatonBuilder) AddState(name string, initial bool) bool {
+	if initial {
+		b.initialNames = append(b.initialNames, name)
+	}
+	if b.seen[name] {
+		return false
+	}
+	b.seen[name] = true
+	b.stateNames = append(b.stateNames, name)
+	return true
+}
+
+/*
+AddTransition
+Description:
+	Adds the transition from -ap-> to.
+*/
+func (b *automatonBuilder) AddTransition(from string, ap mc.AtomicProposition, to string) {
+	if _, found := b.transitions[from]; !found {
+		b.transitions[from] = make(map[mc.AtomicProposition][]string)
+	}
+	for _, existing := range b.transitions[from][ap] {
+		if existing == to {
+			return
+		}
+	}
+	b.transitions[from][ap] = append(b.transitions[from][ap], to)
+}
+
+/*
+Build
+Description:
+	Creates the automaton. The acceptance condition is left empty.
+*/
+func (b *automatonBuilder) Build() *Automaton {
+	a := &Automaton{Alphabet: b.alphabet}
+
+	for _, name := range b.stateNames {
+		a.Q = append(a.Q, State{Name: name, Automaton: a})
+	}
+	for _, name := range b.initialNames {
+		a.Q0 = append(a.Q0, State{Name: name, Automaton: a})
+	}
+
+	a.Delta = make(map[State]map[mc.AtomicProposition][]State)
+	for from, apMap := range b.transitions {
+		tempAPMap := make(map[mc.AtomicProposition][]State)
+		for ap, targets := range apMap {
+			for _, to := range targets {
+				tempAPMap[ap] = append(tempAPMap[ap], State{Name: to, Automaton: a})
+			}
+		}
+		a.Delta[State{Name: from, Automaton: a}] = tempAPMap
+	}
+
+	return a
+}
@@ -0,0 +1,203 @@
+/*
+conversion_test.go
+Description:
+	Tests the functions created in conversion.go by comparing the automata on every short lasso word.
+*/
+
+package omega
+
+import (
+	"testing"
+)
+
+/*
+GetTwoPairStreett
+Description:
+	Creates a deterministic Streett automaton over {a,b,c} which remembers the last symbol read.
+	It accepts the words where infinitely many a's imply infinitely many b's and
+	infinitely many b's imply infinitely many c's.
+*/
+func GetTwoPairStreett() Automaton {
+	a, _ := GetAutomaton(
+		[]string{"sawA", "sawB", "sawC"}, []string{"sawA"}, []string{"a", "b", "c"},
+		map[string]map[string][]string{
+			"sawA": {"a": {"sawA"}, "b": {"sawB"}, "c": {"sawC"}},
+			"sawB": {"a": {"sawA"}, "b": {"sawB"}, "c": {"sawC"}},
+			"sawC": {"a": {"sawA"}, "b": {"sawB"}, "c": {"sawC"}},
+		},
+	)
+	a.Acceptance = Streett{Pairs: []AcceptancePair{
+		{E: a.StatesNamed("sawB"), F: a.StatesNamed("sawA")},
+		{E: a.StatesNamed("sawC"), F: a.StatesNamed("sawB")},
+	}}
+
+	return a
+}
+
+/*
+compareOnLassos
+Description:
+	Reports an error for every short lasso word on which the two automata disagree.
+*/
+func compareOnLassos(t *testing.T, a1 Automaton, a2 Automaton) {
+	for _, lasso := range lassoWords(a1.Alphabet, 2, 3) {
+		tf1, err1 := a1.AcceptsLasso(lasso[0], lasso[1])
+		tf2, err2 := a2.AcceptsLasso(lasso[0], lasso[1])
+		if err1 != nil || err2 != nil {
+			t.Errorf("Unexpected errors: %v, %v", err1, err2)
+			return
+		}
+		if tf1 != tf2 {
+			t.Errorf("The automata disagree on %v (%v)^omega: %v vs. %v.", lasso[0], lasso[1], tf1, tf2)
+		}
+	}
+}
+
+/*
+TestConversion_ToRabin1
+Description:
+	Converts a two-pair Streett automaton into a Rabin automaton.
+*/
+func TestConversion_ToRabin1(t *testing.T) {
+	// Constants
+	a := GetTwoPairStreett()
+
+	// Algorithm
+	rabinAutomaton, err := a.ToRabin()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, ok := rabinAutomaton.Acceptance.(Rabin); !ok {
+		t.Errorf("Expected a Rabin condition, but found %v.", rabinAutomaton.Acceptance)
+	}
+
+	if !rabinAutomaton.IsDeterministic() {
+		t.Errorf("Expected the Rabin automaton to be deterministic.")
+	}
+
+	compareOnLassos(t, a, rabinAutomaton)
+}
+
+/*
+TestConversion_ToRabin2
+Description:
+	Converts a Rabin automaton into a Rabin automaton; the states should not change.
+*/
+func TestConversion_ToRabin2(t *testing.T) {
+	// Constants
+	a := GetEventuallyAlwaysA()
+
+	// Algorithm
+	rabinAutomaton, err := a.ToRabin()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(rabinAutomaton.Q) != len(a.Q) {
+		t.Errorf("Expected %v states, but found %v.", len(a.Q), len(rabinAutomaton.Q))
+	}
+
+	compareOnLassos(t, a, rabinAutomaton)
+}
+
+/*
+TestConversion_ToStreett1
+Description:
+	Converts a Rabin automaton into a Streett automaton.
+*/
+func TestConversion_ToStreett1(t *testing.T) {
+	// Constants
+	a := GetEventuallyAlwaysA()
+
+	// Algorithm
+	streettAutomaton, err := a.ToStreett()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, ok := streettAutomaton.Acceptance.(Streett); !ok {
+		t.Errorf("Expected a Streett condition, but found %v.", streettAutomaton.Acceptance)
+	}
+
+	compareOnLassos(t, a, streettAutomaton)
+}
+
+/*
+TestConversion_ToParity1
+Description:
+	Converts a Rabin automaton into a parity automaton.
+*/
+func TestConversion_ToParity1(t *testing.T) {
+	// Constants
+	a := GetEventuallyAlwaysA()
+
+	// Algorithm
+	parityAutomaton, err := a.ToParity()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, ok := parityAutomaton.Acceptance.(Parity); !ok {
+		t.Errorf("Expected a parity condition, but found %v.", parityAutomaton.Acceptance)
+	}
+
+	compareOnLassos(t, a, parityAutomaton)
+}
+
+/*
+TestConversion_ToParity2
+Description:
+	Converts a two-pair Streett automaton into a parity automaton (through a Rabin automaton).
+*/
+func TestConversion_ToParity2(t *testing.T) {
+	// Constants
+	a := GetTwoPairStreett()
+
+	// Algorithm
+	parityAutomaton, err := a.ToParity()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !parityAutomaton.IsDeterministic() {
+		t.Errorf("Expected the parity automaton to be deterministic.")
+	}
+
+	compareOnLassos(t, a, parityAutomaton)
+}
+
+/*
+TestConversion_Degeneralize1
+Description:
+	Converts a generalized Buchi automaton into a Buchi automaton.
+*/
+func TestConversion_Degeneralize1(t *testing.T) {
+	// Constants
+	a := GetInfinitelyManyAAndB()
+
+	// Algorithm
+	buchiAutomaton, err := a.Degeneralize()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, ok := buchiAutomaton.Acceptance.(Buchi); !ok {
+		t.Errorf("Expected a Buchi condition, but found %v.", buchiAutomaton.Acceptance)
+	}
+
+	compareOnLassos(t, a, buchiAutomaton)
+}
+
+/*
+TestConversion_Degeneralize2
+Description:
+	Verifies that Degeneralize() rejects a Rabin automaton.
+*/
+func TestConversion_Degeneralize2(t *testing.T) {
+	// Algorithm
+	_, err := GetEventuallyAlwaysA().Degeneralize()
+	if err == nil {
+		t.Errorf("Expected an error when degeneralizing a Rabin automaton.")
+	}
+}
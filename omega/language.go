@@ -0,0 +1,671 @@
+/*
+language.go
+Description:
+	Language-level operations on omega-automata: emptiness checking with lasso witnesses,
+	completion, complementation of deterministic automata, intersection and language equivalence.
+*/
+
+package omega
+
+import (
+	"errors"
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/internal/graph"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Lasso
+Description:
+	An ultimately periodic word Prefix (Cycle)^omega together with the run of the automaton that reads it.
+	PrefixStates[i] is the state before reading Prefix[i] and CycleStates[i] is the state before reading Cycle[i].
+*/
+type Lasso struct {
+	Prefix       []mc.AtomicProposition
+	Cycle        []mc.AtomicProposition
+	PrefixStates []State
+	CycleStates  []State
+}
+
+/*
+Constants
+*/
+
+const (
+	SinkStateName = "sink"
+)
+
+/*
+Functions
+*/
+
+/*
+String
+Description:
+	Prints the lasso as prefix (cycle)^omega.
+*/
+func (lasso Lasso) String() string {
+	return fmt.Sprintf("%v (%v)^omega", lasso.Prefix, lasso.Cycle)
+}
+
+/*
+IsEmpty
+Description:
+	Determines if the automaton accepts no words. If the language is not empty, then the returned
+	Lasso is an accepted word together with its accepting run.
+	The acceptance condition is rewritten in disjunctive normal form, and for each clause the algorithm
+	searches for a reachable strongly connected component that avoids the clause's Fin states and meets every Inf set.
+Usage:
+	isEmpty, witness, err := a.IsEmpty()
+*/
+func (a Automaton) IsEmpty() (bool, Lasso, error) {
+	err := a.Check()
+	if err != nil {
+		return false, Lasso{}, fmt.Errorf("There was an issue checking the automaton: %v", err)
+	}
+
+	reachable := a.Reachable()
+	for _, clause := range a.Acceptance.ToEmersonLei().Formula.DNF() {
+		// Remove the states that must be visited finitely often
+		var allowed []State
+		for _, q := range reachable {
+			if !q.In(clause.Fin) {
+				allowed = append(allowed, q)
+			}
+		}
+
+		for _, component := range a.stronglyConnectedComponents(allowed) {
+			if !a.isNontrivial(component) {
+				continue
+			}
+
+			meetsAll := true
+			for _, infSet := range clause.Inf {
+				if !intersects(component, infSet) {
+					meetsAll = false
+				}
+			}
+
+			if meetsAll {
+				return false, a.lassoThrough(component, clause.Inf), nil
+			}
+		}
+	}
+
+	return true, Lasso{}, nil
+}
+
+/*
+Reachable
+Description:
+	Returns the states that are reachable from the initial states, in breadth-first order.
+*/
+func (a Automaton) Reachable() []State {
+	var reachable []State
+	for _, q0 := range a.Q0 {
+		reachable = q0.AppendIfUniqueTo(reachable)
+	}
+
+	for index := 0; index < len(reachable); index++ {
+		for _, ap := range a.Alphabet {
+			for _, successor := range a.Post(reachable[index], ap) {
+				reachable = successor.AppendIfUniqueTo(reachable)
+			}
+		}
+	}
+
+	return reachable
+}
+
+/*
+RemoveUnreachable
+Description:
+	Creates a copy of the automaton without the states that are unreachable from the initial states.
+*/
+func (a Automaton) RemoveUnreachable() Automaton {
+	reachable := a.Reachable()
+
+	b := newAutomatonBuilder(a.Alphabet)
+	for _, q0 := range a.Q0 {
+		b.AddState(q0.Name, true)
+	}
+	for _, q := range reachable {
+		b.AddState(q.Name, false)
+		for _, ap := range a.Alphabet {
+			for _, successor := range a.Post(q, ap) {
+				b.AddTransition(q.Name, ap, successor.Name)
+			}
+		}
+	}
+
+	aOut := b.Build()
+	if a.Acceptance != nil {
+		aOut.Acceptance = restrictAcceptance(a.Acceptance, aOut.Q)
+	}
+
+	return *aOut
+}
+
+/*
+Complete
+Description:
+	Creates a copy of the automaton with a rejecting sink state, so that every state has a successor
+	for every symbol of the alphabet. If the automaton is already complete, it is returned unchanged.
+*/
+func (a Automaton) Complete() (Automaton, error) {
+	err := a.Check()
+	if err != nil {
+		return Automaton{}, fmt.Errorf("There was an issue checking the automaton: %v", err)
+	}
+
+	if a.IsComplete() {
+		return a, nil
+	}
+
+	if len(a.StatesNamed(SinkStateName)) > 0 {
+		return Automaton{}, fmt.Errorf("The automaton already has a state named \"%v\".", SinkStateName)
+	}
+
+	b := newAutomatonBuilder(a.Alphabet)
+	for _, q0 := range a.Q0 {
+		b.AddState(q0.Name, true)
+	}
+	if len(a.Q0) == 0 {
+		b.AddState(SinkStateName, true)
+	}
+	for _, q := range a.Q {
+		b.AddState(q.Name, false)
+	}
+	b.AddState(SinkStateName, false)
+
+	for _, q := range append(append([]State{}, a.Q...), State{Name: SinkStateName}) {
+		for _, ap := range a.Alphabet {
+			successors := a.Post(q, ap)
+			if len(successors) == 0 {
+				b.AddTransition(q.Name, ap, SinkStateName)
+			}
+			for _, successor := range successors {
+				b.AddTransition(q.Name, ap, successor.Name)
+			}
+		}
+	}
+
+	aOut := b.Build()
+	sink := aOut.StatesNamed(SinkStateName)
+	var withoutSink []State
+	for _, q := range aOut.Q {
+		if !q.In(sink) {
+			withoutSink = append(withoutSink, q)
+		}
+	}
+
+	// Make sure that the runs which end in the sink are rejected.
+	switch cond := restrictAcceptance(a.Acceptance, aOut.Q).(type) {
+	case Buchi:
+		aOut.Acceptance = cond
+	case GeneralizedBuchi:
+		if len(cond.F) == 0 {
+			cond.F = [][]State{withoutSink}
+		}
+		aOut.Acceptance = cond
+	case CoBuchi:
+		aOut.Acceptance = CoBuchi{F: append(cond.F, sink...)}
+	case Rabin:
+		var pairs []AcceptancePair
+		for _, pair := range cond.Pairs {
+			pairs = append(pairs, AcceptancePair{E: append(append([]State{}, pair.E...), sink...), F: pair.F})
+		}
+		aOut.Acceptance = Rabin{Pairs: pairs}
+	case Streett:
+		aOut.Acceptance = Streett{Pairs: append(append([]AcceptancePair{}, cond.Pairs...), AcceptancePair{E: []State{}, F: sink})}
+	case Parity:
+		priorityMap := make(map[State]int)
+		for _, q := range withoutSink {
+			priorityMap[q] = cond.PriorityOf(q)
+		}
+		priorityMap[sink[0]] = 1
+		aOut.Acceptance = Parity{Priority: priorityMap}
+	default:
+		aOut.Acceptance = EmersonLei{Formula: And(cond.ToEmersonLei().Formula, Fin(sink))}
+	}
+
+	return *aOut, nil
+}
+
+/*
+Complement
+Description:
+	Creates a deterministic automaton which accepts exactly the words that the deterministic automaton a rejects.
+	The automaton is completed first and then its acceptance condition is negated:
+	Rabin becomes Streett (and vice versa), Buchi becomes co-Buchi (and vice versa),
+	parity priorities are shifted by one and the other conditions become Emerson-Lei conditions.
+*/
+func (a Automaton) Complement() (Automaton, error) {
+	// Input Processing
+	if !a.IsDeterministic() {
+		return Automaton{}, errors.New("Complement() requires a deterministic automaton.")
+	}
+
+	completeAutomaton, err := a.Complete()
+	if err != nil {
+		return Automaton{}, err
+	}
+
+	// Negate the acceptance condition
+	switch cond := completeAutomaton.Acceptance.(type) {
+	case Rabin:
+		completeAutomaton.Acceptance = Streett{Pairs: cond.Pairs}
+	case Streett:
+		completeAutomaton.Acceptance = Rabin{Pairs: cond.Pairs}
+	case Buchi:
+		completeAutomaton.Acceptance = CoBuchi{F: cond.F}
+	case CoBuchi:
+		completeAutomaton.Acceptance = Buchi{F: cond.F}
+	case Parity:
+		priorityMap := make(map[State]int)
+		for _, q := range completeAutomaton.Q {
+			priorityMap[q] = cond.PriorityOf(q) + 1
+		}
+		completeAutomaton.Acceptance = Parity{Priority: priorityMap}
+	default:
+		completeAutomaton.Acceptance = EmersonLei{Formula: cond.ToEmersonLei().Formula.Negate()}
+	}
+
+	return completeAutomaton, nil
+}
+
+/*
+Intersection
+Description:
+	Creates the product automaton which accepts the words accepted by both a1 and a2.
+	The product reads the symbols that are in both alphabets and has an Emerson-Lei acceptance condition.
+*/
+func Intersection(a1 Automaton, a2 Automaton) (Automaton, error) {
+	// Input Processing
+	if err := a1.Check(); err != nil {
+		return Automaton{}, fmt.Errorf("There was an issue checking the first automaton: %v", err)
+	}
+
+	if err := a2.Check(); err != nil {
+		return Automaton{}, fmt.Errorf("There was an issue checking the second automaton: %v", err)
+	}
+
+	var alphabet []mc.AtomicProposition
+	for _, ap := range a1.Alphabet {
+		if ap.In(a2.Alphabet) {
+			alphabet = append(alphabet, ap)
+		}
+	}
+
+	// Explore the product
+	b := newAutomatonBuilder(alphabet)
+	var queue [][2]State
+	for _, q1 := range a1.Q0 {
+		for _, q2 := range a2.Q0 {
+			if b.AddState(pairName(q1, q2), true) {
+				queue = append(queue, [2]State{q1, q2})
+			}
+		}
+	}
+
+	var explored [][2]State
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		explored = append(explored, current)
+
+		for _, ap := range alphabet {
+			for _, successor1 := range a1.Post(current[0], ap) {
+				for _, successor2 := range a2.Post(current[1], ap) {
+					if b.AddState(pairName(successor1, successor2), false) {
+						queue = append(queue, [2]State{successor1, successor2})
+					}
+					b.AddTransition(pairName(current[0], current[1]), ap, pairName(successor1, successor2))
+				}
+			}
+		}
+	}
+
+	product := b.Build()
+
+	// Lift the acceptance conditions to the product
+	lift := func(component int, setIn []State) []State {
+		var setOut []State
+		for _, pair := range explored {
+			if pair[component].In(setIn) {
+				setOut = append(setOut, product.StatesNamed(pairName(pair[0], pair[1]))...)
+			}
+		}
+		return setOut
+	}
+
+	product.Acceptance = EmersonLei{
+		Formula: And(
+			liftFormula(a1.Acceptance.ToEmersonLei().Formula, func(setIn []State) []State { return lift(0, setIn) }),
+			liftFormula(a2.Acceptance.ToEmersonLei().Formula, func(setIn []State) []State { return lift(1, setIn) }),
+		),
+	}
+
+	return *product, nil
+}
+
+/*
+Includes
+Description:
+	Determines if the language of a2 is a subset of the language of a1, where a1 is deterministic.
+	If it is not, then the returned Lasso is a word that a2 accepts and a1 rejects.
+*/
+func Includes(a1 Automaton, a2 Automaton) (bool, Lasso, error) {
+	complement1, err := a1.withAlphabet(a2.Alphabet).Complement()
+	if err != nil {
+		return false, Lasso{}, fmt.Errorf("There was an issue complementing the first automaton: %v", err)
+	}
+
+	difference, err := Intersection(a2, complement1)
+	if err != nil {
+		return false, Lasso{}, err
+	}
+
+	isEmpty, witness, err := difference.IsEmpty()
+	if err != nil {
+		return false, Lasso{}, err
+	}
+
+	return isEmpty, witness, nil
+}
+
+/*
+Equivalent
+Description:
+	Determines if two deterministic automata accept the same language (over the union of their alphabets).
+	If they do not, then the returned Lasso is a word that exactly one of them accepts.
+*/
+func Equivalent(a1 Automaton, a2 Automaton) (bool, Lasso, error) {
+	// Input Processing
+	if !a1.IsDeterministic() || !a2.IsDeterministic() {
+		return false, Lasso{}, errors.New("Equivalent() requires two deterministic automata.")
+	}
+
+	// Use a common alphabet
+	alphabet := append([]mc.AtomicProposition{}, a1.Alphabet...)
+	for _, ap := range a2.Alphabet {
+		if !ap.In(alphabet) {
+			alphabet = append(alphabet, ap)
+		}
+	}
+	a1 = a1.withAlphabet(alphabet)
+	a2 = a2.withAlphabet(alphabet)
+
+	// Check both inclusions
+	includes, witness, err := Includes(a1, a2)
+	if err != nil || !includes {
+		return false, witness, err
+	}
+
+	includes, witness, err = Includes(a2, a1)
+	if err != nil || !includes {
+		return false, witness, err
+	}
+
+	return true, Lasso{}, nil
+}
+
+/*
+withAlphabet
+Description:
+	Returns a copy of the automaton whose alphabet also contains the symbols in alphabet.
+	The new symbols have no transitions.
+*/
+func (a Automaton) withAlphabet(alphabet []mc.AtomicProposition) Automaton {
+	aOut := a
+	aOut.Alphabet = append([]mc.AtomicProposition{}, a.Alphabet...)
+	for _, ap := range alphabet {
+		if !ap.In(aOut.Alphabet) {
+			aOut.Alphabet = append(aOut.Alphabet, ap)
+		}
+	}
+	return aOut
+}
+
+/*
+stronglyConnectedComponents
+Description:
+	Computes the strongly connected components of the transition graph restricted to the states in allowed.
+*/
+func (a Automaton) stronglyConnectedComponents(allowed []State) [][]State {
+	indexOf := make(map[string]int)
+	var states []State
+	for _, q := range allowed {
+		if _, isListed := indexOf[q.Name]; !isListed {
+			indexOf[q.Name] = len(states)
+			states = append(states, q)
+		}
+	}
+
+	successors := make([][]int, len(states))
+	for i, q := range states {
+		for _, ap := range a.Alphabet {
+			for _, successor := range a.Post(q, ap) {
+				if j, isAllowed := indexOf[successor.Name]; isAllowed {
+					successors[i] = append(successors[i], j)
+				}
+			}
+		}
+	}
+
+	var components [][]State
+	for _, indices := range graph.StronglyConnectedComponents(successors, nil) {
+		var component []State
+		for _, i := range indices {
+			component = append(component, states[i])
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+/*
+isNontrivial
+Description:
+	Determines if the strongly connected component contains a cycle,
+	i.e. it has more than one state or its only state has a self-loop.
+*/
+func (a Automaton) isNontrivial(component []State) bool {
+	if len(component) > 1 {
+		return true
+	}
+
+	for _, ap := range a.Alphabet {
+		if component[0].In(a.Post(component[0], ap)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+lassoThrough
+Description:
+	Creates a lasso whose prefix leads from an initial state to the component and whose cycle stays
+	in the component and visits every set in infSets.
+*/
+func (a Automaton) lassoThrough(component []State, infSets [][]State) Lasso {
+	// Find a path from an initial state to the component
+	prefixStates, prefix := a.shortestPath(a.Q0, component, nil)
+	entry := component[0]
+	if len(prefixStates) > 0 {
+		entry = prefixStates[len(prefixStates)-1]
+		prefixStates = prefixStates[:len(prefixStates)-1]
+	}
+
+	// Visit every Inf set within the component and then return to the entry.
+	current := entry
+	var cycleStates []State
+	var cycle []mc.AtomicProposition
+	for _, infSet := range infSets {
+		var targets []State
+		for _, q := range infSet {
+			if q.In(component) {
+				targets = append(targets, q)
+			}
+		}
+		if current.In(targets) {
+			continue
+		}
+		pathStates, path := a.shortestPath([]State{current}, targets, component)
+		cycleStates = append(cycleStates, pathStates[:len(pathStates)-1]...)
+		cycle = append(cycle, path...)
+		current = pathStates[len(pathStates)-1]
+	}
+
+	// Close the cycle with at least one step
+	for _, ap := range a.Alphabet {
+		for _, successor := range a.Post(current, ap) {
+			if !successor.In(component) {
+				continue
+			}
+			pathStates, path := a.shortestPath([]State{successor}, []State{entry}, component)
+			cycleStates = append(cycleStates, current)
+			cycleStates = append(cycleStates, pathStates[:len(pathStates)-1]...)
+			cycle = append(cycle, ap)
+			cycle = append(cycle, path...)
+
+			return Lasso{Prefix: prefix, Cycle: cycle, PrefixStates: prefixStates, CycleStates: cycleStates}
+		}
+	}
+
+	return Lasso{Prefix: prefix, Cycle: cycle, PrefixStates: prefixStates, CycleStates: cycleStates}
+}
+
+/*
+shortestPath
+Description:
+	Finds a shortest path (by breadth-first search) from one of the sources to one of the targets.
+	If within is not nil, then the path only uses states in within.
+	Returns the states of the path (including the source and the target) and the symbols read along the path.
+*/
+func (a Automaton) shortestPath(sources []State, targets []State, within []State) ([]State, []mc.AtomicProposition) {
+	type step struct {
+		previous int
+		state    State
+		symbol   mc.AtomicProposition
+	}
+
+	var steps []step
+	visited := make(map[string]bool)
+	for _, source := range sources {
+		if !visited[source.Name] {
+			visited[source.Name] = true
+			steps = append(steps, step{previous: -1, state: source})
+		}
+	}
+
+	for index := 0; index < len(steps); index++ {
+		if steps[index].state.In(targets) {
+			// Reconstruct the path
+			var states []State
+			var symbols []mc.AtomicProposition
+			for current := index; current >= 0; current = steps[current].previous {
+				states = append([]State{steps[current].state}, states...)
+				if steps[current].previous >= 0 {
+					symbols = append([]mc.AtomicProposition{steps[current].symbol}, symbols...)
+				}
+			}
+			return states, symbols
+		}
+
+		for _, ap := range a.Alphabet {
+			for _, successor := range a.Post(steps[index].state, ap) {
+				if visited[successor.Name] || (within != nil && !successor.In(within)) {
+					continue
+				}
+				visited[successor.Name] = true
+				steps = append(steps, step{previous: index, state: successor, symbol: ap})
+			}
+		}
+	}
+
+	return []State{}, []mc.AtomicProposition{}
+}
+
+/*
+liftFormula
+Description:
+	Replaces every set in the formula by lift(set).
+*/
+func liftFormula(formula AcceptanceFormula, lift func([]State) []State) AcceptanceFormula {
+	formulaOut := AcceptanceFormula{Operator: formula.Operator}
+	if formula.Operator == AcceptInf || formula.Operator == AcceptFin {
+		formulaOut.Set = lift(formula.Set)
+	}
+	for _, operand := range formula.Operands {
+		formulaOut.Operands = append(formulaOut.Operands, liftFormula(operand, lift))
+	}
+	return formulaOut
+}
+
+/*
+restrictAcceptance
+Description:
+	Rewrites the acceptance condition so that its sets only contain the states in Q,
+	and so that its states point to the automaton that owns Q.
+*/
+func restrictAcceptance(cond AcceptanceCondition, Q []State) AcceptanceCondition {
+	restrict := func(setIn []State) []State {
+		var setOut []State
+		for _, q := range Q {
+			if q.In(setIn) {
+				setOut = append(setOut, q)
+			}
+		}
+		return setOut
+	}
+
+	switch typedCond := cond.(type) {
+	case Buchi:
+		return Buchi{F: restrict(typedCond.F)}
+	case GeneralizedBuchi:
+		var F [][]State
+		for _, setIn := range typedCond.F {
+			F = append(F, restrict(setIn))
+		}
+		return GeneralizedBuchi{F: F}
+	case CoBuchi:
+		return CoBuchi{F: restrict(typedCond.F)}
+	case Rabin:
+		var pairs []AcceptancePair
+		for _, pair := range typedCond.Pairs {
+			pairs = append(pairs, AcceptancePair{E: restrict(pair.E), F: restrict(pair.F)})
+		}
+		return Rabin{Pairs: pairs}
+	case Streett:
+		var pairs []AcceptancePair
+		for _, pair := range typedCond.Pairs {
+			pairs = append(pairs, AcceptancePair{E: restrict(pair.E), F: restrict(pair.F)})
+		}
+		return Streett{Pairs: pairs}
+	case Parity:
+		priorityMap := make(map[State]int)
+		for _, q := range Q {
+			priorityMap[q] = typedCond.PriorityOf(q)
+		}
+		return Parity{Priority: priorityMap}
+	}
+
+	return EmersonLei{Formula: liftFormula(cond.ToEmersonLei().Formula, restrict)}
+}
+
+/*
+pairName
+Description:
+	Names the product state "(q1,q2)".
+*/
+func pairName(q1 State, q2 State) string {
+	return fmt.Sprintf("(%v,%v)", q1, q2)
+}
@@ -0,0 +1,184 @@
+/*
+language_test.go
+Description:
+	Tests the functions and objects created in language.go
+*/
+
+package omega
+
+import (
+	"testing"
+)
+
+/*
+TestLanguage_IsEmpty1
+Description:
+	Verifies that the automaton for infinitely many a's and b's is not empty
+	and that the returned witness is accepted.
+*/
+func TestLanguage_IsEmpty1(t *testing.T) {
+	// Constants
+	a := GetInfinitelyManyAAndB()
+
+	// Algorithm
+	isEmpty, witness, err := a.IsEmpty()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if isEmpty {
+		t.Errorf("Expected the language to be nonempty.")
+	}
+
+	tf, err := a.AcceptsLasso(witness.Prefix, witness.Cycle)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !tf {
+		t.Errorf("The witness %v is not accepted by the automaton.", witness)
+	}
+
+	if len(witness.CycleStates) != len(witness.Cycle) || len(witness.PrefixStates) != len(witness.Prefix) {
+		t.Errorf("The run of the witness does not match the word: %v", witness)
+	}
+}
+
+/*
+TestLanguage_IsEmpty2
+Description:
+	Verifies that a Buchi automaton whose accepting state is only visited once is empty.
+*/
+func TestLanguage_IsEmpty2(t *testing.T) {
+	// Constants
+	a, _ := GetAutomaton(
+		[]string{"q0", "q1"}, []string{"q0"}, []string{"a"},
+		map[string]map[string][]string{
+			"q0": {"a": {"q1"}},
+			"q1": {"a": {"q1"}},
+		},
+	)
+	a.Acceptance = Buchi{F: a.StatesNamed("q0")}
+
+	// Algorithm
+	isEmpty, _, err := a.IsEmpty()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !isEmpty {
+		t.Errorf("Expected the language to be empty.")
+	}
+}
+
+/*
+TestLanguage_Complete1
+Description:
+	Verifies that Complete() adds a rejecting sink state to an incomplete automaton.
+*/
+func TestLanguage_Complete1(t *testing.T) {
+	// Constants
+	a, _ := GetAutomaton(
+		[]string{"q0"}, []string{"q0"}, []string{"a", "b"},
+		map[string]map[string][]string{
+			"q0": {"a": {"q0"}},
+		},
+	)
+	a.Acceptance = CoBuchi{F: []State{}}
+
+	// Algorithm
+	completeAutomaton, err := a.Complete()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !completeAutomaton.IsComplete() || len(completeAutomaton.Q) != 2 {
+		t.Errorf("Expected a complete automaton with a sink state, but found %v.", completeAutomaton.Q)
+	}
+
+	compareOnLassos(t, a, completeAutomaton)
+}
+
+/*
+TestLanguage_Complement1
+Description:
+	Verifies that the complement of the Rabin automaton for finitely many b's
+	accepts exactly the lassos that the original automaton rejects.
+*/
+func TestLanguage_Complement1(t *testing.T) {
+	// Constants
+	a := GetEventuallyAlwaysA()
+
+	// Algorithm
+	complement, err := a.Complement()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, ok := complement.Acceptance.(Streett); !ok {
+		t.Errorf("Expected the complement of a Rabin automaton to be a Streett automaton.")
+	}
+
+	for _, word := range lassoWords(a.Alphabet, 2, 3) {
+		tf1, _ := a.AcceptsLasso(word[0], word[1])
+		tf2, _ := complement.AcceptsLasso(word[0], word[1])
+		if tf1 == tf2 {
+			t.Errorf("The automaton and its complement agree on %v (%v)^omega.", word[0], word[1])
+		}
+	}
+}
+
+/*
+TestLanguage_Equivalent1
+Description:
+	Verifies that an automaton is equivalent to its parity automaton.
+*/
+func TestLanguage_Equivalent1(t *testing.T) {
+	// Constants
+	a := GetTwoPairStreett()
+
+	// Algorithm
+	parityAutomaton, err := a.ToParity()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tf, witness, err := Equivalent(a, parityAutomaton)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !tf {
+		t.Errorf("Expected the automata to be equivalent, but they disagree on %v.", witness)
+	}
+}
+
+/*
+TestLanguage_Equivalent2
+Description:
+	Verifies that Equivalent() returns a distinguishing word for two different automata.
+*/
+func TestLanguage_Equivalent2(t *testing.T) {
+	// Constants
+	a1 := GetInfinitelyManyAAndB()
+	a2 := GetEventuallyAlwaysA()
+
+	// Algorithm
+	tf, witness, err := Equivalent(a1, a2)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if tf {
+		t.Errorf("Expected the automata to be different.")
+	}
+
+	tf1, _ := a1.AcceptsLasso(witness.Prefix, witness.Cycle)
+	tf2, _ := a2.AcceptsLasso(witness.Prefix, witness.Cycle)
+	if tf1 == tf2 {
+		t.Errorf("The witness %v does not distinguish the automata.", witness)
+	}
+
+	if len(witness.Cycle) == 0 {
+		t.Errorf("Expected a witness with a nonempty cycle.")
+	}
+}
@@ -0,0 +1,815 @@
+/*
+build.go
+Description:
+	Construction of the explicit DTMC, MDP or CTMC of a PRISM model by exploring the states reachable from the
+	initial state. The states are the valuations of the variables of all modules. A command without an action is
+	executed by its module alone; a command with an action a is executed together with one enabled command with
+	the action a of every other module which uses a, and the probabilities (or rates) of their updates multiply.
+	As in PRISM, the choices of a DTMC are taken uniformly at random, the rates of a CTMC are added up, and states
+	without enabled commands get a self-loop.
+*/
+
+package prism
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kwesiRutledge/ModelChecking/markov"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+compiledModel
+Description:
+	A model whose constants, variable bounds and initial values have been evaluated.
+*/
+type compiledModel struct {
+	Model     Model
+	Evaluator evaluator
+	Variables []Variable
+	ModuleOf  []int
+	Lower     []int
+	Upper     []int
+	Init      []int
+	Actions   []string
+	Alphabets []map[string]bool
+}
+
+/*
+outcome
+Description:
+	One update of a command: its probability (or rate) and the new values of the variables it assigns.
+*/
+type outcome struct {
+	Weight      float64
+	Assignments map[int]int
+}
+
+/*
+transition
+Description:
+	A successor of a state with its probability (or rate).
+*/
+type transition struct {
+	Target []int
+	Weight float64
+}
+
+/*
+choice
+Description:
+	A nondeterministic choice of a state: a command without an action or a synchronisation on an action.
+*/
+type choice struct {
+	Name        string
+	Transitions []transition
+}
+
+// Names of the labels which every built model has: the initial state and the states without enabled commands.
+const (
+	InitLabel     = "init"
+	DeadlockLabel = "deadlock"
+)
+
+/*
+Functions
+*/
+
+/*
+VariableNames
+Description:
+	Returns the names of the variables of all modules, in the order of their values in the names of the states
+	of the built models, e.g. the state "(1,false)" of a model with the variables x and b has x=1 and b=false.
+*/
+func (model Model) VariableNames() []string {
+	var names []string
+	for _, module := range model.Modules {
+		for _, variable := range module.Variables {
+			names = append(names, variable.Name)
+		}
+	}
+	return names
+}
+
+/*
+WithConstants
+Description:
+	Returns a copy of the model in which the constants are given the values, which are parsed as expressions.
+	This defines the constants which are left undefined in the source, and overrides the others.
+
+Usage:
+	model, err = model.WithConstants(map[string]string{"N": "10", "p": "0.25"})
+*/
+func (model Model) WithConstants(values map[string]string) (Model, error) {
+	constants := append([]Constant{}, model.Constants...)
+	for name, valueString := range values {
+		index := -1
+		for i, constant := range constants {
+			if constant.Name == name {
+				index = i
+			}
+		}
+		if index < 0 {
+			return Model{}, fmt.Errorf("The model has no constant \"%v\".", name)
+		}
+
+		tokens, err := tokenize(valueString)
+		if err != nil {
+			return Model{}, fmt.Errorf("There was an issue reading the value of \"%v\": %v", name, err)
+		}
+		p := parser{Tokens: tokens}
+		value, err := p.parseExpression()
+		if err == nil && p.peek(0).Kind != tokenEnd {
+			err = p.unexpected("the end of the value")
+		}
+		if err != nil {
+			return Model{}, fmt.Errorf("There was an issue reading the value of \"%v\": %v", name, err)
+		}
+		constants[index].Value = &value
+	}
+
+	model.Constants = constants
+	return model, nil
+}
+
+/*
+Check
+Description:
+	Checks that the names of the model are unique, that every identifier is declared before it is used (constants
+	may only use constants, and variable bounds and initial values may only use constants), and that commands only
+	assign the variables of their own module, at most once per update.
+*/
+func (model Model) Check() error {
+	kinds := make(map[string]string)
+	declare := func(name string, kind string, position Position) error {
+		if previous, isDeclared := kinds[name]; isDeclared {
+			return fmt.Errorf("The %v \"%v\" at %v has the name of a %v.", kind, name, position, previous)
+		}
+		kinds[name] = kind
+		return nil
+	}
+	checkIdentifiers := func(expression Expression, allowed ...string) error {
+		for _, identifier := range expression.identifiers() {
+			kind, isDeclared := kinds[identifier.Name]
+			if !isDeclared {
+				return fmt.Errorf("Unknown identifier \"%v\" at %v.", identifier.Name, identifier.Position)
+			}
+			isAllowed := len(allowed) == 0
+			for _, allowedKind := range allowed {
+				isAllowed = isAllowed || kind == allowedKind
+			}
+			if !isAllowed {
+				return fmt.Errorf("The %v \"%v\" cannot be used at %v.", kind, identifier.Name, identifier.Position)
+			}
+		}
+		return nil
+	}
+
+	for _, constant := range model.Constants {
+		if constant.Value != nil {
+			if err := checkIdentifiers(*constant.Value, "constant"); err != nil {
+				return err
+			}
+		}
+		if err := declare(constant.Name, "constant", constant.Position); err != nil {
+			return err
+		}
+	}
+
+	moduleNames := make(map[string]bool)
+	for _, module := range model.Modules {
+		if moduleNames[module.Name] {
+			return fmt.Errorf("The module \"%v\" at %v is declared twice.", module.Name, module.Position)
+		}
+		moduleNames[module.Name] = true
+
+		for _, variable := range module.Variables {
+			for _, expression := range []Expression{variable.Lower, variable.Upper} {
+				if err := checkIdentifiers(expression, "constant"); err != nil {
+					return err
+				}
+			}
+			if variable.Init != nil {
+				if err := checkIdentifiers(*variable.Init, "constant"); err != nil {
+					return err
+				}
+			}
+			if err := declare(variable.Name, "variable", variable.Position); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Formulas may use the variables of every module, and the formulas declared before them
+	for _, formula := range model.Formulas {
+		if err := checkIdentifiers(formula.Expression); err != nil {
+			return err
+		}
+		if err := declare(formula.Name, "formula", formula.Position); err != nil {
+			return err
+		}
+	}
+
+	for _, module := range model.Modules {
+		local := make(map[string]bool)
+		for _, variable := range module.Variables {
+			local[variable.Name] = true
+		}
+
+		for _, command := range module.Commands {
+			if err := checkIdentifiers(command.Guard); err != nil {
+				return err
+			}
+			for _, update := range command.Updates {
+				if update.Probability != nil {
+					if err := checkIdentifiers(*update.Probability); err != nil {
+						return err
+					}
+				}
+
+				assigned := make(map[string]bool)
+				for _, assignment := range update.Assignments {
+					if !local[assignment.Variable] {
+						return fmt.Errorf("The command at %v assigns \"%v\", which is not a variable of the module \"%v\".", command.Position, assignment.Variable, module.Name)
+					}
+					if assigned[assignment.Variable] {
+						return fmt.Errorf("The update at %v assigns \"%v\" twice.", update.Position, assignment.Variable)
+					}
+					assigned[assignment.Variable] = true
+					if err := checkIdentifiers(assignment.Expression); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	labelNames := map[string]bool{InitLabel: true, DeadlockLabel: true}
+	for _, label := range model.Labels {
+		if labelNames[label.Name] {
+			return fmt.Errorf("The label \"%v\" at %v is declared twice or is built in.", label.Name, label.Position)
+		}
+		labelNames[label.Name] = true
+		if err := checkIdentifiers(label.Expression); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+BuildDTMC
+Description:
+	Builds the explicit DTMC of a dtmc model.
+
+Usage:
+	model, err := ParseFile("die.pm")
+	chain, err := model.BuildDTMC()
+*/
+func (model Model) BuildDTMC() (markov.DTMC, error) {
+	if model.Type != DTMC {
+		return markov.DTMC{}, fmt.Errorf("The model is of type %v, not %v.", model.Type, DTMC)
+	}
+
+	names, transitions, labels, err := model.explore(func(choices []choice) []choice {
+		combined := choice{}
+		for _, c := range choices {
+			for _, t := range c.Transitions {
+				combined.Transitions = append(combined.Transitions, transition{Target: t.Target, Weight: t.Weight / float64(len(choices))})
+			}
+		}
+		return []choice{combined}
+	})
+	if err != nil {
+		return markov.DTMC{}, err
+	}
+
+	transitionMap := make(map[string]map[string]float64)
+	for source, choices := range transitions {
+		transitionMap[names[source]] = make(map[string]float64)
+		for _, t := range choices[0].Transitions {
+			transitionMap[names[source]][stateName(t.Target, model)] += t.Weight
+		}
+	}
+
+	chain, err := markov.GetDTMC(names, transitionMap, map[string]float64{names[0]: 1}, model.labelNames(), labels)
+	if err != nil {
+		return markov.DTMC{}, fmt.Errorf("There was an issue creating the DTMC: %v", err)
+	}
+	return chain, nil
+}
+
+/*
+BuildMDP
+Description:
+	Builds the explicit MDP of an mdp model. The actions are named after the synchronisation labels; the commands
+	without a label are named after their module and line, e.g. "coin@3", and a name which is used by several
+	choices of a state gets the suffixes #2, #3, ... in its later choices.
+*/
+func (model Model) BuildMDP() (markov.MDP, error) {
+	if model.Type != MDP {
+		return markov.MDP{}, fmt.Errorf("The model is of type %v, not %v.", model.Type, MDP)
+	}
+
+	names, transitions, labels, err := model.explore(func(choices []choice) []choice { return choices })
+	if err != nil {
+		return markov.MDP{}, err
+	}
+
+	var actionNames []string
+	seenActions := make(map[string]bool)
+	transitionMap := make(map[string]map[string]map[string]float64)
+	for source, choices := range transitions {
+		transitionMap[names[source]] = make(map[string]map[string]float64)
+		uses := make(map[string]int)
+		for _, c := range choices {
+			uses[c.Name]++
+			action := c.Name
+			if uses[c.Name] > 1 {
+				action = fmt.Sprintf("%v#%v", c.Name, uses[c.Name])
+			}
+			if !seenActions[action] {
+				seenActions[action] = true
+				actionNames = append(actionNames, action)
+			}
+
+			transitionMap[names[source]][action] = make(map[string]float64)
+			for _, t := range c.Transitions {
+				transitionMap[names[source]][action][stateName(t.Target, model)] += t.Weight
+			}
+		}
+	}
+
+	mdp, err := markov.GetMDP(names, actionNames, transitionMap, map[string]float64{names[0]: 1}, model.labelNames(), labels)
+	if err != nil {
+		return markov.MDP{}, fmt.Errorf("There was an issue creating the MDP: %v", err)
+	}
+	return mdp, nil
+}
+
+/*
+BuildCTMC
+Description:
+	Builds the explicit CTMC of a ctmc model, in which the weights of the updates are rates.
+*/
+func (model Model) BuildCTMC() (markov.CTMC, error) {
+	if model.Type != CTMC {
+		return markov.CTMC{}, fmt.Errorf("The model is of type %v, not %v.", model.Type, CTMC)
+	}
+
+	names, transitions, labels, err := model.explore(func(choices []choice) []choice {
+		combined := choice{}
+		for _, c := range choices {
+			combined.Transitions = append(combined.Transitions, c.Transitions...)
+		}
+		return []choice{combined}
+	})
+	if err != nil {
+		return markov.CTMC{}, err
+	}
+
+	rateMap := make(map[string]map[string]float64)
+	for source, choices := range transitions {
+		for _, t := range choices[0].Transitions {
+			target := stateName(t.Target, model)
+			if target == names[source] || t.Weight == 0 {
+				continue
+			}
+			if rateMap[names[source]] == nil {
+				rateMap[names[source]] = make(map[string]float64)
+			}
+			rateMap[names[source]][target] += t.Weight
+		}
+	}
+
+	chain, err := markov.GetCTMC(names, rateMap, map[string]float64{names[0]: 1}, model.labelNames(), labels)
+	if err != nil {
+		return markov.CTMC{}, fmt.Errorf("There was an issue creating the CTMC: %v", err)
+	}
+	return chain, nil
+}
+
+/*
+labelNames
+Description:
+	Returns the names of the atomic propositions of the built models.
+*/
+func (model Model) labelNames() []string {
+	names := []string{InitLabel, DeadlockLabel}
+	for _, label := range model.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+/*
+explore
+Description:
+	Explores the states which are reachable from the initial state, in breadth-first order. The choices of each
+	state are combined by the function combine, except in states without enabled commands, which get a
+	self-loop. Returns the names of the states, their choices by index and their labels.
+*/
+func (model Model) explore(combine func([]choice) []choice) ([]string, [][]choice, map[string][]string, error) {
+	compiled, err := model.compile()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	indexOf := map[string]int{stateName(compiled.Init, model): 0}
+	states := [][]int{compiled.Init}
+	var names []string
+	var transitions [][]choice
+	labels := make(map[string][]string)
+
+	for index := 0; index < len(states); index++ {
+		state := states[index]
+		name := stateName(state, model)
+		names = append(names, name)
+
+		choices, err := compiled.choices(state)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if index == 0 {
+			labels[name] = append(labels[name], InitLabel)
+		}
+		if len(choices) == 0 {
+			labels[name] = append(labels[name], DeadlockLabel)
+			choices = []choice{{Name: DeadlockLabel, Transitions: []transition{{Target: state, Weight: 1}}}}
+		} else {
+			choices = combine(choices)
+		}
+		transitions = append(transitions, choices)
+
+		for _, c := range choices {
+			for _, t := range c.Transitions {
+				targetName := stateName(t.Target, model)
+				if _, isKnown := indexOf[targetName]; !isKnown {
+					indexOf[targetName] = len(states)
+					states = append(states, t.Target)
+				}
+			}
+		}
+
+		e := compiled.Evaluator
+		e.State = state
+		for _, label := range model.Labels {
+			holds, err := e.evaluateBool(label.Expression, "label")
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if holds {
+				labels[name] = append(labels[name], label.Name)
+			}
+		}
+	}
+
+	return names, transitions, labels, nil
+}
+
+/*
+compile
+Description:
+	Checks the model and evaluates its constants, the bounds and initial values of its variables, and the
+	alphabets of its modules.
+*/
+func (model Model) compile() (compiledModel, error) {
+	if err := model.Check(); err != nil {
+		return compiledModel{}, err
+	}
+
+	compiled := compiledModel{
+		Model: model,
+		Evaluator: evaluator{
+			Constants:     make(map[string]Value),
+			Formulas:      make(map[string]Expression),
+			VariableIndex: make(map[string]int),
+		},
+	}
+	e := &compiled.Evaluator
+
+	for _, constant := range model.Constants {
+		if constant.Value == nil {
+			return compiledModel{}, fmt.Errorf("The constant \"%v\" declared at %v has no value; define it with WithConstants().", constant.Name, constant.Position)
+		}
+		value, err := e.evaluate(*constant.Value)
+		if err != nil {
+			return compiledModel{}, err
+		}
+		converted, isConvertible := value.convertTo(constant.Type)
+		if !isConvertible {
+			return compiledModel{}, fmt.Errorf("The constant \"%v\" at %v has the type %v, but its value %v is a %v.", constant.Name, constant.Position, constant.Type, value, value.Type)
+		}
+		e.Constants[constant.Name] = converted
+	}
+
+	for _, formula := range model.Formulas {
+		e.Formulas[formula.Name] = formula.Expression
+	}
+
+	actionIndex := make(map[string]bool)
+	for m, module := range model.Modules {
+		alphabet := make(map[string]bool)
+		for _, command := range module.Commands {
+			if command.Action == "" {
+				continue
+			}
+			alphabet[command.Action] = true
+			if !actionIndex[command.Action] {
+				actionIndex[command.Action] = true
+				compiled.Actions = append(compiled.Actions, command.Action)
+			}
+		}
+		compiled.Alphabets = append(compiled.Alphabets, alphabet)
+
+		for _, variable := range module.Variables {
+			lower, upper, init, err := e.variableRange(variable)
+			if err != nil {
+				return compiledModel{}, err
+			}
+
+			e.VariableIndex[variable.Name] = len(compiled.Variables)
+			e.VariableTypes = append(e.VariableTypes, variable.Type)
+			compiled.Variables = append(compiled.Variables, variable)
+			compiled.ModuleOf = append(compiled.ModuleOf, m)
+			compiled.Lower = append(compiled.Lower, lower)
+			compiled.Upper = append(compiled.Upper, upper)
+			compiled.Init = append(compiled.Init, init)
+		}
+	}
+
+	return compiled, nil
+}
+
+/*
+variableRange
+Description:
+	Evaluates the bounds and the initial value of a variable. Booleans range over 0 (false) and 1 (true).
+*/
+func (e evaluator) variableRange(variable Variable) (int, int, int, error) {
+	lower, upper := 0, 1
+	if variable.Type == IntType {
+		bounds := make([]int, 2)
+		for b, expression := range []Expression{variable.Lower, variable.Upper} {
+			value, err := e.evaluate(expression)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if value.Type != IntType {
+				return 0, 0, 0, fmt.Errorf("The bound \"%v\" of \"%v\" at %v is not an integer.", expression, variable.Name, expression.Position)
+			}
+			bounds[b] = int(value.Number)
+		}
+		lower, upper = bounds[0], bounds[1]
+		if lower > upper {
+			return 0, 0, 0, fmt.Errorf("The range [%v..%v] of \"%v\" at %v is empty.", lower, upper, variable.Name, variable.Position)
+		}
+	}
+
+	init := lower
+	if variable.Init != nil {
+		value, err := e.evaluate(*variable.Init)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if init, err = variable.encode(value, variable.Init.Position); err != nil {
+			return 0, 0, 0, err
+		}
+		if init < lower || init > upper {
+			return 0, 0, 0, fmt.Errorf("The initial value %v of \"%v\" at %v is outside its range [%v..%v].", init, variable.Name, variable.Position, lower, upper)
+		}
+	}
+
+	return lower, upper, init, nil
+}
+
+/*
+encode
+Description:
+	Converts a value of the variable into the integer which is stored in states.
+*/
+func (variable Variable) encode(value Value, position Position) (int, error) {
+	switch {
+	case variable.Type == BoolType && value.Type == BoolType:
+		if value.Bool {
+			return 1, nil
+		}
+		return 0, nil
+	case variable.Type == IntType && value.Type == IntType:
+		return int(value.Number), nil
+	default:
+		return 0, fmt.Errorf("The %v variable \"%v\" cannot take the %v value %v at %v.", variable.Type, variable.Name, value.Type, value, position)
+	}
+}
+
+/*
+choices
+Description:
+	Returns the choices of the state: one for each enabled command without an action, and one for each
+	combination of enabled commands which synchronise on an action.
+*/
+func (compiled compiledModel) choices(state []int) ([]choice, error) {
+	var choices []choice
+
+	for m, module := range compiled.Model.Modules {
+		for _, command := range module.Commands {
+			if command.Action != "" {
+				continue
+			}
+			enabled, err := compiled.enabled(command, state)
+			if err != nil {
+				return nil, err
+			}
+			if !enabled {
+				continue
+			}
+
+			transitions, err := compiled.synchronise([]int{m}, []Command{command}, state)
+			if err != nil {
+				return nil, err
+			}
+			choices = append(choices, choice{Name: fmt.Sprintf("%v@%v", module.Name, command.Position.Line), Transitions: transitions})
+		}
+	}
+
+	for _, action := range compiled.Actions {
+		// The enabled commands of each module which uses the action
+		var modules []int
+		var enabledCommands [][]Command
+		for m, module := range compiled.Model.Modules {
+			if !compiled.Alphabets[m][action] {
+				continue
+			}
+			var commands []Command
+			for _, command := range module.Commands {
+				if command.Action != action {
+					continue
+				}
+				enabled, err := compiled.enabled(command, state)
+				if err != nil {
+					return nil, err
+				}
+				if enabled {
+					commands = append(commands, command)
+				}
+			}
+			modules = append(modules, m)
+			enabledCommands = append(enabledCommands, commands)
+		}
+
+		// Every combination of one enabled command per module
+		combinations := [][]Command{{}}
+		for _, commands := range enabledCommands {
+			var extended [][]Command
+			for _, combination := range combinations {
+				for _, command := range commands {
+					extended = append(extended, append(append([]Command{}, combination...), command))
+				}
+			}
+			combinations = extended
+		}
+
+		for _, combination := range combinations {
+			transitions, err := compiled.synchronise(modules, combination, state)
+			if err != nil {
+				return nil, err
+			}
+			choices = append(choices, choice{Name: action, Transitions: transitions})
+		}
+	}
+
+	return choices, nil
+}
+
+/*
+enabled
+Description:
+	Evaluates the guard of the command in the state.
+*/
+func (compiled compiledModel) enabled(command Command, state []int) (bool, error) {
+	e := compiled.Evaluator
+	e.State = state
+	return e.evaluateBool(command.Guard, "guard")
+}
+
+/*
+synchronise
+Description:
+	Returns the successors of the state when the commands of the modules are executed together. The updates of
+	the commands are evaluated in the state, and every combination of one update per command is a successor
+	whose probability (or rate) is the product of theirs.
+*/
+func (compiled compiledModel) synchronise(modules []int, commands []Command, state []int) ([]transition, error) {
+	transitions := []transition{{Target: state, Weight: 1}}
+	for c, command := range commands {
+		outcomes, err := compiled.outcomes(modules[c], command, state)
+		if err != nil {
+			return nil, err
+		}
+
+		var extended []transition
+		for _, t := range transitions {
+			for _, o := range outcomes {
+				target := append([]int{}, t.Target...)
+				for index, value := range o.Assignments {
+					target[index] = value
+				}
+				extended = append(extended, transition{Target: target, Weight: t.Weight * o.Weight})
+			}
+		}
+		transitions = extended
+	}
+	return transitions, nil
+}
+
+/*
+outcomes
+Description:
+	Evaluates the updates of the command in the state. In DTMCs and MDPs the probabilities must be in [0,1] and
+	sum to one; in CTMCs the rates must be nonnegative. The new values must be in the ranges of the variables.
+*/
+func (compiled compiledModel) outcomes(module int, command Command, state []int) ([]outcome, error) {
+	e := compiled.Evaluator
+	e.State = state
+	isCTMC := compiled.Model.Type == CTMC
+
+	var outcomes []outcome
+	total := 0.0
+	for _, update := range command.Updates {
+		weight := 1.0
+		if update.Probability != nil {
+			description := "probability"
+			if isCTMC {
+				description = "rate"
+			}
+			var err error
+			if weight, err = e.evaluateNumber(*update.Probability, description); err != nil {
+				return nil, err
+			}
+			if math.IsNaN(weight) || weight < 0 || math.IsInf(weight, 0) || (!isCTMC && weight > 1) {
+				return nil, fmt.Errorf("The %v \"%v\" at %v is %v in the state %v.", description, *update.Probability, update.Probability.Position, weight, stateName(state, compiled.Model))
+			}
+		}
+		total += weight
+		if weight == 0 {
+			continue
+		}
+
+		o := outcome{Weight: weight, Assignments: make(map[int]int)}
+		for _, assignment := range update.Assignments {
+			value, err := e.evaluate(assignment.Expression)
+			if err != nil {
+				return nil, err
+			}
+
+			index := e.VariableIndex[assignment.Variable]
+			variable := compiled.Variables[index]
+			encoded, err := variable.encode(value, assignment.Position)
+			if err != nil {
+				return nil, err
+			}
+			if encoded < compiled.Lower[index] || encoded > compiled.Upper[index] {
+				return nil, fmt.Errorf("The update at %v gives \"%v\" the value %v outside its range [%v..%v] in the state %v.", assignment.Position, variable.Name, value, compiled.Lower[index], compiled.Upper[index], stateName(state, compiled.Model))
+			}
+			o.Assignments[index] = encoded
+		}
+		outcomes = append(outcomes, o)
+	}
+
+	if !isCTMC && math.Abs(total-1) > markov.ProbabilityTolerance {
+		return nil, fmt.Errorf("The probabilities of the command at %v sum to %v instead of 1 in the state %v.", command.Position, total, stateName(state, compiled.Model))
+	}
+
+	return outcomes, nil
+}
+
+/*
+stateName
+Description:
+	Names a state after the values of its variables, e.g. "(1,false)".
+*/
+func stateName(state []int, model Model) string {
+	var values []string
+	index := 0
+	for _, module := range model.Modules {
+		for _, variable := range module.Variables {
+			switch {
+			case variable.Type == BoolType && state[index] != 0:
+				values = append(values, "true")
+			case variable.Type == BoolType:
+				values = append(values, "false")
+			default:
+				values = append(values, fmt.Sprintf("%v", state[index]))
+			}
+			index++
+		}
+	}
+	return "(" + strings.Join(values, ",") + ")"
+}
@@ -0,0 +1,542 @@
+/*
+build_test.go
+Description:
+
+	Tests for the construction of explicit models in build.go
+*/
+package prism
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/markov"
+)
+
+/*
+GetKnuthYaoDie
+Description:
+
+	The Knuth-Yao die as a PRISM model: a fair coin is flipped until one of the six faces d is reached.
+*/
+func GetKnuthYaoDie() Model {
+	model, _ := Parse(`
+dtmc
+
+module die
+	s : [0..7] init 0;
+	d : [0..6] init 0;
+
+	[] s=0 -> 0.5 : (s'=1) + 0.5 : (s'=2);
+	[] s=1 -> 0.5 : (s'=3) + 0.5 : (s'=4);
+	[] s=2 -> 0.5 : (s'=5) + 0.5 : (s'=6);
+	[] s=3 -> 0.5 : (s'=1) + 0.5 : (s'=7) & (d'=1);
+	[] s=4 -> 0.5 : (s'=7) & (d'=2) + 0.5 : (s'=7) & (d'=3);
+	[] s=5 -> 0.5 : (s'=7) & (d'=4) + 0.5 : (s'=7) & (d'=5);
+	[] s=6 -> 0.5 : (s'=2) + 0.5 : (s'=7) & (d'=6);
+	[] s=7 -> (s'=7);
+endmodule
+
+label "done" = s=7;
+label "six" = s=7 & d=6;
+`)
+	return model
+}
+
+/*
+GetSynchronisedCounters
+Description:
+
+	Two counters which only move together on the action tick, each by one or two with probability 1/2,
+	until one of them reaches N. The second module is a renamed copy of the first.
+*/
+func GetSynchronisedCounters() Model {
+	model, _ := Parse(`
+dtmc
+
+const int N = 4;
+formula finished = x >= N | y >= N;
+
+module first
+	x : [0..N+1] init 0;
+	[tick] !finished -> 0.5 : (x'=x+1) + 0.5 : (x'=x+2);
+endmodule
+
+module second = first [x=y] endmodule
+
+label "finished" = finished;
+`)
+	return model
+}
+
+/*
+GetGamble
+Description:
+
+	An MDP in which the player either stops or bets; a bet wins with probability p and can be repeated until
+	it is lost. A coin which is flipped without a label reaches the goal with probability 1/2.
+*/
+func GetGamble() Model {
+	model, _ := Parse(`
+mdp
+
+const double p = 0.4;
+
+module player
+	s : [0..3] init 0; // 0 playing, 1 lost, 2 won, 3 stopped
+
+	[stop] s=0 -> (s'=3);
+	[bet] s=0 -> p : (s'=2) + (1-p) : (s'=1);
+	[] s=0 -> 0.5 : (s'=2) + 0.5 : (s'=1);
+	[bet] s=2 -> p : (s'=2) + (1-p) : (s'=1);
+endmodule
+
+label "won" = s=2;
+`)
+	return model
+}
+
+/*
+GetRepairableMachine
+Description:
+
+	A machine which fails with rate 1 and is repaired with rate 2; it is up two thirds of the time.
+*/
+func GetRepairableMachine() Model {
+	model, _ := Parse(`
+ctmc
+
+const double failure = 1;
+const double repair = 2;
+
+module machine
+	up : bool init true;
+	[] up -> failure : (up'=false);
+	[] !up -> repair : (up'=true);
+endmodule
+
+label "down" = !up;
+`)
+	return model
+}
+
+/*
+parseValues
+Description:
+
+	Reads the integer values of the name of a state, such as "(1,2)".
+*/
+func parseValues(t *testing.T, name string) []int {
+	var values []int
+	for _, valueString := range strings.Split(strings.Trim(name, "()"), ",") {
+		value, err := strconv.Atoi(valueString)
+		if err != nil {
+			t.Errorf("The state name %v does not consist of integers.", name)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+/*
+TestModel_VariableNames1
+Description:
+
+	The variables are ordered by module, and the renamed module has the renamed variable.
+*/
+func TestModel_VariableNames1(t *testing.T) {
+	names := GetSynchronisedCounters().VariableNames()
+	if strings.Join(names, ",") != "x,y" {
+		t.Errorf("Expected the variables x,y, but they were %v.", names)
+	}
+}
+
+/*
+TestModel_WithConstants1
+Description:
+
+	A constant without a value must be defined before building, and only declared constants can be defined.
+*/
+func TestModel_WithConstants1(t *testing.T) {
+	model, err := Parse(`
+dtmc
+const int N;
+module counter
+	x : [0..N] init 0;
+	[] x<N -> (x'=x+1);
+endmodule
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing: %v", err)
+	}
+
+	_, err = model.BuildDTMC()
+	if err == nil || !strings.Contains(err.Error(), "\"N\" declared at line 3, column 1 has no value") {
+		t.Errorf("Expected an error about the undefined constant N, but received %v.", err)
+	}
+
+	defined, err := model.WithConstants(map[string]string{"N": "2*3"})
+	if err != nil {
+		t.Fatalf("Unexpected error while defining N: %v", err)
+	}
+	chain, err := defined.BuildDTMC()
+	if err != nil {
+		t.Fatalf("Unexpected error while building: %v", err)
+	}
+	if len(chain.S) != 7 {
+		t.Errorf("Expected 7 states when N=6, but there were %v.", len(chain.S))
+	}
+	if model.Constants[0].Value != nil {
+		t.Errorf("WithConstants() should not modify the original model.")
+	}
+
+	if _, err = model.WithConstants(map[string]string{"M": "1"}); err == nil {
+		t.Errorf("Expected an error when defining the unknown constant M.")
+	}
+	if _, err = model.WithConstants(map[string]string{"N": "1 +"}); err == nil {
+		t.Errorf("Expected an error when the value of N cannot be parsed.")
+	}
+}
+
+/*
+TestModel_Check1
+Description:
+
+	Checks the errors about names and assignments.
+*/
+func TestModel_Check1(t *testing.T) {
+	testCases := []struct {
+		Source   string
+		Expected string
+	}{
+		{
+			"dtmc\nmodule a\n\tx : [0..1];\n\t[] x=0 -> (z'=1);\nendmodule\n",
+			"assigns \"z\", which is not a variable of the module \"a\"",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..1];\n\t[] y=0 -> (x'=1);\nendmodule\n",
+			"Unknown identifier \"y\" at line 4, column 5.",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..1];\nendmodule\nmodule b\n\tx : [0..1];\nendmodule\n",
+			"The variable \"x\" at line 6, column 2 has the name of a variable.",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..1];\n\t[] true -> (x'=1) & (x'=0);\nendmodule\n",
+			"assigns \"x\" twice",
+		},
+		{
+			"dtmc\nconst int N = M;\nconst int M = 1;\nmodule a\n\tx : [0..1];\nendmodule\n",
+			"Unknown identifier \"M\" at line 2, column 15.",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..1];\nendmodule\nlabel \"init\" = x=0;\n",
+			"The label \"init\" at line 5, column 1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		model, err := Parse(testCase.Source)
+		if err != nil {
+			t.Errorf("Unexpected error while parsing %q: %v", testCase.Source, err)
+			continue
+		}
+		err = model.Check()
+		if err == nil || !strings.Contains(err.Error(), testCase.Expected) {
+			t.Errorf("Expected an error containing %q for %q, but received %v.", testCase.Expected, testCase.Source, err)
+		}
+	}
+}
+
+/*
+TestModel_BuildDTMC1
+Description:
+
+	Builds the Knuth-Yao die and checks its states and the probability 1/6 of the face six.
+*/
+func TestModel_BuildDTMC1(t *testing.T) {
+	chain, err := GetKnuthYaoDie().BuildDTMC()
+	if err != nil {
+		t.Fatalf("Unexpected error while building the die: %v", err)
+	}
+
+	if len(chain.S) != 13 {
+		t.Errorf("Expected 13 reachable states, but there were %v.", len(chain.S))
+	}
+	if chain.S[0].Name != "(0,0)" || chain.I[chain.S[0]] != 1 {
+		t.Errorf("Expected the initial state \"(0,0)\", but the first state was %v with probability %v.", chain.S[0].Name, chain.I[chain.S[0]])
+	}
+	if !(mc.AtomicProposition{Name: InitLabel}).In(chain.Labels(chain.S[0])) {
+		t.Errorf("Expected the initial state to be labelled %v.", InitLabel)
+	}
+
+	formula, _ := markov.ParsePCTLFormula("P=? [ F six ]")
+	values, err := chain.Values(formula, markov.DefaultSolverOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error while checking the die: %v", err)
+	}
+	if math.Abs(values[chain.S[0]]-1.0/6) > 1e-8 {
+		t.Errorf("Expected the face six with probability 1/6, but it was %v.", values[chain.S[0]])
+	}
+}
+
+/*
+TestModel_BuildDTMC2
+Description:
+
+	The counters synchronise on tick, so each step has four outcomes and they never drift apart by more than one.
+	The states in which a counter has reached N are deadlocks with a self-loop.
+*/
+func TestModel_BuildDTMC2(t *testing.T) {
+	chain, err := GetSynchronisedCounters().BuildDTMC()
+	if err != nil {
+		t.Fatalf("Unexpected error while building the counters: %v", err)
+	}
+
+	initial := chain.StatesNamed("(0,0)")[0]
+	for _, target := range []string{"(1,1)", "(1,2)", "(2,1)", "(2,2)"} {
+		if p := chain.Probability(initial, chain.StatesNamed(target)[0]); math.Abs(p-0.25) > 1e-12 {
+			t.Errorf("Expected the probability 0.25 from (0,0) to %v, but it was %v.", target, p)
+		}
+	}
+
+	for _, state := range chain.S {
+		values := parseValues(t, state.Name)
+		finished := values[0] >= 4 || values[1] >= 4
+		labels := chain.Labels(state)
+		if (mc.AtomicProposition{Name: "finished"}).In(labels) != finished {
+			t.Errorf("The state %v should be labelled finished exactly when a counter reached 4.", state.Name)
+		}
+		if (mc.AtomicProposition{Name: DeadlockLabel}).In(labels) != finished {
+			t.Errorf("The state %v should be a deadlock exactly when a counter reached 4.", state.Name)
+		}
+		if finished && chain.Probability(state, state) != 1 {
+			t.Errorf("Expected a self-loop in the deadlock state %v.", state.Name)
+		}
+	}
+}
+
+/*
+TestModel_BuildDTMC3
+Description:
+
+	Without synchronisation the modules interleave, and the choices of a DTMC are taken uniformly.
+*/
+func TestModel_BuildDTMC3(t *testing.T) {
+	model, err := Parse(`
+dtmc
+module a
+	x : bool init false;
+	[] !x -> (x'=true);
+endmodule
+module b = a [x=y] endmodule
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing: %v", err)
+	}
+
+	chain, err := model.BuildDTMC()
+	if err != nil {
+		t.Fatalf("Unexpected error while building: %v", err)
+	}
+
+	initial := chain.StatesNamed("(false,false)")[0]
+	for _, target := range []string{"(true,false)", "(false,true)"} {
+		if p := chain.Probability(initial, chain.StatesNamed(target)[0]); math.Abs(p-0.5) > 1e-12 {
+			t.Errorf("Expected the probability 0.5 from (false,false) to %v, but it was %v.", target, p)
+		}
+	}
+	if len(chain.S) != 4 {
+		t.Errorf("Expected 4 states, but there were %v.", len(chain.S))
+	}
+}
+
+/*
+TestModel_BuildDTMC4
+Description:
+
+	Errors found while exploring the states report the position of the command or the assignment and the state.
+*/
+func TestModel_BuildDTMC4(t *testing.T) {
+	testCases := []struct {
+		Source   string
+		Expected string
+	}{
+		{
+			"dtmc\nmodule a\n\tx : [0..2];\n\t[] x<2 -> 0.5 : (x'=x+1) + 0.4 : (x'=0);\nendmodule\n",
+			"The probabilities of the command at line 4, column 2 sum to 0.9 instead of 1 in the state (0).",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..2];\n\t[] true -> (x'=x+1);\nendmodule\n",
+			"The update at line 4, column 14 gives \"x\" the value 3 outside its range [0..2] in the state (2).",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..2];\n\t[] true -> 2 : (x'=0);\nendmodule\n",
+			"The probability \"2\" at line 4, column 13 is 2 in the state (0).",
+		},
+		{
+			"dtmc\nmodule a\n\tx : [0..2];\n\t[] x : (x'=0);\nendmodule\n",
+			"Expected",
+		},
+		{
+			"dtmc\nmodule a\n\tb : bool;\n\t[] b+1>0 -> (b'=true);\nendmodule\n",
+			"line 4",
+		},
+		{
+			"mdp\nmodule a\n\tx : [0..2];\nendmodule\n",
+			"The model is of type mdp, not dtmc.",
+		},
+	}
+
+	for _, testCase := range testCases {
+		model, err := Parse(testCase.Source)
+		if err == nil {
+			_, err = model.BuildDTMC()
+		}
+		if err == nil || !strings.Contains(err.Error(), testCase.Expected) {
+			t.Errorf("Expected an error containing %q for %q, but received %v.", testCase.Expected, testCase.Source, err)
+		}
+	}
+}
+
+/*
+TestModel_BuildMDP1
+Description:
+
+	The actions of the gamble are named after their labels and, without a label, after their module and line.
+	The best scheduler flips the coin, which wins with probability 1/2 rather than 0.4.
+*/
+func TestModel_BuildMDP1(t *testing.T) {
+	mdp, err := GetGamble().BuildMDP()
+	if err != nil {
+		t.Fatalf("Unexpected error while building the gamble: %v", err)
+	}
+
+	initial := mdp.StatesNamed("(0)")[0]
+	actions := strings.Join(mdp.EnabledActions(initial), ",")
+	for _, action := range []string{"stop", "bet", "player@11"} {
+		if !strings.Contains(actions, action) {
+			t.Errorf("Expected the action %v in the initial state, but the actions were %v.", action, actions)
+		}
+	}
+	if p := mdp.Probability(initial, "bet", mdp.StatesNamed("(2)")[0]); math.Abs(p-0.4) > 1e-12 {
+		t.Errorf("Expected the bet to win with probability 0.4, but it was %v.", p)
+	}
+
+	result, err := mdp.ReachabilityProbabilities(mdp.StatesNamed("(2)"), markov.Maximize, markov.DefaultMDPSolverOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error while checking the gamble: %v", err)
+	}
+	if math.Abs(result.Values[initial]-0.5) > 1e-8 {
+		t.Errorf("Expected the maximal probability 0.5 of winning, but it was %v.", result.Values[initial])
+	}
+	if result.Scheduler[initial] != "player@11" {
+		t.Errorf("Expected the best scheduler to flip the coin, but it chose %v.", result.Scheduler[initial])
+	}
+
+	// The deadlocks of an MDP get a self-loop with the action deadlock
+	stopped := mdp.StatesNamed("(3)")[0]
+	if mdp.Probability(stopped, DeadlockLabel, stopped) != 1 {
+		t.Errorf("Expected the deadlock action in the stopped state, but the actions were %v.", mdp.EnabledActions(stopped))
+	}
+}
+
+/*
+TestModel_BuildMDP2
+Description:
+
+	Two enabled commands with the same action are separate choices; the second gets the suffix #2.
+*/
+func TestModel_BuildMDP2(t *testing.T) {
+	model, err := Parse(`
+mdp
+module a
+	x : [0..2] init 0;
+	[go] x=0 -> (x'=1);
+	[go] x=0 -> (x'=2);
+endmodule
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing: %v", err)
+	}
+
+	mdp, err := model.BuildMDP()
+	if err != nil {
+		t.Fatalf("Unexpected error while building: %v", err)
+	}
+	initial := mdp.StatesNamed("(0)")[0]
+	if mdp.Probability(initial, "go", mdp.StatesNamed("(1)")[0]) != 1 || mdp.Probability(initial, "go#2", mdp.StatesNamed("(2)")[0]) != 1 {
+		t.Errorf("Expected the actions go and go#2, but the actions were %v.", mdp.EnabledActions(initial))
+	}
+}
+
+/*
+TestModel_BuildCTMC1
+Description:
+
+	The repairable machine has the rates of the PRISM model, and is down one third of the time.
+*/
+func TestModel_BuildCTMC1(t *testing.T) {
+	chain, err := GetRepairableMachine().BuildCTMC()
+	if err != nil {
+		t.Fatalf("Unexpected error while building the machine: %v", err)
+	}
+
+	up, down := chain.StatesNamed("(true)")[0], chain.StatesNamed("(false)")[0]
+	if chain.Rate(up, down) != 1 || chain.Rate(down, up) != 2 {
+		t.Errorf("Expected the rates 1 and 2, but they were %v and %v.", chain.Rate(up, down), chain.Rate(down, up))
+	}
+
+	formula, _ := markov.ParseCSLFormula("S=? [ down ]")
+	values, err := chain.Values(formula, markov.DefaultSolverOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error while checking the machine: %v", err)
+	}
+	if math.Abs(values[up]-1.0/3) > 1e-8 {
+		t.Errorf("Expected the machine to be down one third of the time, but it was %v.", values[up])
+	}
+
+	if _, err = GetRepairableMachine().BuildDTMC(); err == nil {
+		t.Errorf("Expected an error when building a ctmc as a DTMC.")
+	}
+}
+
+/*
+TestModel_BuildCTMC2
+Description:
+
+	Synchronised rates multiply, and the rates of the commands which lead to the same state add up.
+*/
+func TestModel_BuildCTMC2(t *testing.T) {
+	model, err := Parse(`
+ctmc
+module a
+	x : [0..1] init 0;
+	[go] x=0 -> 2 : (x'=1);
+	[] x=0 -> 0.5 : (x'=1);
+endmodule
+module b
+	y : [0..1] init 0;
+	[go] y=0 -> 3 : (y'=1);
+endmodule
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing: %v", err)
+	}
+
+	chain, err := model.BuildCTMC()
+	if err != nil {
+		t.Fatalf("Unexpected error while building: %v", err)
+	}
+
+	initial := chain.StatesNamed("(0,0)")[0]
+	if rate := chain.Rate(initial, chain.StatesNamed("(1,1)")[0]); rate != 6 {
+		t.Errorf("Expected the synchronised rate 6, but it was %v.", rate)
+	}
+	if rate := chain.Rate(initial, chain.StatesNamed("(1,0)")[0]); rate != 0.5 {
+		t.Errorf("Expected the rate 0.5, but it was %v.", rate)
+	}
+}
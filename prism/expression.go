@@ -0,0 +1,429 @@
+/*
+expression.go
+Description:
+	The values of PRISM expressions and their evaluation in a state. Integers and doubles are both stored as
+	float64 (integers are exact up to 2^53); division always gives a double, as in PRISM.
+*/
+
+package prism
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+Type Definitions
+*/
+
+type ValueType int
+
+const (
+	IntType ValueType = iota
+	DoubleType
+	BoolType
+)
+
+/*
+Value
+Description:
+	The value of an expression: Number for integers and doubles, Bool for booleans.
+*/
+type Value struct {
+	Type   ValueType
+	Number float64
+	Bool   bool
+}
+
+/*
+evaluator
+Description:
+	Evaluates expressions in a state. State holds the values of the variables in the order of their indices,
+	with booleans stored as 0 and 1.
+*/
+type evaluator struct {
+	Constants     map[string]Value
+	Formulas      map[string]Expression
+	VariableIndex map[string]int
+	VariableTypes []ValueType
+	State         []int
+}
+
+/*
+Functions
+*/
+
+/*
+IntValue
+Description:
+	The integer value n.
+*/
+func IntValue(n int) Value {
+	return Value{Type: IntType, Number: float64(n)}
+}
+
+/*
+DoubleValue
+Description:
+	The double value x.
+*/
+func DoubleValue(x float64) Value {
+	return Value{Type: DoubleType, Number: x}
+}
+
+/*
+BoolValue
+Description:
+	The boolean value b.
+*/
+func BoolValue(b bool) Value {
+	return Value{Type: BoolType, Bool: b}
+}
+
+/*
+String
+Description:
+	Returns the keyword of the type.
+*/
+func (valueType ValueType) String() string {
+	switch valueType {
+	case IntType:
+		return "int"
+	case DoubleType:
+		return "double"
+	case BoolType:
+		return "bool"
+	default:
+		return fmt.Sprintf("ValueType(%v)", int(valueType))
+	}
+}
+
+/*
+String
+Description:
+	Prints the value as a PRISM literal. Doubles with an integer value keep a decimal point.
+*/
+func (value Value) String() string {
+	switch value.Type {
+	case BoolType:
+		return fmt.Sprintf("%v", value.Bool)
+	case DoubleType:
+		if value.Number == math.Trunc(value.Number) && !math.IsInf(value.Number, 0) {
+			return formatNumber(value.Number) + ".0"
+		}
+		return formatNumber(value.Number)
+	default:
+		return formatNumber(value.Number)
+	}
+}
+
+/*
+IsNumeric
+Description:
+	Returns true for integers and doubles.
+*/
+func (value Value) IsNumeric() bool {
+	return value.Type == IntType || value.Type == DoubleType
+}
+
+/*
+convertTo
+Description:
+	Converts the value to a variable or constant of the given type. Integers can be used as doubles, but no
+	other conversion is allowed.
+*/
+func (value Value) convertTo(valueType ValueType) (Value, bool) {
+	switch {
+	case value.Type == valueType:
+		return value, true
+	case value.Type == IntType && valueType == DoubleType:
+		return DoubleValue(value.Number), true
+	default:
+		return Value{}, false
+	}
+}
+
+/*
+variableValue
+Description:
+	Returns the value of the variable with the given index.
+*/
+func (e evaluator) variableValue(index int) Value {
+	if e.VariableTypes[index] == BoolType {
+		return BoolValue(e.State[index] != 0)
+	}
+	return IntValue(e.State[index])
+}
+
+/*
+evaluateBool
+Description:
+	Evaluates an expression which must be a boolean, such as a guard. The description names the expression in errors.
+*/
+func (e evaluator) evaluateBool(expression Expression, description string) (bool, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return false, err
+	}
+
+	if value.Type != BoolType {
+		return false, fmt.Errorf("The %v \"%v\" at %v is not a boolean.", description, expression, expression.Position)
+	}
+	return value.Bool, nil
+}
+
+/*
+evaluateNumber
+Description:
+	Evaluates an expression which must be a number, such as a probability. The description names the expression in errors.
+*/
+func (e evaluator) evaluateNumber(expression Expression, description string) (float64, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return 0, err
+	}
+
+	if !value.IsNumeric() {
+		return 0, fmt.Errorf("The %v \"%v\" at %v is not a number.", description, expression, expression.Position)
+	}
+	return value.Number, nil
+}
+
+/*
+evaluate
+Description:
+	Evaluates the expression in the state of the evaluator.
+*/
+func (e evaluator) evaluate(expression Expression) (Value, error) {
+	switch expression.Operator {
+	case OpLiteral:
+		return expression.Value, nil
+
+	case OpIdentifier:
+		if index, isVariable := e.VariableIndex[expression.Name]; isVariable {
+			return e.variableValue(index), nil
+		}
+		if value, isConstant := e.Constants[expression.Name]; isConstant {
+			return value, nil
+		}
+		if formula, isFormula := e.Formulas[expression.Name]; isFormula {
+			return e.evaluate(formula)
+		}
+		return Value{}, fmt.Errorf("Unknown identifier \"%v\" at %v.", expression.Name, expression.Position)
+
+	case OpFunction:
+		return e.evaluateFunction(expression)
+
+	case OpConditional:
+		condition, err := e.evaluateBool(expression.Operands[0], "condition")
+		if err != nil {
+			return Value{}, err
+		}
+
+		branches := make([]Value, 2)
+		for b := range branches {
+			if branches[b], err = e.evaluate(expression.Operands[b+1]); err != nil {
+				return Value{}, err
+			}
+		}
+
+		resultType, err := commonType(expression, branches...)
+		if err != nil {
+			return Value{}, err
+		}
+		chosen := branches[1]
+		if condition {
+			chosen = branches[0]
+		}
+		chosen, _ = chosen.convertTo(resultType)
+		return chosen, nil
+	}
+
+	operands := make([]Value, len(expression.Operands))
+	for i, operand := range expression.Operands {
+		var err error
+		if operands[i], err = e.evaluate(operand); err != nil {
+			return Value{}, err
+		}
+	}
+
+	switch expression.Operator {
+	case OpNot:
+		if operands[0].Type != BoolType {
+			return Value{}, operandError(expression, "a boolean")
+		}
+		return BoolValue(!operands[0].Bool), nil
+
+	case OpNegate:
+		if !operands[0].IsNumeric() {
+			return Value{}, operandError(expression, "a number")
+		}
+		return Value{Type: operands[0].Type, Number: -operands[0].Number}, nil
+
+	case OpAnd, OpOr, OpImplies, OpIff:
+		if operands[0].Type != BoolType || operands[1].Type != BoolType {
+			return Value{}, operandError(expression, "booleans")
+		}
+		left, right := operands[0].Bool, operands[1].Bool
+		switch expression.Operator {
+		case OpAnd:
+			return BoolValue(left && right), nil
+		case OpOr:
+			return BoolValue(left || right), nil
+		case OpImplies:
+			return BoolValue(!left || right), nil
+		default:
+			return BoolValue(left == right), nil
+		}
+
+	case OpEqual, OpNotEqual:
+		var equal bool
+		switch {
+		case operands[0].Type == BoolType && operands[1].Type == BoolType:
+			equal = operands[0].Bool == operands[1].Bool
+		case operands[0].IsNumeric() && operands[1].IsNumeric():
+			equal = operands[0].Number == operands[1].Number
+		default:
+			return Value{}, operandError(expression, "two booleans or two numbers")
+		}
+		return BoolValue(equal == (expression.Operator == OpEqual)), nil
+
+	case OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual:
+		if !operands[0].IsNumeric() || !operands[1].IsNumeric() {
+			return Value{}, operandError(expression, "numbers")
+		}
+		left, right := operands[0].Number, operands[1].Number
+		switch expression.Operator {
+		case OpLess:
+			return BoolValue(left < right), nil
+		case OpLessOrEqual:
+			return BoolValue(left <= right), nil
+		case OpGreater:
+			return BoolValue(left > right), nil
+		default:
+			return BoolValue(left >= right), nil
+		}
+
+	case OpPlus, OpMinus, OpTimes, OpDivide:
+		resultType, err := commonType(expression, operands...)
+		if err != nil || resultType == BoolType {
+			return Value{}, operandError(expression, "numbers")
+		}
+		left, right := operands[0].Number, operands[1].Number
+		switch expression.Operator {
+		case OpPlus:
+			return Value{Type: resultType, Number: left + right}, nil
+		case OpMinus:
+			return Value{Type: resultType, Number: left - right}, nil
+		case OpTimes:
+			return Value{Type: resultType, Number: left * right}, nil
+		default:
+			return DoubleValue(left / right), nil
+		}
+
+	default:
+		return Value{}, fmt.Errorf("Unrecognized operator %v at %v.", expression.Operator, expression.Position)
+	}
+}
+
+/*
+evaluateFunction
+Description:
+	Evaluates a call of min, max, floor, ceil, pow or mod.
+*/
+func (e evaluator) evaluateFunction(expression Expression) (Value, error) {
+	arguments := make([]Value, len(expression.Operands))
+	for i, operand := range expression.Operands {
+		var err error
+		if arguments[i], err = e.evaluate(operand); err != nil {
+			return Value{}, err
+		}
+		if !arguments[i].IsNumeric() {
+			return Value{}, fmt.Errorf("The arguments of %v at %v must be numbers, but \"%v\" is not.", expression.Name, expression.Position, operand)
+		}
+	}
+
+	arity := map[string]int{"floor": 1, "ceil": 1, "pow": 2, "mod": 2}[expression.Name]
+	if (arity > 0 && len(arguments) != arity) || (arity == 0 && len(arguments) < 2) {
+		return Value{}, fmt.Errorf("The function %v at %v has the wrong number of arguments (%v).", expression.Name, expression.Position, len(arguments))
+	}
+
+	resultType, _ := commonType(expression, arguments...)
+	switch expression.Name {
+	case "min", "max":
+		result := arguments[0].Number
+		for _, argument := range arguments[1:] {
+			if expression.Name == "min" {
+				result = math.Min(result, argument.Number)
+			} else {
+				result = math.Max(result, argument.Number)
+			}
+		}
+		return Value{Type: resultType, Number: result}, nil
+
+	case "floor":
+		return IntValue(int(math.Floor(arguments[0].Number))), nil
+
+	case "ceil":
+		return IntValue(int(math.Ceil(arguments[0].Number))), nil
+
+	case "pow":
+		result := math.Pow(arguments[0].Number, arguments[1].Number)
+		if resultType == IntType && arguments[1].Number >= 0 {
+			return IntValue(int(result)), nil
+		}
+		return DoubleValue(result), nil
+
+	case "mod":
+		if resultType != IntType {
+			return Value{}, fmt.Errorf("The arguments of mod at %v must be integers.", expression.Position)
+		}
+		if arguments[1].Number == 0 {
+			return Value{}, fmt.Errorf("Division by zero in mod at %v.", expression.Position)
+		}
+		n, d := int(arguments[0].Number), int(arguments[1].Number)
+		result := n % d
+		if result < 0 {
+			result += d
+		}
+		return IntValue(result), nil
+
+	default:
+		return Value{}, fmt.Errorf("Unknown function \"%v\" at %v.", expression.Name, expression.Position)
+	}
+}
+
+/*
+commonType
+Description:
+	Returns the type of a result which combines the values: bool if they are all booleans, int if they are all
+	integers, and double if they are numbers and one of them is a double.
+*/
+func commonType(expression Expression, values ...Value) (ValueType, error) {
+	allBool, allInt, allNumeric := true, true, true
+	for _, value := range values {
+		allBool = allBool && value.Type == BoolType
+		allInt = allInt && value.Type == IntType
+		allNumeric = allNumeric && value.IsNumeric()
+	}
+
+	switch {
+	case allBool:
+		return BoolType, nil
+	case allInt:
+		return IntType, nil
+	case allNumeric:
+		return DoubleType, nil
+	default:
+		return BoolType, fmt.Errorf("The expression \"%v\" at %v mixes booleans and numbers.", expression, expression.Position)
+	}
+}
+
+/*
+operandError
+Description:
+	Returns the error for operands of the wrong type.
+*/
+func operandError(expression Expression, expected string) error {
+	return fmt.Errorf("The operands of \"%v\" at %v must be %v.", expression, expression.Position, expected)
+}
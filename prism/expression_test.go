@@ -0,0 +1,152 @@
+/*
+expression_test.go
+Description:
+
+	Tests for the values and the evaluation of expressions defined in expression.go
+*/
+package prism
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+GetEvaluator
+Description:
+
+	An evaluator with the constant N=3, the formula big = x > N, the integer variable x=5 and the boolean b=true.
+*/
+func GetEvaluator(t *testing.T) evaluator {
+	return evaluator{
+		Constants:     map[string]Value{"N": IntValue(3)},
+		Formulas:      map[string]Expression{"big": parseExpressionString(t, "x > N")},
+		VariableIndex: map[string]int{"x": 0, "b": 1},
+		VariableTypes: []ValueType{IntType, BoolType},
+		State:         []int{5, 1},
+	}
+}
+
+/*
+TestValue_String1
+Description:
+
+	Values print as PRISM literals, and doubles keep their decimal point.
+*/
+func TestValue_String1(t *testing.T) {
+	testCases := map[string]Value{
+		"3":     IntValue(3),
+		"3.0":   DoubleValue(3),
+		"0.25":  DoubleValue(0.25),
+		"false": BoolValue(false),
+	}
+	for expected, value := range testCases {
+		if value.String() != expected {
+			t.Errorf("Expected \"%v\", but received \"%v\".", expected, value)
+		}
+	}
+}
+
+/*
+TestValue_convertTo1
+Description:
+
+	Integers can be converted to doubles, but doubles cannot become integers and numbers cannot become booleans.
+*/
+func TestValue_convertTo1(t *testing.T) {
+	if converted, ok := IntValue(2).convertTo(DoubleType); !ok || converted != DoubleValue(2) {
+		t.Errorf("Expected the integer 2 to become the double 2.0, but received %v.", converted)
+	}
+	if _, ok := DoubleValue(2).convertTo(IntType); ok {
+		t.Errorf("A double should not be converted to an integer.")
+	}
+	if _, ok := IntValue(1).convertTo(BoolType); ok {
+		t.Errorf("An integer should not be converted to a boolean.")
+	}
+}
+
+/*
+TestEvaluator_evaluate1
+Description:
+
+	Evaluates expressions with constants, formulas, variables and functions.
+*/
+func TestEvaluator_evaluate1(t *testing.T) {
+	e := GetEvaluator(t)
+	testCases := map[string]Value{
+		"x + N * 2":        IntValue(11),
+		"x / 2":            DoubleValue(2.5),
+		"big & b":          BoolValue(true),
+		"!b | x = 4":       BoolValue(false),
+		"b => x >= 5":      BoolValue(true),
+		"b <=> x < 5":      BoolValue(false),
+		"x != N ? 0.5 : 1": DoubleValue(0.5),
+		"min(x, N, 4)":     IntValue(3),
+		"max(x, 2.5)":      DoubleValue(5),
+		"floor(x / 2)":     IntValue(2),
+		"ceil(x / 2)":      IntValue(3),
+		"pow(2, N)":        IntValue(8),
+		"pow(2, -1)":       DoubleValue(0.5),
+		"mod(-x, N)":       IntValue(1),
+		"-(x - 7) * 1.5":   DoubleValue(3),
+	}
+
+	for source, expected := range testCases {
+		value, err := e.evaluate(parseExpressionString(t, source))
+		if err != nil {
+			t.Errorf("Unexpected error while evaluating \"%v\": %v", source, err)
+			continue
+		}
+		if value != expected {
+			t.Errorf("Expected \"%v\" to be %v (%v), but it was %v (%v).", source, expected, expected.Type, value, value.Type)
+		}
+	}
+}
+
+/*
+TestEvaluator_evaluate2
+Description:
+
+	Type errors and unknown identifiers are reported with the position of the expression.
+*/
+func TestEvaluator_evaluate2(t *testing.T) {
+	e := GetEvaluator(t)
+	testCases := map[string]string{
+		"x + b":       "The operands of \"x+b\" at line 1, column 1 must be numbers.",
+		"b ? x : b":   "The expression \"b ? x : b\" at line 1, column 1 mixes booleans and numbers.",
+		"y = 1":       "Unknown identifier \"y\" at line 1, column 1.",
+		"x & b":       "line 1, column 1",
+		"mod(x, 0)":   "Division by zero in mod at line 1, column 1.",
+		"floor(1, 2)": "wrong number of arguments",
+		"2 * (!b)":    "line 1, column 1",
+	}
+
+	for source, expected := range testCases {
+		_, err := e.evaluate(parseExpressionString(t, source))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error containing %q for \"%v\", but received %v.", expected, source, err)
+		}
+	}
+}
+
+/*
+TestEvaluator_evaluateBool1
+Description:
+
+	Guards and labels must be booleans.
+*/
+func TestEvaluator_evaluateBool1(t *testing.T) {
+	e := GetEvaluator(t)
+	if holds, err := e.evaluateBool(parseExpressionString(t, "big"), "guard"); err != nil || !holds {
+		t.Errorf("Expected the guard big to hold, but received %v and %v.", holds, err)
+	}
+
+	_, err := e.evaluateBool(parseExpressionString(t, "x + 1"), "guard")
+	if err == nil || err.Error() != "The guard \"x+1\" at line 1, column 1 is not a boolean." {
+		t.Errorf("Expected an error about a guard which is not a boolean, but received %v.", err)
+	}
+
+	if _, err = e.evaluateNumber(parseExpressionString(t, "b"), "rate"); err == nil {
+		t.Errorf("Expected an error about a rate which is not a number.")
+	}
+}
@@ -0,0 +1,161 @@
+/*
+lexer.go
+Description:
+	Splits the source of a PRISM model into tokens. Comments start with // and end at the end of the line.
+*/
+
+package prism
+
+import (
+	"fmt"
+	"unicode"
+)
+
+/*
+Type Definitions
+*/
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdentifier
+	tokenInteger
+	tokenDouble
+	tokenString
+	tokenSymbol
+)
+
+/*
+token
+Description:
+	A token of the source. Text is the identifier, the number, the symbol or the contents of a string.
+*/
+type token struct {
+	Kind     tokenKind
+	Text     string
+	Position Position
+}
+
+// Symbols, longest first so that "<=>" is not read as "<=" followed by ">"
+var symbols = []string{
+	"<=>", "..", "=>", "->", "<=", ">=", "!=",
+	"(", ")", "[", "]", "{", "}", ";", ":", ",", "'", "=", "<", ">", "+", "-", "*", "/", "&", "|", "!", "?",
+}
+
+/*
+Functions
+*/
+
+/*
+tokenize
+Description:
+	Returns the tokens of the source, ending with a token of kind tokenEnd.
+*/
+func tokenize(source string) ([]token, error) {
+	runes := []rune(source)
+	var tokens []token
+	line, column := 1, 1
+
+	advance := func(count int) {
+		for ; count > 0; count-- {
+			if runes[0] == '\n' {
+				line, column = line+1, 1
+			} else {
+				column++
+			}
+			runes = runes[1:]
+		}
+	}
+
+	for len(runes) > 0 {
+		r := runes[0]
+		position := Position{Line: line, Column: column}
+
+		switch {
+		case unicode.IsSpace(r):
+			advance(1)
+
+		case r == '/' && len(runes) > 1 && runes[1] == '/':
+			for len(runes) > 0 && runes[0] != '\n' {
+				advance(1)
+			}
+
+		case unicode.IsLetter(r) || r == '_':
+			length := 0
+			for length < len(runes) && (unicode.IsLetter(runes[length]) || unicode.IsDigit(runes[length]) || runes[length] == '_') {
+				length++
+			}
+			tokens = append(tokens, token{Kind: tokenIdentifier, Text: string(runes[:length]), Position: position})
+			advance(length)
+
+		case unicode.IsDigit(r) || (r == '.' && len(runes) > 1 && unicode.IsDigit(runes[1])):
+			length, kind := numberLength(runes)
+			tokens = append(tokens, token{Kind: kind, Text: string(runes[:length]), Position: position})
+			advance(length)
+
+		case r == '"':
+			length := 1
+			for length < len(runes) && runes[length] != '"' && runes[length] != '\n' {
+				length++
+			}
+			if length == len(runes) || runes[length] != '"' {
+				return nil, fmt.Errorf("The string starting at %v is never closed.", position)
+			}
+			tokens = append(tokens, token{Kind: tokenString, Text: string(runes[1:length]), Position: position})
+			advance(length + 1)
+
+		default:
+			symbol := ""
+			for _, candidate := range symbols {
+				if len(runes) >= len([]rune(candidate)) && string(runes[:len([]rune(candidate))]) == candidate {
+					symbol = candidate
+					break
+				}
+			}
+			if symbol == "" {
+				return nil, fmt.Errorf("Unexpected character '%v' at %v.", string(r), position)
+			}
+			tokens = append(tokens, token{Kind: tokenSymbol, Text: symbol, Position: position})
+			advance(len([]rune(symbol)))
+		}
+	}
+
+	return append(tokens, token{Kind: tokenEnd, Text: "end of file", Position: Position{Line: line, Column: column}}), nil
+}
+
+/*
+numberLength
+Description:
+	Returns the length of the number at the start of the runes, and whether it is an integer or a double.
+	A dot followed by another dot ends an integer, so that ranges such as [0..N] are read correctly.
+*/
+func numberLength(runes []rune) (int, tokenKind) {
+	length, kind := 0, tokenInteger
+	digits := func() {
+		for length < len(runes) && unicode.IsDigit(runes[length]) {
+			length++
+		}
+	}
+
+	digits()
+	if length < len(runes) && runes[length] == '.' && !(length+1 < len(runes) && runes[length+1] == '.') {
+		kind = tokenDouble
+		length++
+		digits()
+	}
+
+	if length < len(runes) && (runes[length] == 'e' || runes[length] == 'E') {
+		exponent := length + 1
+		if exponent < len(runes) && (runes[exponent] == '+' || runes[exponent] == '-') {
+			exponent++
+		}
+		if exponent < len(runes) && unicode.IsDigit(runes[exponent]) {
+			kind = tokenDouble
+			length = exponent
+			digits()
+		}
+	}
+
+	return length, kind
+}
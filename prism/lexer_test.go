@@ -0,0 +1,84 @@
+/*
+lexer_test.go
+Description:
+
+	Tests for the tokenizer defined in lexer.go
+*/
+package prism
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+TestTokenize1
+Description:
+
+	Tokenizes a command, checking the kinds, the texts and the positions of the tokens.
+*/
+func TestTokenize1(t *testing.T) {
+	tokens, err := tokenize("// a comment\n[go] x<=N -> 0.5 : (x'=x+1);")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"[", "go", "]", "x", "<=", "N", "->", "0.5", ":", "(", "x", "'", "=", "x", "+", "1", ")", ";", "end of file"}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %v tokens, but there were %v.", len(expected), len(tokens))
+	}
+	for i, text := range expected {
+		if tokens[i].Text != text {
+			t.Errorf("Expected the token %v to be \"%v\", but it was \"%v\".", i, text, tokens[i].Text)
+		}
+	}
+
+	if tokens[0].Position != (Position{Line: 2, Column: 1}) {
+		t.Errorf("Expected the first token at line 2, column 1, but it was at %v.", tokens[0].Position)
+	}
+	if tokens[7].Kind != tokenDouble || tokens[15].Kind != tokenInteger || tokens[1].Kind != tokenIdentifier {
+		t.Errorf("The kinds of the tokens were not recognized correctly.")
+	}
+	if tokens[7].Position != (Position{Line: 2, Column: 14}) {
+		t.Errorf("Expected 0.5 at line 2, column 14, but it was at %v.", tokens[7].Position)
+	}
+}
+
+/*
+TestTokenize2
+Description:
+
+	A range [0..N] is an integer followed by the symbol .., and numbers may have exponents.
+*/
+func TestTokenize2(t *testing.T) {
+	tokens, err := tokenize("[0..10] 1e-3 2.5E2 .5 \"label\"")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var texts []string
+	for _, token := range tokens {
+		texts = append(texts, token.Text)
+	}
+	if strings.Join(texts, " ") != "[ 0 .. 10 ] 1e-3 2.5E2 .5 label end of file" {
+		t.Errorf("The tokens were %v.", texts)
+	}
+	if tokens[1].Kind != tokenInteger || tokens[5].Kind != tokenDouble || tokens[8].Kind != tokenString {
+		t.Errorf("The kinds of the tokens were not recognized correctly.")
+	}
+}
+
+/*
+TestTokenize3
+Description:
+
+	Unknown characters and unclosed strings are reported with their positions.
+*/
+func TestTokenize3(t *testing.T) {
+	if _, err := tokenize("x = 1;\n  y # 2"); err == nil || err.Error() != "Unexpected character '#' at line 2, column 5." {
+		t.Errorf("Expected an error about the character #, but received %v.", err)
+	}
+	if _, err := tokenize("label \"done = x"); err == nil || !strings.Contains(err.Error(), "line 1, column 7") {
+		t.Errorf("Expected an error about the unclosed string, but received %v.", err)
+	}
+}
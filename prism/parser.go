@@ -0,0 +1,833 @@
+/*
+parser.go
+Description:
+	A recursive descent parser for the core of the PRISM modelling language. The supported declarations are
+	the model type (dtmc, mdp or ctmc, or their long names probabilistic, nondeterministic and stochastic),
+	constants, formulas, labels and modules, including renamed copies of modules such as
+	"module P2 = P1 [ x1=x2, go1=go2 ] endmodule". Reward structures (rewards...endrewards) are parsed and
+	ignored, since the models built from PRISM files have no rewards. Global variables, init...endinit and
+	system...endsystem are not supported.
+*/
+
+package prism
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+/*
+Type Definitions
+*/
+
+type parser struct {
+	Tokens []token
+	Index  int
+}
+
+var modelTypeKeywords = map[string]ModelType{
+	"dtmc":             DTMC,
+	"probabilistic":    DTMC,
+	"mdp":              MDP,
+	"nondeterministic": MDP,
+	"ctmc":             CTMC,
+	"stochastic":       CTMC,
+}
+
+var unsupportedKeywords = map[string]string{
+	"global":  "Global variables",
+	"init":    "Initial state sets (init...endinit)",
+	"system":  "System definitions (system...endsystem)",
+}
+
+var functionNames = map[string]bool{"min": true, "max": true, "floor": true, "ceil": true, "pow": true, "mod": true}
+
+/*
+Functions
+*/
+
+/*
+Parse
+Description:
+	Parses the source of a PRISM model.
+Usage:
+	model, err := Parse(`
+		dtmc
+		module coin
+			heads : bool init false;
+			[] !heads -> 0.5 : (heads'=true) + 0.5 : true;
+		endmodule
+		label "heads" = heads;
+	`)
+*/
+func Parse(source string) (Model, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return Model{}, err
+	}
+
+	p := parser{Tokens: tokens}
+	return p.parseModel()
+}
+
+/*
+ParseFile
+Description:
+	Reads and parses the PRISM model in the file.
+*/
+func ParseFile(filename string) (Model, error) {
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		return Model{}, fmt.Errorf("There was an issue reading the model \"%v\": %v", filename, err)
+	}
+	return Parse(string(source))
+}
+
+/*
+peek
+Description:
+	Returns the token at the given offset from the current one without consuming it.
+*/
+func (p *parser) peek(offset int) token {
+	if p.Index+offset >= len(p.Tokens) {
+		return p.Tokens[len(p.Tokens)-1]
+	}
+	return p.Tokens[p.Index+offset]
+}
+
+/*
+next
+Description:
+	Consumes and returns the next token.
+*/
+func (p *parser) next() token {
+	t := p.peek(0)
+	if t.Kind != tokenEnd {
+		p.Index++
+	}
+	return t
+}
+
+/*
+is
+Description:
+	Returns true if the next token is the symbol or keyword.
+*/
+func (p *parser) is(text string) bool {
+	t := p.peek(0)
+	return (t.Kind == tokenSymbol || t.Kind == tokenIdentifier) && t.Text == text
+}
+
+/*
+expect
+Description:
+	Consumes the next token and returns an error if it is not the symbol or keyword.
+*/
+func (p *parser) expect(text string) (token, error) {
+	if !p.is(text) {
+		return token{}, p.unexpected(fmt.Sprintf("\"%v\"", text))
+	}
+	return p.next(), nil
+}
+
+/*
+expectIdentifier
+Description:
+	Consumes the next token and returns an error if it is not an identifier.
+*/
+func (p *parser) expectIdentifier(description string) (token, error) {
+	if p.peek(0).Kind != tokenIdentifier {
+		return token{}, p.unexpected(description)
+	}
+	return p.next(), nil
+}
+
+/*
+unexpected
+Description:
+	Returns the error for an unexpected next token.
+*/
+func (p *parser) unexpected(expected string) error {
+	t := p.peek(0)
+	return fmt.Errorf("Expected %v at %v, but found \"%v\".", expected, t.Position, t.Text)
+}
+
+/*
+parseModel
+Description:
+	model := modelType { constant | formula | label | module | rewards }
+	Reward structures are checked for syntax errors and then dropped.
+*/
+func (p *parser) parseModel() (Model, error) {
+	var model Model
+
+	typeToken := p.peek(0)
+	modelType, isModelType := modelTypeKeywords[typeToken.Text]
+	if typeToken.Kind != tokenIdentifier || !isModelType {
+		return Model{}, p.unexpected("the model type (dtmc, mdp or ctmc)")
+	}
+	p.next()
+	model.Type = modelType
+
+	for p.peek(0).Kind != tokenEnd {
+		t := p.peek(0)
+		var err error
+		switch {
+		case p.is("const"):
+			var constant Constant
+			if constant, err = p.parseConstant(); err == nil {
+				model.Constants = append(model.Constants, constant)
+			}
+		case p.is("formula"):
+			var formula Formula
+			if formula, err = p.parseFormula(); err == nil {
+				model.Formulas = append(model.Formulas, formula)
+			}
+		case p.is("label"):
+			var label Label
+			if label, err = p.parseLabel(); err == nil {
+				model.Labels = append(model.Labels, label)
+			}
+		case p.is("module"):
+			var module Module
+			if module, err = p.parseModule(model.Modules); err == nil {
+				model.Modules = append(model.Modules, module)
+			}
+		case p.is("rewards"):
+			err = p.skipRewards()
+		case t.Kind == tokenIdentifier && unsupportedKeywords[t.Text] != "":
+			err = fmt.Errorf("%v are not supported (at %v).", unsupportedKeywords[t.Text], t.Position)
+		default:
+			err = p.unexpected("a declaration (const, formula, label or module)")
+		}
+		if err != nil {
+			return Model{}, err
+		}
+	}
+
+	return model, nil
+}
+
+/*
+parseConstant
+Description:
+	constant := "const" [ "int" | "double" | "bool" ] name [ "=" expression ] ";"
+	A constant without a type is an integer.
+*/
+func (p *parser) parseConstant() (Constant, error) {
+	start := p.next().Position
+	constant := Constant{Type: IntType, Position: start}
+
+	types := map[string]ValueType{"int": IntType, "double": DoubleType, "bool": BoolType}
+	if valueType, isType := types[p.peek(0).Text]; isType && p.peek(1).Kind == tokenIdentifier {
+		constant.Type = valueType
+		p.next()
+	}
+
+	name, err := p.expectIdentifier("the name of the constant")
+	if err != nil {
+		return Constant{}, err
+	}
+	constant.Name = name.Text
+
+	if p.is("=") {
+		p.next()
+		value, err := p.parseExpression()
+		if err != nil {
+			return Constant{}, err
+		}
+		constant.Value = &value
+	}
+
+	_, err = p.expect(";")
+	return constant, err
+}
+
+/*
+parseFormula
+Description:
+	formula := "formula" name "=" expression ";"
+*/
+func (p *parser) parseFormula() (Formula, error) {
+	start := p.next().Position
+
+	name, err := p.expectIdentifier("the name of the formula")
+	if err != nil {
+		return Formula{}, err
+	}
+
+	if _, err = p.expect("="); err != nil {
+		return Formula{}, err
+	}
+
+	expression, err := p.parseExpression()
+	if err != nil {
+		return Formula{}, err
+	}
+
+	_, err = p.expect(";")
+	return Formula{Name: name.Text, Expression: expression, Position: start}, err
+}
+
+/*
+parseLabel
+Description:
+	label := "label" "\"" name "\"" "=" expression ";"
+*/
+func (p *parser) parseLabel() (Label, error) {
+	start := p.next().Position
+
+	if p.peek(0).Kind != tokenString {
+		return Label{}, p.unexpected("the name of the label in double quotes")
+	}
+	name := p.next()
+
+	if _, err := p.expect("="); err != nil {
+		return Label{}, err
+	}
+
+	expression, err := p.parseExpression()
+	if err != nil {
+		return Label{}, err
+	}
+
+	_, err = p.expect(";")
+	return Label{Name: name.Text, Expression: expression, Position: start}, err
+}
+
+/*
+skipRewards
+Description:
+	rewards := "rewards" [ "\"" name "\"" ] { [ "[" [ action ] "]" ] expression ":" expression ";" } "endrewards"
+	Parses a reward structure and discards it.
+*/
+func (p *parser) skipRewards() error {
+	p.next()
+	if p.peek(0).Kind == tokenString {
+		p.next()
+	}
+
+	for !p.is("endrewards") {
+		if p.is("[") {
+			p.next()
+			if p.peek(0).Kind == tokenIdentifier {
+				p.next()
+			}
+			if _, err := p.expect("]"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := p.parseExpression(); err != nil {
+			return err
+		}
+		if _, err := p.expect(":"); err != nil {
+			return err
+		}
+		if _, err := p.parseExpression(); err != nil {
+			return err
+		}
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+
+	p.next()
+	return nil
+}
+
+/*
+parseModule
+Description:
+	module := "module" name { variable | command } "endmodule"
+		| "module" name "=" name "[" name "=" name { "," name "=" name } "]" "endmodule"
+	Renamed modules are copies of one of the previous modules.
+*/
+func (p *parser) parseModule(previous []Module) (Module, error) {
+	start := p.next().Position
+
+	name, err := p.expectIdentifier("the name of the module")
+	if err != nil {
+		return Module{}, err
+	}
+
+	if p.is("=") {
+		p.next()
+		return p.parseRenamedModule(name.Text, start, previous)
+	}
+
+	module := Module{Name: name.Text, Position: start}
+	for !p.is("endmodule") {
+		switch {
+		case p.is("["):
+			command, err := p.parseCommand()
+			if err != nil {
+				return Module{}, err
+			}
+			module.Commands = append(module.Commands, command)
+		case p.peek(0).Kind == tokenIdentifier && p.peek(1).Text == ":":
+			variable, err := p.parseVariable()
+			if err != nil {
+				return Module{}, err
+			}
+			module.Variables = append(module.Variables, variable)
+		default:
+			return Module{}, p.unexpected("a variable, a command or \"endmodule\"")
+		}
+	}
+	p.next()
+
+	return module, nil
+}
+
+/*
+parseRenamedModule
+Description:
+	Parses the renaming of a module after "module name =" and returns the renamed copy. Every variable of the
+	original module must be renamed.
+*/
+func (p *parser) parseRenamedModule(name string, start Position, previous []Module) (Module, error) {
+	baseToken, err := p.expectIdentifier("the name of the module to rename")
+	if err != nil {
+		return Module{}, err
+	}
+
+	var base *Module
+	for index := range previous {
+		if previous[index].Name == baseToken.Text {
+			base = &previous[index]
+		}
+	}
+	if base == nil {
+		return Module{}, fmt.Errorf("The module \"%v\" at %v is not declared before it is renamed.", baseToken.Text, baseToken.Position)
+	}
+
+	if _, err = p.expect("["); err != nil {
+		return Module{}, err
+	}
+
+	renaming := make(map[string]string)
+	for {
+		oldName, err := p.expectIdentifier("the name to rename")
+		if err != nil {
+			return Module{}, err
+		}
+		if _, err = p.expect("="); err != nil {
+			return Module{}, err
+		}
+		newName, err := p.expectIdentifier("the new name")
+		if err != nil {
+			return Module{}, err
+		}
+		if _, isRenamed := renaming[oldName.Text]; isRenamed {
+			return Module{}, fmt.Errorf("The name \"%v\" at %v is renamed twice.", oldName.Text, oldName.Position)
+		}
+		renaming[oldName.Text] = newName.Text
+
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+
+	if _, err = p.expect("]"); err != nil {
+		return Module{}, err
+	}
+	if _, err = p.expect("endmodule"); err != nil {
+		return Module{}, err
+	}
+
+	for _, variable := range base.Variables {
+		if _, isRenamed := renaming[variable.Name]; !isRenamed {
+			return Module{}, fmt.Errorf("The module \"%v\" at %v must rename the variable \"%v\" of \"%v\".", name, start, variable.Name, base.Name)
+		}
+	}
+
+	return base.rename(name, start, renaming), nil
+}
+
+/*
+parseVariable
+Description:
+	variable := name ":" ( "[" expression ".." expression "]" | "bool" ) [ "init" expression ] ";"
+*/
+func (p *parser) parseVariable() (Variable, error) {
+	name := p.next()
+	p.next()
+	variable := Variable{Name: name.Text, Type: IntType, Position: name.Position}
+
+	if p.is("bool") {
+		p.next()
+		variable.Type = BoolType
+	} else {
+		if _, err := p.expect("["); err != nil {
+			return Variable{}, err
+		}
+		lower, err := p.parseExpression()
+		if err != nil {
+			return Variable{}, err
+		}
+		if _, err = p.expect(".."); err != nil {
+			return Variable{}, err
+		}
+		upper, err := p.parseExpression()
+		if err != nil {
+			return Variable{}, err
+		}
+		if _, err = p.expect("]"); err != nil {
+			return Variable{}, err
+		}
+		variable.Lower, variable.Upper = lower, upper
+	}
+
+	if p.is("init") {
+		p.next()
+		init, err := p.parseExpression()
+		if err != nil {
+			return Variable{}, err
+		}
+		variable.Init = &init
+	}
+
+	_, err := p.expect(";")
+	return variable, err
+}
+
+/*
+parseCommand
+Description:
+	command := "[" [ action ] "]" expression "->" update { "+" update } ";"
+*/
+func (p *parser) parseCommand() (Command, error) {
+	start := p.next().Position
+	command := Command{Position: start}
+
+	if p.peek(0).Kind == tokenIdentifier {
+		command.Action = p.next().Text
+	}
+	if _, err := p.expect("]"); err != nil {
+		return Command{}, err
+	}
+
+	guard, err := p.parseExpression()
+	if err != nil {
+		return Command{}, err
+	}
+	command.Guard = guard
+
+	if _, err = p.expect("->"); err != nil {
+		return Command{}, err
+	}
+
+	for {
+		update, err := p.parseUpdate()
+		if err != nil {
+			return Command{}, err
+		}
+		command.Updates = append(command.Updates, update)
+
+		if !p.is("+") {
+			break
+		}
+		p.next()
+	}
+
+	_, err = p.expect(";")
+	return command, err
+}
+
+/*
+parseUpdate
+Description:
+	update := [ expression ":" ] assignments
+	The probability is omitted when the update starts with "true" or with "(name'".
+*/
+func (p *parser) parseUpdate() (Update, error) {
+	update := Update{Position: p.peek(0).Position}
+
+	startsAssignments := (p.is("true") && (p.peek(1).Text == ";" || p.peek(1).Text == "+")) ||
+		(p.is("(") && p.peek(1).Kind == tokenIdentifier && p.peek(2).Text == "'")
+	if !startsAssignments {
+		probability, err := p.parseExpression()
+		if err != nil {
+			return Update{}, err
+		}
+		update.Probability = &probability
+
+		if !p.is(":") {
+			return Update{}, p.unexpected(fmt.Sprintf("\":\" after the probability \"%v\"", probability))
+		}
+		p.next()
+	}
+
+	if p.is("true") {
+		p.next()
+		return update, nil
+	}
+
+	for {
+		assignment, err := p.parseAssignment()
+		if err != nil {
+			return Update{}, err
+		}
+		update.Assignments = append(update.Assignments, assignment)
+
+		if !p.is("&") {
+			break
+		}
+		p.next()
+	}
+	return update, nil
+}
+
+/*
+parseAssignment
+Description:
+	assignment := "(" name "'" "=" expression ")"
+*/
+func (p *parser) parseAssignment() (Assignment, error) {
+	if _, err := p.expect("("); err != nil {
+		return Assignment{}, err
+	}
+
+	name, err := p.expectIdentifier("the name of a variable")
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	if _, err = p.expect("'"); err != nil {
+		return Assignment{}, err
+	}
+	if _, err = p.expect("="); err != nil {
+		return Assignment{}, err
+	}
+
+	expression, err := p.parseExpression()
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	_, err = p.expect(")")
+	return Assignment{Variable: name.Text, Expression: expression, Position: name.Position}, err
+}
+
+/*
+parseExpression
+Description:
+	expression := iff [ "?" expression ":" expression ]
+	The precedence of the operators follows PRISM, from the weakest: ?:, <=>, =>, |, &, !, = and !=,
+	relations, + and -, * and /, unary minus.
+*/
+func (p *parser) parseExpression() (Expression, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	if !p.is("?") {
+		return condition, nil
+	}
+	p.next()
+
+	then, err := p.parseExpression()
+	if err != nil {
+		return Expression{}, err
+	}
+	if _, err = p.expect(":"); err != nil {
+		return Expression{}, err
+	}
+	otherwise, err := p.parseExpression()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	return Expression{Operator: OpConditional, Operands: []Expression{condition, then, otherwise}, Position: condition.Position}, nil
+}
+
+// binaryLevels lists the binary operators from the weakest to the strongest; the level of ! is marked by nil.
+var binaryLevels = []map[string]ExpressionOperator{
+	{"<=>": OpIff},
+	{"=>": OpImplies},
+	{"|": OpOr},
+	{"&": OpAnd},
+	nil,
+	{"=": OpEqual, "!=": OpNotEqual},
+	{"<": OpLess, "<=": OpLessOrEqual, ">": OpGreater, ">=": OpGreaterOrEqual},
+	{"+": OpPlus, "-": OpMinus},
+	{"*": OpTimes, "/": OpDivide},
+}
+
+/*
+parseBinary
+Description:
+	Parses the operators of the given level and above. Implication is right-associative, relations cannot be
+	chained and the other binary operators are left-associative.
+*/
+func (p *parser) parseBinary(level int) (Expression, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+
+	if binaryLevels[level] == nil {
+		if p.is("!") {
+			start := p.next().Position
+			operand, err := p.parseBinary(level)
+			if err != nil {
+				return Expression{}, err
+			}
+			return Expression{Operator: OpNot, Operands: []Expression{operand}, Position: start}, nil
+		}
+		return p.parseBinary(level + 1)
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return Expression{}, err
+	}
+
+	for {
+		t := p.peek(0)
+		operator, isOperator := binaryLevels[level][t.Text]
+		if t.Kind != tokenSymbol || !isOperator {
+			return left, nil
+		}
+		p.next()
+
+		var right Expression
+		if operator == OpImplies {
+			right, err = p.parseBinary(level)
+		} else {
+			right, err = p.parseBinary(level + 1)
+		}
+		if err != nil {
+			return Expression{}, err
+		}
+
+		left = Expression{Operator: operator, Operands: []Expression{left, right}, Position: left.Position}
+		if operator == OpImplies || operator == OpLess || operator == OpLessOrEqual || operator == OpGreater || operator == OpGreaterOrEqual {
+			return left, nil
+		}
+	}
+}
+
+/*
+parseUnary
+Description:
+	unary := "-" unary | number | "true" | "false" | function "(" expression { "," expression } ")"
+		| name | "(" expression ")"
+*/
+func (p *parser) parseUnary() (Expression, error) {
+	t := p.peek(0)
+	switch {
+	case p.is("-"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return Expression{}, err
+		}
+		return Expression{Operator: OpNegate, Operands: []Expression{operand}, Position: t.Position}, nil
+
+	case p.is("("):
+		p.next()
+		expression, err := p.parseExpression()
+		if err != nil {
+			return Expression{}, err
+		}
+		_, err = p.expect(")")
+		return expression, err
+
+	case t.Kind == tokenInteger:
+		p.next()
+		n, err := strconv.Atoi(t.Text)
+		if err != nil {
+			return Expression{}, fmt.Errorf("The integer \"%v\" at %v is out of range.", t.Text, t.Position)
+		}
+		return Expression{Operator: OpLiteral, Value: IntValue(n), Position: t.Position}, nil
+
+	case t.Kind == tokenDouble:
+		p.next()
+		x, err := strconv.ParseFloat(t.Text, 64)
+		if err != nil {
+			return Expression{}, fmt.Errorf("The number \"%v\" at %v is out of range.", t.Text, t.Position)
+		}
+		return Expression{Operator: OpLiteral, Value: DoubleValue(x), Position: t.Position}, nil
+
+	case p.is("true"), p.is("false"):
+		p.next()
+		return Expression{Operator: OpLiteral, Value: BoolValue(t.Text == "true"), Position: t.Position}, nil
+
+	case t.Kind == tokenIdentifier && functionNames[t.Text] && p.peek(1).Text == "(":
+		p.next()
+		p.next()
+		call := Expression{Operator: OpFunction, Name: t.Text, Position: t.Position}
+		for {
+			argument, err := p.parseExpression()
+			if err != nil {
+				return Expression{}, err
+			}
+			call.Operands = append(call.Operands, argument)
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+		_, err := p.expect(")")
+		return call, err
+
+	case t.Kind == tokenIdentifier:
+		p.next()
+		return Expression{Operator: OpIdentifier, Name: t.Text, Position: t.Position}, nil
+
+	default:
+		return Expression{}, p.unexpected("an expression")
+	}
+}
+
+/*
+rename
+Description:
+	Returns a copy of the module with a new name in which the variables, actions and identifiers are renamed.
+*/
+func (module Module) rename(name string, position Position, renaming map[string]string) Module {
+	renamedName := func(oldName string) string {
+		if newName, isRenamed := renaming[oldName]; isRenamed {
+			return newName
+		}
+		return oldName
+	}
+
+	renamed := Module{Name: name, Position: position}
+	for _, variable := range module.Variables {
+		variable.Name = renamedName(variable.Name)
+		variable.Lower = variable.Lower.rename(renaming)
+		variable.Upper = variable.Upper.rename(renaming)
+		if variable.Init != nil {
+			init := variable.Init.rename(renaming)
+			variable.Init = &init
+		}
+		renamed.Variables = append(renamed.Variables, variable)
+	}
+
+	for _, command := range module.Commands {
+		renamedCommand := Command{Action: renamedName(command.Action), Guard: command.Guard.rename(renaming), Position: command.Position}
+		for _, update := range command.Updates {
+			renamedUpdate := Update{Position: update.Position}
+			if update.Probability != nil {
+				probability := update.Probability.rename(renaming)
+				renamedUpdate.Probability = &probability
+			}
+			for _, assignment := range update.Assignments {
+				renamedUpdate.Assignments = append(renamedUpdate.Assignments, Assignment{
+					Variable:   renamedName(assignment.Variable),
+					Expression: assignment.Expression.rename(renaming),
+					Position:   assignment.Position,
+				})
+			}
+			renamedCommand.Updates = append(renamedCommand.Updates, renamedUpdate)
+		}
+		renamed.Commands = append(renamed.Commands, renamedCommand)
+	}
+
+	return renamed
+}
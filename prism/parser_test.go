@@ -0,0 +1,223 @@
+/*
+parser_test.go
+Description:
+
+	Tests for the parser defined in parser.go
+*/
+package prism
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/*
+TestParse1
+Description:
+
+	Parses a model with every supported declaration and checks the syntax tree.
+*/
+func TestParse1(t *testing.T) {
+	model, err := Parse(`
+probabilistic // the long name of dtmc
+
+const int N = 3;
+const double p;
+formula done = x = N;
+
+module counter
+	x : [0..N] init 1;
+	b : bool;
+
+	[inc] !done -> p : (x'=x+1) & (b'=!b) + 1-p : true;
+	[] done -> (x'=0);
+endmodule
+
+label "done" = done;
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if model.Type != DTMC {
+		t.Errorf("Expected a dtmc, but the model type was %v.", model.Type)
+	}
+	if len(model.Constants) != 2 || model.Constants[0].Type != IntType || model.Constants[1].Type != DoubleType || model.Constants[1].Value != nil {
+		t.Errorf("The constants were not parsed correctly: %v", model.Constants)
+	}
+	if len(model.Formulas) != 1 || model.Formulas[0].Expression.String() != "x=N" {
+		t.Errorf("The formulas were not parsed correctly: %v", model.Formulas)
+	}
+	if len(model.Labels) != 1 || model.Labels[0].Name != "done" || model.Labels[0].Position != (Position{Line: 16, Column: 1}) {
+		t.Errorf("The labels were not parsed correctly: %v", model.Labels)
+	}
+
+	if len(model.Modules) != 1 {
+		t.Fatalf("Expected one module, but there were %v.", len(model.Modules))
+	}
+	module := model.Modules[0]
+	if module.Name != "counter" || len(module.Variables) != 2 || len(module.Commands) != 2 {
+		t.Fatalf("The module was not parsed correctly: %v", module)
+	}
+
+	x, b := module.Variables[0], module.Variables[1]
+	if x.Type != IntType || x.Lower.String() != "0" || x.Upper.String() != "N" || x.Init == nil || x.Init.String() != "1" {
+		t.Errorf("The variable x was not parsed correctly: %v", x)
+	}
+	if b.Type != BoolType || b.Init != nil {
+		t.Errorf("The variable b was not parsed correctly: %v", b)
+	}
+
+	command := module.Commands[0]
+	if command.Action != "inc" || command.Guard.String() != "!done" || len(command.Updates) != 2 {
+		t.Fatalf("The first command was not parsed correctly: %v", command)
+	}
+	if command.Position != (Position{Line: 12, Column: 2}) {
+		t.Errorf("Expected the first command at line 12, column 2, but it was at %v.", command.Position)
+	}
+	first, second := command.Updates[0], command.Updates[1]
+	if first.Probability.String() != "p" || len(first.Assignments) != 2 || first.Assignments[1].Variable != "b" || first.Assignments[1].Expression.String() != "!b" {
+		t.Errorf("The first update was not parsed correctly: %v", first)
+	}
+	if second.Probability.String() != "1-p" || len(second.Assignments) != 0 {
+		t.Errorf("The second update was not parsed correctly: %v", second)
+	}
+
+	if module.Commands[1].Action != "" || module.Commands[1].Updates[0].Probability != nil {
+		t.Errorf("The second command was not parsed correctly: %v", module.Commands[1])
+	}
+}
+
+/*
+TestParse2
+Description:
+
+	A renamed module copies the commands of its base with the variables, actions and identifiers renamed.
+*/
+func TestParse2(t *testing.T) {
+	model, err := Parse(`
+mdp
+module P1
+	x1 : [0..2];
+	[go1] x1<2 -> (x1'=x1+1);
+	[sync] x1=2 -> (x1'=0);
+endmodule
+module P2 = P1 [ x1=x2, go1=go2 ] endmodule
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	renamed := model.Modules[1]
+	if renamed.Name != "P2" || renamed.Variables[0].Name != "x2" || renamed.Position != (Position{Line: 8, Column: 1}) {
+		t.Errorf("The renamed module was not parsed correctly: %v", renamed)
+	}
+	if renamed.Commands[0].Action != "go2" || renamed.Commands[0].Guard.String() != "x2<2" || renamed.Commands[1].Action != "sync" {
+		t.Errorf("The commands were not renamed correctly: %v", renamed.Commands)
+	}
+	if renamed.Commands[0].Updates[0].Assignments[0].Variable != "x2" || renamed.Commands[0].Updates[0].Assignments[0].Expression.String() != "x2+1" {
+		t.Errorf("The assignments were not renamed correctly: %v", renamed.Commands[0].Updates)
+	}
+	if model.Modules[0].Variables[0].Name != "x1" || model.Modules[0].Commands[0].Guard.String() != "x1<2" {
+		t.Errorf("Renaming modified the base module: %v", model.Modules[0])
+	}
+}
+
+/*
+TestParse3
+Description:
+
+	Syntax errors are reported with the line and column of the unexpected token.
+*/
+func TestParse3(t *testing.T) {
+	testCases := []struct {
+		Source   string
+		Expected string
+	}{
+		{"pta\n", "Expected the model type (dtmc, mdp or ctmc) at line 1, column 1, but found \"pta\"."},
+		{"dtmc\nconst int N = 3\nmodule a endmodule", "Expected \";\" at line 3, column 1, but found \"module\"."},
+		{"dtmc\nmodule a\n\tx : [0..2] init 0;\n\t[] x<2 -> (x'=x+1)\nendmodule", "Expected \";\" at line 5, column 1, but found \"endmodule\"."},
+		{"dtmc\nmodule a\n\tx : [0 .. 2;\nendmodule", "Expected \"]\" at line 3, column 13, but found \";\"."},
+		{"dtmc\nmodule a\n\t[] true -> (x=1);\nendmodule", "Expected \":\" after the probability \"x=1\" at line 3, column 18, but found \";\"."},
+		{"dtmc\nmodule a\n\t[] x < 1 < 2 -> true;\nendmodule", "Expected \"->\" at line 3, column 11, but found \"<\"."},
+		{"dtmc\nlabel done = true;", "Expected the name of the label in double quotes at line 2, column 7, but found \"done\"."},
+		{"dtmc\nrewards \"r\"\n\t[go] true 1;\nendrewards", "Expected \":\" at line 3, column 12, but found \"1\"."},
+		{"dtmc\nmodule b = a [x=y] endmodule", "The module \"a\" at line 2, column 12 is not declared before it is renamed."},
+		{"dtmc\nmodule a\n\tx : [0..1];\n\ty : [0..1];\nendmodule\nmodule b = a [x=z] endmodule", "The module \"b\" at line 6, column 1 must rename the variable \"y\" of \"a\"."},
+		{"dtmc\nmodule a\n\t[] x > -> true;\nendmodule", "Expected an expression at line 3, column 9, but found \"->\"."},
+	}
+
+	for _, testCase := range testCases {
+		_, err := Parse(testCase.Source)
+		if err == nil || err.Error() != testCase.Expected {
+			t.Errorf("Expected the error %q for %q, but received %v.", testCase.Expected, testCase.Source, err)
+		}
+	}
+}
+
+/*
+TestParse4
+Description:
+
+	Reward structures, named or not, are parsed and ignored without affecting the rest of the model.
+*/
+func TestParse4(t *testing.T) {
+	model, err := Parse(`
+dtmc
+
+module coin
+	heads : bool init false;
+	[flip] !heads -> 0.5 : (heads'=true) + 0.5 : true;
+endmodule
+
+rewards "flips"
+	[flip] true : 1;
+endrewards
+
+rewards
+	heads : 2.5;
+	!heads & true : heads ? 1 : 0;
+endrewards
+
+label "heads" = heads;
+`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(model.Modules) != 1 || len(model.Modules[0].Commands) != 1 {
+		t.Errorf("The module was not parsed correctly: %v", model.Modules)
+	}
+	if len(model.Labels) != 1 || model.Labels[0].Name != "heads" {
+		t.Errorf("Expected the label after the reward structures to be parsed, but found %v.", model.Labels)
+	}
+}
+
+/*
+TestParseFile1
+Description:
+
+	Reads a model from a file, and reports files which cannot be read.
+*/
+func TestParseFile1(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "coin.pm")
+	source := "dtmc\nmodule coin\n\theads : bool init false;\n\t[] !heads -> 0.5 : (heads'=true) + 0.5 : true;\nendmodule\n"
+	if err := os.WriteFile(filename, []byte(source), 0644); err != nil {
+		t.Fatalf("Unexpected error while writing the model: %v", err)
+	}
+
+	model, err := ParseFile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(model.Modules) != 1 || model.Modules[0].Name != "coin" {
+		t.Errorf("The model was not read correctly: %v", model)
+	}
+
+	_, err = ParseFile(filepath.Join(t.TempDir(), "missing.pm"))
+	if err == nil || !strings.Contains(err.Error(), "There was an issue reading the model") {
+		t.Errorf("Expected an error about the missing file, but received %v.", err)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+syntax.go
+Description:
+	The syntax tree of models written in the core of the PRISM modelling language: the model type, constants,
+	formulas, modules with bounded integer and boolean variables, guarded commands with probabilistic (or
+	stochastic) updates, and labels. Every node remembers the position at which it starts in the source.
+*/
+
+package prism
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Type Definitions
+*/
+
+type ModelType int
+
+const (
+	DTMC ModelType = iota
+	MDP
+	CTMC
+)
+
+/*
+Position
+Description:
+	A position in the source of a model. Lines and columns are counted from 1, and columns count characters.
+*/
+type Position struct {
+	Line   int
+	Column int
+}
+
+/*
+Model
+Description:
+	A parsed PRISM model. The modules are listed in the order of the source, with renamed modules expanded.
+*/
+type Model struct {
+	Type      ModelType
+	Constants []Constant
+	Formulas  []Formula
+	Modules   []Module
+	Labels    []Label
+}
+
+/*
+Constant
+Description:
+	A constant such as "const double p = 0.5;". Value is nil if the constant is left undefined in the source,
+	in which case it must be given a value with Model.WithConstants() before the model is built.
+*/
+type Constant struct {
+	Name     string
+	Type     ValueType
+	Value    *Expression
+	Position Position
+}
+
+/*
+Formula
+Description:
+	A named expression such as "formula done = x=N;", which can be used in any expression after its declaration.
+*/
+type Formula struct {
+	Name       string
+	Expression Expression
+	Position   Position
+}
+
+/*
+Module
+Description:
+	A module with its local variables and its commands.
+*/
+type Module struct {
+	Name      string
+	Variables []Variable
+	Commands  []Command
+	Position  Position
+}
+
+/*
+Variable
+Description:
+	A local variable of a module. Integer variables range over [Lower, Upper]; Lower and Upper are unused for
+	booleans. Init is nil if the variable starts at its lower bound (or false).
+*/
+type Variable struct {
+	Name     string
+	Type     ValueType
+	Lower    Expression
+	Upper    Expression
+	Init     *Expression
+	Position Position
+}
+
+/*
+Command
+Description:
+	A guarded command "[action] guard -> updates;". Action is empty for commands which do not synchronise.
+*/
+type Command struct {
+	Action   string
+	Guard    Expression
+	Updates  []Update
+	Position Position
+}
+
+/*
+Update
+Description:
+	One outcome of a command, taken with the given probability (or rate, in a CTMC). Probability is nil when
+	the command has a single update without a probability, which is taken with probability 1.
+*/
+type Update struct {
+	Probability *Expression
+	Assignments []Assignment
+	Position    Position
+}
+
+/*
+Assignment
+Description:
+	The assignment (variable' = expression) of an update.
+*/
+type Assignment struct {
+	Variable   string
+	Expression Expression
+	Position   Position
+}
+
+/*
+Label
+Description:
+	A label such as "label \"goal\" = x=N;", which becomes an atomic proposition of the built model.
+*/
+type Label struct {
+	Name       string
+	Expression Expression
+	Position   Position
+}
+
+type ExpressionOperator int
+
+const (
+	OpLiteral ExpressionOperator = iota
+	OpIdentifier
+	OpFunction
+	OpNot
+	OpNegate
+	OpAnd
+	OpOr
+	OpImplies
+	OpIff
+	OpEqual
+	OpNotEqual
+	OpLess
+	OpLessOrEqual
+	OpGreater
+	OpGreaterOrEqual
+	OpPlus
+	OpMinus
+	OpTimes
+	OpDivide
+	OpConditional
+)
+
+/*
+Expression
+Description:
+	A node of the syntax tree of an expression. Value is only used by OpLiteral, and Name by OpIdentifier and
+	OpFunction (min, max, floor, ceil, pow or mod). OpConditional has the operands condition, then and else.
+*/
+type Expression struct {
+	Operator ExpressionOperator
+	Value    Value
+	Name     string
+	Operands []Expression
+	Position Position
+}
+
+/*
+Functions
+*/
+
+/*
+String
+Description:
+	Returns the keyword of the model type.
+*/
+func (modelType ModelType) String() string {
+	switch modelType {
+	case DTMC:
+		return "dtmc"
+	case MDP:
+		return "mdp"
+	case CTMC:
+		return "ctmc"
+	default:
+		return fmt.Sprintf("ModelType(%v)", int(modelType))
+	}
+}
+
+/*
+String
+Description:
+	Prints the position as "line l, column c".
+*/
+func (position Position) String() string {
+	return fmt.Sprintf("line %v, column %v", position.Line, position.Column)
+}
+
+var expressionOperatorSymbols = map[ExpressionOperator]string{
+	OpAnd:            "&",
+	OpOr:             "|",
+	OpImplies:        "=>",
+	OpIff:            "<=>",
+	OpEqual:          "=",
+	OpNotEqual:       "!=",
+	OpLess:           "<",
+	OpLessOrEqual:    "<=",
+	OpGreater:        ">",
+	OpGreaterOrEqual: ">=",
+	OpPlus:           "+",
+	OpMinus:          "-",
+	OpTimes:          "*",
+	OpDivide:         "/",
+}
+
+/*
+String
+Description:
+	Prints the expression in PRISM syntax, with parentheses around every compound operand.
+*/
+func (expression Expression) String() string {
+	switch expression.Operator {
+	case OpLiteral:
+		return expression.Value.String()
+	case OpIdentifier:
+		return expression.Name
+	case OpFunction:
+		var operandStrings []string
+		for _, operand := range expression.Operands {
+			operandStrings = append(operandStrings, operand.String())
+		}
+		return fmt.Sprintf("%v(%v)", expression.Name, strings.Join(operandStrings, ","))
+	case OpNot:
+		return "!" + expression.Operands[0].operandString()
+	case OpNegate:
+		return "-" + expression.Operands[0].operandString()
+	case OpConditional:
+		return fmt.Sprintf("%v ? %v : %v", expression.Operands[0].operandString(), expression.Operands[1].operandString(), expression.Operands[2].operandString())
+	default:
+		symbol, isBinary := expressionOperatorSymbols[expression.Operator]
+		if !isBinary {
+			return "?"
+		}
+		return fmt.Sprintf("%v%v%v", expression.Operands[0].operandString(), symbol, expression.Operands[1].operandString())
+	}
+}
+
+/*
+operandString
+Description:
+	Prints the expression, in parentheses unless it is a literal, an identifier or a function call.
+*/
+func (expression Expression) operandString() string {
+	switch expression.Operator {
+	case OpLiteral, OpIdentifier, OpFunction:
+		return expression.String()
+	default:
+		return "(" + expression.String() + ")"
+	}
+}
+
+/*
+identifiers
+Description:
+	Returns the identifiers which appear in the expression, with their positions.
+*/
+func (expression Expression) identifiers() []Expression {
+	if expression.Operator == OpIdentifier {
+		return []Expression{expression}
+	}
+
+	var identifiers []Expression
+	for _, operand := range expression.Operands {
+		identifiers = append(identifiers, operand.identifiers()...)
+	}
+	return identifiers
+}
+
+/*
+rename
+Description:
+	Returns a copy of the expression in which the identifiers are renamed according to the map.
+*/
+func (expression Expression) rename(renaming map[string]string) Expression {
+	renamed := expression
+	if newName, isRenamed := renaming[expression.Name]; isRenamed && expression.Operator == OpIdentifier {
+		renamed.Name = newName
+	}
+
+	renamed.Operands = nil
+	for _, operand := range expression.Operands {
+		renamed.Operands = append(renamed.Operands, operand.rename(renaming))
+	}
+	return renamed
+}
+
+/*
+formatNumber
+Description:
+	Prints a number without a trailing ".0" for integers.
+*/
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'g', -1, 64)
+}
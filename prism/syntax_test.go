@@ -0,0 +1,121 @@
+/*
+syntax_test.go
+Description:
+
+	Tests for the syntax tree defined in syntax.go
+*/
+package prism
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+parseExpressionString
+Description:
+
+	Parses the source as a single expression, failing the test on errors.
+*/
+func parseExpressionString(t *testing.T, source string) Expression {
+	tokens, err := tokenize(source)
+	if err != nil {
+		t.Fatalf("Unexpected error while tokenizing \"%v\": %v", source, err)
+	}
+	p := parser{Tokens: tokens}
+	expression, err := p.parseExpression()
+	if err != nil {
+		t.Fatalf("Unexpected error while parsing \"%v\": %v", source, err)
+	}
+	if p.peek(0).Kind != tokenEnd {
+		t.Fatalf("The expression \"%v\" was not read up to its end.", source)
+	}
+	return expression
+}
+
+/*
+TestModelType_String1
+Description:
+
+	The model types print as their keywords.
+*/
+func TestModelType_String1(t *testing.T) {
+	if DTMC.String() != "dtmc" || MDP.String() != "mdp" || CTMC.String() != "ctmc" {
+		t.Errorf("The model types printed as %v, %v and %v.", DTMC, MDP, CTMC)
+	}
+}
+
+/*
+TestPosition_String1
+Description:
+
+	Positions print with their line and column.
+*/
+func TestPosition_String1(t *testing.T) {
+	if s := (Position{Line: 3, Column: 14}).String(); s != "line 3, column 14" {
+		t.Errorf("Expected \"line 3, column 14\", but received \"%v\".", s)
+	}
+}
+
+/*
+TestExpression_String1
+Description:
+
+	Expressions print with parentheses around their compound operands.
+*/
+func TestExpression_String1(t *testing.T) {
+	testCases := map[string]string{
+		"x+1":               "x+1",
+		"a & b | !c":        "(a&b)|(!c)",
+		"x = 1 ? 0.5 : 1.0": "(x=1) ? 0.5 : 1.0",
+		"min(x, N-1) * -y":  "min(x,N-1)*(-y)",
+		"(x+1)*2 >= 4":      "((x+1)*2)>=4",
+		"a => b => c":       "a=>(b=>c)",
+		"1 - 2 - 3":         "(1-2)-3",
+	}
+
+	for source, expected := range testCases {
+		if s := parseExpressionString(t, source).String(); s != expected {
+			t.Errorf("Expected \"%v\" to print as \"%v\", but it printed as \"%v\".", source, expected, s)
+		}
+	}
+}
+
+/*
+TestExpression_identifiers1
+Description:
+
+	Returns the identifiers of an expression with their positions, but not the names of functions.
+*/
+func TestExpression_identifiers1(t *testing.T) {
+	identifiers := parseExpressionString(t, "max(x, y) + x").identifiers()
+
+	var names []string
+	for _, identifier := range identifiers {
+		names = append(names, identifier.Name)
+	}
+	if strings.Join(names, ",") != "x,y,x" {
+		t.Errorf("Expected the identifiers x,y,x, but they were %v.", names)
+	}
+	if identifiers[1].Position != (Position{Line: 1, Column: 8}) {
+		t.Errorf("Expected y at line 1, column 8, but it was at %v.", identifiers[1].Position)
+	}
+}
+
+/*
+TestExpression_rename1
+Description:
+
+	Renaming changes the identifiers, but not the original expression or the names of functions.
+*/
+func TestExpression_rename1(t *testing.T) {
+	expression := parseExpressionString(t, "min(x, y) + x")
+	renamed := expression.rename(map[string]string{"x": "z", "min": "max"})
+
+	if renamed.String() != "min(z,y)+z" {
+		t.Errorf("Expected \"min(z,y)+z\", but received \"%v\".", renamed)
+	}
+	if expression.String() != "min(x,y)+x" {
+		t.Errorf("The original expression was modified to \"%v\".", expression)
+	}
+}
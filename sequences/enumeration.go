@@ -0,0 +1,189 @@
+/*
+enumeration.go
+Description:
+	Lazy enumeration of the initial finite path fragments and execution fragments of a transition system.
+	The enumerators are iterators (iter.Seq), so a range loop can stop the enumeration at any time with break.
+*/
+
+package sequences
+
+import (
+	"iter"
+	"slices"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+SearchOrder
+Description:
+	The order in which fragments are enumerated. BreadthFirst yields every fragment of length n before the
+	fragments of length n+1; DepthFirst yields each fragment immediately before its extensions.
+*/
+type SearchOrder int
+
+const (
+	BreadthFirst SearchOrder = iota
+	DepthFirst
+)
+
+/*
+Functions
+*/
+
+/*
+InitialPathFragments
+Description:
+	Yields every initial finite path fragment of the transition system with at most k transitions
+	(i.e. at most k+1 states). Each path is yielded once, even if several actions lead to the same successor
+	or a state appears several times in ts.I.
+Usage:
+	for fragment := range InitialPathFragments(ts, 3, BreadthFirst) {
+		if !fragment.ToTrace().SatisfiesAPInvariant(ap) {
+			break
+		}
+	}
+*/
+func InitialPathFragments(ts mc.TransitionSystem, k int, order SearchOrder) iter.Seq[FinitePathFragment] {
+	return func(yield func(FinitePathFragment) bool) {
+		if k < 0 {
+			return
+		}
+
+		var frontier []FinitePathFragment
+		for _, initialState := range uniqueStates(ts.I) {
+			frontier = append(frontier, FinitePathFragment{s: []mc.TransitionSystemState{initialState}})
+		}
+		if order == DepthFirst {
+			slices.Reverse(frontier)
+		}
+
+		for len(frontier) > 0 {
+			var current FinitePathFragment
+			current, frontier = nextFragment(frontier, order)
+
+			if !yield(current) {
+				return
+			}
+
+			if len(current.s)-1 >= k {
+				continue
+			}
+
+			// Extend the fragment with each successor of its last state
+			successors, err := mc.Post(current.s[len(current.s)-1])
+			if err != nil {
+				continue
+			}
+
+			var extensions []FinitePathFragment
+			for _, successor := range successors {
+				extensions = append(extensions, FinitePathFragment{s: appendState(current.s, successor)})
+			}
+			if order == DepthFirst {
+				slices.Reverse(extensions)
+			}
+			frontier = append(frontier, extensions...)
+		}
+	}
+}
+
+/*
+InitialExecutionFragments
+Description:
+	Yields every initial finite execution fragment of the transition system with at most k transitions.
+	Actions are tried in the order of ts.Act. Each execution is yielded once.
+Usage:
+	for fragment := range InitialExecutionFragments(ts, 2, DepthFirst) {
+		if maximal, _ := fragment.IsMaximal(); maximal {
+			break
+		}
+	}
+*/
+func InitialExecutionFragments(ts mc.TransitionSystem, k int, order SearchOrder) iter.Seq[FiniteExecutionFragment] {
+	return func(yield func(FiniteExecutionFragment) bool) {
+		if k < 0 {
+			return
+		}
+
+		var frontier []FiniteExecutionFragment
+		for _, initialState := range uniqueStates(ts.I) {
+			frontier = append(frontier, FiniteExecutionFragment{s: []mc.TransitionSystemState{initialState}})
+		}
+		if order == DepthFirst {
+			slices.Reverse(frontier)
+		}
+
+		for len(frontier) > 0 {
+			var current FiniteExecutionFragment
+			current, frontier = nextFragment(frontier, order)
+
+			if !yield(current) {
+				return
+			}
+
+			if len(current.a) >= k {
+				continue
+			}
+
+			// Extend the fragment with each action and successor of its last state
+			var extensions []FiniteExecutionFragment
+			for _, action := range ts.Act {
+				successors, err := mc.Post(current.s[len(current.s)-1], action)
+				if err != nil {
+					continue
+				}
+
+				for _, successor := range successors {
+					extensions = append(extensions, FiniteExecutionFragment{
+						s: appendState(current.s, successor),
+						a: append(append([]string{}, current.a...), action),
+					})
+				}
+			}
+			if order == DepthFirst {
+				slices.Reverse(extensions)
+			}
+			frontier = append(frontier, extensions...)
+		}
+	}
+}
+
+/*
+uniqueStates
+Description:
+	Removes the repeated states of a slice.
+*/
+func uniqueStates(states []mc.TransitionSystemState) []mc.TransitionSystemState {
+	var statesOut []mc.TransitionSystemState
+	for _, state := range states {
+		statesOut = state.AppendIfUniqueTo(statesOut)
+	}
+	return statesOut
+}
+
+/*
+appendState
+Description:
+	Returns a copy of states with one more state, so that fragments never share their backing arrays.
+*/
+func appendState(states []mc.TransitionSystemState, state mc.TransitionSystemState) []mc.TransitionSystemState {
+	return append(append([]mc.TransitionSystemState{}, states...), state)
+}
+
+/*
+nextFragment
+Description:
+	Removes the next fragment from the frontier: the oldest one for BreadthFirst (a queue) and
+	the newest one for DepthFirst (a stack).
+*/
+func nextFragment[F any](frontier []F, order SearchOrder) (F, []F) {
+	if order == DepthFirst {
+		return frontier[len(frontier)-1], frontier[:len(frontier)-1]
+	}
+	return frontier[0], frontier[1:]
+}
@@ -0,0 +1,125 @@
+/*
+enumeration_test.go
+Description:
+	Tests for the enumerators defined in enumeration.go
+*/
+package sequences
+
+import (
+	"strings"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+pathNames
+Description:
+	Writes a path fragment as the names of its states separated by spaces.
+*/
+func pathNames(fragment FinitePathFragment) string {
+	var names []string
+	for _, state := range fragment.s {
+		names = append(names, state.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+/*
+TestInitialPathFragments1
+Description:
+	Enumerates the paths of the beverage vending machine in breadth-first and depth-first order.
+*/
+func TestInitialPathFragments1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+
+	expectedOrders := map[SearchOrder][]string{
+		BreadthFirst: {"pay", "pay select", "pay select beer", "pay select soda", "pay select beer pay", "pay select soda pay"},
+		DepthFirst:   {"pay", "pay select", "pay select beer", "pay select beer pay", "pay select soda", "pay select soda pay"},
+	}
+
+	for order, expected := range expectedOrders {
+		var found []string
+		for fragment := range InitialPathFragments(ts0, 3, order) {
+			if err := fragment.Check(); err != nil {
+				t.Errorf("The fragment %v is invalid: %v", pathNames(fragment), err)
+			}
+			if !fragment.IsInitial() {
+				t.Errorf("The fragment %v is not initial.", pathNames(fragment))
+			}
+			found = append(found, pathNames(fragment))
+		}
+
+		if strings.Join(found, ", ") != strings.Join(expected, ", ") {
+			t.Errorf("Expected the paths %v in order %v, but found %v.", expected, order, found)
+		}
+	}
+}
+
+/*
+TestInitialPathFragments2
+Description:
+	Verifies that successors reached with several actions only give one path and that a break stops the enumeration.
+*/
+func TestInitialPathFragments2(t *testing.T) {
+	ts0 := mc.GetSimpleTS1()
+
+	count := 0
+	seen := make(map[string]bool)
+	for fragment := range InitialPathFragments(ts0, 2, BreadthFirst) {
+		if seen[pathNames(fragment)] {
+			t.Errorf("The path %v was yielded twice.", pathNames(fragment))
+		}
+		seen[pathNames(fragment)] = true
+		count++
+	}
+
+	if count != 8 {
+		t.Errorf("Expected 8 paths with at most 2 transitions, but found %v.", count)
+	}
+
+	// Early termination
+	count = 0
+	for range InitialPathFragments(ts0, 10, DepthFirst) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("Expected the enumeration to stop after 3 paths, but found %v.", count)
+	}
+
+	// Negative lengths give nothing
+	for fragment := range InitialPathFragments(ts0, -1, BreadthFirst) {
+		t.Errorf("Expected no paths, but found %v.", pathNames(fragment))
+	}
+}
+
+/*
+TestInitialExecutionFragments1
+Description:
+	Enumerates the executions of a transition system in which different actions lead to the same state.
+*/
+func TestInitialExecutionFragments1(t *testing.T) {
+	ts0 := mc.GetSimpleTS1()
+
+	for _, order := range []SearchOrder{BreadthFirst, DepthFirst} {
+		count := 0
+		previousLength := 0
+		for fragment := range InitialExecutionFragments(ts0, 2, order) {
+			if err := fragment.Check(); err != nil {
+				t.Errorf("The execution %v, %v is invalid: %v", fragment.s, fragment.a, err)
+			}
+			if order == BreadthFirst && len(fragment.a) < previousLength {
+				t.Errorf("Breadth-first enumeration yielded a shorter execution after a longer one.")
+			}
+			previousLength = len(fragment.a)
+			count++
+		}
+
+		if count != 9 {
+			t.Errorf("Expected 9 executions with at most 2 transitions in order %v, but found %v.", order, count)
+		}
+	}
+}
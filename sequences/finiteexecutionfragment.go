@@ -88,3 +88,107 @@ func (fe FiniteExecutionFragment) IsInitial() (bool, error) {
 
 	return firstState.In(System.I), nil
 }
+
+/*
+NewFiniteExecutionFragment
+Description:
+	Creates the finite execution fragment states[0] actions[0] states[1] ... states[n]. There must be one
+	more state than actions and each transition must belong to the transition system (see Check()).
+*/
+func NewFiniteExecutionFragment(states []mc.TransitionSystemState, actions []string) (FiniteExecutionFragment, error) {
+	if len(states) == 0 {
+		return FiniteExecutionFragment{}, fmt.Errorf("An execution fragment must contain at least one state.")
+	}
+
+	fe := FiniteExecutionFragment{
+		s: append([]mc.TransitionSystemState{}, states...),
+		a: append([]string{}, actions...),
+	}
+	if err := fe.Check(); err != nil {
+		return FiniteExecutionFragment{}, err
+	}
+
+	return fe, nil
+}
+
+/*
+Len
+Description:
+	Returns the length of the execution fragment, i.e. its number of actions.
+*/
+func (fe FiniteExecutionFragment) Len() int {
+	return len(fe.a)
+}
+
+/*
+States
+Description:
+	Returns a copy of the states of the execution fragment.
+*/
+func (fe FiniteExecutionFragment) States() []mc.TransitionSystemState {
+	return append([]mc.TransitionSystemState{}, fe.s...)
+}
+
+/*
+Actions
+Description:
+	Returns a copy of the actions of the execution fragment.
+*/
+func (fe FiniteExecutionFragment) Actions() []string {
+	return append([]string{}, fe.a...)
+}
+
+/*
+At
+Description:
+	Returns the i-th state of the execution fragment (0 <= i <= Len()).
+*/
+func (fe FiniteExecutionFragment) At(i int) (mc.TransitionSystemState, error) {
+	if i < 0 || i > fe.Len() {
+		return mc.TransitionSystemState{}, fmt.Errorf("The index %v is outside of the execution fragment of length %v.", i, fe.Len())
+	}
+	return fe.s[i], nil
+}
+
+/*
+Prefix
+Description:
+	Returns the execution fragment made of the first n actions (and the states 0 to n).
+*/
+func (fe FiniteExecutionFragment) Prefix(n int) (FiniteExecutionFragment, error) {
+	if n < 0 || n > fe.Len() {
+		return FiniteExecutionFragment{}, fmt.Errorf("Cannot take the prefix of length %v of an execution fragment of length %v.", n, fe.Len())
+	}
+	return NewFiniteExecutionFragment(fe.s[:n+1], fe.a[:n])
+}
+
+/*
+Suffix
+Description:
+	Returns the execution fragment which starts at the n-th state.
+*/
+func (fe FiniteExecutionFragment) Suffix(n int) (FiniteExecutionFragment, error) {
+	if n < 0 || n > fe.Len() {
+		return FiniteExecutionFragment{}, fmt.Errorf("Cannot take the suffix from state %v of an execution fragment of length %v.", n, fe.Len())
+	}
+	return NewFiniteExecutionFragment(fe.s[n:], fe.a[n:])
+}
+
+/*
+Concat
+Description:
+	Appends fe2 to the execution fragment. fe2 must start in the last state of the execution fragment,
+	so that fe.Prefix(n).Concat(fe.Suffix(n)) is fe.
+*/
+func (fe FiniteExecutionFragment) Concat(fe2 FiniteExecutionFragment) (FiniteExecutionFragment, error) {
+	if len(fe.s) == 0 || len(fe2.s) == 0 {
+		return FiniteExecutionFragment{}, fmt.Errorf("Cannot concatenate an empty execution fragment.")
+	}
+
+	lastState := fe.s[len(fe.s)-1]
+	if !lastState.Equals(fe2.s[0]) {
+		return FiniteExecutionFragment{}, fmt.Errorf("The second fragment starts in \"%v\", but the first fragment ends in \"%v\".", fe2.s[0], lastState)
+	}
+
+	return NewFiniteExecutionFragment(append(fe.States(), fe2.s[1:]...), append(fe.Actions(), fe2.a...))
+}
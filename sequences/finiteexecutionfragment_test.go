@@ -8,6 +8,7 @@ package sequences
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	mc "github.com/kwesiRutledge/ModelChecking"
@@ -292,3 +293,42 @@ func TestFiniteExectutionFragment_IsInitial2(t *testing.T) {
 	}
 
 }
+
+/*
+TestNewFiniteExecutionFragment1
+Description:
+	Verifies the constructor and the algebra of finite execution fragments.
+*/
+func TestNewFiniteExecutionFragment1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+
+	fe, err := NewFiniteExecutionFragment(
+		[]mc.TransitionSystemState{ts0.S[0], ts0.S[1], ts0.S[3], ts0.S[0]},
+		[]string{"insert_coin", "", "get_soda"},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if fe.Len() != 3 || len(fe.Actions()) != 3 {
+		t.Errorf("Expected an execution of length 3, but found %v.", fe.Len())
+	}
+
+	prefix, _ := fe.Prefix(2)
+	suffix, _ := fe.Suffix(2)
+	concatenation, err := prefix.Concat(suffix)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if strings.Join(concatenation.Actions(), ",") != strings.Join(fe.Actions(), ",") {
+		t.Errorf("Expected the concatenation to have the actions %v, but found %v.", fe.Actions(), concatenation.Actions())
+	}
+
+	if state, _ := suffix.At(0); state.Name != "soda" {
+		t.Errorf("Expected the suffix to start in soda, but found %v.", state)
+	}
+
+	if _, err := NewFiniteExecutionFragment([]mc.TransitionSystemState{ts0.S[0], ts0.S[1]}, []string{"get_beer"}); err == nil {
+		t.Errorf("Expected an error for the invalid transition from pay with get_beer.")
+	}
+}
@@ -0,0 +1,168 @@
+/*
+import.go
+Description:
+	Importers which replay recorded logs as traces and path fragments.
+	A trace log lists one label set per step and a path log lists the names of the visited states, either as CSV
+	or as JSON. For example, the trace {request} {} {grant} can be written as
+
+		CSV:  request          JSON: [["request"], [], ["grant"]]
+		      ""
+		      grant
+
+	(encoding/csv skips blank lines, so an empty label set is written as an empty quoted field or a lone comma).
+*/
+
+package sequences
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+ReadFiniteTraceCSV
+Description:
+	Reads a finite trace with one label set per CSV record. Each nonempty field of a record is the name of an
+	atomic proposition; surrounding spaces are removed.
+Usage:
+	file, _ := os.Open("device.csv")
+	trace, err := ReadFiniteTraceCSV(file)
+*/
+func ReadFiniteTraceCSV(reader io.Reader) (FiniteTrace, error) {
+	records, err := readCSVRecords(reader)
+	if err != nil {
+		return FiniteTrace{}, err
+	}
+
+	trace := FiniteTrace{L: [][]mc.AtomicProposition{}}
+	for _, record := range records {
+		trace.L = append(trace.L, namesToAPs(record))
+	}
+
+	return trace, nil
+}
+
+/*
+ReadFiniteTraceJSON
+Description:
+	Reads a finite trace from a JSON array of arrays of proposition names.
+*/
+func ReadFiniteTraceJSON(reader io.Reader) (FiniteTrace, error) {
+	var labelSets [][]string
+	if err := json.NewDecoder(reader).Decode(&labelSets); err != nil {
+		return FiniteTrace{}, fmt.Errorf("The trace could not be read as a JSON list of label sets: %v", err)
+	}
+
+	trace := FiniteTrace{L: [][]mc.AtomicProposition{}}
+	for _, labelSet := range labelSets {
+		trace.L = append(trace.L, namesToAPs(labelSet))
+	}
+
+	return trace, nil
+}
+
+/*
+ReadFinitePathFragmentCSV
+Description:
+	Reads the names of the states of a path fragment of ts from CSV, in order. The names can be written one per
+	record, all in one record or a mix of both; empty fields are ignored. The path must be valid in ts.
+*/
+func ReadFinitePathFragmentCSV(reader io.Reader, ts mc.TransitionSystem) (FinitePathFragment, error) {
+	records, err := readCSVRecords(reader)
+	if err != nil {
+		return FinitePathFragment{}, err
+	}
+
+	var stateNames []string
+	for _, record := range records {
+		for _, field := range record {
+			if name := strings.TrimSpace(field); name != "" {
+				stateNames = append(stateNames, name)
+			}
+		}
+	}
+
+	return pathFragmentFromNames(stateNames, ts)
+}
+
+/*
+ReadFinitePathFragmentJSON
+Description:
+	Reads a path fragment of ts from a JSON array of state names.
+*/
+func ReadFinitePathFragmentJSON(reader io.Reader, ts mc.TransitionSystem) (FinitePathFragment, error) {
+	var stateNames []string
+	if err := json.NewDecoder(reader).Decode(&stateNames); err != nil {
+		return FinitePathFragment{}, fmt.Errorf("The path could not be read as a JSON list of state names: %v", err)
+	}
+
+	return pathFragmentFromNames(stateNames, ts)
+}
+
+/*
+readCSVRecords
+Description:
+	Reads every record of a CSV file whose records may have different numbers of fields.
+*/
+func readCSVRecords(reader io.Reader) ([][]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("The log could not be read as CSV: %v", err)
+	}
+
+	return records, nil
+}
+
+/*
+namesToAPs
+Description:
+	Converts the nonempty names (without surrounding spaces) into a set of atomic propositions.
+*/
+func namesToAPs(names []string) []mc.AtomicProposition {
+	labelSet := []mc.AtomicProposition{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		ap := mc.AtomicProposition{Name: name}
+		if !ap.In(labelSet) {
+			labelSet = append(labelSet, ap)
+		}
+	}
+	return labelSet
+}
+
+/*
+pathFragmentFromNames
+Description:
+	Looks up the named states in ts and creates the path fragment which visits them.
+*/
+func pathFragmentFromNames(stateNames []string, ts mc.TransitionSystem) (FinitePathFragment, error) {
+	var states []mc.TransitionSystemState
+	for index, name := range stateNames {
+		found := false
+		for _, state := range ts.S {
+			if state.Name == name {
+				states = append(states, state)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return FinitePathFragment{}, fmt.Errorf("The state \"%v\" at position %v of the log is not a state of the transition system.", name, index)
+		}
+	}
+
+	return NewFinitePathFragment(states)
+}
@@ -0,0 +1,137 @@
+/*
+import_test.go
+Description:
+	Tests for the log importers defined in import.go
+*/
+package sequences
+
+import (
+	"strings"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+TestReadFiniteTraceCSV1
+Description:
+	Reads a trace with an empty label set and repeated, padded names, and replays it against GetSimpleTS1().
+*/
+func TestReadFiniteTraceCSV1(t *testing.T) {
+	log := "A\n B , D\n\"\"\nC,D,C\n"
+
+	trace, err := ReadFiniteTraceCSV(strings.NewReader(log))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(trace.L) != 4 {
+		t.Errorf("Expected 4 label sets, but found %v.", len(trace.L))
+	}
+
+	expectedSizes := []int{1, 2, 0, 2}
+	for index, labelSet := range trace.L {
+		if len(labelSet) != expectedSizes[index] {
+			t.Errorf("Expected label set %v to have %v elements, but found %v.", index, expectedSizes[index], labelSet)
+		}
+	}
+
+	// The empty label set is not produced by the model
+	accepted, divergence, err := mc.GetSimpleTS1().AcceptsTrace(trace.L)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if accepted || divergence.Step != 2 {
+		t.Errorf("Expected the trace to diverge at step 2, but found accepted = %v and %v.", accepted, divergence)
+	}
+}
+
+/*
+TestReadFiniteTraceCSV2
+Description:
+	A malformed CSV file produces an error.
+*/
+func TestReadFiniteTraceCSV2(t *testing.T) {
+	_, err := ReadFiniteTraceCSV(strings.NewReader("A,\"B\n"))
+	if err == nil {
+		t.Errorf("Expected an error for the unterminated quote.")
+	}
+
+	if !strings.HasPrefix(err.Error(), "The log could not be read as CSV:") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+/*
+TestReadFiniteTraceJSON1
+Description:
+	Reads a JSON trace which GetSimpleTS1() produces.
+*/
+func TestReadFiniteTraceJSON1(t *testing.T) {
+	trace, err := ReadFiniteTraceJSON(strings.NewReader(`[["A"], ["B", "D"], ["B", "D"], ["D", "C"]]`))
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	accepted, _, err := mc.GetSimpleTS1().AcceptsTrace(trace.L)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !accepted {
+		t.Errorf("Expected the trace to be accepted.")
+	}
+
+	_, err = ReadFiniteTraceJSON(strings.NewReader(`["A", "B"]`))
+	if err == nil {
+		t.Errorf("Expected an error for a list of names which are not in label sets.")
+	}
+}
+
+/*
+TestReadFinitePathFragmentCSV1
+Description:
+	Reads a path whose state names are split over several records.
+*/
+func TestReadFinitePathFragmentCSV1(t *testing.T) {
+	ts := mc.GetSimpleTS1()
+
+	fragment, err := ReadFinitePathFragmentCSV(strings.NewReader("1,2\n3\n,2\n"), ts)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if pathNames(fragment) != "1 2 3 2" {
+		t.Errorf("Expected the path 1 2 3 2, but found %v.", pathNames(fragment))
+	}
+
+	accepted, _, _ := ts.AcceptsTrace(fragment.ToTrace().L)
+	if !accepted {
+		t.Errorf("Expected the trace of the path to be accepted.")
+	}
+}
+
+/*
+TestReadFinitePathFragmentJSON1
+Description:
+	Unknown states and invalid transitions produce errors.
+*/
+func TestReadFinitePathFragmentJSON1(t *testing.T) {
+	ts := mc.GetSimpleTS1()
+
+	_, err := ReadFinitePathFragmentJSON(strings.NewReader(`["1", "7"]`), ts)
+	if err == nil || err.Error() != "The state \"7\" at position 1 of the log is not a state of the transition system." {
+		t.Errorf("Expected an error for the unknown state, but found %v.", err)
+	}
+
+	_, err = ReadFinitePathFragmentJSON(strings.NewReader(`["1", "3"]`), ts)
+	if err == nil {
+		t.Errorf("Expected an error for the missing transition from 1 to 3.")
+	}
+
+	fragment, err := ReadFinitePathFragmentJSON(strings.NewReader(`["1", "1", "2"]`), ts)
+	if err != nil || fragment.Len() != 2 {
+		t.Errorf("Expected a path with 2 transitions, but found %v (error: %v).", fragment.Len(), err)
+	}
+}
@@ -6,7 +6,11 @@ Description:
 
 package sequences
 
-import "fmt"
+import (
+	"fmt"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
 
 type InfiniteExecutionFragment struct {
 	UniquePrefix    FiniteExecutionFragment
@@ -29,7 +33,11 @@ func (ief InfiniteExecutionFragment) Check() error {
 		return fmt.Errorf("The number of states in ief.RepeatingSuffix is supposed to be equal to the number of actions, but there are %v states and %v actions.", len(pref.s), len(pref.a))
 	}
 
-	system := pref.s[0].System
+	if len(suffix.s) == 0 {
+		return fmt.Errorf("The RepeatingSuffix value has length 0. If this is a FiniteExecutionFragment, then use that object instead!")
+	}
+
+	system := suffix.s[0].System
 
 	// Verify that the UniquePrefix of the transitions are okay
 	for sIndex := 0; sIndex < len(pref.s)-1; sIndex++ {
@@ -46,13 +54,15 @@ func (ief InfiniteExecutionFragment) Check() error {
 		}
 	}
 
-	prefixFinalState := pref.s[len(pref.s)-1]
-	prefixFinalAction := pref.a[len(pref.a)-1]
-	if !suffix.s[0].In(system.Transition[prefixFinalState][prefixFinalAction]) {
-		return fmt.Errorf(
-			"The transition from the prefix to the suffix was invalid! (i.e. %v not in Transition[%v][%v]).",
-			suffix.s[0], prefixFinalState, prefixFinalAction,
-		)
+	if len(pref.s) > 0 {
+		prefixFinalState := pref.s[len(pref.s)-1]
+		prefixFinalAction := pref.a[len(pref.a)-1]
+		if !suffix.s[0].In(system.Transition[prefixFinalState][prefixFinalAction]) {
+			return fmt.Errorf(
+				"The transition from the prefix to the suffix was invalid! (i.e. %v not in Transition[%v][%v]).",
+				suffix.s[0], prefixFinalState, prefixFinalAction,
+			)
+		}
 	}
 
 	// Verify that the RepeatingSuffix of the transitions are okay
@@ -70,8 +80,8 @@ func (ief InfiniteExecutionFragment) Check() error {
 		}
 	}
 
-	suffixFinalState := suffix.s[len(pref.s)-1]
-	suffixFinalAction := suffix.a[len(pref.a)-1]
+	suffixFinalState := suffix.s[len(suffix.s)-1]
+	suffixFinalAction := suffix.a[len(suffix.a)-1]
 	if !suffix.s[0].In(system.Transition[suffixFinalState][suffixFinalAction]) {
 		return fmt.Errorf(
 			"The transition from the suffix end to the suffix beginning was invalid! (i.e. %v not in Transition[%v][%v]).",
@@ -98,3 +108,73 @@ func (ief InfiniteExecutionFragment) IsMaximal() (bool, error) {
 	// Return true
 	return true, nil
 }
+
+/*
+NewInfiniteExecutionFragment
+Description:
+	Creates the infinite execution fragment which takes the steps of the prefix once and then the steps of the cycle
+	forever. Each step is a state followed by the action taken in it, so every state needs an action.
+	The prefix may be empty, but the cycle may not (see Check()).
+*/
+func NewInfiniteExecutionFragment(prefixStates []mc.TransitionSystemState, prefixActions []string, cycleStates []mc.TransitionSystemState, cycleActions []string) (InfiniteExecutionFragment, error) {
+	ief := InfiniteExecutionFragment{
+		UniquePrefix: FiniteExecutionFragment{
+			s: append([]mc.TransitionSystemState{}, prefixStates...),
+			a: append([]string{}, prefixActions...),
+		},
+		RepeatingSuffix: FiniteExecutionFragment{
+			s: append([]mc.TransitionSystemState{}, cycleStates...),
+			a: append([]string{}, cycleActions...),
+		},
+	}
+	if err := ief.Check(); err != nil {
+		return InfiniteExecutionFragment{}, err
+	}
+
+	return ief, nil
+}
+
+/*
+At
+Description:
+	Returns the i-th state and the action taken in it.
+*/
+func (ief InfiniteExecutionFragment) At(i int) (mc.TransitionSystemState, string, error) {
+	if i < 0 {
+		return mc.TransitionSystemState{}, "", fmt.Errorf("The index %v is negative.", i)
+	}
+
+	prefixLength := len(ief.UniquePrefix.s)
+	if i < prefixLength {
+		return ief.UniquePrefix.s[i], ief.UniquePrefix.a[i], nil
+	}
+
+	cycleIndex := (i - prefixLength) % len(ief.RepeatingSuffix.s)
+	return ief.RepeatingSuffix.s[cycleIndex], ief.RepeatingSuffix.a[cycleIndex], nil
+}
+
+/*
+Unroll
+Description:
+	Returns the finite execution fragment made of the first k actions of the infinite execution fragment.
+*/
+func (ief InfiniteExecutionFragment) Unroll(k int) (FiniteExecutionFragment, error) {
+	if k < 0 {
+		return FiniteExecutionFragment{}, fmt.Errorf("Cannot unroll an infinite execution fragment to the negative length %v.", k)
+	}
+
+	var states []mc.TransitionSystemState
+	var actions []string
+	for i := 0; i <= k; i++ {
+		state, action, err := ief.At(i)
+		if err != nil {
+			return FiniteExecutionFragment{}, err
+		}
+		states = append(states, state)
+		if i < k {
+			actions = append(actions, action)
+		}
+	}
+
+	return NewFiniteExecutionFragment(states, actions)
+}
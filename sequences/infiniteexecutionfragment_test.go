@@ -323,3 +323,52 @@ func TestInfiniteExecutionFragment_IsMaximal1(t *testing.T) {
 	}
 
 }
+
+/*
+TestNewInfiniteExecutionFragment1
+Description:
+	Creates an infinite execution with an empty prefix and unrolls it.
+*/
+func TestNewInfiniteExecutionFragment1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+
+	ief, err := NewInfiniteExecutionFragment(
+		nil, nil,
+		[]mc.TransitionSystemState{ts0.S[0], ts0.S[1], ts0.S[2]},
+		[]string{"insert_coin", "", "get_beer"},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	fe, err := ief.Unroll(4)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if fe.Len() != 4 || fe.Actions()[3] != "insert_coin" {
+		t.Errorf("Expected 4 actions ending with insert_coin, but found %v.", fe.Actions())
+	}
+
+	if state, action, _ := ief.At(5); state.Name != "beer" || action != "get_beer" {
+		t.Errorf("Expected the 5th step to be (beer, get_beer), but found (%v, %v).", state, action)
+	}
+}
+
+/*
+TestInfiniteExecutionFragment_Check8
+Description:
+	Checks that a suffix which is longer than the prefix is checked correctly.
+*/
+func TestInfiniteExecutionFragment_Check8(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+
+	_, err := NewInfiniteExecutionFragment(
+		[]mc.TransitionSystemState{ts0.S[0]}, []string{"insert_coin"},
+		[]mc.TransitionSystemState{ts0.S[1], ts0.S[3], ts0.S[0]},
+		[]string{"", "get_soda", "insert_coin"},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
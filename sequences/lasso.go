@@ -0,0 +1,195 @@
+/*
+lasso.go
+Description:
+	Canonical form of the infinite sequences which are stored as a UniquePrefix followed by a RepeatingSuffix.
+	The same infinite sequence u (v)^omega has many representations (e.g. a (b a)^omega = (a b)^omega).
+	The canonical form uses the shortest cycle (the primitive root of v) and the shortest prefix, so two
+	representations describe the same sequence if and only if their canonical forms are equal.
+*/
+
+package sequences
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+Functions for InfiniteTrace
+*/
+
+/*
+Normalize
+Description:
+	Returns the canonical representation of the trace: the repeating suffix is replaced by its primitive root
+	and the elements of the prefix which repeat the end of the cycle are rotated into the cycle.
+Usage:
+	trace2 := trace1.Normalize()
+*/
+func (traceIn InfiniteTrace) Normalize() InfiniteTrace {
+	prefix, cycle := normalizeLasso(traceIn.UniquePrefix.L, traceIn.RepeatingSuffix.L, mc.APSetsAreEqual)
+	return InfiniteTrace{
+		UniquePrefix:    FiniteTrace{L: prefix},
+		RepeatingSuffix: FiniteTrace{L: cycle},
+	}
+}
+
+/*
+Equals
+Description:
+	Determines if the two traces are the same infinite sequence of sets of atomic propositions,
+	regardless of how they are split into a prefix and a repeating suffix.
+*/
+func (traceIn InfiniteTrace) Equals(trace2 InfiniteTrace) bool {
+	normalized1 := traceIn.Normalize()
+	normalized2 := trace2.Normalize()
+
+	return lassosAreEqual(normalized1.UniquePrefix.L, normalized2.UniquePrefix.L, mc.APSetsAreEqual) &&
+		lassosAreEqual(normalized1.RepeatingSuffix.L, normalized2.RepeatingSuffix.L, mc.APSetsAreEqual)
+}
+
+/*
+Key
+Description:
+	Returns a string which identifies the infinite sequence of the trace. Two traces have the same key
+	if and only if they are Equal, so keys can be used to deduplicate traces in a map.
+*/
+func (traceIn InfiniteTrace) Key() string {
+	normalized := traceIn.Normalize()
+	return fmt.Sprintf("%v (%v)^omega", apSetsKey(normalized.UniquePrefix.L), apSetsKey(normalized.RepeatingSuffix.L))
+}
+
+/*
+Functions for InfinitePathFragment
+*/
+
+/*
+Normalize
+Description:
+	Returns the canonical representation of the path fragment: the repeating suffix is replaced by its primitive
+	root and the states of the prefix which repeat the end of the cycle are rotated into the cycle.
+*/
+func (fragmentIn InfinitePathFragment) Normalize() InfinitePathFragment {
+	prefix, cycle := normalizeLasso(fragmentIn.UniquePrefix.s, fragmentIn.RepeatingSuffix.s, func(s1, s2 mc.TransitionSystemState) bool {
+		return s1.Equals(s2)
+	})
+	return InfinitePathFragment{
+		UniquePrefix:    FinitePathFragment{s: prefix},
+		RepeatingSuffix: FinitePathFragment{s: cycle},
+	}
+}
+
+/*
+Equals
+Description:
+	Determines if the two path fragments visit the same infinite sequence of states.
+*/
+func (fragmentIn InfinitePathFragment) Equals(fragment2 InfinitePathFragment) bool {
+	return fragmentIn.Key() == fragment2.Key()
+}
+
+/*
+Key
+Description:
+	Returns a string which identifies the infinite sequence of states of the path fragment.
+*/
+func (fragmentIn InfinitePathFragment) Key() string {
+	normalized := fragmentIn.Normalize()
+	return fmt.Sprintf("%q (%q)^omega", stateNames(normalized.UniquePrefix.s), stateNames(normalized.RepeatingSuffix.s))
+}
+
+/*
+Helpers
+*/
+
+/*
+normalizeLasso
+Description:
+	Computes the canonical form of prefix (cycle)^omega for a given equality of the elements.
+	The cycle is first reduced to its primitive root; then, while the last element of the prefix is equal
+	to the last element of the cycle, that element is moved from the prefix to the front of the cycle.
+*/
+func normalizeLasso[T any](prefix []T, cycle []T, equal func(T, T) bool) ([]T, []T) {
+	if len(cycle) == 0 {
+		return append([]T{}, prefix...), []T{}
+	}
+
+	// Primitive root
+	rootLength := len(cycle)
+	for length := 1; length < len(cycle); length++ {
+		if len(cycle)%length != 0 {
+			continue
+		}
+
+		isRoot := true
+		for i := length; i < len(cycle) && isRoot; i++ {
+			isRoot = equal(cycle[i], cycle[i-length])
+		}
+		if isRoot {
+			rootLength = length
+			break
+		}
+	}
+
+	prefixOut := append([]T{}, prefix...)
+	cycleOut := append([]T{}, cycle[:rootLength]...)
+
+	// Rotate the end of the prefix into the cycle
+	for len(prefixOut) > 0 && equal(prefixOut[len(prefixOut)-1], cycleOut[len(cycleOut)-1]) {
+		cycleOut = append([]T{prefixOut[len(prefixOut)-1]}, cycleOut[:len(cycleOut)-1]...)
+		prefixOut = prefixOut[:len(prefixOut)-1]
+	}
+
+	return prefixOut, cycleOut
+}
+
+/*
+lassosAreEqual
+Description:
+	Determines if two slices have equal elements at every index.
+*/
+func lassosAreEqual[T any](slice1 []T, slice2 []T, equal func(T, T) bool) bool {
+	if len(slice1) != len(slice2) {
+		return false
+	}
+	for i := range slice1 {
+		if !equal(slice1[i], slice2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+apSetsKey
+Description:
+	Writes a sequence of sets of atomic propositions, with the names of each set sorted and without repetitions.
+*/
+func apSetsKey(sets [][]mc.AtomicProposition) string {
+	var setKeys []string
+	for _, set := range sets {
+		var names []string
+		for _, ap := range set {
+			names = mc.AppendIfUnique(names, ap.Name)
+		}
+		sort.Strings(names)
+		setKeys = append(setKeys, fmt.Sprintf("%q", names))
+	}
+	return "[" + strings.Join(setKeys, " ") + "]"
+}
+
+/*
+stateNames
+Description:
+	Collects the names of a slice of states.
+*/
+func stateNames(states []mc.TransitionSystemState) []string {
+	names := []string{}
+	for _, state := range states {
+		names = append(names, state.Name)
+	}
+	return names
+}
@@ -0,0 +1,118 @@
+/*
+lasso_test.go
+Description:
+	Tests for the canonical forms defined in lasso.go
+*/
+package sequences
+
+import (
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+TestInfiniteTrace_Normalize1
+Description:
+	Normalizes a (b a b a)^omega, which is (a b)^omega.
+*/
+func TestInfiniteTrace_Normalize1(t *testing.T) {
+	a := []mc.AtomicProposition{{Name: "a"}}
+	b := []mc.AtomicProposition{{Name: "b"}}
+
+	trace := InfiniteTrace{
+		UniquePrefix:    FiniteTrace{L: [][]mc.AtomicProposition{a}},
+		RepeatingSuffix: FiniteTrace{L: [][]mc.AtomicProposition{b, a, b, a}},
+	}
+
+	normalized := trace.Normalize()
+	if len(normalized.UniquePrefix.L) != 0 {
+		t.Errorf("Expected the prefix to be empty, but found %v.", normalized.UniquePrefix.L)
+	}
+
+	if len(normalized.RepeatingSuffix.L) != 2 || !mc.APSetsAreEqual(normalized.RepeatingSuffix.L[0], a) {
+		t.Errorf("Expected the cycle (a b), but found %v.", normalized.RepeatingSuffix.L)
+	}
+}
+
+/*
+TestInfiniteTrace_Equals1
+Description:
+	Compares traces which are split differently and traces whose sets are ordered differently.
+*/
+func TestInfiniteTrace_Equals1(t *testing.T) {
+	a := []mc.AtomicProposition{{Name: "a"}}
+	b := []mc.AtomicProposition{{Name: "b"}}
+	ab := []mc.AtomicProposition{{Name: "a"}, {Name: "b"}}
+	ba := []mc.AtomicProposition{{Name: "b"}, {Name: "a"}}
+
+	trace1 := InfiniteTrace{
+		UniquePrefix:    FiniteTrace{L: [][]mc.AtomicProposition{b, ab}},
+		RepeatingSuffix: FiniteTrace{L: [][]mc.AtomicProposition{a, ab}},
+	}
+	trace2 := InfiniteTrace{
+		UniquePrefix:    FiniteTrace{L: [][]mc.AtomicProposition{b}},
+		RepeatingSuffix: FiniteTrace{L: [][]mc.AtomicProposition{ba, a, ba, a}},
+	}
+	trace3 := InfiniteTrace{
+		UniquePrefix:    FiniteTrace{L: [][]mc.AtomicProposition{}},
+		RepeatingSuffix: FiniteTrace{L: [][]mc.AtomicProposition{ab, a}},
+	}
+
+	if !trace1.Equals(trace2) || trace1.Key() != trace2.Key() {
+		t.Errorf("Expected %v and %v to be equal.", trace1.Key(), trace2.Key())
+	}
+
+	if trace1.Equals(trace3) || trace1.Key() == trace3.Key() {
+		t.Errorf("Expected %v and %v to be different.", trace1.Key(), trace3.Key())
+	}
+
+	// Traces can be used as map keys
+	seen := map[string]bool{trace1.Key(): true}
+	if !seen[trace2.Key()] {
+		t.Errorf("Expected the key of trace2 to be in the map.")
+	}
+}
+
+/*
+TestInfinitePathFragment_Normalize1
+Description:
+	Normalizes a path of the beverage vending machine whose prefix repeats the end of its cycle.
+*/
+func TestInfinitePathFragment_Normalize1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+	pay, sel, beer := ts0.S[0], ts0.S[1], ts0.S[2]
+
+	fragment1, err := NewInfinitePathFragment(
+		[]mc.TransitionSystemState{pay, sel, beer},
+		[]mc.TransitionSystemState{pay, sel, beer, pay, sel, beer},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	normalized := fragment1.Normalize()
+	if len(normalized.UniquePrefix.s) != 0 || len(normalized.RepeatingSuffix.s) != 3 {
+		t.Errorf("Expected (pay select beer)^omega, but found %v.", normalized.Key())
+	}
+
+	if err := normalized.Check(); err != nil {
+		t.Errorf("Expected the normalized fragment to be valid, but received: %v", err)
+	}
+
+	fragment2, _ := NewInfinitePathFragment(
+		[]mc.TransitionSystemState{pay},
+		[]mc.TransitionSystemState{sel, beer, pay},
+	)
+	if !fragment1.Equals(fragment2) {
+		t.Errorf("Expected %v and %v to be equal.", fragment1.Key(), fragment2.Key())
+	}
+
+	fragment3, _ := NewInfinitePathFragment(
+		[]mc.TransitionSystemState{pay},
+		[]mc.TransitionSystemState{sel, ts0.S[3], pay},
+	)
+	if fragment1.Equals(fragment3) {
+		t.Errorf("Expected %v and %v to be different.", fragment1.Key(), fragment3.Key())
+	}
+}
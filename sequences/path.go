@@ -81,21 +81,23 @@ func (fragmentIn InfinitePathFragment) Check() error {
 		return fmt.Errorf("There was an issue while checking the suffix of the path fragment: %v", err)
 	}
 
-	//Check that the first state in the suffix is in the Post of the last state from the prefix
-	lastStateInPrefix := fragmentIn.UniquePrefix.s[len(fragmentIn.UniquePrefix.s)-1]
+	//Check that the first state in the suffix is in the Post of the last state from the prefix (if there is a prefix)
 	firstStateInSuffix := fragmentIn.RepeatingSuffix.s[0]
-	ancestorsOfLastState, err := mc.Post(lastStateInPrefix)
-	if err != nil {
-		return fmt.Errorf("There was an error computing the Post of the last state in the prefix: %v", err)
-	}
+	if len(fragmentIn.UniquePrefix.s) > 0 {
+		lastStateInPrefix := fragmentIn.UniquePrefix.s[len(fragmentIn.UniquePrefix.s)-1]
+		ancestorsOfLastState, err := mc.Post(lastStateInPrefix)
+		if err != nil {
+			return fmt.Errorf("There was an error computing the Post of the last state in the prefix: %v", err)
+		}
 
-	if !firstStateInSuffix.In(ancestorsOfLastState) {
-		return fmt.Errorf("The first state in the suffix \"%v\" was not an ancestor of the last state in the prefix \"%v\".", firstStateInSuffix, lastStateInPrefix)
+		if !firstStateInSuffix.In(ancestorsOfLastState) {
+			return fmt.Errorf("The first state in the suffix \"%v\" was not an ancestor of the last state in the prefix \"%v\".", firstStateInSuffix, lastStateInPrefix)
+		}
 	}
 
 	//Check that the first state in the suffix is in the Post of the last state in the suffix
 	lastStateInSuffix := fragmentIn.RepeatingSuffix.s[len(fragmentIn.RepeatingSuffix.s)-1]
-	ancestorsOfLastState, err = mc.Post(lastStateInSuffix)
+	ancestorsOfLastState, err := mc.Post(lastStateInSuffix)
 	if err != nil {
 		return fmt.Errorf("There was an error computing the Post of the last state in the suffix: %v", err)
 	}
@@ -199,3 +201,152 @@ func (fragmentIn InfinitePathFragment) ToTrace() InfiniteTrace {
 		RepeatingSuffix: fragmentIn.RepeatingSuffix.ToTrace(),
 	}
 }
+
+/*
+NewFinitePathFragment
+Description:
+	Creates the finite path fragment that visits the given states in order. The fragment must contain at least
+	one state and each state must be a successor of the previous one (see Check()).
+*/
+func NewFinitePathFragment(states []mc.TransitionSystemState) (FinitePathFragment, error) {
+	if len(states) == 0 {
+		return FinitePathFragment{}, fmt.Errorf("A path fragment must contain at least one state.")
+	}
+
+	fragment := FinitePathFragment{s: append([]mc.TransitionSystemState{}, states...)}
+	if err := fragment.Check(); err != nil {
+		return FinitePathFragment{}, err
+	}
+
+	return fragment, nil
+}
+
+/*
+NewInfinitePathFragment
+Description:
+	Creates the infinite path fragment that visits the states of prefix once and then the states of cycle forever.
+	The prefix may be empty, but the cycle may not (see Check()).
+*/
+func NewInfinitePathFragment(prefix []mc.TransitionSystemState, cycle []mc.TransitionSystemState) (InfinitePathFragment, error) {
+	fragment := InfinitePathFragment{
+		UniquePrefix:    FinitePathFragment{s: append([]mc.TransitionSystemState{}, prefix...)},
+		RepeatingSuffix: FinitePathFragment{s: append([]mc.TransitionSystemState{}, cycle...)},
+	}
+	if err := fragment.Check(); err != nil {
+		return InfinitePathFragment{}, err
+	}
+
+	return fragment, nil
+}
+
+/*
+Len
+Description:
+	Returns the length of the path fragment, i.e. its number of transitions (one less than its number of states).
+*/
+func (fragmentIn FinitePathFragment) Len() int {
+	return len(fragmentIn.s) - 1
+}
+
+/*
+States
+Description:
+	Returns a copy of the states of the path fragment.
+*/
+func (fragmentIn FinitePathFragment) States() []mc.TransitionSystemState {
+	return append([]mc.TransitionSystemState{}, fragmentIn.s...)
+}
+
+/*
+At
+Description:
+	Returns the i-th state of the path fragment (0 <= i <= Len()).
+*/
+func (fragmentIn FinitePathFragment) At(i int) (mc.TransitionSystemState, error) {
+	if i < 0 || i > fragmentIn.Len() {
+		return mc.TransitionSystemState{}, fmt.Errorf("The index %v is outside of the path fragment of length %v.", i, fragmentIn.Len())
+	}
+	return fragmentIn.s[i], nil
+}
+
+/*
+Prefix
+Description:
+	Returns the path fragment made of the first n transitions (i.e. the states 0 to n).
+*/
+func (fragmentIn FinitePathFragment) Prefix(n int) (FinitePathFragment, error) {
+	if n < 0 || n > fragmentIn.Len() {
+		return FinitePathFragment{}, fmt.Errorf("Cannot take the prefix of length %v of a path fragment of length %v.", n, fragmentIn.Len())
+	}
+	return NewFinitePathFragment(fragmentIn.s[:n+1])
+}
+
+/*
+Suffix
+Description:
+	Returns the path fragment which starts at the n-th state.
+*/
+func (fragmentIn FinitePathFragment) Suffix(n int) (FinitePathFragment, error) {
+	if n < 0 || n > fragmentIn.Len() {
+		return FinitePathFragment{}, fmt.Errorf("Cannot take the suffix from state %v of a path fragment of length %v.", n, fragmentIn.Len())
+	}
+	return NewFinitePathFragment(fragmentIn.s[n:])
+}
+
+/*
+Concat
+Description:
+	Appends fragment2 to the path fragment. fragment2 must start in the last state of the path fragment,
+	so that fragment.Prefix(n).Concat(fragment.Suffix(n)) is fragment.
+*/
+func (fragmentIn FinitePathFragment) Concat(fragment2 FinitePathFragment) (FinitePathFragment, error) {
+	if len(fragmentIn.s) == 0 || len(fragment2.s) == 0 {
+		return FinitePathFragment{}, fmt.Errorf("Cannot concatenate an empty path fragment.")
+	}
+
+	lastState := fragmentIn.s[len(fragmentIn.s)-1]
+	if !lastState.Equals(fragment2.s[0]) {
+		return FinitePathFragment{}, fmt.Errorf("The second fragment starts in \"%v\", but the first fragment ends in \"%v\".", fragment2.s[0], lastState)
+	}
+	return NewFinitePathFragment(append(fragmentIn.States(), fragment2.s[1:]...))
+}
+
+/*
+At
+Description:
+	Returns the i-th state of the infinite path fragment.
+*/
+func (fragmentIn InfinitePathFragment) At(i int) (mc.TransitionSystemState, error) {
+	if i < 0 {
+		return mc.TransitionSystemState{}, fmt.Errorf("The index %v is negative.", i)
+	}
+
+	prefixLength := len(fragmentIn.UniquePrefix.s)
+	if i < prefixLength {
+		return fragmentIn.UniquePrefix.s[i], nil
+	}
+	return fragmentIn.RepeatingSuffix.s[(i-prefixLength)%len(fragmentIn.RepeatingSuffix.s)], nil
+}
+
+/*
+Unroll
+Description:
+	Returns the finite path fragment made of the first k transitions of the infinite path fragment
+	(the prefix followed by as many copies of the cycle as needed).
+*/
+func (fragmentIn InfinitePathFragment) Unroll(k int) (FinitePathFragment, error) {
+	if k < 0 {
+		return FinitePathFragment{}, fmt.Errorf("Cannot unroll an infinite path fragment to the negative length %v.", k)
+	}
+
+	var states []mc.TransitionSystemState
+	for i := 0; i <= k; i++ {
+		state, err := fragmentIn.At(i)
+		if err != nil {
+			return FinitePathFragment{}, err
+		}
+		states = append(states, state)
+	}
+
+	return NewFinitePathFragment(states)
+}
@@ -478,3 +478,111 @@ func TestPath_ToTrace2(t *testing.T) {
 	}
 
 }
+
+/*
+TestNewFinitePathFragment1
+Description:
+	Verifies that the constructor checks the transitions of the path fragment.
+*/
+func TestNewFinitePathFragment1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+
+	fragment, err := NewFinitePathFragment([]mc.TransitionSystemState{ts0.S[0], ts0.S[1], ts0.S[2]})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if fragment.Len() != 2 || len(fragment.States()) != 3 {
+		t.Errorf("Expected a fragment of length 2 with 3 states, but found length %v.", fragment.Len())
+	}
+
+	if _, err := NewFinitePathFragment([]mc.TransitionSystemState{ts0.S[0], ts0.S[2]}); err == nil {
+		t.Errorf("Expected an error for the invalid transition from pay to beer.")
+	}
+
+	if _, err := NewFinitePathFragment([]mc.TransitionSystemState{}); err == nil {
+		t.Errorf("Expected an error for an empty path fragment.")
+	}
+}
+
+/*
+TestFinitePathFragment_Prefix1
+Description:
+	Verifies that a prefix and a suffix concatenate back to the original fragment.
+*/
+func TestFinitePathFragment_Prefix1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+	fragment, _ := NewFinitePathFragment([]mc.TransitionSystemState{ts0.S[0], ts0.S[1], ts0.S[2], ts0.S[0]})
+
+	prefix, err := fragment.Prefix(1)
+	if err != nil || prefix.Len() != 1 {
+		t.Errorf("Expected a prefix of length 1, but found %v (%v).", prefix.Len(), err)
+	}
+
+	suffix, err := fragment.Suffix(1)
+	if err != nil || suffix.Len() != 2 {
+		t.Errorf("Expected a suffix of length 2, but found %v (%v).", suffix.Len(), err)
+	}
+
+	concatenation, err := prefix.Concat(suffix)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for i := 0; i <= fragment.Len(); i++ {
+		s1, _ := fragment.At(i)
+		s2, _ := concatenation.At(i)
+		if !s1.Equals(s2) {
+			t.Errorf("Expected state %v of the concatenation to be %v, but found %v.", i, s1, s2)
+		}
+	}
+
+	if _, err := fragment.Prefix(4); err == nil {
+		t.Errorf("Expected an error for a prefix longer than the fragment.")
+	}
+
+	if _, err := fragment.At(-1); err == nil {
+		t.Errorf("Expected an error for a negative index.")
+	}
+
+	// The second fragment must start where the first ends
+	if _, err := suffix.Concat(prefix); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := prefix.Concat(prefix); err == nil {
+		t.Errorf("Expected an error when concatenating fragments that do not meet.")
+	}
+}
+
+/*
+TestInfinitePathFragment_Unroll1
+Description:
+	Unrolls the cycle of the beverage vending machine.
+*/
+func TestInfinitePathFragment_Unroll1(t *testing.T) {
+	ts0 := mc.GetBeverageVendingMachineTS()
+
+	fragment, err := NewInfinitePathFragment(
+		[]mc.TransitionSystemState{},
+		[]mc.TransitionSystemState{ts0.S[0], ts0.S[1], ts0.S[2]},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	unrolled, err := fragment.Unroll(4)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"pay", "select", "beer", "pay", "select"}
+	for i, name := range expected {
+		state, _ := unrolled.At(i)
+		if state.Name != name {
+			t.Errorf("Expected state %v to be %v, but found %v.", i, name, state)
+		}
+	}
+
+	if _, err := NewInfinitePathFragment([]mc.TransitionSystemState{ts0.S[0]}, []mc.TransitionSystemState{ts0.S[0]}); err == nil {
+		t.Errorf("Expected an error for the cycle pay -> pay.")
+	}
+}
@@ -9,6 +9,7 @@ package sequences
 
 import (
 	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
 )
 
 /*
@@ -32,6 +33,7 @@ type InfiniteTrace struct {
 
 type Trace interface {
 	SatisfiesAPInvariant(mc.AtomicProposition) bool
+	Satisfies(ltl.Formula) (bool, error)
 }
 
 /*
@@ -55,5 +57,28 @@ func (traceIn FiniteTrace) SatisfiesAPInvariant(apIn mc.AtomicProposition) bool
 
 func (traceIn InfiniteTrace) SatisfiesAPInvariant(apIn mc.AtomicProposition) bool {
 
-	return traceIn.UniquePrefix.SatisfiesAPInvariant(apIn) && traceIn.RepeatingSuffix.SatisfiesAPInvariant()
+	return traceIn.UniquePrefix.SatisfiesAPInvariant(apIn) && traceIn.RepeatingSuffix.SatisfiesAPInvariant(apIn)
+}
+
+/*
+Satisfies
+Description:
+	Determines if the finite trace satisfies the formula with the finite-trace (LTLf) semantics.
+	The trace must contain at least one set of atomic propositions.
+Usage:
+	formula, _ := ltl.Parse("F done")
+	satisfied, err := trace.Satisfies(formula)
+*/
+func (traceIn FiniteTrace) Satisfies(formula ltl.Formula) (bool, error) {
+	return ltl.EvaluateFinite(formula, traceIn.L)
+}
+
+/*
+Satisfies
+Description:
+	Determines if the infinite trace UniquePrefix (RepeatingSuffix)^omega satisfies the LTL formula.
+	The evaluation is exact and takes time linear in the length of the trace times the size of the formula.
+*/
+func (traceIn InfiniteTrace) Satisfies(formula ltl.Formula) (bool, error) {
+	return ltl.EvaluateLasso(formula, traceIn.UniquePrefix.L, traceIn.RepeatingSuffix.L)
 }
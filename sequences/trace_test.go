@@ -0,0 +1,131 @@
+/*
+trace_test.go
+Description:
+	Tests for the traces defined in trace.go
+*/
+package sequences
+
+import (
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+)
+
+/*
+TestFiniteTrace_SatisfiesAPInvariant1
+Description:
+	Checks an invariant which holds and one which does not.
+*/
+func TestFiniteTrace_SatisfiesAPInvariant1(t *testing.T) {
+	a := mc.AtomicProposition{Name: "a"}
+	b := mc.AtomicProposition{Name: "b"}
+
+	trace := FiniteTrace{L: [][]mc.AtomicProposition{{a}, {a, b}}}
+
+	if !trace.SatisfiesAPInvariant(a) {
+		t.Errorf("Expected the trace to satisfy the invariant a.")
+	}
+
+	if trace.SatisfiesAPInvariant(b) {
+		t.Errorf("Expected the trace to not satisfy the invariant b.")
+	}
+}
+
+/*
+TestFiniteTrace_Satisfies1
+Description:
+	Evaluates a request-grant specification on a finite behaviour with the LTLf semantics.
+*/
+func TestFiniteTrace_Satisfies1(t *testing.T) {
+	request := mc.AtomicProposition{Name: "request"}
+	grant := mc.AtomicProposition{Name: "grant"}
+
+	trace := FiniteTrace{L: [][]mc.AtomicProposition{{}, {request}, {}, {grant}}}
+
+	formula, err := ltl.Parse("G (request -> F grant)")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	satisfied, err := trace.Satisfies(formula)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !satisfied {
+		t.Errorf("Expected the trace to satisfy %v.", formula)
+	}
+
+	// The request at the end of a shorter trace is never granted
+	satisfied, _ = FiniteTrace{L: trace.L[:3]}.Satisfies(formula)
+	if satisfied {
+		t.Errorf("Expected the shortened trace to not satisfy %v.", formula)
+	}
+}
+
+/*
+TestFiniteTrace_Satisfies2
+Description:
+	The empty trace produces an error.
+*/
+func TestFiniteTrace_Satisfies2(t *testing.T) {
+	_, err := FiniteTrace{}.Satisfies(ltl.True())
+	if err == nil {
+		t.Errorf("Expected an error for the empty trace.")
+	}
+}
+
+/*
+TestInfiniteTrace_Satisfies1
+Description:
+	Evaluates liveness and safety properties on the trace {} ({request} {grant})^omega through the Trace interface.
+*/
+func TestInfiniteTrace_Satisfies1(t *testing.T) {
+	request := mc.AtomicProposition{Name: "request"}
+	grant := mc.AtomicProposition{Name: "grant"}
+
+	var trace Trace = InfiniteTrace{
+		UniquePrefix:    FiniteTrace{L: [][]mc.AtomicProposition{{}}},
+		RepeatingSuffix: FiniteTrace{L: [][]mc.AtomicProposition{{request}, {grant}}},
+	}
+
+	testCases := map[string]bool{
+		"G (request -> X grant)":  true,
+		"G F request & G F grant": true,
+		"F G grant":               false,
+		"!grant U request":        true,
+		"G !(request & grant)":    true,
+	}
+
+	for formulaString, expected := range testCases {
+		formula, err := ltl.Parse(formulaString)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		satisfied, err := trace.Satisfies(formula)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if satisfied != expected {
+			t.Errorf("Expected \"%v\" to evaluate to %v, but found %v.", formulaString, expected, satisfied)
+		}
+	}
+}
+
+/*
+TestInfiniteTrace_Satisfies2
+Description:
+	A trace without a repeating suffix is not an infinite trace.
+*/
+func TestInfiniteTrace_Satisfies2(t *testing.T) {
+	trace := InfiniteTrace{
+		UniquePrefix: FiniteTrace{L: [][]mc.AtomicProposition{{}}},
+	}
+
+	_, err := trace.Satisfies(ltl.True())
+	if err == nil {
+		t.Errorf("Expected an error for the empty repeating suffix.")
+	}
+}
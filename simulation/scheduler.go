@@ -0,0 +1,176 @@
+/*
+scheduler.go
+Description:
+	Schedulers resolve the choice of action in each step of a simulation.
+	The choice of successor for the chosen action is always made uniformly at random by the Simulator.
+*/
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/sequences"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Scheduler
+Description:
+	Chooses one of the enabled actions of the last state of history. Randomized schedulers must only use
+	the given source of randomness, so that simulations with the same seed are reproducible.
+*/
+type Scheduler interface {
+	Choose(history History, enabled []string, random *rand.Rand) (string, error)
+}
+
+/*
+History
+Description:
+	A read-only view of the steps of a run which have been simulated so far. It shares its storage with
+	the Simulator, which only appends to it, so that a step does not copy and check the whole run.
+	Use Fragment() to obtain a sequences.FiniteExecutionFragment.
+*/
+type History struct {
+	states  []mc.TransitionSystemState
+	actions []string
+}
+
+/*
+UniformScheduler
+Description:
+	Chooses each enabled action with the same probability.
+*/
+type UniformScheduler struct{}
+
+/*
+WeightedScheduler
+Description:
+	Chooses each enabled action with a probability proportional to its weight.
+	Actions which are missing from Weights have weight zero.
+*/
+type WeightedScheduler struct {
+	Weights map[string]float64
+}
+
+/*
+SchedulerFunc
+Description:
+	Allows an ordinary function to be used as a Scheduler (e.g. to replay a recorded run or to ask a user).
+Usage:
+	scheduler := SchedulerFunc(func(history History, enabled []string, random *rand.Rand) (string, error) {
+		return enabled[0], nil
+	})
+*/
+type SchedulerFunc func(history History, enabled []string, random *rand.Rand) (string, error)
+
+/*
+Functions
+*/
+
+/*
+Len
+Description:
+	Returns the number of actions taken so far.
+*/
+func (history History) Len() int {
+	return len(history.actions)
+}
+
+/*
+At
+Description:
+	Returns the i-th state of the history (0 <= i <= Len()).
+*/
+func (history History) At(i int) (mc.TransitionSystemState, error) {
+	if i < 0 || i >= len(history.states) {
+		return mc.TransitionSystemState{}, fmt.Errorf("The index %v is outside of the history of length %v.", i, history.Len())
+	}
+	return history.states[i], nil
+}
+
+/*
+ActionAt
+Description:
+	Returns the i-th action of the history (0 <= i < Len()).
+*/
+func (history History) ActionAt(i int) (string, error) {
+	if i < 0 || i >= len(history.actions) {
+		return "", fmt.Errorf("The index %v is outside of the actions of the history of length %v.", i, history.Len())
+	}
+	return history.actions[i], nil
+}
+
+/*
+Fragment
+Description:
+	Returns a checked copy of the history as an execution fragment.
+*/
+func (history History) Fragment() (sequences.FiniteExecutionFragment, error) {
+	return sequences.NewFiniteExecutionFragment(history.states, history.actions)
+}
+
+/*
+Choose
+Description:
+	Returns one of the enabled actions, chosen uniformly at random.
+*/
+func (scheduler UniformScheduler) Choose(history History, enabled []string, random *rand.Rand) (string, error) {
+	if len(enabled) == 0 {
+		return "", errors.New("There are no enabled actions to choose from.")
+	}
+
+	return enabled[random.Intn(len(enabled))], nil
+}
+
+/*
+Choose
+Description:
+	Returns one of the enabled actions, chosen with a probability proportional to its weight.
+*/
+func (scheduler WeightedScheduler) Choose(history History, enabled []string, random *rand.Rand) (string, error) {
+	totalWeight := 0.0
+	for _, action := range enabled {
+		weight := scheduler.Weights[action]
+		if weight < 0 {
+			return "", fmt.Errorf("The weight of action \"%v\" is negative (%v).", action, weight)
+		}
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return "", fmt.Errorf("None of the enabled actions %v has a positive weight.", enabled)
+	}
+
+	threshold := random.Float64() * totalWeight
+	lastPositive := ""
+	for _, action := range enabled {
+		weight := scheduler.Weights[action]
+		if weight == 0 {
+			continue
+		}
+		if threshold < weight {
+			return action, nil
+		}
+		threshold -= weight
+		lastPositive = action
+	}
+
+	// Only reached through rounding errors
+	return lastPositive, nil
+}
+
+/*
+Choose
+Description:
+	Calls the function.
+*/
+func (scheduler SchedulerFunc) Choose(history History, enabled []string, random *rand.Rand) (string, error) {
+	return scheduler(history, enabled, random)
+}
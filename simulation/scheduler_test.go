@@ -0,0 +1,153 @@
+/*
+scheduler_test.go
+Description:
+
+	Tests for the schedulers defined in scheduler.go
+*/
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+)
+
+/*
+TestUniformScheduler_Choose1
+Description:
+
+	Each of three actions is chosen about a third of the time.
+*/
+func TestUniformScheduler_Choose1(t *testing.T) {
+	random := rand.New(rand.NewSource(41))
+	enabled := []string{"a", "b", "c"}
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		action, err := UniformScheduler{}.Choose(History{}, enabled, random)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		counts[action]++
+	}
+
+	for _, action := range enabled {
+		if counts[action] < 900 || counts[action] > 1100 {
+			t.Errorf("Expected action %v to be chosen about 1000 times, but it was chosen %v times.", action, counts[action])
+		}
+	}
+
+	_, err := UniformScheduler{}.Choose(History{}, []string{}, random)
+	if err == nil {
+		t.Errorf("Expected an error when no actions are enabled.")
+	}
+}
+
+/*
+TestWeightedScheduler_Choose1
+Description:
+
+	With the weights a: 3, b: 1 and c: 0, a is chosen about 75% of the time and c is never chosen.
+*/
+func TestWeightedScheduler_Choose1(t *testing.T) {
+	random := rand.New(rand.NewSource(41))
+	scheduler := WeightedScheduler{Weights: map[string]float64{"a": 3, "b": 1}}
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		action, err := scheduler.Choose(History{}, []string{"a", "b", "c"}, random)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		counts[action]++
+	}
+
+	if counts["a"] < 2850 || counts["a"] > 3150 {
+		t.Errorf("Expected a to be chosen about 3000 times, but it was chosen %v times.", counts["a"])
+	}
+
+	if counts["c"] != 0 {
+		t.Errorf("Expected c to never be chosen, but it was chosen %v times.", counts["c"])
+	}
+}
+
+/*
+TestWeightedScheduler_Choose2
+Description:
+
+	Negative weights and enabled actions without positive weights produce errors.
+*/
+func TestWeightedScheduler_Choose2(t *testing.T) {
+	random := rand.New(rand.NewSource(41))
+
+	_, err := WeightedScheduler{Weights: map[string]float64{"a": -1}}.Choose(History{}, []string{"a"}, random)
+	if err == nil || err.Error() != "The weight of action \"a\" is negative (-1)." {
+		t.Errorf("Expected an error for the negative weight, but found %v.", err)
+	}
+
+	_, err = WeightedScheduler{Weights: map[string]float64{"a": 1}}.Choose(History{}, []string{"b", "c"}, random)
+	if err == nil || err.Error() != "None of the enabled actions [b c] has a positive weight." {
+		t.Errorf("Expected an error for the missing weights, but found %v.", err)
+	}
+}
+
+/*
+TestSchedulerFunc_Choose1
+Description:
+
+	A SchedulerFunc can use the history, e.g. to alternate between two actions.
+*/
+func TestSchedulerFunc_Choose1(t *testing.T) {
+	scheduler := SchedulerFunc(func(history History, enabled []string, random *rand.Rand) (string, error) {
+		return enabled[history.Len()%len(enabled)], nil
+	})
+
+	action, err := scheduler.Choose(History{}, []string{"a", "b"}, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if action != "a" {
+		t.Errorf("Expected a, but found %v.", action)
+	}
+}
+
+/*
+TestHistory_Fragment1
+Description:
+
+	The history given to the scheduler contains the steps simulated so far and converts to a valid fragment.
+*/
+func TestHistory_Fragment1(t *testing.T) {
+	var lengths []int
+	scheduler := SchedulerFunc(func(history History, enabled []string, random *rand.Rand) (string, error) {
+		fragment, err := history.Fragment()
+		if err != nil || fragment.Len() != history.Len() {
+			t.Errorf("Expected a valid fragment of length %v, but found %v (%v).", history.Len(), fragment.Len(), err)
+		}
+
+		if history.Len() > 0 {
+			action, err := history.ActionAt(history.Len() - 1)
+			if err != nil || action != "1" {
+				t.Errorf("Expected the last action to be 1, but found %v (%v).", action, err)
+			}
+		}
+		lengths = append(lengths, history.Len())
+		return "1", nil
+	})
+
+	sim, _ := NewSimulator(mc.GetSimpleTS1(), scheduler, 0)
+	sim.IgnoreCycles = true
+	if _, err := sim.Run(3); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(lengths) != 3 || lengths[2] != 2 {
+		t.Errorf("Expected the scheduler to see histories of lengths 0, 1 and 2, but found %v.", lengths)
+	}
+
+	if _, err := (History{}).At(0); err == nil {
+		t.Errorf("Expected an error for a state outside of the empty history.")
+	}
+}
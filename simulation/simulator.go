@@ -0,0 +1,263 @@
+/*
+simulator.go
+Description:
+	Monte Carlo exploration of transition systems. A Simulator starts in a random initial state and repeatedly
+	lets its Scheduler choose an enabled action and then moves to a random successor for that action.
+	A run ends when it reaches a terminal state, when it closes a cycle (i.e. revisits a state) or after a
+	maximum number of steps. All random choices come from a seeded source, so runs are reproducible.
+*/
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/adaptive"
+	"github.com/kwesiRutledge/ModelChecking/sequences"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Outcome
+Description:
+	The reason that a run ended.
+*/
+type Outcome int
+
+const (
+	ReachedStepLimit Outcome = iota
+	ReachedTerminalState
+	ClosedCycle
+)
+
+/*
+Run
+Description:
+	The result of one simulation. Fragment contains every step that was simulated. If the run closed a cycle,
+	then Lasso is the infinite execution fragment which repeats that cycle forever.
+*/
+type Run struct {
+	Outcome  Outcome
+	Fragment sequences.FiniteExecutionFragment
+	Lasso    sequences.InfiniteExecutionFragment
+}
+
+/*
+Simulator
+Description:
+	Simulates runs of System. If IgnoreCycles is true, then runs only end in terminal states or at the step limit.
+	A Simulator is not safe for concurrent use, because its runs share one source of randomness.
+*/
+type Simulator struct {
+	System       mc.TransitionSystem
+	Scheduler    Scheduler
+	IgnoreCycles bool
+	random       *rand.Rand
+}
+
+/*
+Functions
+*/
+
+/*
+String
+Description:
+	Returns a readable name for the outcome.
+*/
+func (outcome Outcome) String() string {
+	switch outcome {
+	case ReachedStepLimit:
+		return "reached the step limit"
+	case ReachedTerminalState:
+		return "reached a terminal state"
+	case ClosedCycle:
+		return "closed a cycle"
+	default:
+		return fmt.Sprintf("Outcome(%v)", int(outcome))
+	}
+}
+
+/*
+NewSimulator
+Description:
+	Creates a simulator for the transition system whose random choices are determined by the seed.
+Usage:
+	sim, err := NewSimulator(ts, UniformScheduler{}, 42)
+	run, err := sim.Run(100)
+*/
+func NewSimulator(ts mc.TransitionSystem, scheduler Scheduler, seed int64) (Simulator, error) {
+	// Input Processing
+	if err := ts.Check(); err != nil {
+		return Simulator{}, err
+	}
+
+	if len(ts.I) == 0 {
+		return Simulator{}, errors.New("The transition system has no initial states to start a simulation from.")
+	}
+
+	if scheduler == nil {
+		return Simulator{}, errors.New("The simulator needs a scheduler, but the given scheduler is nil.")
+	}
+
+	return Simulator{
+		System:    ts,
+		Scheduler: scheduler,
+		random:    rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+/*
+NewAdaptiveSimulator
+Description:
+	Creates a simulator for an adaptive.TransitionSystem which starts in one of the named states.
+	The runs are fragments of ts.ToModelCheckingTransitionSystem(initialStateNames).
+*/
+func NewAdaptiveSimulator(ts adaptive.TransitionSystem, initialStateNames []string, scheduler Scheduler, seed int64) (Simulator, error) {
+	converted, err := ts.ToModelCheckingTransitionSystem(initialStateNames)
+	if err != nil {
+		return Simulator{}, err
+	}
+
+	return NewSimulator(converted, scheduler, seed)
+}
+
+/*
+Run
+Description:
+	Simulates one run with at most maxSteps transitions.
+*/
+func (sim Simulator) Run(maxSteps int) (Run, error) {
+	if maxSteps < 0 {
+		return Run{}, fmt.Errorf("The maximum number of steps must be nonnegative, but it is %v.", maxSteps)
+	}
+
+	initialStates := uniqueStates(sim.System.I)
+	states := []mc.TransitionSystemState{initialStates[sim.random.Intn(len(initialStates))]}
+	var actions []string
+	firstVisit := map[string]int{states[0].Name: 0}
+
+	for len(actions) < maxSteps {
+		current := states[len(states)-1]
+
+		enabled, err := sim.enabledActions(current)
+		if err != nil {
+			return Run{}, err
+		}
+		if len(enabled) == 0 {
+			return sim.finish(ReachedTerminalState, states, actions, -1)
+		}
+
+		action, err := sim.Scheduler.Choose(History{states: states, actions: actions}, enabled, sim.random)
+		if err != nil {
+			return Run{}, err
+		}
+		if _, isEnabled := mc.FindInSlice(action, enabled); !isEnabled {
+			return Run{}, fmt.Errorf("The scheduler chose the action \"%v\", which is not enabled in state \"%v\".", action, current.Name)
+		}
+
+		successors, err := mc.Post(current, action)
+		if err != nil {
+			return Run{}, err
+		}
+		next := successors[sim.random.Intn(len(successors))]
+
+		states = append(states, next)
+		actions = append(actions, action)
+
+		cycleStart, visited := firstVisit[next.Name]
+		if !visited {
+			firstVisit[next.Name] = len(states) - 1
+		} else if !sim.IgnoreCycles {
+			return sim.finish(ClosedCycle, states, actions, cycleStart)
+		}
+	}
+
+	if states[len(states)-1].IsTerminal() {
+		return sim.finish(ReachedTerminalState, states, actions, -1)
+	}
+	return sim.finish(ReachedStepLimit, states, actions, -1)
+}
+
+/*
+Runs
+Description:
+	Simulates n runs, one after the other, with at most maxSteps transitions each.
+*/
+func (sim Simulator) Runs(n int, maxSteps int) ([]Run, error) {
+	var runs []Run
+	for i := 0; i < n; i++ {
+		run, err := sim.Run(maxSteps)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+/*
+enabledActions
+Description:
+	Returns the actions of the system (in the order of Act) which have at least one successor in the state.
+*/
+func (sim Simulator) enabledActions(state mc.TransitionSystemState) ([]string, error) {
+	var enabled []string
+	for _, action := range sim.System.Act {
+		successors, err := mc.Post(state, action)
+		if err != nil {
+			return nil, err
+		}
+		if len(successors) > 0 {
+			enabled = append(enabled, action)
+		}
+	}
+	return enabled, nil
+}
+
+/*
+finish
+Description:
+	Builds the Run for the simulated states and actions. If cycleStart is nonnegative, then the last state
+	is a repetition of states[cycleStart] and the lasso repeats the steps from cycleStart onwards.
+*/
+func (sim Simulator) finish(outcome Outcome, states []mc.TransitionSystemState, actions []string, cycleStart int) (Run, error) {
+	fragment, err := sequences.NewFiniteExecutionFragment(states, actions)
+	if err != nil {
+		return Run{}, err
+	}
+
+	run := Run{Outcome: outcome, Fragment: fragment}
+	if cycleStart < 0 {
+		return run, nil
+	}
+
+	lastStep := len(states) - 1
+	run.Lasso, err = sequences.NewInfiniteExecutionFragment(
+		states[:cycleStart], actions[:cycleStart],
+		states[cycleStart:lastStep], actions[cycleStart:lastStep],
+	)
+	if err != nil {
+		return Run{}, err
+	}
+
+	return run, nil
+}
+
+/*
+uniqueStates
+Description:
+	Removes the repeated states of a slice.
+*/
+func uniqueStates(states []mc.TransitionSystemState) []mc.TransitionSystemState {
+	var statesOut []mc.TransitionSystemState
+	for _, state := range states {
+		statesOut = state.AppendIfUniqueTo(statesOut)
+	}
+	return statesOut
+}
@@ -0,0 +1,239 @@
+/*
+simulator_test.go
+Description:
+	Tests for the simulator defined in simulator.go
+*/
+package simulation
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/adaptive"
+	"github.com/kwesiRutledge/ModelChecking/sequences"
+)
+
+/*
+stateNames
+Description:
+	Writes the states of a fragment as their names separated by spaces.
+*/
+func stateNames(fragment sequences.FiniteExecutionFragment) string {
+	var names []string
+	for _, state := range fragment.States() {
+		names = append(names, state.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+/*
+TestSimulator_Run1
+Description:
+	Two simulators with the same seed produce the same runs; a different seed produces different runs.
+*/
+func TestSimulator_Run1(t *testing.T) {
+	ts := mc.GetSimpleTS1()
+
+	var runNames [3][]string
+	for index, seed := range []int64{7, 7, 8} {
+		sim, err := NewSimulator(ts, UniformScheduler{}, seed)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		sim.IgnoreCycles = true
+
+		runs, err := sim.Runs(5, 20)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		for _, run := range runs {
+			if run.Outcome != ReachedStepLimit || run.Fragment.Len() != 20 {
+				t.Errorf("Expected every run to take 20 steps, but found a run which %v after %v steps.", run.Outcome, run.Fragment.Len())
+			}
+			runNames[index] = append(runNames[index], stateNames(run.Fragment))
+		}
+	}
+
+	if strings.Join(runNames[0], ",") != strings.Join(runNames[1], ",") {
+		t.Errorf("Expected the same seed to produce the same runs, but found %v and %v.", runNames[0], runNames[1])
+	}
+
+	if strings.Join(runNames[0], ",") == strings.Join(runNames[2], ",") {
+		t.Errorf("Expected different seeds to produce different runs, but both produced %v.", runNames[0])
+	}
+}
+
+/*
+TestSimulator_Run2
+Description:
+	A scheduler which always plays action "1" in state 1 of GetSimpleTS1() closes the cycle 1 -> 1 immediately.
+*/
+func TestSimulator_Run2(t *testing.T) {
+	scheduler := SchedulerFunc(func(history History, enabled []string, random *rand.Rand) (string, error) {
+		return "1", nil
+	})
+
+	sim, err := NewSimulator(mc.GetSimpleTS1(), scheduler, 0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	run, err := sim.Run(10)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if run.Outcome != ClosedCycle {
+		t.Errorf("Expected the run to close a cycle, but it %v.", run.Outcome)
+	}
+
+	if stateNames(run.Fragment) != "1 1" {
+		t.Errorf("Expected the fragment 1 1, but found %v.", stateNames(run.Fragment))
+	}
+
+	if err := run.Lasso.Check(); err != nil {
+		t.Errorf("Expected the lasso to be valid, but found error: %v", err)
+	}
+
+	state, action, _ := run.Lasso.At(5)
+	if state.Name != "1" || action != "1" {
+		t.Errorf("Expected the lasso to take action 1 in state 1 forever, but found (%v,%v) at index 5.", state.Name, action)
+	}
+}
+
+/*
+TestSimulator_Run3
+Description:
+	Without cycle detection, runs of GetSimpleTS2() eventually reach the terminal state 4.
+*/
+func TestSimulator_Run3(t *testing.T) {
+	sim, err := NewSimulator(mc.GetSimpleTS2(), UniformScheduler{}, 41)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	sim.IgnoreCycles = true
+
+	for runIndex := 0; runIndex < 10; runIndex++ {
+		run, err := sim.Run(1000)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		lastState, _ := run.Fragment.At(run.Fragment.Len())
+		if run.Outcome != ReachedTerminalState || lastState.Name != "4" {
+			t.Errorf("Expected run %v to reach the terminal state 4, but it %v in state %v.", runIndex, run.Outcome, lastState.Name)
+		}
+
+		if maximal, _ := run.Fragment.IsMaximal(); !maximal {
+			t.Errorf("Expected run %v to be maximal.", runIndex)
+		}
+	}
+}
+
+/*
+TestSimulator_Run4
+Description:
+	A scheduler which chooses an action which is not enabled produces an error.
+*/
+func TestSimulator_Run4(t *testing.T) {
+	scheduler := SchedulerFunc(func(history History, enabled []string, random *rand.Rand) (string, error) {
+		return "3", nil
+	})
+
+	sim, _ := NewSimulator(mc.GetSimpleTS1(), scheduler, 0)
+	_, err := sim.Run(10)
+	if err == nil {
+		t.Errorf("Expected an error for the action which is not enabled.")
+	}
+
+	if err.Error() != "The scheduler chose the action \"3\", which is not enabled in state \"1\"." {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+/*
+TestSimulator_Run5
+Description:
+	Runs with zero steps contain only an initial state; a negative number of steps produces an error.
+*/
+func TestSimulator_Run5(t *testing.T) {
+	sim, _ := NewSimulator(mc.GetSimpleTS1(), UniformScheduler{}, 0)
+
+	run, err := sim.Run(0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if run.Outcome != ReachedStepLimit || stateNames(run.Fragment) != "1" {
+		t.Errorf("Expected a run with only the initial state, but found %v (%v).", stateNames(run.Fragment), run.Outcome)
+	}
+
+	_, err = sim.Run(-1)
+	if err == nil {
+		t.Errorf("Expected an error for the negative number of steps.")
+	}
+}
+
+/*
+TestNewSimulator1
+Description:
+	A transition system without initial states or a missing scheduler produces an error.
+*/
+func TestNewSimulator1(t *testing.T) {
+	ts := mc.GetSimpleTS1()
+	ts.I = nil
+
+	_, err := NewSimulator(ts, UniformScheduler{}, 0)
+	if err == nil || err.Error() != "The transition system has no initial states to start a simulation from." {
+		t.Errorf("Expected an error for the missing initial states, but found %v.", err)
+	}
+
+	_, err = NewSimulator(mc.GetSimpleTS1(), nil, 0)
+	if err == nil {
+		t.Errorf("Expected an error for the nil scheduler.")
+	}
+}
+
+/*
+TestNewAdaptiveSimulator1
+Description:
+	Simulates the beverage vending machine, which closes a cycle after paying and getting a drink.
+*/
+func TestNewAdaptiveSimulator1(t *testing.T) {
+	sim, err := NewAdaptiveSimulator(adaptive.GetBeverageVendingMachineTS(), []string{"pay"}, UniformScheduler{}, 1)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	run, err := sim.Run(10)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	names := stateNames(run.Fragment)
+	if run.Outcome != ClosedCycle || (names != "pay select beer pay" && names != "pay select soda pay") {
+		t.Errorf("Expected the run to pay, select, get a drink and return to pay, but found %v (%v).", names, run.Outcome)
+	}
+
+	if len(run.Lasso.RepeatingSuffix.States()) != 3 {
+		t.Errorf("Expected a cycle with 3 states, but found %v.", run.Lasso.RepeatingSuffix.States())
+	}
+}
+
+/*
+TestOutcome_String1
+Description:
+	Checks the names of the outcomes.
+*/
+func TestOutcome_String1(t *testing.T) {
+	if ClosedCycle.String() != "closed a cycle" {
+		t.Errorf("Unexpected name for ClosedCycle: %v", ClosedCycle)
+	}
+
+	if Outcome(7).String() != "Outcome(7)" {
+		t.Errorf("Unexpected name for an unknown outcome: %v", Outcome(7))
+	}
+}
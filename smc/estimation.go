@@ -0,0 +1,154 @@
+/*
+estimation.go
+Description:
+	Estimation of the probability of a property with a number of samples chosen by the Chernoff-Hoeffding bound:
+	with N >= ln(2/delta) / (2 epsilon^2) samples, the fraction of the paths which satisfy the property is within
+	epsilon of the probability with confidence at least 1 - delta (also known as the Okamoto bound).
+*/
+
+package smc
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+EstimationOptions
+Description:
+	The precision Epsilon and the error probability Delta of the estimate, the number of worker goroutines,
+	and the seed from which the sources of randomness of the workers are derived.
+*/
+type EstimationOptions struct {
+	Epsilon float64
+	Delta   float64
+	Workers int
+	Seed    int64
+}
+
+/*
+Estimate
+Description:
+	An estimate of the probability of a property, with the confidence interval [Lower, Upper] which contains the
+	probability with the given confidence, and the number of samples which were drawn and which satisfied the property.
+*/
+type Estimate struct {
+	Probability float64
+	Lower       float64
+	Upper       float64
+	Confidence  float64
+	Samples     int
+	Successes   int
+}
+
+/*
+Functions
+*/
+
+/*
+DefaultEstimationOptions
+Description:
+	Returns options which estimate the probability within 0.01 with confidence 0.95 using 4 workers.
+*/
+func DefaultEstimationOptions() EstimationOptions {
+	return EstimationOptions{
+		Epsilon: 0.01,
+		Delta:   0.05,
+		Workers: 4,
+		Seed:    1,
+	}
+}
+
+/*
+Check
+Description:
+	Checks that the precision and the error probability are in (0,1) and that there is at least one worker.
+*/
+func (options EstimationOptions) Check() error {
+	if !(options.Epsilon > 0 && options.Epsilon < 1) {
+		return fmt.Errorf("The precision epsilon must be in (0,1), but it is %v.", options.Epsilon)
+	}
+
+	if !(options.Delta > 0 && options.Delta < 1) {
+		return fmt.Errorf("The error probability delta must be in (0,1), but it is %v.", options.Delta)
+	}
+
+	if options.Workers <= 0 {
+		return fmt.Errorf("The number of workers must be positive, but it is %v.", options.Workers)
+	}
+
+	return nil
+}
+
+/*
+ChernoffHoeffdingSamples
+Description:
+	Returns the number of samples which are needed for an estimate within epsilon of the probability with
+	confidence 1 - delta.
+Usage:
+	n := ChernoffHoeffdingSamples(0.01, 0.05) // 18445
+*/
+func ChernoffHoeffdingSamples(epsilon float64, delta float64) int {
+	return int(math.Ceil(math.Log(2/delta) / (2 * epsilon * epsilon)))
+}
+
+/*
+hoeffdingInterval
+Description:
+	Returns the confidence interval of the probability with confidence 1 - delta after n samples with the given
+	fraction of successes, clipped to [0,1].
+*/
+func hoeffdingInterval(fraction float64, n int, delta float64) (float64, float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	width := math.Sqrt(math.Log(2/delta) / (2 * float64(n)))
+	return math.Max(0, fraction-width), math.Min(1, fraction+width)
+}
+
+/*
+EstimateProbability
+Description:
+	Estimates the probability that a path of the model satisfies the property. The samples are divided evenly
+	among the workers, and the result only depends on the seed and the number of workers.
+Usage:
+	model, _ := FromDTMC(chain)
+	estimate, err := EstimateProbability(model, Property{Formula: formula, Steps: 10}, DefaultEstimationOptions())
+*/
+func EstimateProbability(model Model, property Property, options EstimationOptions) (Estimate, error) {
+	if err := options.Check(); err != nil {
+		return Estimate{}, err
+	}
+
+	s, err := newSampler(model, property, options.Workers, options.Seed)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	n := ChernoffHoeffdingSamples(options.Epsilon, options.Delta)
+	outcomes, err := s.draw(s.split(n))
+	if err != nil {
+		return Estimate{}, fmt.Errorf("There was an issue evaluating the property: %v", err)
+	}
+
+	successes := 0
+	for _, holds := range outcomes {
+		if holds {
+			successes++
+		}
+	}
+
+	probability := float64(successes) / float64(n)
+	return Estimate{
+		Probability: probability,
+		Lower:       math.Max(0, probability-options.Epsilon),
+		Upper:       math.Min(1, probability+options.Epsilon),
+		Confidence:  1 - options.Delta,
+		Samples:     n,
+		Successes:   successes,
+	}, nil
+}
@@ -0,0 +1,105 @@
+/*
+estimation_test.go
+Description:
+
+	Tests for the Chernoff-Hoeffding estimation defined in estimation.go
+*/
+package smc
+
+import (
+	"testing"
+)
+
+/*
+TestChernoffHoeffdingSamples1
+Description:
+
+	The number of samples for a precision of 0.01 with confidence 0.95 is ceil(ln(40) / 0.0002) = 18445.
+*/
+func TestChernoffHoeffdingSamples1(t *testing.T) {
+	if n := ChernoffHoeffdingSamples(0.01, 0.05); n != 18445 {
+		t.Errorf("Expected 18445 samples, but found %v.", n)
+	}
+
+	if n := ChernoffHoeffdingSamples(0.1, 0.05); n != 185 {
+		t.Errorf("Expected 185 samples, but found %v.", n)
+	}
+}
+
+/*
+TestEstimateProbability1
+Description:
+
+	Estimates the probability that the die shows six within 3 steps (1/8) and that it is done (3/4).
+*/
+func TestEstimateProbability1(t *testing.T) {
+	model := getDieModel(t)
+	options := DefaultEstimationOptions()
+
+	for formula, expected := range map[string]float64{"F six": 0.125, "F done": 0.75} {
+		estimate, err := EstimateProbability(model, getProperty(t, formula, 3), options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if estimate.Samples != 18445 || estimate.Confidence != 0.95 {
+			t.Errorf("Expected 18445 samples with confidence 0.95, but found %v.", estimate)
+		}
+
+		if estimate.Lower > expected || estimate.Upper < expected || estimate.Upper-estimate.Lower > 0.02+1e-12 {
+			t.Errorf("Expected an interval of width 0.02 around %v, but found %v.", expected, estimate)
+		}
+
+		if float64(estimate.Successes)/float64(estimate.Samples) != estimate.Probability {
+			t.Errorf("Expected the estimate to be the fraction of successes, but found %v.", estimate)
+		}
+	}
+}
+
+/*
+TestEstimateProbability2
+Description:
+
+	The estimate only depends on the seed and the number of workers.
+*/
+func TestEstimateProbability2(t *testing.T) {
+	model := getDieModel(t)
+	property := getProperty(t, "F done", 3)
+	options := EstimationOptions{Epsilon: 0.05, Delta: 0.05, Workers: 8, Seed: 3}
+
+	first, _ := EstimateProbability(model, property, options)
+	for run := 0; run < 5; run++ {
+		if estimate, _ := EstimateProbability(model, property, options); estimate != first {
+			t.Errorf("Expected the estimate %v, but found %v.", first, estimate)
+		}
+	}
+}
+
+/*
+TestEstimateProbability3
+Description:
+
+	Invalid options are rejected.
+*/
+func TestEstimateProbability3(t *testing.T) {
+	model := getDieModel(t)
+	property := getProperty(t, "F done", 3)
+
+	options := DefaultEstimationOptions()
+	options.Epsilon = 0
+	if _, err := EstimateProbability(model, property, options); err == nil || err.Error() != "The precision epsilon must be in (0,1), but it is 0." {
+		t.Errorf("Expected an error for epsilon, but found %v.", err)
+	}
+
+	options = DefaultEstimationOptions()
+	options.Delta = 1
+	if _, err := EstimateProbability(model, property, options); err == nil || err.Error() != "The error probability delta must be in (0,1), but it is 1." {
+		t.Errorf("Expected an error for delta, but found %v.", err)
+	}
+
+	options = DefaultEstimationOptions()
+	options.Workers = -2
+	if _, err := EstimateProbability(model, property, options); err == nil || err.Error() != "The number of workers must be positive, but it is -2." {
+		t.Errorf("Expected an error for the workers, but found %v.", err)
+	}
+}
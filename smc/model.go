@@ -0,0 +1,204 @@
+/*
+model.go
+Description:
+	The models and properties of statistical model checking. A Model draws random paths of a fixed number of
+	steps; Markov chains and Markov decision processes whose nondeterminism is resolved by a scheduler are supported.
+	A Property is an LTL formula which is evaluated on the first steps of each path.
+*/
+
+package smc
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	mc "github.com/kwesiRutledge/ModelChecking"
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+	"github.com/kwesiRutledge/ModelChecking/markov"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+Model
+Description:
+	A stochastic model which can draw random paths. Path returns the labels of the steps+1 states of a path
+	which starts in a random initial state. Implementations must be safe for concurrent use, as long as each
+	goroutine uses its own source of randomness.
+*/
+type Model interface {
+	Path(random *rand.Rand, steps int) [][]mc.AtomicProposition
+}
+
+/*
+Property
+Description:
+	A bounded LTL property: the formula is evaluated with the finite-trace semantics of ltl.EvaluateFinite on the
+	first Steps transitions of each path. For example, F goal with 10 steps holds if goal is reached within 10 steps.
+*/
+type Property struct {
+	Formula ltl.Formula
+	Steps   int
+}
+
+/*
+distribution
+Description:
+	A discrete distribution over state indices, stored as cumulative probabilities in the order of the indices.
+*/
+type distribution struct {
+	States     []int
+	Cumulative []float64
+}
+
+/*
+chainModel
+Description:
+	A DTMC with its distributions stored by state index, so that paths can be drawn without map lookups
+	and in an order which does not depend on map iteration.
+*/
+type chainModel struct {
+	Initial     distribution
+	Successors  []distribution
+	StateLabels [][]mc.AtomicProposition
+}
+
+/*
+Functions
+*/
+
+/*
+FromDTMC
+Description:
+	Creates a model which draws the paths of the Markov chain. The chain is checked first, so that every
+	state has a distribution of successors to draw from.
+Usage:
+	model, err := FromDTMC(chain)
+*/
+func FromDTMC(chain markov.DTMC) (Model, error) {
+	if err := chain.Check(); err != nil {
+		return nil, fmt.Errorf("There was an issue checking the chain: %v", err)
+	}
+
+	index := make(map[string]int)
+	for i, state := range chain.S {
+		index[state.Name] = i
+	}
+
+	model := chainModel{
+		Initial:     newDistribution(chain.I, index),
+		Successors:  make([]distribution, len(chain.S)),
+		StateLabels: make([][]mc.AtomicProposition, len(chain.S)),
+	}
+	for i, state := range chain.S {
+		model.Successors[i] = newDistribution(chain.P[state], index)
+		model.StateLabels[i] = chain.L[state]
+	}
+
+	return model, nil
+}
+
+/*
+FromMDP
+Description:
+	Creates a model which draws the paths of the MDP when the memoryless scheduler chooses the actions.
+*/
+func FromMDP(mdp markov.MDP, scheduler markov.MemorylessScheduler) (Model, error) {
+	chain, err := mdp.InducedDTMC(scheduler)
+	if err != nil {
+		return nil, fmt.Errorf("There was an issue applying the scheduler: %v", err)
+	}
+	return FromDTMC(chain)
+}
+
+/*
+FromMDPWithMemory
+Description:
+	Creates a model which draws the paths of the MDP when the finite-memory scheduler chooses the actions,
+	such as the schedulers computed by MDP.MaxRabinProbability().
+*/
+func FromMDPWithMemory(mdp markov.MDP, scheduler markov.FiniteMemoryScheduler) (Model, error) {
+	chain, err := scheduler.InducedDTMC(mdp)
+	if err != nil {
+		return nil, fmt.Errorf("There was an issue applying the scheduler: %v", err)
+	}
+	return FromDTMC(chain)
+}
+
+/*
+newDistribution
+Description:
+	Converts a distribution over the states of a DTMC into cumulative probabilities over their indices.
+*/
+func newDistribution(probabilities map[markov.DTMCState]float64, index map[string]int) distribution {
+	byIndex := make(map[int]float64)
+	var d distribution
+	for state, probability := range probabilities {
+		if probability > 0 {
+			byIndex[index[state.Name]] = probability
+			d.States = append(d.States, index[state.Name])
+		}
+	}
+	sort.Ints(d.States)
+
+	total := 0.0
+	for _, i := range d.States {
+		total += byIndex[i]
+		d.Cumulative = append(d.Cumulative, total)
+	}
+	return d
+}
+
+/*
+sample
+Description:
+	Draws a state index from the distribution.
+*/
+func (d distribution) sample(random *rand.Rand) int {
+	u := random.Float64() * d.Cumulative[len(d.Cumulative)-1]
+	for position, cumulative := range d.Cumulative {
+		if u < cumulative {
+			return d.States[position]
+		}
+	}
+	return d.States[len(d.States)-1]
+}
+
+/*
+Path
+Description:
+	Draws a path of the chain with the given number of steps.
+*/
+func (model chainModel) Path(random *rand.Rand, steps int) [][]mc.AtomicProposition {
+	state := model.Initial.sample(random)
+	path := [][]mc.AtomicProposition{model.StateLabels[state]}
+	for step := 0; step < steps; step++ {
+		state = model.Successors[state].sample(random)
+		path = append(path, model.StateLabels[state])
+	}
+	return path
+}
+
+/*
+Check
+Description:
+	Checks that the property looks at a nonnegative number of steps.
+*/
+func (property Property) Check() error {
+	if property.Steps < 0 {
+		return fmt.Errorf("The number of steps of a property must be nonnegative, but it is %v.", property.Steps)
+	}
+	return nil
+}
+
+/*
+Sample
+Description:
+	Draws a path of the model and determines if it satisfies the property.
+*/
+func (property Property) Sample(model Model, random *rand.Rand) (bool, error) {
+	return ltl.EvaluateFinite(property.Formula, model.Path(random, property.Steps))
+}
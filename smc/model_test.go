@@ -0,0 +1,248 @@
+/*
+model_test.go
+Description:
+
+	Tests for the models and properties defined in model.go
+*/
+package smc
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kwesiRutledge/ModelChecking/ltl"
+	"github.com/kwesiRutledge/ModelChecking/markov"
+)
+
+/*
+GetKnuthYaoDie
+Description:
+
+	The Knuth-Yao simulation of a fair die with a fair coin (Baier and Katoen, Example 10.3).
+	Within 3 steps the die is done with probability 0.75, and it shows six with probability 1/8.
+*/
+func GetKnuthYaoDie() markov.DTMC {
+	chain, _ := markov.GetDTMC(
+		[]string{"s0", "s1", "s2", "s3", "s4", "s5", "s6", "d1", "d2", "d3", "d4", "d5", "d6"},
+		map[string]map[string]float64{
+			"s0": {"s1": 0.5, "s2": 0.5},
+			"s1": {"s3": 0.5, "s4": 0.5},
+			"s2": {"s5": 0.5, "s6": 0.5},
+			"s3": {"s1": 0.5, "d1": 0.5},
+			"s4": {"d2": 0.5, "d3": 0.5},
+			"s5": {"d4": 0.5, "d5": 0.5},
+			"s6": {"s2": 0.5, "d6": 0.5},
+			"d1": {"d1": 1},
+			"d2": {"d2": 1},
+			"d3": {"d3": 1},
+			"d4": {"d4": 1},
+			"d5": {"d5": 1},
+			"d6": {"d6": 1},
+		},
+		map[string]float64{"s0": 1},
+		[]string{"done", "one", "two", "three", "four", "five", "six"},
+		map[string][]string{
+			"d1": {"done", "one"},
+			"d2": {"done", "two"},
+			"d3": {"done", "three"},
+			"d4": {"done", "four"},
+			"d5": {"done", "five"},
+			"d6": {"done", "six"},
+		},
+	)
+	return chain
+}
+
+/*
+GetDetourMDP
+Description:
+
+	An MDP in which s0 can gamble on reaching the goal (b) or take a detour through s1 (a).
+*/
+func GetDetourMDP() markov.MDP {
+	mdp, _ := markov.GetMDP(
+		[]string{"s0", "s1", "goal", "fail"},
+		[]string{"a", "b", "stay"},
+		map[string]map[string]map[string]float64{
+			"s0":   {"a": {"s1": 1}, "b": {"goal": 0.5, "fail": 0.5}},
+			"s1":   {"a": {"s0": 1}, "b": {"goal": 0.9, "fail": 0.1}},
+			"goal": {"stay": {"goal": 1}},
+			"fail": {"stay": {"fail": 1}},
+		},
+		map[string]float64{"s0": 1},
+		[]string{"goal", "fail"},
+		map[string][]string{"goal": {"goal"}, "fail": {"fail"}},
+	)
+	return mdp
+}
+
+/*
+getProperty
+Description:
+
+	Parses the formula of a property.
+*/
+func getProperty(t *testing.T, formula string, steps int) Property {
+	parsed, err := ltl.Parse(formula)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing \"%v\": %v", formula, err)
+	}
+	return Property{Formula: parsed, Steps: steps}
+}
+
+/*
+getDieModel
+Description:
+
+	Creates the model of the Knuth-Yao die.
+*/
+func getDieModel(t *testing.T) Model {
+	model, err := FromDTMC(GetKnuthYaoDie())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return model
+}
+
+/*
+fraction
+Description:
+
+	Returns the fraction of n samples of the property which hold.
+*/
+func fraction(t *testing.T, model Model, property Property, n int) float64 {
+	random := rand.New(rand.NewSource(7))
+	successes := 0
+	for sample := 0; sample < n; sample++ {
+		holds, err := property.Sample(model, random)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if holds {
+			successes++
+		}
+	}
+	return float64(successes) / float64(n)
+}
+
+/*
+TestFromDTMC1
+Description:
+
+	Paths of the Knuth-Yao die start in s0, have one label set per state and end in a done state after 3 steps
+	in about 3/4 of the samples.
+*/
+func TestFromDTMC1(t *testing.T) {
+	model, err := FromDTMC(GetKnuthYaoDie())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	path := model.Path(rand.New(rand.NewSource(1)), 4)
+	if len(path) != 5 || len(path[0]) != 0 || len(path[1]) != 0 {
+		t.Errorf("Expected 5 label sets starting with two unlabelled states, but found %v.", path)
+	}
+
+	if p := fraction(t, model, getProperty(t, "F done", 3), 20000); p < 0.73 || p > 0.77 {
+		t.Errorf("Expected about 0.75 of the paths to be done, but found %v.", p)
+	}
+}
+
+/*
+TestFromDTMC2
+Description:
+
+	A chain without an initial distribution, or with a state without successors, is rejected instead of
+	producing a model which cannot draw paths.
+*/
+func TestFromDTMC2(t *testing.T) {
+	chain := GetKnuthYaoDie()
+	chain.I = map[markov.DTMCState]float64{}
+	if _, err := FromDTMC(chain); err == nil {
+		t.Errorf("Expected an error for the empty initial distribution.")
+	}
+
+	chain = GetKnuthYaoDie()
+	chain.P = map[markov.DTMCState]map[markov.DTMCState]float64{}
+	if _, err := FromDTMC(chain); err == nil || !strings.Contains(err.Error(), "has no outgoing transitions") {
+		t.Errorf("Expected an error for the missing transitions, but found %v.", err)
+	}
+}
+
+/*
+TestFromMDP1
+Description:
+
+	Gambling in s0 reaches the goal in one step with probability 1/2.
+*/
+func TestFromMDP1(t *testing.T) {
+	mdp := GetDetourMDP()
+	scheduler := markov.MemorylessScheduler{
+		mdp.S[0]: "b", mdp.S[1]: "a", mdp.S[2]: "stay", mdp.S[3]: "stay",
+	}
+
+	model, err := FromMDP(mdp, scheduler)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if p := fraction(t, model, getProperty(t, "X goal", 1), 20000); p < 0.48 || p > 0.52 {
+		t.Errorf("Expected about half of the paths to reach the goal, but found %v.", p)
+	}
+
+	delete(scheduler, mdp.S[3])
+	if _, err := FromMDP(mdp, scheduler); err == nil {
+		t.Errorf("Expected an error for the incomplete scheduler.")
+	}
+}
+
+/*
+TestFromMDPWithMemory1
+Description:
+
+	A scheduler which takes the detour once before gambling in s0 reaches the goal after exactly 3 steps.
+*/
+func TestFromMDPWithMemory1(t *testing.T) {
+	mdp := GetDetourMDP()
+	s0, s1, goal, fail := mdp.S[0], mdp.S[1], mdp.S[2], mdp.S[3]
+	scheduler := markov.FiniteMemoryScheduler{
+		InitialMemory: map[markov.MDPState]string{s0: "first"},
+		NextMemory: map[string]map[markov.MDPState]string{
+			"first":  {s1: "first", s0: "second"},
+			"second": {goal: "second", fail: "second"},
+		},
+		Actions: map[markov.MDPState]map[string]string{
+			s0:   {"first": "a", "second": "b"},
+			s1:   {"first": "a"},
+			goal: {"second": "stay"},
+			fail: {"second": "stay"},
+		},
+	}
+
+	model, err := FromMDPWithMemory(mdp, scheduler)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if p := fraction(t, model, getProperty(t, "F goal", 2), 1000); p != 0 {
+		t.Errorf("Expected no path to reach the goal within 2 steps, but found %v.", p)
+	}
+
+	if p := fraction(t, model, getProperty(t, "F goal", 3), 20000); p < 0.48 || p > 0.52 {
+		t.Errorf("Expected about half of the paths to reach the goal within 3 steps, but found %v.", p)
+	}
+}
+
+/*
+TestProperty_Check1
+Description:
+
+	A property with a negative number of steps is rejected.
+*/
+func TestProperty_Check1(t *testing.T) {
+	property := getProperty(t, "F done", -1)
+	if err := property.Check(); err == nil || err.Error() != "The number of steps of a property must be nonnegative, but it is -1." {
+		t.Errorf("Expected an error for the number of steps, but found %v.", err)
+	}
+}
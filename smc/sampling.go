@@ -0,0 +1,127 @@
+/*
+sampling.go
+Description:
+	Parallel sampling of a property. Each worker goroutine has its own source of randomness, whose seed is derived
+	from the seed of the computation and the index of the worker, and the outcomes of the workers are combined in a
+	fixed order. The results therefore only depend on the seed and the number of workers, and not on the timing of
+	the goroutines.
+*/
+
+package smc
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+sampler
+Description:
+	Draws samples of a property with one source of randomness per worker.
+*/
+type sampler struct {
+	Model    Model
+	Property Property
+	randoms  []*rand.Rand
+}
+
+/*
+Functions
+*/
+
+/*
+newSampler
+Description:
+	Creates a sampler with the given number of workers.
+*/
+func newSampler(model Model, property Property, workers int, seed int64) (*sampler, error) {
+	if model == nil {
+		return nil, fmt.Errorf("The model must not be nil.")
+	}
+
+	if err := property.Check(); err != nil {
+		return nil, err
+	}
+
+	if workers <= 0 {
+		return nil, fmt.Errorf("The number of workers must be positive, but it is %v.", workers)
+	}
+
+	s := &sampler{Model: model, Property: property}
+	for worker := 0; worker < workers; worker++ {
+		s.randoms = append(s.randoms, rand.New(rand.NewSource(WorkerSeed(seed, worker))))
+	}
+	return s, nil
+}
+
+/*
+WorkerSeed
+Description:
+	Returns the seed of the source of randomness of a worker. The seeds of different workers are decorrelated with
+	the SplitMix64 finalizer, so that neighbouring seeds do not produce similar streams.
+*/
+func WorkerSeed(seed int64, worker int) int64 {
+	z := uint64(seed) + uint64(worker+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return int64(z ^ (z >> 31))
+}
+
+/*
+draw
+Description:
+	Lets worker w draw counts[w] samples in its own goroutine and returns the outcomes of worker 0, then those of
+	worker 1, and so on. If some samples fail, the error of the first failing worker is returned.
+*/
+func (s *sampler) draw(counts []int) ([]bool, error) {
+	outcomes := make([][]bool, len(s.randoms))
+	errs := make([]error, len(s.randoms))
+
+	var wg sync.WaitGroup
+	for worker := range s.randoms {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for sample := 0; sample < counts[worker]; sample++ {
+				holds, err := s.Property.Sample(s.Model, s.randoms[worker])
+				if err != nil {
+					errs[worker] = err
+					return
+				}
+				outcomes[worker] = append(outcomes[worker], holds)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	var all []bool
+	for worker := range s.randoms {
+		if errs[worker] != nil {
+			return nil, errs[worker]
+		}
+		all = append(all, outcomes[worker]...)
+	}
+	return all, nil
+}
+
+/*
+split
+Description:
+	Divides n samples among the workers as evenly as possible, giving the extra samples to the first workers.
+*/
+func (s *sampler) split(n int) []int {
+	workers := len(s.randoms)
+	counts := make([]int, workers)
+	for worker := range counts {
+		counts[worker] = n / workers
+		if worker < n%workers {
+			counts[worker]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,84 @@
+/*
+sampling_test.go
+Description:
+
+	Tests for the parallel sampling defined in sampling.go
+*/
+package smc
+
+import (
+	"testing"
+)
+
+/*
+TestWorkerSeed1
+Description:
+
+	The seeds of the workers are distinct and do not depend on anything but the seed and the worker.
+*/
+func TestWorkerSeed1(t *testing.T) {
+	seeds := make(map[int64]bool)
+	for worker := 0; worker < 100; worker++ {
+		seeds[WorkerSeed(1, worker)] = true
+		if WorkerSeed(1, worker) != WorkerSeed(1, worker) {
+			t.Errorf("Expected the seed of worker %v to be deterministic.", worker)
+		}
+	}
+
+	if len(seeds) != 100 || WorkerSeed(1, 0) == WorkerSeed(2, 0) {
+		t.Errorf("Expected distinct seeds, but found %v distinct seeds.", len(seeds))
+	}
+}
+
+/*
+TestSampler_draw1
+Description:
+
+	Two samplers with the same seed draw the same outcomes, and the outcomes are ordered by worker.
+*/
+func TestSampler_draw1(t *testing.T) {
+	property := getProperty(t, "F done", 3)
+
+	var runs [][]bool
+	for run := 0; run < 2; run++ {
+		s, err := newSampler(getDieModel(t), property, 3, 42)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		counts := s.split(100)
+		if counts[0] != 34 || counts[1] != 33 || counts[2] != 33 {
+			t.Errorf("Expected the counts 34 33 33, but found %v.", counts)
+		}
+
+		outcomes, err := s.draw(counts)
+		if err != nil || len(outcomes) != 100 {
+			t.Fatalf("Expected 100 outcomes, but found %v (error %v).", len(outcomes), err)
+		}
+		runs = append(runs, outcomes)
+	}
+
+	for i := range runs[0] {
+		if runs[0][i] != runs[1][i] {
+			t.Fatalf("Expected identical outcomes, but they differ at sample %v.", i)
+		}
+	}
+}
+
+/*
+TestNewSampler1
+Description:
+
+	Samplers need a model and at least one worker.
+*/
+func TestNewSampler1(t *testing.T) {
+	property := getProperty(t, "F done", 3)
+
+	if _, err := newSampler(nil, property, 1, 1); err == nil || err.Error() != "The model must not be nil." {
+		t.Errorf("Expected an error for the model, but found %v.", err)
+	}
+
+	if _, err := newSampler(getDieModel(t), property, 0, 1); err == nil || err.Error() != "The number of workers must be positive, but it is 0." {
+		t.Errorf("Expected an error for the workers, but found %v.", err)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+sprt.go
+Description:
+	Wald's sequential probability ratio test for threshold queries P~theta [phi]. The query is decided by testing
+	the hypothesis H0: p >= theta + delta against H1: p <= theta - delta, where delta is the half-width of the
+	indifference region; samples are drawn until the log-likelihood ratio crosses one of Wald's bounds. The test
+	accepts H1 when H0 holds with probability at most Alpha, and accepts H0 when H1 holds with probability at most Beta.
+*/
+
+package smc
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/kwesiRutledge/ModelChecking/markov"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+SPRTOptions
+Description:
+	The half-width of the indifference region, the error bounds Alpha and Beta of the test, the largest number of
+	samples to draw, the number of samples which each worker draws per round, the number of worker goroutines and
+	the seed from which the sources of randomness of the workers are derived.
+*/
+type SPRTOptions struct {
+	Indifference float64
+	Alpha        float64
+	Beta         float64
+	MaxSamples   int
+	BatchSize    int
+	Workers      int
+	Seed         int64
+}
+
+/*
+TestResult
+Description:
+	The result of a sequential test. Holds is the answer to the query; it is only backed by the error bounds of the
+	test when Decided is true, and otherwise compares the estimate with the threshold after MaxSamples samples.
+	[Lower, Upper] is the Hoeffding confidence interval of the probability with confidence 1 - Alpha.
+*/
+type TestResult struct {
+	Holds              bool
+	Decided            bool
+	Samples            int
+	Successes          int
+	Estimate           float64
+	Lower              float64
+	Upper              float64
+	LogLikelihoodRatio float64
+}
+
+/*
+Functions
+*/
+
+/*
+DefaultSPRTOptions
+Description:
+	Returns options with an indifference region of half-width 0.01 and error bounds of 0.01, which draw at most
+	a million samples in rounds of 64 samples per worker using 4 workers.
+*/
+func DefaultSPRTOptions() SPRTOptions {
+	return SPRTOptions{
+		Indifference: 0.01,
+		Alpha:        0.01,
+		Beta:         0.01,
+		MaxSamples:   1000000,
+		BatchSize:    64,
+		Workers:      4,
+		Seed:         1,
+	}
+}
+
+/*
+Check
+Description:
+	Checks that the error bounds are in (0,1), that the indifference region is positive and that the sample counts
+	and the number of workers are positive.
+*/
+func (options SPRTOptions) Check() error {
+	if !(options.Indifference > 0) {
+		return fmt.Errorf("The indifference region must have a positive half-width, but it is %v.", options.Indifference)
+	}
+
+	if !(options.Alpha > 0 && options.Alpha < 1) || !(options.Beta > 0 && options.Beta < 1) {
+		return fmt.Errorf("The error bounds must be in (0,1), but they are %v and %v.", options.Alpha, options.Beta)
+	}
+
+	if options.MaxSamples <= 0 || options.BatchSize <= 0 {
+		return fmt.Errorf("The maximal number of samples and the batch size must be positive, but they are %v and %v.", options.MaxSamples, options.BatchSize)
+	}
+
+	if options.Workers <= 0 {
+		return fmt.Errorf("The number of workers must be positive, but it is %v.", options.Workers)
+	}
+
+	return nil
+}
+
+/*
+SequentialTest
+Description:
+	Decides whether the probability that a path of the model satisfies the property compares to the threshold as
+	required, e.g. SequentialTest(model, property, markov.CompareGreaterOrEqual, 0.5, options) decides P>=0.5 [phi].
+	Each round lets every worker draw BatchSize samples; the outcomes are examined in worker order and the test
+	stops at the first sample whose log-likelihood ratio crosses a bound, so the result only depends on the seed,
+	the number of workers and the batch size.
+*/
+func SequentialTest(model Model, property Property, comparison markov.Comparison, threshold float64, options SPRTOptions) (TestResult, error) {
+	if err := options.Check(); err != nil {
+		return TestResult{}, err
+	}
+
+	switch comparison {
+	case markov.CompareLess, markov.CompareLessOrEqual, markov.CompareGreater, markov.CompareGreaterOrEqual:
+	default:
+		return TestResult{}, fmt.Errorf("The comparison \"%v\" is not a threshold; use EstimateProbability() for queries.", comparison)
+	}
+
+	p0, p1 := threshold+options.Indifference, threshold-options.Indifference
+	if !(p1 > 0 && p0 < 1) {
+		return TestResult{}, fmt.Errorf("The indifference region (%v,%v) around the threshold %v must be inside (0,1).", p1, p0, threshold)
+	}
+
+	s, err := newSampler(model, property, options.Workers, options.Seed)
+	if err != nil {
+		return TestResult{}, err
+	}
+
+	successStep := math.Log(p1 / p0)
+	failureStep := math.Log((1 - p1) / (1 - p0))
+	acceptH1 := math.Log((1 - options.Beta) / options.Alpha)
+	acceptH0 := math.Log(options.Beta / (1 - options.Alpha))
+
+	var result TestResult
+	acceptedH0 := false
+	counts := make([]int, options.Workers)
+	for result.Samples < options.MaxSamples && !result.Decided {
+		for worker := range counts {
+			counts[worker] = options.BatchSize
+		}
+		outcomes, err := s.draw(counts)
+		if err != nil {
+			return TestResult{}, fmt.Errorf("There was an issue evaluating the property: %v", err)
+		}
+
+		for _, holds := range outcomes {
+			result.Samples++
+			if holds {
+				result.Successes++
+				result.LogLikelihoodRatio += successStep
+			} else {
+				result.LogLikelihoodRatio += failureStep
+			}
+
+			if result.LogLikelihoodRatio >= acceptH1 || result.LogLikelihoodRatio <= acceptH0 {
+				result.Decided = true
+				acceptedH0 = result.LogLikelihoodRatio <= acceptH0
+				break
+			}
+			if result.Samples == options.MaxSamples {
+				break
+			}
+		}
+	}
+
+	result.Estimate = float64(result.Successes) / float64(result.Samples)
+	result.Lower, result.Upper = hoeffdingInterval(result.Estimate, result.Samples, options.Alpha)
+
+	switch {
+	case !result.Decided:
+		result.Holds = comparison.Holds(result.Estimate, threshold)
+	case comparison == markov.CompareGreater || comparison == markov.CompareGreaterOrEqual:
+		result.Holds = acceptedH0
+	default:
+		result.Holds = !acceptedH0
+	}
+
+	return result, nil
+}
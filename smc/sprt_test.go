@@ -0,0 +1,117 @@
+/*
+sprt_test.go
+Description:
+
+	Tests for the sequential probability ratio test defined in sprt.go
+*/
+package smc
+
+import (
+	"testing"
+
+	"github.com/kwesiRutledge/ModelChecking/markov"
+)
+
+/*
+TestSequentialTest1
+Description:
+
+	The die is done within 3 steps with probability 0.75, so P>=0.7 and P<0.8 hold while P>0.8 and P<=0.7 do not.
+*/
+func TestSequentialTest1(t *testing.T) {
+	model := getDieModel(t)
+	property := getProperty(t, "F done", 3)
+
+	for _, query := range []struct {
+		Comparison markov.Comparison
+		Threshold  float64
+		Holds      bool
+	}{
+		{markov.CompareGreaterOrEqual, 0.7, true},
+		{markov.CompareLess, 0.8, true},
+		{markov.CompareGreater, 0.8, false},
+		{markov.CompareLessOrEqual, 0.7, false},
+	} {
+		result, err := SequentialTest(model, property, query.Comparison, query.Threshold, DefaultSPRTOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !result.Decided || result.Holds != query.Holds {
+			t.Errorf("Expected P%v%v to be decided as %v, but found %v.", query.Comparison, query.Threshold, query.Holds, result)
+		}
+
+		if result.Samples >= ChernoffHoeffdingSamples(0.01, 0.01) || result.Lower > 0.75 || result.Upper < 0.75 {
+			t.Errorf("Expected fewer samples than a fixed-size estimate and an interval around 0.75, but found %v.", result)
+		}
+	}
+}
+
+/*
+TestSequentialTest2
+Description:
+
+	The result only depends on the seed, the number of workers and the batch size.
+*/
+func TestSequentialTest2(t *testing.T) {
+	model := getDieModel(t)
+	property := getProperty(t, "F six", 3)
+
+	first, _ := SequentialTest(model, property, markov.CompareGreaterOrEqual, 0.1, DefaultSPRTOptions())
+	for run := 0; run < 5; run++ {
+		if result, _ := SequentialTest(model, property, markov.CompareGreaterOrEqual, 0.1, DefaultSPRTOptions()); result != first {
+			t.Errorf("Expected the result %v, but found %v.", first, result)
+		}
+	}
+}
+
+/*
+TestSequentialTest3
+Description:
+
+	When the probability is inside the indifference region, the test may run out of samples; it then reports
+	an undecided result after exactly MaxSamples samples.
+*/
+func TestSequentialTest3(t *testing.T) {
+	model := getDieModel(t)
+	options := DefaultSPRTOptions()
+	options.Indifference = 0.001
+	options.MaxSamples = 100
+
+	result, err := SequentialTest(model, getProperty(t, "F done", 3), markov.CompareGreaterOrEqual, 0.75, options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Decided || result.Samples != 100 || result.Holds != (result.Estimate >= 0.75) {
+		t.Errorf("Expected an undecided result after 100 samples, but found %v.", result)
+	}
+}
+
+/*
+TestSequentialTest4
+Description:
+
+	Queries and thresholds whose indifference region leaves (0,1) are rejected.
+*/
+func TestSequentialTest4(t *testing.T) {
+	model := getDieModel(t)
+	property := getProperty(t, "F done", 3)
+
+	_, err := SequentialTest(model, property, markov.CompareQuery, 0.5, DefaultSPRTOptions())
+	if err == nil || err.Error() != "The comparison \"=?\" is not a threshold; use EstimateProbability() for queries." {
+		t.Errorf("Expected an error for the query, but found %v.", err)
+	}
+
+	_, err = SequentialTest(model, property, markov.CompareGreaterOrEqual, 0.995, DefaultSPRTOptions())
+	if err == nil || err.Error() != "The indifference region (0.985,1.005) around the threshold 0.995 must be inside (0,1)." {
+		t.Errorf("Expected an error for the threshold, but found %v.", err)
+	}
+
+	options := DefaultSPRTOptions()
+	options.BatchSize = 0
+	_, err = SequentialTest(model, property, markov.CompareGreaterOrEqual, 0.5, options)
+	if err == nil || err.Error() != "The maximal number of samples and the batch size must be positive, but they are 1000000 and 0." {
+		t.Errorf("Expected an error for the batch size, but found %v.", err)
+	}
+}
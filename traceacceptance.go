@@ -0,0 +1,116 @@
+/*
+traceacceptance.go
+Description:
+	Decides whether a recorded sequence of label sets (e.g. the L field of a sequences.FiniteTrace which was read
+	from a log) is the trace of some initial path fragment of a transition system. When it is not, the first
+	step where the model and the log diverge is reported.
+*/
+package modelchecking
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Type Definitions
+*/
+
+/*
+TraceDivergence
+Description:
+	Describes the first step of a trace which no initial path of the system can produce.
+	Step is the index of that label set in the trace, Observed is the label set and Possible lists the
+	distinct label sets which the system could have produced at that step instead. Reached contains the states
+	which produce the trace up to (but not including) Step.
+*/
+type TraceDivergence struct {
+	Step     int
+	Observed []AtomicProposition
+	Possible [][]AtomicProposition
+	Reached  []TransitionSystemState
+}
+
+/*
+Functions
+*/
+
+/*
+AcceptsTrace
+Description:
+	Determines if some initial path fragment s0 s1 ... sn of the transition system has L(s0) L(s1) ... L(sn) equal
+	to the trace (each label set is compared as a set). If not, the returned TraceDivergence describes the first
+	step at which every candidate path fails.
+Usage:
+	accepted, divergence, err := ts.AcceptsTrace(trace.L)
+	if !accepted {
+		fmt.Println(divergence)
+	}
+*/
+func (ts TransitionSystem) AcceptsTrace(trace [][]AtomicProposition) (bool, TraceDivergence, error) {
+	// Input Processing
+	if len(trace) == 0 {
+		return false, TraceDivergence{}, errors.New("The trace is empty, but every path produces at least one label set.")
+	}
+
+	// Algorithm
+	candidates := ts.I
+	var reached []TransitionSystemState
+	for step, observed := range trace {
+		var matching []TransitionSystemState
+		for _, candidate := range candidates {
+			if APSetsAreEqual(ts.L[candidate], observed) {
+				matching = candidate.AppendIfUniqueTo(matching)
+			}
+		}
+
+		if len(matching) == 0 {
+			divergence := TraceDivergence{Step: step, Observed: observed, Reached: reached}
+			for _, candidate := range candidates {
+				divergence.Possible = appendLabelSetIfUnique(divergence.Possible, ts.L[candidate])
+			}
+			return false, divergence, nil
+		}
+
+		// Find the candidates for the next step
+		reached = matching
+		candidates = nil
+		for _, state := range matching {
+			successors, err := Post(state)
+			if err != nil {
+				return false, TraceDivergence{}, err
+			}
+			for _, successor := range successors {
+				candidates = successor.AppendIfUniqueTo(candidates)
+			}
+		}
+	}
+
+	return true, TraceDivergence{}, nil
+}
+
+/*
+String
+Description:
+	Describes where the trace and the model diverge.
+*/
+func (divergence TraceDivergence) String() string {
+	return fmt.Sprintf(
+		"The trace diverges from the model at step %v: the label set %v was observed, but the model could only produce %v.",
+		divergence.Step, divergence.Observed, divergence.Possible,
+	)
+}
+
+/*
+appendLabelSetIfUnique
+Description:
+	Appends the label set to sets if no equal label set is already in sets.
+*/
+func appendLabelSetIfUnique(sets [][]AtomicProposition, set []AtomicProposition) [][]AtomicProposition {
+	for _, existing := range sets {
+		if APSetsAreEqual(existing, set) {
+			return sets
+		}
+	}
+	return append(sets, set)
+}
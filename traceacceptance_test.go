@@ -0,0 +1,99 @@
+/*
+traceacceptance_test.go
+Description:
+	Tests for the trace membership functions defined in traceacceptance.go
+*/
+package modelchecking
+
+import (
+	"testing"
+)
+
+/*
+TestTransitionSystem_AcceptsTrace1
+Description:
+	The trace {A} {B,D} {D,C} {B,D} is produced by the path 1 2 3 2 of GetSimpleTS1().
+*/
+func TestTransitionSystem_AcceptsTrace1(t *testing.T) {
+	ts := GetSimpleTS1()
+
+	trace := [][]AtomicProposition{
+		StringSliceToAPs([]string{"A"}),
+		StringSliceToAPs([]string{"B", "D"}),
+		StringSliceToAPs([]string{"D", "C"}),
+		StringSliceToAPs([]string{"B", "D"}),
+	}
+
+	accepted, _, err := ts.AcceptsTrace(trace)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !accepted {
+		t.Errorf("Expected the trace to be accepted.")
+	}
+}
+
+/*
+TestTransitionSystem_AcceptsTrace2
+Description:
+	The trace {A} {C,D} diverges at step 1, where only {A} and {B,D} are possible.
+*/
+func TestTransitionSystem_AcceptsTrace2(t *testing.T) {
+	ts := GetSimpleTS1()
+
+	trace := [][]AtomicProposition{
+		StringSliceToAPs([]string{"A"}),
+		StringSliceToAPs([]string{"C", "D"}),
+	}
+
+	accepted, divergence, err := ts.AcceptsTrace(trace)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if accepted {
+		t.Errorf("Expected the trace to be rejected.")
+	}
+
+	if divergence.Step != 1 {
+		t.Errorf("Expected the divergence at step 1, but found step %v.", divergence.Step)
+	}
+
+	if len(divergence.Possible) != 2 {
+		t.Errorf("Expected 2 possible label sets, but found %v.", divergence.Possible)
+	}
+
+	if len(divergence.Reached) != 1 || divergence.Reached[0].Name != "1" {
+		t.Errorf("Expected the trace to reach state 1 before diverging, but found %v.", divergence.Reached)
+	}
+
+	expected := "The trace diverges from the model at step 1: the label set [C D] was observed, but the model could only produce [[A] [B D]]."
+	if divergence.String() != expected {
+		t.Errorf("Expected \"%v\", but found \"%v\".", expected, divergence)
+	}
+}
+
+/*
+TestTransitionSystem_AcceptsTrace3
+Description:
+	A trace which does not start with the label of an initial state diverges at step 0;
+	the empty trace produces an error.
+*/
+func TestTransitionSystem_AcceptsTrace3(t *testing.T) {
+	ts := GetSimpleTS1()
+
+	accepted, divergence, err := ts.AcceptsTrace([][]AtomicProposition{StringSliceToAPs([]string{"B"})})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if accepted || divergence.Step != 0 || len(divergence.Possible) != 1 {
+		t.Errorf("Expected the trace to diverge at step 0 with one possible label set, but found %v.", divergence)
+	}
+
+	_, _, err = ts.AcceptsTrace([][]AtomicProposition{})
+	if err == nil {
+		t.Errorf("Expected an error for the empty trace.")
+	}
+}